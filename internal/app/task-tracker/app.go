@@ -11,34 +11,88 @@ import (
 
 	"mkk-luna-test-task/internal/email"
 	"mkk-luna-test-task/internal/repository"
+	"mkk-luna-test-task/internal/storage"
 	"mkk-luna-test-task/internal/user"
 	"mkk-luna-test-task/internal/utils"
 
 	"github.com/go-chi/chi"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	taskarchivehandler "mkk-luna-test-task/internal/task/archive"
+	attachmentdownloadhandler "mkk-luna-test-task/internal/task/attachment/download"
+	attachmentlisthandler "mkk-luna-test-task/internal/task/attachment/list"
+	attachmentremovehandler "mkk-luna-test-task/internal/task/attachment/remove"
+	attachmentuploadhandler "mkk-luna-test-task/internal/task/attachment/upload"
+	taskblockeraddhandler "mkk-luna-test-task/internal/task/blocker/add"
+	taskblockerremovehandler "mkk-luna-test-task/internal/task/blocker/remove"
 	commentcreatehandler "mkk-luna-test-task/internal/task/comment/create"
+	commentedithandler "mkk-luna-test-task/internal/task/comment/edit"
 	commentlisthandler "mkk-luna-test-task/internal/task/comment/list"
+	commentmentionshandler "mkk-luna-test-task/internal/task/comment/mentions"
+	commentremovehandler "mkk-luna-test-task/internal/task/comment/remove"
 	taskcreatehandler "mkk-luna-test-task/internal/task/create"
 	taskedithandler "mkk-luna-test-task/internal/task/edit"
+	taskgethandler "mkk-luna-test-task/internal/task/get"
+	taskhistoryasofhandler "mkk-luna-test-task/internal/task/history/asof"
 	taskhistorylisthandler "mkk-luna-test-task/internal/task/history/list"
+	taskhistoryreverthandler "mkk-luna-test-task/internal/task/history/revert"
+	taskinboxhandler "mkk-luna-test-task/internal/task/inbox"
+	tasklabelattachhandler "mkk-luna-test-task/internal/task/label/attach"
+	tasklabeldetachhandler "mkk-luna-test-task/internal/task/label/detach"
 	tasklisthandler "mkk-luna-test-task/internal/task/list"
+	taskoverdue "mkk-luna-test-task/internal/task/overdue"
+	taskpurgehandler "mkk-luna-test-task/internal/task/purge"
+	"mkk-luna-test-task/internal/task/render"
+	taskrestorehandler "mkk-luna-test-task/internal/task/restore"
+	tasksubtaskaddhandler "mkk-luna-test-task/internal/task/subtask/add"
+	tasksubtaskremovehandler "mkk-luna-test-task/internal/task/subtask/remove"
+	taskunwatchhandler "mkk-luna-test-task/internal/task/watcher/unwatch"
+	taskwatchhandler "mkk-luna-test-task/internal/task/watcher/watch"
 	teamcreatehandler "mkk-luna-test-task/internal/team/create"
+	"mkk-luna-test-task/internal/team/invitation"
+	invitationaccepthandler "mkk-luna-test-task/internal/team/invitation/accept"
+	invitationdeclinehandler "mkk-luna-test-task/internal/team/invitation/decline"
+	invitationlisthandler "mkk-luna-test-task/internal/team/invitation/list"
+	labelcreatehandler "mkk-luna-test-task/internal/team/label/create"
+	labeledithandler "mkk-luna-test-task/internal/team/label/edit"
+	labellisthandler "mkk-luna-test-task/internal/team/label/list"
+	labelremovehandler "mkk-luna-test-task/internal/team/label/remove"
 	teamlisthandler "mkk-luna-test-task/internal/team/list"
+	teammemberedithandler "mkk-luna-test-task/internal/team/member/edit"
 	teaminvitehandler "mkk-luna-test-task/internal/team/member/invite"
+	teammemberleavehandler "mkk-luna-test-task/internal/team/member/leave"
+	teammemberlisthandler "mkk-luna-test-task/internal/team/member/list"
+	teammemberremovehandler "mkk-luna-test-task/internal/team/member/remove"
+	teammembertransferhandler "mkk-luna-test-task/internal/team/member/transfer"
+	"mkk-luna-test-task/internal/team/permission"
+	permissionlisthandler "mkk-luna-test-task/internal/team/permission/list"
+	permissionsethandler "mkk-luna-test-task/internal/team/permission/set"
+	workflowgethandler "mkk-luna-test-task/internal/team/workflow/get"
+	workflowsethandler "mkk-luna-test-task/internal/team/workflow/set"
+	jwkshandler "mkk-luna-test-task/internal/user/jwks"
 	loginhandler "mkk-luna-test-task/internal/user/login"
+	logouthandler "mkk-luna-test-task/internal/user/logout"
+	notificationlisthandler "mkk-luna-test-task/internal/user/notification/list"
+	notificationreadhandler "mkk-luna-test-task/internal/user/notification/read"
+	notificationreadallhandler "mkk-luna-test-task/internal/user/notification/readall"
 	registerhandler "mkk-luna-test-task/internal/user/register"
+	"mkk-luna-test-task/internal/user/session"
+	tokenrefreshhandler "mkk-luna-test-task/internal/user/token/refresh"
 )
 
 const (
 	timeout = 5 * time.Second
 
+	// Запрос к S3 передаёт файл целиком, поэтому ему нужно больше времени, чем обычному.
+	s3Timeout = 5 * time.Minute
+
 	circuitBreakerDefaultMaxFailures = 5
 	circuitBreakerDefaultTimeout     = 10 * time.Second
 )
 
 type app struct {
-	server http.Server
+	server     http.Server
+	overdueJob *taskoverdue.Job
 }
 
 func NewApp() *app {
@@ -93,7 +147,24 @@ func (a *app) Register() error {
 	chiRouter.Handle("/metrics", promhttp.Handler())
 	chiRouter.Handle("/health", utils.NewHealthcheckHandler())
 
-	userGetter := user.NewJwtUserFromRequestGetter([]byte(envs.JwtSecret))
+	jwtKeys, err := loadOrGenerateJwtKeys(envs.JwtKeysDir, envs.JwtSigningKeyId)
+
+	if err != nil {
+		return err
+	}
+
+	userGetter := user.NewJwtUserFromRequestGetter(jwtKeys, repo)
+
+	tokenIssuer := session.NewJwtIssuer(jwtKeys, envs.JwtExpiration)
+
+	permissions := permission.NewEngine(repo, repo)
+	renderer := render.NewRenderer(repo, permissions)
+
+	blobs, err := newAttachmentStorage(envs)
+
+	if err != nil {
+		return err
+	}
 
 	rateLimitingMiddleware := func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -113,6 +184,14 @@ func (a *app) Register() error {
 		})
 	}
 
+	jwksExec := jwkshandler.NewExecutor(jwtKeys)
+
+	chiRouter.Get("/.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(jwkshandler.NewHandler(jwksExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
 	registerExec := registerhandler.NewExecutor(repo)
 
 	chiRouter.Post("/api/v1/register", func(w http.ResponseWriter, r *http.Request) {
@@ -121,7 +200,7 @@ func (a *app) Register() error {
 		utils.ChainMiddlewares(h, rateLimitingMiddleware, metricsMiddleware).ServeHTTP(w, r)
 	})
 
-	loginExec := loginhandler.NewExecutor(repo, []byte(envs.JwtSecret), envs.JwtExpiration)
+	loginExec := loginhandler.NewExecutor(repo, repo, tokenIssuer, envs.RefreshExpiry)
 
 	chiRouter.Post("/api/v1/login", func(w http.ResponseWriter, r *http.Request) {
 		h := http.HandlerFunc(loginhandler.NewHandler(loginExec).Handle)
@@ -129,6 +208,22 @@ func (a *app) Register() error {
 		utils.ChainMiddlewares(h, rateLimitingMiddleware, metricsMiddleware).ServeHTTP(w, r)
 	})
 
+	tokenRefreshExec := tokenrefreshhandler.NewExecutor(repo, repo, repo, repo, repo, tokenIssuer)
+
+	chiRouter.Post("/api/v1/token/refresh", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(tokenrefreshhandler.NewHandler(tokenRefreshExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	logoutExec := logouthandler.NewExecutor(repo)
+
+	chiRouter.Post("/api/v1/logout", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(logouthandler.NewHandler(logoutExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
 	teamCreateExec := teamcreatehandler.NewExecutor(repo)
 
 	chiRouter.Post("/api/v1/teams", func(w http.ResponseWriter, r *http.Request) {
@@ -152,7 +247,13 @@ func (a *app) Register() error {
 
 	mockEmailSender := email.NewStubSender(circuitBreakerClient)
 
-	inviteExec := teaminvitehandler.NewExecutor(repo, repo, mockEmailSender)
+	if envs.InviteTokenSecret == "" {
+		return errors.New("INVITE_TOKEN_SECRET is not set")
+	}
+
+	inviteTokenSigner := invitation.NewTokenSigner([]byte(envs.InviteTokenSecret))
+
+	inviteExec := teaminvitehandler.NewExecutor(repo, permissions, repo, repo, inviteTokenSigner, mockEmailSender, repo, envs.InviteExpiry)
 
 	chiRouter.Post("/api/v1/teams/{id}/invite", func(w http.ResponseWriter, r *http.Request) {
 		h := http.HandlerFunc(teaminvitehandler.NewHandler(inviteExec).Handle)
@@ -160,7 +261,147 @@ func (a *app) Register() error {
 		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
 	})
 
-	taskCreateExec := taskcreatehandler.NewExecutor(repo, repo)
+	invitationAcceptExec := invitationaccepthandler.NewExecutor(inviteTokenSigner, repo, repo, repo, repo, repo)
+
+	chiRouter.Post("/api/v1/invitations/accept", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(invitationaccepthandler.NewHandler(invitationAcceptExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	chiRouter.Post("/api/v1/invitations/{invitationId}/accept", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(invitationaccepthandler.NewHandler(invitationAcceptExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	invitationDeclineExec := invitationdeclinehandler.NewExecutor(inviteTokenSigner, repo, repo, repo)
+
+	chiRouter.Post("/api/v1/invitations/decline", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(invitationdeclinehandler.NewHandler(invitationDeclineExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	chiRouter.Post("/api/v1/invitations/{invitationId}/decline", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(invitationdeclinehandler.NewHandler(invitationDeclineExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	invitationListExec := invitationlisthandler.NewExecutor(repo, repo)
+
+	chiRouter.Get("/api/v1/me/invitations", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(invitationlisthandler.NewHandler(invitationListExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	permissionListExec := permissionlisthandler.NewExecutor(permissions, permissions)
+
+	chiRouter.Get("/api/v1/teams/{id}/permissions", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(permissionlisthandler.NewHandler(permissionListExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	permissionSetExec := permissionsethandler.NewExecutor(permissions, repo)
+
+	chiRouter.Put("/api/v1/teams/{id}/permissions", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(permissionsethandler.NewHandler(permissionSetExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	workflowGetExec := workflowgethandler.NewExecutor(permissions, repo)
+
+	chiRouter.Get("/api/v1/teams/{id}/workflow", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(workflowgethandler.NewHandler(workflowGetExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	workflowSetExec := workflowsethandler.NewExecutor(permissions, repo, repo)
+
+	chiRouter.Put("/api/v1/teams/{id}/workflow", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(workflowsethandler.NewHandler(workflowSetExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	labelListExec := labellisthandler.NewExecutor(permissions, repo)
+
+	chiRouter.Get("/api/v1/teams/{id}/labels", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(labellisthandler.NewHandler(labelListExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	labelCreateExec := labelcreatehandler.NewExecutor(permissions, repo)
+
+	chiRouter.Post("/api/v1/teams/{id}/labels", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(labelcreatehandler.NewHandler(labelCreateExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	labelEditExec := labeledithandler.NewExecutor(permissions, repo, repo)
+
+	chiRouter.Put("/api/v1/teams/{id}/labels/{labelId}", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(labeledithandler.NewHandler(labelEditExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	labelRemoveExec := labelremovehandler.NewExecutor(permissions, repo, repo)
+
+	chiRouter.Delete("/api/v1/teams/{id}/labels/{labelId}", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(labelremovehandler.NewHandler(labelRemoveExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	memberListExec := teammemberlisthandler.NewExecutor(repo, permissions)
+
+	chiRouter.Get("/api/v1/teams/{id}/members", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(teammemberlisthandler.NewHandler(memberListExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	memberEditExec := teammemberedithandler.NewExecutor(permissions, repo, repo)
+
+	chiRouter.Put("/api/v1/teams/{id}/members/{userId}", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(teammemberedithandler.NewHandler(memberEditExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	memberRemoveExec := teammemberremovehandler.NewExecutor(permissions, repo, repo)
+
+	chiRouter.Delete("/api/v1/teams/{id}/members/{userId}", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(teammemberremovehandler.NewHandler(memberRemoveExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	memberLeaveExec := teammemberleavehandler.NewExecutor(repo, repo, repo)
+
+	chiRouter.Post("/api/v1/teams/{id}/members/leave", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(teammemberleavehandler.NewHandler(memberLeaveExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	memberTransferExec := teammembertransferhandler.NewExecutor(permissions, repo, repo)
+
+	chiRouter.Post("/api/v1/teams/{id}/members/transfer-ownership", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(teammembertransferhandler.NewHandler(memberTransferExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	taskCreateExec := taskcreatehandler.NewExecutor(repo, permissions, repo, repo, repo)
 
 	chiRouter.Post("/api/v1/tasks", func(w http.ResponseWriter, r *http.Request) {
 		h := http.HandlerFunc(taskcreatehandler.NewHandler(taskCreateExec).Handle)
@@ -168,7 +409,7 @@ func (a *app) Register() error {
 		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
 	})
 
-	taskListExec := tasklisthandler.NewExecutor(repo, repo, redisRepo, redisRepo)
+	taskListExec := tasklisthandler.NewExecutor(repo, permissions, redisRepo, redisRepo)
 
 	chiRouter.Get("/api/v1/tasks", func(w http.ResponseWriter, r *http.Request) {
 		h := http.HandlerFunc(tasklisthandler.NewHandler(taskListExec).Handle)
@@ -176,7 +417,55 @@ func (a *app) Register() error {
 		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
 	})
 
-	taskEditExec := taskedithandler.NewExecutor(repo, repo, repo, repo, redisRepo)
+	taskInboxExec := taskinboxhandler.NewExecutor(repo)
+
+	chiRouter.Get("/api/v1/me/tasks", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(taskinboxhandler.NewHandler(taskInboxExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	commentMentionsExec := commentmentionshandler.NewExecutor(repo)
+
+	chiRouter.Get("/api/v1/me/mentions", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(commentmentionshandler.NewHandler(commentMentionsExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	notificationListExec := notificationlisthandler.NewExecutor(repo)
+
+	chiRouter.Get("/api/v1/me/notifications", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(notificationlisthandler.NewHandler(notificationListExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	notificationReadAllExec := notificationreadallhandler.NewExecutor(repo)
+
+	chiRouter.Post("/api/v1/me/notifications/read", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(notificationreadallhandler.NewHandler(notificationReadAllExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	notificationReadExec := notificationreadhandler.NewExecutor(repo)
+
+	chiRouter.Post("/api/v1/me/notifications/{notificationId}/read", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(notificationreadhandler.NewHandler(notificationReadExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	taskGetExec := taskgethandler.NewExecutor(repo, permissions, repo, repo, repo, repo, repo, repo, repo, repo, repo, renderer)
+
+	chiRouter.Get("/api/v1/tasks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(taskgethandler.NewHandler(taskGetExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	taskEditExec := taskedithandler.NewExecutor(repo, repo, permissions, repo, repo, repo, redisRepo, repo, repo)
 
 	chiRouter.Put("/api/v1/tasks/{id}", func(w http.ResponseWriter, r *http.Request) {
 		h := http.HandlerFunc(taskedithandler.NewHandler(taskEditExec).Handle)
@@ -184,15 +473,59 @@ func (a *app) Register() error {
 		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
 	})
 
-	taskHistoryListExec := taskhistorylisthandler.NewExecutor(repo, repo)
+	chiRouter.Patch("/api/v1/tasks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(taskedithandler.NewPatchHandler(taskEditExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	taskArchiveExec := taskarchivehandler.NewExecutor(repo, permissions, repo, redisRepo)
+
+	chiRouter.Post("/api/v1/tasks/{id}/archive", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(taskarchivehandler.NewHandler(taskArchiveExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	taskRestoreExec := taskrestorehandler.NewExecutor(repo, permissions, repo, redisRepo)
+
+	chiRouter.Post("/api/v1/tasks/{id}/restore", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(taskrestorehandler.NewHandler(taskRestoreExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	taskPurgeExec := taskpurgehandler.NewExecutor(repo, permissions, repo, repo, blobs)
+
+	chiRouter.Delete("/api/v1/tasks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(taskpurgehandler.NewHandler(taskPurgeExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	taskHistoryListExec := taskhistorylisthandler.NewExecutor(repo, repo, permissions)
+	taskHistoryAsOfExec := taskhistoryasofhandler.NewExecutor(repo, repo, permissions)
 
 	chiRouter.Get("/api/v1/tasks/{id}/history", func(w http.ResponseWriter, r *http.Request) {
 		h := http.HandlerFunc(taskhistorylisthandler.NewHandler(taskHistoryListExec).Handle)
 
+		// С as_of вместо списка изменений отдаётся задача на этот момент.
+		if r.URL.Query().Has("as_of") {
+			h = taskhistoryasofhandler.NewHandler(taskHistoryAsOfExec).Handle
+		}
+
 		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
 	})
 
-	commentCreateExec := commentcreatehandler.NewExecutor(repo, repo)
+	taskHistoryRevertExec := taskhistoryreverthandler.NewExecutor(repo, taskEditExec)
+
+	chiRouter.Post("/api/v1/tasks/{id}/history/{historyId}/revert", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(taskhistoryreverthandler.NewHandler(taskHistoryRevertExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	commentCreateExec := commentcreatehandler.NewExecutor(repo, repo, permissions, repo, repo, repo, repo)
 
 	chiRouter.Post("/api/v1/tasks/{id}/comments", func(w http.ResponseWriter, r *http.Request) {
 		h := http.HandlerFunc(commentcreatehandler.NewHandler(commentCreateExec).Handle)
@@ -200,7 +533,7 @@ func (a *app) Register() error {
 		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
 	})
 
-	commentListExec := commentlisthandler.NewExecutor(repo)
+	commentListExec := commentlisthandler.NewExecutor(repo, repo, permissions, renderer)
 
 	chiRouter.Get("/api/v1/tasks/{id}/comments", func(w http.ResponseWriter, r *http.Request) {
 		h := http.HandlerFunc(commentlisthandler.NewHandler(commentListExec).Handle)
@@ -208,15 +541,163 @@ func (a *app) Register() error {
 		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
 	})
 
+	commentEditExec := commentedithandler.NewExecutor(repo, repo, repo, permissions, repo)
+
+	chiRouter.Put("/api/v1/tasks/{id}/comments/{commentId}", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(commentedithandler.NewHandler(commentEditExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	commentRemoveExec := commentremovehandler.NewExecutor(repo, repo, repo, permissions)
+
+	chiRouter.Delete("/api/v1/tasks/{id}/comments/{commentId}", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(commentremovehandler.NewHandler(commentRemoveExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	attachmentUploadExec := attachmentuploadhandler.NewExecutor(repo, repo, permissions, blobs, repo, envs.AttachmentMaxSize)
+
+	chiRouter.Post("/api/v1/tasks/{id}/attachments", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(attachmentuploadhandler.NewHandler(attachmentUploadExec, envs.AttachmentMaxSize).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	chiRouter.Post("/api/v1/tasks/{id}/comments/{commentId}/attachments", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(attachmentuploadhandler.NewHandler(attachmentUploadExec, envs.AttachmentMaxSize).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	attachmentListExec := attachmentlisthandler.NewExecutor(repo, permissions, repo)
+
+	chiRouter.Get("/api/v1/tasks/{id}/attachments", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(attachmentlisthandler.NewHandler(attachmentListExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	attachmentDownloadExec := attachmentdownloadhandler.NewExecutor(repo, permissions, repo, blobs)
+
+	chiRouter.Get("/api/v1/tasks/{id}/attachments/{attachmentId}", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(attachmentdownloadhandler.NewHandler(attachmentDownloadExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	attachmentRemoveExec := attachmentremovehandler.NewExecutor(repo, permissions, repo, repo, blobs)
+
+	chiRouter.Delete("/api/v1/tasks/{id}/attachments/{attachmentId}", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(attachmentremovehandler.NewHandler(attachmentRemoveExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	taskLabelAttachExec := tasklabelattachhandler.NewExecutor(repo, permissions, repo, repo)
+
+	chiRouter.Put("/api/v1/tasks/{id}/labels/{labelId}", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(tasklabelattachhandler.NewHandler(taskLabelAttachExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	taskLabelDetachExec := tasklabeldetachhandler.NewExecutor(repo, permissions, repo, repo)
+
+	chiRouter.Delete("/api/v1/tasks/{id}/labels/{labelId}", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(tasklabeldetachhandler.NewHandler(taskLabelDetachExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	taskSubtaskAddExec := tasksubtaskaddhandler.NewExecutor(repo, permissions, repo)
+
+	chiRouter.Put("/api/v1/tasks/{id}/subtasks/{subtaskId}", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(tasksubtaskaddhandler.NewHandler(taskSubtaskAddExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	taskSubtaskRemoveExec := tasksubtaskremovehandler.NewExecutor(repo, permissions, repo)
+
+	chiRouter.Delete("/api/v1/tasks/{id}/subtasks/{subtaskId}", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(tasksubtaskremovehandler.NewHandler(taskSubtaskRemoveExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	taskBlockerAddExec := taskblockeraddhandler.NewExecutor(repo, permissions, repo)
+
+	chiRouter.Put("/api/v1/tasks/{id}/blockers/{blockerId}", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(taskblockeraddhandler.NewHandler(taskBlockerAddExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	taskBlockerRemoveExec := taskblockerremovehandler.NewExecutor(repo, permissions, repo)
+
+	chiRouter.Delete("/api/v1/tasks/{id}/blockers/{blockerId}", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(taskblockerremovehandler.NewHandler(taskBlockerRemoveExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	taskWatchExec := taskwatchhandler.NewExecutor(repo, permissions, repo)
+
+	chiRouter.Post("/api/v1/tasks/{id}/watch", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(taskwatchhandler.NewHandler(taskWatchExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	taskUnwatchExec := taskunwatchhandler.NewExecutor(repo)
+
+	chiRouter.Post("/api/v1/tasks/{id}/unwatch", func(w http.ResponseWriter, r *http.Request) {
+		h := http.HandlerFunc(taskunwatchhandler.NewHandler(taskUnwatchExec).Handle)
+
+		utils.ChainMiddlewares(h, rateLimitingMiddleware, userGetterMiddleware, metricsMiddleware).ServeHTTP(w, r)
+	})
+
+	a.overdueJob = taskoverdue.NewJob(repo, taskoverdue.NewNotifier(repo, repo), envs.OverdueCheckInterval)
+
 	a.server.Handler = chiRouter
 	a.server.Addr = ":" + strconv.Itoa(envs.Port)
 
 	return nil
 }
 
-func (a *app) Resolve(context.Context) error {
+// Для локального запуска ключ генерируется сам, в проде каталог с ключами монтируется снаружи.
+func loadOrGenerateJwtKeys(dir, activeKeyId string) (*session.KeySet, error) {
+	keys, generated, err := session.LoadOrGenerateKeySet(dir, activeKeyId, time.Now().UTC().Format("20060102150405"))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to load jwt keys: %w", err)
+	}
+
+	if generated {
+		log.Printf("no jwt keys found in %q, generated key %s", dir, keys.SigningKey().Id)
+	}
+
+	return keys, nil
+}
+
+func newAttachmentStorage(envs utils.Envs) (storage.Storage, error) {
+	switch envs.AttachmentsStorage {
+	case "", "local":
+		return storage.NewLocal(envs.AttachmentsDir)
+	case "s3":
+		return storage.NewS3(envs.S3, &http.Client{Timeout: s3Timeout}), nil
+	default:
+		return nil, fmt.Errorf("unknown ATTACHMENTS_STORAGE %q", envs.AttachmentsStorage)
+	}
+}
+
+func (a *app) Resolve(ctx context.Context) error {
 	errChan := make(chan error)
 
+	go a.overdueJob.Run(ctx)
+
 	go func() {
 		log.Printf("server started on address: %s", a.server.Addr)
 
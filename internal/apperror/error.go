@@ -0,0 +1,144 @@
+package apperror
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+)
+
+// Kind определяет, как ошибка видна клиенту: по нему выбирается HTTP-статус и код в ответе.
+type Kind string
+
+const (
+	KindValidation   Kind = "validation"
+	KindUnauthorized Kind = "unauthorized"
+	KindForbidden    Kind = "forbidden"
+	KindNotFound     Kind = "not_found"
+	KindConflict     Kind = "conflict"
+	KindPrecondition Kind = "precondition_failed"
+	KindTooLarge     Kind = "too_large"
+	KindUnsupported  Kind = "unsupported_media_type"
+	KindRateLimited  Kind = "rate_limited"
+	KindUnavailable  Kind = "unavailable"
+	KindInternal     Kind = "internal"
+)
+
+type Error struct {
+	Kind    Kind
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+// FieldError описывает ошибку валидации конкретного поля запроса.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is позволяет проверять вид ошибки: errors.Is(err, apperror.ErrNotFound).
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+
+	return ok && t.Message == "" && t.Kind == e.Kind
+}
+
+var (
+	ErrValidation   = &Error{Kind: KindValidation}
+	ErrUnauthorized = &Error{Kind: KindUnauthorized}
+	ErrForbidden    = &Error{Kind: KindForbidden}
+	ErrNotFound     = &Error{Kind: KindNotFound}
+	ErrConflict     = &Error{Kind: KindConflict}
+	ErrPrecondition = &Error{Kind: KindPrecondition}
+	ErrTooLarge     = &Error{Kind: KindTooLarge}
+	ErrUnsupported  = &Error{Kind: KindUnsupported}
+	ErrRateLimited  = &Error{Kind: KindRateLimited}
+	ErrUnavailable  = &Error{Kind: KindUnavailable}
+)
+
+func Validation(message string) error {
+	return &Error{Kind: KindValidation, Message: message}
+}
+
+// InvalidFields возвращает ошибку валидации с подробностями по каждому полю.
+func InvalidFields(fields ...FieldError) error {
+	return &Error{Kind: KindValidation, Message: "validation failed", Fields: fields}
+}
+
+func InvalidField(field, message string) error {
+	return InvalidFields(FieldError{Field: field, Message: message})
+}
+
+func Unauthorized(message string) error {
+	return &Error{Kind: KindUnauthorized, Message: message}
+}
+
+func Forbidden(message string) error {
+	return &Error{Kind: KindForbidden, Message: message}
+}
+
+func NotFound(message string) error {
+	return &Error{Kind: KindNotFound, Message: message}
+}
+
+func Conflict(message string) error {
+	return &Error{Kind: KindConflict, Message: message}
+}
+
+// PreconditionFailed — объект изменился после того, как клиент его прочитал (If-Match не совпал).
+func PreconditionFailed(message string) error {
+	return &Error{Kind: KindPrecondition, Message: message}
+}
+
+func TooLarge(message string) error {
+	return &Error{Kind: KindTooLarge, Message: message}
+}
+
+// UnsupportedMediaType — тип содержимого запроса не принимается.
+func UnsupportedMediaType(message string) error {
+	return &Error{Kind: KindUnsupported, Message: message}
+}
+
+func RateLimited(message string) error {
+	return &Error{Kind: KindRateLimited, Message: message}
+}
+
+func Unavailable(message string, err error) error {
+	return &Error{Kind: KindUnavailable, Message: message, Err: err}
+}
+
+// KindOf возвращает вид ошибки. Ошибки без вида считаются внутренними,
+// кроме обрывов соединения и таймаутов — их клиент может повторить.
+func KindOf(err error) Kind {
+	var appErr *Error
+
+	if errors.As(err, &appErr) {
+		return appErr.Kind
+	}
+
+	if isUnavailable(err) {
+		return KindUnavailable
+	}
+
+	return KindInternal
+}
+
+func isUnavailable(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,80 @@
+package apperror
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Kind
+	}{
+		{"typed", NotFound("task not found"), KindNotFound},
+		{"wrapped typed", fmt.Errorf("edit: %w", Forbidden("forbidden")), KindForbidden},
+		{"bad connection", fmt.Errorf("query: %w", driver.ErrBadConn), KindUnavailable},
+		{"deadline", context.DeadlineExceeded, KindUnavailable},
+		{"plain", errors.New("boom"), KindInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, KindOf(tt.err))
+		})
+	}
+}
+
+func TestError_Is(t *testing.T) {
+	err := fmt.Errorf("wrap: %w", Conflict("already a member"))
+
+	assert.True(t, errors.Is(err, ErrConflict))
+	assert.False(t, errors.Is(err, ErrNotFound))
+	assert.EqualError(t, err, "wrap: already a member")
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   Kind
+		wantMsg    string
+		retryAfter bool
+	}{
+		{"validation", Validation("invalid team id"), http.StatusBadRequest, KindValidation, "invalid team id", false},
+		{"forbidden", Forbidden("forbidden"), http.StatusForbidden, KindForbidden, "forbidden", false},
+		{"not found", NotFound("task not found"), http.StatusNotFound, KindNotFound, "task not found", false},
+		{"conflict", Conflict("already a member"), http.StatusConflict, KindConflict, "already a member", false},
+		{"precondition", PreconditionFailed("task has been modified"), http.StatusPreconditionFailed, KindPrecondition, "task has been modified", false},
+		{"too large", TooLarge("file is too large"), http.StatusRequestEntityTooLarge, KindTooLarge, "file is too large", false},
+		{"unsupported", UnsupportedMediaType("file type is not allowed"), http.StatusUnsupportedMediaType, KindUnsupported, "file type is not allowed", false},
+		{"unavailable", fmt.Errorf("dial: %w", driver.ErrBadConn), http.StatusServiceUnavailable, KindUnavailable, "service unavailable", true},
+		{"internal", errors.New("secret sql details"), http.StatusInternalServerError, KindInternal, "internal error", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			Write(rec, tt.err)
+
+			var resp envelope
+
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantCode, resp.Error.Code)
+			assert.Equal(t, tt.wantMsg, resp.Error.Message)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			assert.Equal(t, tt.retryAfter, rec.Header().Get("Retry-After") != "")
+		})
+	}
+}
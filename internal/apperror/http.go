@@ -0,0 +1,80 @@
+package apperror
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+)
+
+const retryAfterSeconds = "5"
+
+type envelope struct {
+	Error body `json:"error"`
+}
+
+type body struct {
+	Code    Kind         `json:"code"`
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+func Status(kind Kind) int {
+	switch kind {
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindPrecondition:
+		return http.StatusPreconditionFailed
+	case KindTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case KindUnsupported:
+		return http.StatusUnsupportedMediaType
+	case KindRateLimited:
+		return http.StatusTooManyRequests
+	case KindUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Write отдаёт ошибку в едином формате {"error": {"code": ..., "message": ...}}.
+// Текст ошибок без вида (БД, сеть, сериализация) наружу не отдаём, только пишем в лог.
+func Write(w http.ResponseWriter, err error) {
+	kind := KindOf(err)
+	message := err.Error()
+
+	var fields []FieldError
+	var appErr *Error
+
+	if errors.As(err, &appErr) {
+		fields = appErr.Fields
+	} else {
+		log.Printf("%s error: %v", kind, err)
+
+		message = "internal error"
+
+		if kind == KindUnavailable {
+			message = "service unavailable"
+		}
+	}
+
+	if kind == KindUnavailable {
+		w.Header().Set("Retry-After", retryAfterSeconds)
+	}
+
+	respBody, _ := json.Marshal(envelope{Error: body{Code: kind, Message: message, Fields: fields}})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(Status(kind))
+	w.Write(respBody)
+}
@@ -0,0 +1,228 @@
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	headingPattern  = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ ]+(.*?))?[ ]*$`)
+	rulePattern     = regexp.MustCompile(`^ {0,3}(?:(?:\*[ ]*){3,}|(?:-[ ]*){3,}|(?:_[ ]*){3,})$`)
+	fencePattern    = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})[ ]*([^`]*?)[ ]*$")
+	quotePattern    = regexp.MustCompile(`^ {0,3}>[ ]?`)
+	listItemPattern = regexp.MustCompile(`^( {0,3})([-*+]|[0-9]{1,9}[.)])(?:[ ]+|$)`)
+	languagePattern = regexp.MustCompile(`^[A-Za-z0-9_+-]+$`)
+)
+
+// blocks выводит строки как последовательность блоков. В tight-режиме (пункт списка) абзацы не оборачиваются в <p>.
+func (r *renderer) blocks(lines []string, depth int, tight bool) string {
+	var b strings.Builder
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+		case fencePattern.MatchString(line):
+			i += r.codeBlock(&b, lines[i:])
+		case headingPattern.MatchString(line):
+			m := headingPattern.FindStringSubmatch(line)
+			tag := "h" + strconv.Itoa(len(m[1]))
+
+			b.WriteString("<" + tag + ">" + r.inline(m[2], depth, false) + "</" + tag + ">\n")
+			i++
+		case rulePattern.MatchString(line):
+			b.WriteString("<hr>\n")
+			i++
+		case r.nests(depth) && quotePattern.MatchString(line):
+			i += r.quote(&b, lines[i:], depth)
+		case r.nests(depth) && listItemPattern.MatchString(line):
+			i += r.list(&b, lines[i:], depth)
+		default:
+			i += r.paragraph(&b, lines[i:], depth, tight)
+		}
+	}
+
+	return b.String()
+}
+
+func (r *renderer) nests(depth int) bool {
+	return depth < maxDepth
+}
+
+// startsBlock — строка начинает новый блок и прерывает абзац.
+func (r *renderer) startsBlock(line string, depth int) bool {
+	if fencePattern.MatchString(line) || headingPattern.MatchString(line) || rulePattern.MatchString(line) {
+		return true
+	}
+
+	return r.nests(depth) && (quotePattern.MatchString(line) || listItemPattern.MatchString(line))
+}
+
+func (r *renderer) paragraph(b *strings.Builder, lines []string, depth int, tight bool) int {
+	n := 1
+
+	for n < len(lines) && strings.TrimSpace(lines[n]) != "" && !r.startsBlock(lines[n], depth) {
+		n++
+	}
+
+	text := make([]string, 0, n)
+
+	for _, line := range lines[:n] {
+		text = append(text, strings.TrimSpace(line))
+	}
+
+	content := r.inline(strings.Join(text, "\n"), depth, false)
+
+	if tight {
+		b.WriteString(content + "\n")
+	} else {
+		b.WriteString("<p>" + content + "</p>\n")
+	}
+
+	return n
+}
+
+// codeBlock выводит блок кода до закрывающей ограды или до конца текста.
+func (r *renderer) codeBlock(b *strings.Builder, lines []string) int {
+	m := fencePattern.FindStringSubmatch(lines[0])
+	fence := m[1]
+
+	n := 1
+	closed := false
+
+	for ; n < len(lines); n++ {
+		t := strings.TrimSpace(lines[n])
+
+		if len(t) >= len(fence) && strings.Trim(t, fence[:1]) == "" {
+			closed = true
+
+			break
+		}
+	}
+
+	class := ""
+
+	if lang := strings.Fields(m[2]); len(lang) > 0 && languagePattern.MatchString(lang[0]) {
+		class = ` class="language-` + lang[0] + `"`
+	}
+
+	b.WriteString("<pre><code" + class + ">")
+
+	for _, line := range lines[1:n] {
+		b.WriteString(escape(line) + "\n")
+	}
+
+	b.WriteString("</code></pre>\n")
+
+	if closed {
+		return n + 1
+	}
+
+	return n
+}
+
+func (r *renderer) quote(b *strings.Builder, lines []string, depth int) int {
+	n := 0
+	inner := []string{}
+
+	for n < len(lines) && quotePattern.MatchString(lines[n]) {
+		inner = append(inner, quotePattern.ReplaceAllString(lines[n], ""))
+		n++
+	}
+
+	b.WriteString("<blockquote>\n" + r.blocks(inner, depth+1, false) + "</blockquote>\n")
+
+	return n
+}
+
+// list выводит подряд идущие пункты одного вида. Строки с отступом не меньше ширины маркера
+// относятся к пункту, поэтому вложенные списки и блоки кода пишутся с таким отступом.
+func (r *renderer) list(b *strings.Builder, lines []string, depth int) int {
+	first := listItemPattern.FindStringSubmatch(lines[0])
+	ordered := isOrdered(first[2])
+
+	tag := "ul"
+	open := "<ul>\n"
+
+	if ordered {
+		tag = "ol"
+		open = "<ol>\n"
+
+		if start, _ := strconv.Atoi(first[2][:len(first[2])-1]); start != 1 {
+			open = `<ol start="` + strconv.Itoa(start) + `">` + "\n"
+		}
+	}
+
+	b.WriteString(open)
+
+	n := 0
+
+	for n < len(lines) {
+		m := listItemPattern.FindStringSubmatch(lines[n])
+
+		if m == nil || isOrdered(m[2]) != ordered {
+			break
+		}
+
+		width := len(m[0])
+		body := []string{lines[n][width:]}
+		n++
+
+		for n < len(lines) {
+			line := lines[n]
+
+			if strings.TrimSpace(line) == "" {
+				next := nextNonBlank(lines, n)
+
+				if next < 0 || indent(lines[next]) < width {
+					break
+				}
+
+				body = append(body, "")
+				n++
+
+				continue
+			}
+
+			if indent(line) < width {
+				break
+			}
+
+			body = append(body, line[width:])
+			n++
+		}
+
+		b.WriteString("<li>" + strings.TrimSuffix(r.blocks(body, depth+1, true), "\n") + "</li>\n")
+
+		// Пустые строки между пунктами не прерывают список.
+		if next := nextNonBlank(lines, n); next > n && listItemPattern.MatchString(lines[next]) {
+			n = next
+		}
+	}
+
+	b.WriteString("</" + tag + ">\n")
+
+	return n
+}
+
+func isOrdered(marker string) bool {
+	return marker[0] >= '0' && marker[0] <= '9'
+}
+
+func indent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// nextNonBlank возвращает индекс первой непустой строки начиная с from или -1.
+func nextNonBlank(lines []string, from int) int {
+	for i := from; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != "" {
+			return i
+		}
+	}
+
+	return -1
+}
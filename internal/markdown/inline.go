@@ -0,0 +1,390 @@
+package markdown
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const punctuation = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+// inline выводит текст абзаца или заголовка. Внутри ссылки (inLink) другие ссылки не создаются.
+func (r *renderer) inline(s string, depth int, inLink bool) string {
+	var b strings.Builder
+
+	plain := 0
+	closers := closerCache{}
+
+	flush := func(i int) {
+		b.WriteString(escape(s[plain:i]))
+	}
+
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte(punctuation, s[i+1]) >= 0:
+			flush(i)
+			b.WriteString(escape(s[i+1 : i+2]))
+			i += 2
+			plain = i
+		case c == '\n':
+			flush(i)
+			b.WriteString("<br>\n")
+			i++
+			plain = i
+		case c == '`':
+			n := runLength(s, i)
+			end := closingBackticks(s, i+n, n)
+
+			if end < 0 {
+				i += n
+
+				continue
+			}
+
+			flush(i)
+			b.WriteString("<code>" + escape(codeSpan(s[i+n:end])) + "</code>")
+			i = end + n
+			plain = i
+		case (c == '*' || c == '_') && r.nests(depth):
+			html, next := r.emphasis(s, i, depth, inLink, closers)
+
+			if next < 0 {
+				i += runLength(s, i)
+
+				continue
+			}
+
+			flush(i)
+			b.WriteString(html)
+			i = next
+			plain = i
+		case c == '[' && !inLink:
+			html, next := r.link(s, i, depth)
+
+			if next < 0 {
+				i++
+
+				continue
+			}
+
+			flush(i)
+			b.WriteString(html)
+			i = next
+			plain = i
+		case c == '<' && !inLink:
+			end := strings.IndexByte(s[i:], '>')
+
+			if end < 0 || !isAutolink(s[i+1:i+end]) {
+				i++
+
+				continue
+			}
+
+			flush(i)
+			b.WriteString(linkHTML(s[i+1:i+end], escape(s[i+1:i+end])))
+			i += end + 1
+			plain = i
+		case c == 'h' && !inLink && !isWordByte(s, i-1) && hasURLPrefix(s[i:]):
+			raw := bareURL(s[i:])
+
+			if !isSafeURL(raw) {
+				i += len(raw)
+
+				continue
+			}
+
+			flush(i)
+			b.WriteString(linkHTML(raw, escape(raw)))
+			i += len(raw)
+			plain = i
+		case c == '#' && !inLink && !isWordByte(s, i-1):
+			id, n := taskNumber(s[i+1:])
+
+			if n == 0 {
+				i++
+
+				continue
+			}
+
+			ref, ok := r.taskRef(id)
+
+			if !ok {
+				i += n + 1
+
+				continue
+			}
+
+			flush(i)
+			b.WriteString(taskRefHTML(ref))
+			i += n + 1
+			plain = i
+		default:
+			i++
+		}
+	}
+
+	flush(len(s))
+
+	return b.String()
+}
+
+// emphasis разбирает **жирный** и *курсив* (или с _), начиная с s[i]. Если пары нет, next равен -1.
+func (r *renderer) emphasis(s string, i int, depth int, inLink bool, closers closerCache) (string, int) {
+	c := s[i]
+
+	// _ внутри слова (snake_case) выделением не считается.
+	if c == '_' && isWordByte(s, i-1) {
+		return "", -1
+	}
+
+	sizes := []int{1}
+
+	if runLength(s, i) >= 2 {
+		sizes = []int{2, 1}
+	}
+
+	for _, size := range sizes {
+		from := i + size
+
+		if from >= len(s) || s[from] == ' ' || s[from] == '\n' {
+			continue
+		}
+
+		end := closers.find(s, from, c, size)
+
+		if end < 0 {
+			continue
+		}
+
+		tag := "em"
+
+		if size == 2 {
+			tag = "strong"
+		}
+
+		return "<" + tag + ">" + r.inline(s[from:end], depth+1, inLink) + "</" + tag + ">", end + size
+	}
+
+	return "", -1
+}
+
+type closerKey struct {
+	c    byte
+	size int
+}
+
+type closerPos struct {
+	from int
+	end  int
+}
+
+// closerCache запоминает последний найденный закрывающий разделитель каждого вида в строке.
+// Годится ли позиция в закрывающие, от открывающего не зависит, а открывающие идут слева направо,
+// поэтому ответ верен для всех следующих открывающих до него и строка не просматривается заново для каждого.
+type closerCache map[closerKey]closerPos
+
+func (cc closerCache) find(s string, from int, c byte, size int) int {
+	key := closerKey{c: c, size: size}
+
+	if p, ok := cc[key]; ok && from >= p.from && (p.end < 0 || p.end > from) {
+		return p.end
+	}
+
+	end := closingDelimiter(s, from, c, size)
+	cc[key] = closerPos{from: from, end: end}
+
+	return end
+}
+
+// closingDelimiter ищет закрывающий разделитель из size символов c после непробельного символа.
+func closingDelimiter(s string, from int, c byte, size int) int {
+	delim := strings.Repeat(string(c), size)
+
+	for k := from + 1; k < len(s); k++ {
+		j := strings.Index(s[k:], delim)
+
+		if j < 0 {
+			return -1
+		}
+
+		k += j
+
+		if s[k-1] == ' ' || s[k-1] == '\n' {
+			continue
+		}
+
+		// Одиночный разделитель не может быть частью двойного.
+		if size == 1 && (s[k-1] == c || (k+1 < len(s) && s[k+1] == c)) {
+			continue
+		}
+
+		if c == '_' && isWordByte(s, k+size) {
+			continue
+		}
+
+		return k
+	}
+
+	return -1
+}
+
+// link разбирает [текст](адрес). Ссылка с небезопасным адресом выводится одним текстом.
+func (r *renderer) link(s string, i int, depth int) (string, int) {
+	closeText := strings.IndexByte(s[i:], ']')
+
+	if closeText < 0 || i+closeText+1 >= len(s) || s[i+closeText+1] != '(' {
+		return "", -1
+	}
+
+	closeText += i
+
+	closeURL := strings.IndexByte(s[closeText:], ')')
+
+	if closeURL < 0 {
+		return "", -1
+	}
+
+	closeURL += closeText
+
+	text := r.inline(s[i+1:closeText], depth+1, true)
+
+	// Необязательный заголовок ссылки после адреса не выводится.
+	raw := ""
+
+	if fields := strings.Fields(s[closeText+2 : closeURL]); len(fields) > 0 {
+		raw = fields[0]
+	}
+
+	if !isSafeURL(raw) {
+		return text, closeURL + 1
+	}
+
+	return linkHTML(raw, text), closeURL + 1
+}
+
+func linkHTML(rawURL, text string) string {
+	return `<a href="` + escape(rawURL) + `" rel="nofollow noopener noreferrer">` + text + `</a>`
+}
+
+// isSafeURL пропускает http(s), mailto и относительные адреса; javascript:, data: и прочие схемы отбрасываются.
+func isSafeURL(raw string) bool {
+	if raw == "" || strings.ContainsAny(raw, " \"'<>`\\") {
+		return false
+	}
+
+	for _, c := range raw {
+		if c < 0x20 || c == 0x7f {
+			return false
+		}
+	}
+
+	u, err := url.Parse(raw)
+
+	if err != nil {
+		return false
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return u.Host != ""
+	case "mailto":
+		return u.Opaque != ""
+	case "":
+		// //host — адрес на другом сайте без схемы.
+		return !strings.HasPrefix(raw, "//")
+	}
+
+	return false
+}
+
+func isAutolink(raw string) bool {
+	u, err := url.Parse(raw)
+
+	return err == nil && u.Scheme != "" && isSafeURL(raw)
+}
+
+func hasURLPrefix(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// bareURL возвращает адрес до пробела без завершающих знаков препинания.
+func bareURL(s string) string {
+	end := strings.IndexAny(s, " \n<")
+
+	if end < 0 {
+		end = len(s)
+	}
+
+	return strings.TrimRight(s[:end], ".,:;!?)'\"")
+}
+
+// taskNumber разбирает номер задачи в начале s. n равно длине номера или 0, если номера нет.
+func taskNumber(s string) (id int, n int) {
+	for n < len(s) && n < 9 && s[n] >= '0' && s[n] <= '9' {
+		n++
+	}
+
+	if n == 0 || s[0] == '0' || isWordByte(s, n) {
+		return 0, 0
+	}
+
+	id, _ = strconv.Atoi(s[:n])
+
+	return id, n
+}
+
+// isWordByte — s[i] часть слова: буква, цифра, _, & (начало сущности вроде &#35;) или не-ASCII символ.
+// За границами строки — нет.
+func isWordByte(s string, i int) bool {
+	if i < 0 || i >= len(s) {
+		return false
+	}
+
+	c := s[i]
+
+	return c == '_' || c == '&' || c >= 0x80 ||
+		(c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func runLength(s string, i int) int {
+	n := 1
+
+	for i+n < len(s) && s[i+n] == s[i] {
+		n++
+	}
+
+	return n
+}
+
+func closingBackticks(s string, from int, n int) int {
+	for k := from; k < len(s); {
+		j := strings.IndexByte(s[k:], '`')
+
+		if j < 0 {
+			return -1
+		}
+
+		k += j
+
+		if m := runLength(s, k); m != n {
+			k += m
+
+			continue
+		}
+
+		return k
+	}
+
+	return -1
+}
+
+// codeSpan убирает по одному пробелу с краёв, если они есть с обеих сторон: так в коде можно начать с `.
+func codeSpan(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+
+	if len(s) >= 2 && s[0] == ' ' && s[len(s)-1] == ' ' && strings.TrimSpace(s) != "" {
+		return s[1 : len(s)-1]
+	}
+
+	return s
+}
@@ -0,0 +1,83 @@
+// Package markdown превращает Markdown из описаний задач и комментариев в безопасный HTML.
+//
+// Поддерживается подмножество: абзацы, заголовки, цитаты, списки, блоки и фрагменты кода,
+// выделение, ссылки и ссылки на задачи вида #123. Сырой HTML из исходника не пропускается,
+// а всегда экранируется, поэтому результат можно вставлять в страницу как есть.
+package markdown
+
+import (
+	"html"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MaxTaskRefs — сколько разных задач раскрывается в одном тексте; остальные #N остаются текстом.
+const MaxTaskRefs = 50
+
+// Вложенность цитат, списков и выделения глубже этой выводится текстом.
+const maxDepth = 8
+
+// TaskRef — задача, на которую можно сослаться как #Id.
+type TaskRef struct {
+	Id    int
+	Title string
+}
+
+// TaskRefIds возвращает номера задач из ссылок #N в порядке появления, без повторов.
+// Ссылки внутри кода не учитываются.
+func TaskRefIds(src string) []int {
+	r := &renderer{collect: true}
+
+	r.blocks(splitLines(src), 0, false)
+
+	return r.ids
+}
+
+// Render возвращает HTML для src. #N становится ссылкой, только если задача есть в refs.
+func Render(src string, refs map[int]TaskRef) string {
+	r := &renderer{refs: refs}
+
+	return r.blocks(splitLines(src), 0, false)
+}
+
+type renderer struct {
+	refs map[int]TaskRef
+
+	// В режиме collect рендер только собирает номера задач.
+	collect bool
+	ids     []int
+}
+
+func (r *renderer) taskRef(id int) (TaskRef, bool) {
+	if r.collect {
+		if len(r.ids) < MaxTaskRefs && !slices.Contains(r.ids, id) {
+			r.ids = append(r.ids, id)
+		}
+
+		return TaskRef{}, false
+	}
+
+	ref, ok := r.refs[id]
+
+	return ref, ok
+}
+
+func taskRefHTML(ref TaskRef) string {
+	id := strconv.Itoa(ref.Id)
+
+	return `<a href="/api/v1/tasks/` + id + `" class="task-ref" data-task-id="` + id + `" title="` +
+		escape(ref.Title) + `">#` + id + `</a>`
+}
+
+func splitLines(src string) []string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\r", "\n")
+	src = strings.ReplaceAll(src, "\t", "    ")
+
+	return strings.Split(src, "\n")
+}
+
+func escape(s string) string {
+	return html.EscapeString(s)
+}
@@ -0,0 +1,181 @@
+package markdown
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	refs := map[int]TaskRef{
+		12: {Id: 12, Title: "Отчёт <за> март"},
+	}
+
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "paragraphs and line breaks",
+			src:  "первая строка\nвторая\n\nновый абзац",
+			want: "<p>первая строка<br>\nвторая</p>\n<p>новый абзац</p>\n",
+		},
+		{
+			name: "raw html is escaped",
+			src:  `<script>alert("x")</script> <img src=x onerror=alert(1)>`,
+			want: "<p>&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &lt;img src=x onerror=alert(1)&gt;</p>\n",
+		},
+		{
+			name: "heading and rule",
+			src:  "## План работ\n---",
+			want: "<h2>План работ</h2>\n<hr>\n",
+		},
+		{
+			name: "emphasis and inline code",
+			src:  "**важно**, *срочно*, `a < b` и snake_case_name",
+			want: "<p><strong>важно</strong>, <em>срочно</em>, <code>a &lt; b</code> и snake_case_name</p>\n",
+		},
+		{
+			name: "unclosed emphasis stays text",
+			src:  "2 * 3 = 6, **",
+			want: "<p>2 * 3 = 6, **</p>\n",
+		},
+		{
+			name: "escaped characters",
+			src:  `\*не курсив\* и \#12`,
+			want: "<p>*не курсив* и #12</p>\n",
+		},
+		{
+			name: "fenced code block",
+			src:  "```go\nif a < b {\n```\nпосле",
+			want: "<pre><code class=\"language-go\">if a &lt; b {\n</code></pre>\n<p>после</p>\n",
+		},
+		{
+			name: "unsafe language is dropped",
+			src:  "```\"><script>\nx\n```",
+			want: "<pre><code>x\n</code></pre>\n",
+		},
+		{
+			name: "quote",
+			src:  "> цитата\n> **жирная**",
+			want: "<blockquote>\n<p>цитата<br>\n<strong>жирная</strong></p>\n</blockquote>\n",
+		},
+		{
+			name: "nested lists",
+			src:  "- первый\n  - вложенный\n- второй\n\n3. три\n4. четыре",
+			want: "<ul>\n<li>первый\n<ul>\n<li>вложенный</li>\n</ul></li>\n<li>второй</li>\n</ul>\n" +
+				"<ol start=\"3\">\n<li>три</li>\n<li>четыре</li>\n</ol>\n",
+		},
+		{
+			name: "safe links",
+			src:  "[документ](https://example.com/a?b=1&c=2) и [задачи](/api/v1/tasks)",
+			want: `<p><a href="https://example.com/a?b=1&amp;c=2" rel="nofollow noopener noreferrer">документ</a>` +
+				` и <a href="/api/v1/tasks" rel="nofollow noopener noreferrer">задачи</a></p>` + "\n",
+		},
+		{
+			name: "unsafe links keep only text",
+			src:  "[клик](javascript:alert(1)) [ещё](JavaScript:x) [и](//evil.example) [data](data:text/html,x)",
+			want: "<p>клик) ещё и data</p>\n",
+		},
+		{
+			name: "autolinks",
+			src:  "см. https://example.com/path. и <mailto:dev@example.com>",
+			want: `<p>см. <a href="https://example.com/path" rel="nofollow noopener noreferrer">https://example.com/path</a>.` +
+				` и <a href="mailto:dev@example.com" rel="nofollow noopener noreferrer">mailto:dev@example.com</a></p>` + "\n",
+		},
+		{
+			name: "task refs",
+			src:  "блокирует #12, а #13 недоступна; не ссылки: a#12, #12b, `#12`",
+			want: `<p>блокирует <a href="/api/v1/tasks/12" class="task-ref" data-task-id="12" title="Отчёт &lt;за&gt; март">#12</a>,` +
+				" а #13 недоступна; не ссылки: a#12, #12b, <code>#12</code></p>\n",
+		},
+		{
+			name: "heading needs a space",
+			src:  "#12 в начале строки",
+			want: `<p><a href="/api/v1/tasks/12" class="task-ref" data-task-id="12" title="Отчёт &lt;за&gt; март">#12</a> в начале строки</p>` + "\n",
+		},
+		{
+			name: "empty source",
+			src:  "",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Render(tt.src, refs))
+		})
+	}
+}
+
+func TestRender_DeepNesting(t *testing.T) {
+	src := ""
+
+	for i := 0; i < 1000; i++ {
+		src += "> "
+	}
+
+	got := Render(src+"текст", nil)
+
+	assert.Contains(t, got, "&gt; &gt;")
+	assert.Contains(t, got, "текст")
+}
+
+// Непарные разделители не должны делать разбор квадратичным: без кэша такие строки рендерятся секунды.
+func TestRender_UnmatchedEmphasis(t *testing.T) {
+	for _, unit := range []string{"**a ", "*a ", "__a ", "_a "} {
+		t.Run(unit, func(t *testing.T) {
+			src := strings.Repeat(unit, 100000/len(unit))
+
+			started := time.Now()
+			got := Render(src, nil)
+
+			assert.Less(t, time.Since(started), time.Second)
+			assert.NotContains(t, got, "<em>")
+			assert.NotContains(t, got, "<strong>")
+		})
+	}
+}
+
+func TestTaskRefIds(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []int
+	}{
+		{
+			name: "in order without duplicates",
+			src:  "#5 зависит от #3 и снова #5",
+			want: []int{5, 3},
+		},
+		{
+			name: "code and link text are skipped",
+			src:  "`#1`\n```\n#2\n```\n[#3](/x) > #4\n\n> #6",
+			want: []int{4, 6},
+		},
+		{
+			name: "not refs",
+			src:  "#0 #01 a#7 #7a &#35; # 8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, TaskRefIds(tt.src))
+		})
+	}
+}
+
+func TestTaskRefIds_Limit(t *testing.T) {
+	src := ""
+
+	for i := 1; i <= MaxTaskRefs+10; i++ {
+		src += " #" + strconv.Itoa(i)
+	}
+
+	assert.Len(t, TaskRefIds(src), MaxTaskRefs)
+}
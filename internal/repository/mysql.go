@@ -3,10 +3,11 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"slices"
 	"strings"
 	"time"
 
+	"mkk-luna-test-task/internal/apperror"
 	"mkk-luna-test-task/internal/task"
 	"mkk-luna-test-task/internal/task/comment"
 	"mkk-luna-test-task/internal/task/history"
@@ -17,8 +18,8 @@ import (
 
 const (
 	queryInsertTaskComment = `
-		INSERT INTO task_comments (commenter_id, task_id, text, created_at)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO task_comments (commenter_id, task_id, parent_comment_id, text, created_at)
+		VALUES (?, ?, ?, ?, ?)
 	`
 
 	queryInviteMember = `
@@ -26,15 +27,6 @@ const (
 		VALUES (?, ?, ?)
 	`
 
-	queryCanUserInvite = `
-		SELECT EXISTS(
-			SELECT 1
-			FROM team_members
-			WHERE team_id = ? AND user_id = ? AND role IN ('owner', 'admin')
-			LIMIT 1
-		)
-	`
-
 	queryTeamInsert = `
 		INSERT INTO teams (name)
 		VALUES (?)
@@ -52,13 +44,6 @@ const (
 		WHERE tm.user_id = ?
 	`
 
-	queryIsPartOfTeamByTaskId = `
-		SELECT COUNT(*) 
-		FROM team_members tm
-		INNER JOIN tasks t ON tm.team_id = t.team_id
-		WHERE tm.user_id = ? AND t.id = ?
-	`
-
 	queryCheckUserIsInTeam = `
 		SELECT COUNT(*) FROM team_members WHERE user_id = ? AND team_id = ?
 	`
@@ -74,8 +59,14 @@ const (
 		WHERE username = ?
 	`
 
+	queryGetUserById = `
+		SELECT id, username, password_hashed
+		FROM users
+		WHERE id = ?
+	`
+
 	queryListTaskComments = `
-		SELECT id, created_at, commenter_id, task_id, text
+		SELECT ` + commentColumns + `
 		FROM task_comments
 		WHERE task_id = ? AND id > ?
 		ORDER BY id ASC
@@ -89,11 +80,15 @@ const (
 			title,
 			description,
 			creator_id,
-			assignee_id,
+			assignee_ids,
 			team_id,
 			changed_by,
-			changed_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			changed_at,
+			event,
+			due_at,
+			priority,
+			label
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	queryListHistory = `
@@ -104,10 +99,14 @@ const (
 			title,
 			description,
 			creator_id,
-			assignee_id,
+			assignee_ids,
 			team_id,
 			changed_by,
-			changed_at
+			changed_at,
+			event,
+			due_at,
+			priority,
+			label
 		FROM task_history
 		WHERE task_id = ? AND id > ?
 		ORDER BY id ASC
@@ -115,19 +114,45 @@ const (
 	`
 
 	queryInsertTask = `
-		INSERT INTO tasks (status, title, description, creator_id, assignee_id, team_id, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO tasks (status, title, description, creator_id, team_id, created_at, updated_at, due_at, priority)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	queryInsertTaskAssignee = `
+		INSERT INTO task_assignees (task_id, user_id)
+		VALUES (?, ?)
+	`
+
+	queryDeleteTaskAssignees = `
+		DELETE FROM task_assignees
+		WHERE task_id = ?
 	`
 
 	queryGetTaskById = `
-		SELECT id, status, title, description, creator_id, assignee_id, team_id, created_at
-		FROM tasks
-		WHERE id = ?
+		SELECT t.id, t.status, t.title, t.description, t.creator_id, ` + assigneeIdsColumn + `, t.team_id, t.created_at, t.updated_at, t.version, t.archived_at, t.due_at, t.priority
+		FROM tasks t
+		WHERE t.id = ?
 	`
 
-	queryEditTask = `
+	queryGetTaskByIdForUpdate = `
+		SELECT t.id, t.status, t.title, t.description, t.creator_id, ` + assigneeIdsColumn + `, t.team_id, t.created_at, t.updated_at, t.version, t.archived_at, t.due_at, t.priority
+		FROM tasks t
+		WHERE t.id = ?
+		FOR UPDATE
+	`
+
+	queryUpdateTask = `
 		UPDATE tasks
-		SET status = ?, title = ?, description = ?, assignee_id = ?
+		SET
+			-- Сброс уведомления о просрочке должен идти до присваивания due_at: MySQL применяет SET по порядку.
+			overdue_notified_at = IF(due_at <=> ?, overdue_notified_at, NULL),
+			status = ?,
+			title = ?,
+			description = ?,
+			due_at = ?,
+			priority = ?,
+			updated_at = ?,
+			version = version + 1
 		WHERE id = ?
 	`
 )
@@ -140,31 +165,55 @@ func NewMysql(db *sql.DB) *Mysql {
 	return &Mysql{db: db}
 }
 
+// CreateTaskComment сохраняет комментарий вместе с упоминаниями mentionIds.
 func (r *Mysql) CreateTaskComment(
 	ctx context.Context,
 	commenterId int,
 	taskId int,
+	parentCommentId *int,
 	text string,
+	mentionIds []int,
 	createdAt time.Time,
 ) (*comment.Model, error) {
-	result, err := r.db.ExecContext(ctx, queryInsertTaskComment, commenterId, taskId, text, createdAt)
+	tx, err := r.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := tx.ExecContext(ctx, queryInsertTaskComment, commenterId, taskId, parentCommentId, text, createdAt)
 
 	if err != nil {
+		tx.Rollback()
+
 		return nil, err
 	}
 
 	insertedId, err := result.LastInsertId()
 
 	if err != nil {
+		tx.Rollback()
+
+		return nil, err
+	}
+
+	if err := insertCommentMentions(ctx, tx, int(insertedId), mentionIds); err != nil {
+		tx.Rollback()
+
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
 	return &comment.Model{
-		Id:          int(insertedId),
-		CreatedAt:   createdAt,
-		CommenterId: commenterId,
-		TaskId:      taskId,
-		Text:        text,
+		Id:              int(insertedId),
+		CreatedAt:       createdAt,
+		CommenterId:     commenterId,
+		TaskId:          taskId,
+		Text:            text,
+		ParentCommentId: parentCommentId,
 	}, nil
 }
 
@@ -175,7 +224,7 @@ func (r *Mysql) ListTaskComments(
 	limit int,
 ) ([]comment.Model, error) {
 	if limit <= 0 {
-		return nil, fmt.Errorf("invalid limit")
+		return nil, apperror.Validation("invalid limit")
 	}
 
 	rows, err := r.db.QueryContext(ctx, queryListTaskComments, taskId, startFromId, limit)
@@ -189,13 +238,13 @@ func (r *Mysql) ListTaskComments(
 	var comments []comment.Model
 
 	for rows.Next() {
-		var c comment.Model
+		c, err := scanTaskComment(rows)
 
-		if err := rows.Scan(&c.Id, &c.CreatedAt, &c.CommenterId, &c.TaskId, &c.Text); err != nil {
+		if err != nil {
 			return nil, err
 		}
 
-		comments = append(comments, c)
+		comments = append(comments, *c)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -205,50 +254,6 @@ func (r *Mysql) ListTaskComments(
 	return comments, nil
 }
 
-func (r *Mysql) CreateHistory(
-	ctx context.Context,
-	t *task.Model,
-	changedBy int,
-	changedAt time.Time,
-) (*history.Model, error) {
-	result, err := r.db.ExecContext(
-		ctx,
-		queryInsertTaskHistory,
-		t.Id,
-		t.Status,
-		t.Title,
-		t.Description,
-		t.CreatorId,
-		t.AssigneeId,
-		t.TeamId,
-		changedBy,
-		changedAt,
-	)
-
-	if err != nil {
-		return nil, err
-	}
-
-	id, err := result.LastInsertId()
-
-	if err != nil {
-		return nil, err
-	}
-
-	return &history.Model{
-		Id:          int(id),
-		TaskId:      t.Id,
-		Status:      t.Status,
-		Title:       t.Title,
-		Description: t.Description,
-		CreatorId:   t.CreatorId,
-		AssigneeId:  t.AssigneeId,
-		TeamId:      t.TeamId,
-		ChangedBy:   changedBy,
-		ChangedAt:   changedAt,
-	}, nil
-}
-
 func (r *Mysql) ListHistory(
 	ctx context.Context,
 	taskId int,
@@ -256,7 +261,7 @@ func (r *Mysql) ListHistory(
 	limit int,
 ) ([]*history.Model, error) {
 	if limit <= 0 {
-		return nil, fmt.Errorf("invalid limit")
+		return nil, apperror.Validation("invalid limit")
 	}
 
 	rows, err := r.db.QueryContext(ctx, queryListHistory, taskId, startFromId, limit)
@@ -267,6 +272,10 @@ func (r *Mysql) ListHistory(
 
 	defer rows.Close()
 
+	return scanHistory(rows)
+}
+
+func scanHistory(rows *sql.Rows) ([]*history.Model, error) {
 	var histories []*history.Model
 
 	for rows.Next() {
@@ -279,10 +288,14 @@ func (r *Mysql) ListHistory(
 			&h.Title,
 			&h.Description,
 			&h.CreatorId,
-			&h.AssigneeId,
+			(*idList)(&h.AssigneeIds),
 			&h.TeamId,
 			&h.ChangedBy,
 			&h.ChangedAt,
+			&h.Event,
+			&h.DueAt,
+			&h.Priority,
+			&h.Label,
 		); err != nil {
 			return nil, err
 		}
@@ -300,28 +313,54 @@ func (r *Mysql) ListHistory(
 func (r *Mysql) CreateTask(
 	ctx context.Context,
 	status, title, description string,
-	creatorId, assigneeId, teamId int,
+	creatorId int,
+	assigneeIds []int,
+	teamId int,
 	createdAt time.Time,
+	dueAt *time.Time,
+	priority task.Priority,
 ) (*task.Model, error) {
-	result, err := r.db.ExecContext(
+	tx, err := r.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := tx.ExecContext(
 		ctx,
 		queryInsertTask,
 		status,
 		title,
 		description,
 		creatorId,
-		assigneeId,
 		teamId,
 		createdAt,
+		createdAt,
+		dueAt,
+		priority,
 	)
 
 	if err != nil {
+		tx.Rollback()
+
 		return nil, err
 	}
 
 	id, err := result.LastInsertId()
 
 	if err != nil {
+		tx.Rollback()
+
+		return nil, err
+	}
+
+	if err := insertTaskAssignees(ctx, tx, int(id), assigneeIds); err != nil {
+		tx.Rollback()
+
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
@@ -331,9 +370,13 @@ func (r *Mysql) CreateTask(
 		Title:       title,
 		Description: description,
 		CreatorId:   creatorId,
-		AssigneeId:  assigneeId,
+		AssigneeIds: assigneeIds,
 		TeamId:      teamId,
 		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+		Version:     1,
+		DueAt:       dueAt,
+		Priority:    priority,
 	}, nil
 }
 
@@ -349,9 +392,14 @@ func (r *Mysql) GetTaskById(
 		&t.Title,
 		&t.Description,
 		&t.CreatorId,
-		&t.AssigneeId,
+		(*idList)(&t.AssigneeIds),
 		&t.TeamId,
 		&t.CreatedAt,
+		&t.UpdatedAt,
+		&t.Version,
+		&t.ArchivedAt,
+		&t.DueAt,
+		&t.Priority,
 	)
 
 	if err != nil {
@@ -363,41 +411,23 @@ func (r *Mysql) GetTaskById(
 
 func (r *Mysql) ListTasks(
 	ctx context.Context,
-	teamId int,
-	status string,
-	assigneeId int,
-	startFromId int,
+	f task.Filter,
 	limit int,
 ) ([]*task.Model, error) {
 	if limit <= 0 {
-		return nil, fmt.Errorf("invalid limit")
+		return nil, apperror.Validation("invalid limit")
 	}
 
 	sb := strings.Builder{}
 
-	sb.WriteString("SELECT id, status, title, description, creator_id, assignee_id, team_id, created_at FROM tasks WHERE team_id = ?")
-
-	args := []any{teamId}
-
-	if status != "" {
-		sb.WriteString(" AND status = ?")
-
-		args = append(args, status)
-	}
-
-	if assigneeId != 0 {
-		sb.WriteString(" AND assignee_id = ?")
-
-		args = append(args, assigneeId)
-	}
+	// Архивные задачи на доске не показываем.
+	sb.WriteString("SELECT " + taskListColumns + " FROM tasks t WHERE t.team_id = ? AND t.archived_at IS NULL")
 
-	if startFromId != 0 {
-		sb.WriteString(" AND id > ?")
+	args := []any{f.TeamId}
 
-		args = append(args, startFromId)
-	}
+	args = appendTaskFilter(&sb, args, f)
 
-	sb.WriteString(" ORDER BY id ASC LIMIT ?")
+	sb.WriteString(" LIMIT ?")
 
 	args = append(args, limit)
 
@@ -409,6 +439,10 @@ func (r *Mysql) ListTasks(
 
 	defer rows.Close()
 
+	return scanTasks(rows, limit)
+}
+
+func scanTasks(rows *sql.Rows, limit int) ([]*task.Model, error) {
 	tasks := make([]*task.Model, 0, limit)
 
 	for rows.Next() {
@@ -420,9 +454,13 @@ func (r *Mysql) ListTasks(
 			&t.Title,
 			&t.Description,
 			&t.CreatorId,
-			&t.AssigneeId,
+			(*idList)(&t.AssigneeIds),
 			&t.TeamId,
 			&t.CreatedAt,
+			&t.UpdatedAt,
+			&t.Version,
+			&t.DueAt,
+			&t.Priority,
 		); err != nil {
 			return nil, err
 		}
@@ -437,37 +475,152 @@ func (r *Mysql) ListTasks(
 	return tasks, nil
 }
 
-func (r *Mysql) EditTask(
+// UpdateTask сохраняет новую версию задачи вместе с записью истории о предыдущей.
+// Строка блокируется до конца транзакции, поэтому параллельные правки не затирают друг друга:
+// если версия в базе уже не expectedVersion, возвращается task.ErrVersionMismatch.
+func (r *Mysql) UpdateTask(
 	ctx context.Context,
-	id int,
-	status, title, description string,
-	assigneeId int,
-) error {
-	result, err := r.db.ExecContext(
+	t task.Model,
+	expectedVersion int,
+	changedBy int,
+	changedAt time.Time,
+) (*task.Model, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	old, err := lockTask(ctx, tx, t.Id, expectedVersion)
+
+	if err != nil {
+		tx.Rollback()
+
+		return nil, err
+	}
+
+	if old.IsArchived() {
+		tx.Rollback()
+
+		return nil, task.ErrArchived
+	}
+
+	if err := insertTaskHistory(ctx, tx, old, history.EditEvent, changedBy, changedAt); err != nil {
+		tx.Rollback()
+
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(
 		ctx,
-		queryEditTask,
-		status,
-		title,
-		description,
-		assigneeId,
-		id,
+		queryUpdateTask,
+		t.DueAt,
+		t.Status,
+		t.Title,
+		t.Description,
+		t.DueAt,
+		t.Priority,
+		changedAt,
+		t.Id,
 	)
 
 	if err != nil {
-		return err
+		tx.Rollback()
+
+		return nil, err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	if !slices.Equal(old.AssigneeIds, t.AssigneeIds) {
+		if err := replaceTaskAssignees(ctx, tx, t.Id, t.AssigneeIds); err != nil {
+			tx.Rollback()
+
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	updated := *old
+	updated.Status = t.Status
+	updated.Title = t.Title
+	updated.Description = t.Description
+	updated.AssigneeIds = t.AssigneeIds
+	updated.DueAt = t.DueAt
+	updated.Priority = t.Priority
+	updated.UpdatedAt = changedAt
+	updated.Version = old.Version + 1
+
+	return &updated, nil
+}
+
+// lockTask блокирует строку задачи до конца транзакции и сверяет версию.
+// expectedVersion == 0 означает, что версия не проверяется.
+func lockTask(ctx context.Context, tx *sql.Tx, id int, expectedVersion int) (*task.Model, error) {
+	var t task.Model
+
+	err := tx.QueryRowContext(ctx, queryGetTaskByIdForUpdate, id).Scan(
+		&t.Id,
+		&t.Status,
+		&t.Title,
+		&t.Description,
+		&t.CreatorId,
+		(*idList)(&t.AssigneeIds),
+		&t.TeamId,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+		&t.Version,
+		&t.ArchivedAt,
+		&t.DueAt,
+		&t.Priority,
+	)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if rowsAffected == 0 {
-		return sql.ErrNoRows
+	if expectedVersion != 0 && t.Version != expectedVersion {
+		return nil, task.ErrVersionMismatch
 	}
 
-	return nil
+	return &t, nil
+}
+
+// insertTaskHistory сохраняет состояние задачи до изменения.
+func insertTaskHistory(ctx context.Context, tx *sql.Tx, t *task.Model, event history.Event, changedBy int, changedAt time.Time) error {
+	return insertTaskHistoryWithLabel(ctx, tx, t, event, nil, changedBy, changedAt)
+}
+
+// insertTaskHistoryWithLabel — то же для событий с метками: label — имя метки, которую повесили или сняли.
+func insertTaskHistoryWithLabel(
+	ctx context.Context,
+	tx *sql.Tx,
+	t *task.Model,
+	event history.Event,
+	label *string,
+	changedBy int,
+	changedAt time.Time,
+) error {
+	_, err := tx.ExecContext(
+		ctx,
+		queryInsertTaskHistory,
+		t.Id,
+		t.Status,
+		t.Title,
+		t.Description,
+		t.CreatorId,
+		idList(t.AssigneeIds),
+		t.TeamId,
+		changedBy,
+		changedAt,
+		event,
+		t.DueAt,
+		t.Priority,
+		label,
+	)
+
+	return err
 }
 
 func (r *Mysql) InviteMember(
@@ -488,22 +641,6 @@ func (r *Mysql) InviteMember(
 	}, nil
 }
 
-func (r *Mysql) CanUserInvite(
-	ctx context.Context,
-	teamId int,
-	userId int,
-) (bool, error) {
-	exists := false
-
-	err := r.db.QueryRowContext(ctx, queryCanUserInvite, teamId, userId).Scan(&exists)
-
-	if err != nil {
-		return false, err
-	}
-
-	return exists, nil
-}
-
 func (r *Mysql) CreateTeamAndMakeUserItsOwner(
 	ctx context.Context,
 	name string,
@@ -520,6 +657,10 @@ func (r *Mysql) CreateTeamAndMakeUserItsOwner(
 	if err != nil {
 		tx.Rollback()
 
+		if isDuplicateEntry(err) {
+			return nil, apperror.Conflict("team name already taken")
+		}
+
 		return nil, err
 	}
 
@@ -576,20 +717,6 @@ func (r *Mysql) ListTeams(
 	return teams, nil
 }
 
-func (r *Mysql) IsPartOfTeamByTaskId(
-	userId, taskId int,
-) (bool, error) {
-	count := 0
-
-	err := r.db.QueryRow(queryIsPartOfTeamByTaskId, userId, taskId).Scan(&count)
-
-	if err != nil {
-		return false, err
-	}
-
-	return count > 0, nil
-}
-
 func (r *Mysql) CheckUserIsInTeam(
 	userId, teamId int,
 ) (bool, error) {
@@ -610,6 +737,10 @@ func (r *Mysql) RegisterUser(
 ) (*user.Model, error) {
 	result, err := r.db.ExecContext(ctx, queryRegisterUser, username, passwordHashed)
 
+	if isDuplicateEntry(err) {
+		return nil, apperror.Conflict("username already taken")
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -641,3 +772,18 @@ func (r *Mysql) GetUser(
 
 	return &u, nil
 }
+
+func (r *Mysql) GetUserById(
+	ctx context.Context,
+	id int,
+) (*user.Model, error) {
+	row := r.db.QueryRowContext(ctx, queryGetUserById, id)
+
+	var u user.Model
+
+	if err := row.Scan(&u.Id, &u.Username, &u.PasswordHashed); err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
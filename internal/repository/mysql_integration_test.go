@@ -4,10 +4,21 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/attachment"
+	"mkk-luna-test-task/internal/task/comment"
+	"mkk-luna-test-task/internal/task/history"
+	"mkk-luna-test-task/internal/team/invitation"
+	"mkk-luna-test-task/internal/team/label"
 	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+	"mkk-luna-test-task/internal/team/workflow"
+	"mkk-luna-test-task/internal/user/notification"
+	"mkk-luna-test-task/internal/user/session"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
@@ -20,8 +31,9 @@ import (
 const queryListNonMembers = `
 SELECT t.id
 FROM tasks t
+INNER JOIN task_assignees ta ON ta.task_id = t.id
 LEFT JOIN team_members tm
-    ON t.team_id = tm.team_id AND t.assignee_id = tm.user_id
+    ON t.team_id = tm.team_id AND ta.user_id = tm.user_id
 WHERE tm.id IS NULL;
 `
 
@@ -230,32 +242,32 @@ func TestMysqlRepositoryIntegration(t *testing.T) {
 	assert.True(t, foundBackend)
 	assert.True(t, foundFrontend)
 
-	task1, err := repo.CreateTask(ctx, "todo", "Реализовать вход", "API эндпоинт для входа пользователей", userLead.Id, userDev1.Id, teamBackend.Id, utcBeginLastMonth.Add(24*time.Hour))
+	task1, err := repo.CreateTask(ctx, "todo", "Реализовать вход", "API эндпоинт для входа пользователей", userLead.Id, []int{userDev1.Id}, teamBackend.Id, utcBeginLastMonth.Add(24*time.Hour), nil, task.NormalPriority)
 	assert.NoError(t, err)
 	assert.NotZero(t, task1.Id)
 	assert.Equal(t, "Реализовать вход", task1.Title)
 
-	task2, err := repo.CreateTask(ctx, "todo", "Написать юнит-тесты", "Добавить тесты для jwt-аутентификации", userDev1.Id, userDev2.Id, teamBackend.Id, utcBeginLastMonth.Add(48*time.Hour))
+	task2, err := repo.CreateTask(ctx, "todo", "Написать юнит-тесты", "Добавить тесты для jwt-аутентификации", userDev1.Id, []int{userDev2.Id}, teamBackend.Id, utcBeginLastMonth.Add(48*time.Hour), nil, task.NormalPriority)
 	assert.NoError(t, err)
 
-	task3, err := repo.CreateTask(ctx, "todo", "Провести тестирование функций", "Функциональное тестирование для QA", userDev1.Id, userQa.Id, teamBackend.Id, utcBeginLastMonth.Add(72*time.Hour))
+	task3, err := repo.CreateTask(ctx, "todo", "Провести тестирование функций", "Функциональное тестирование для QA", userDev1.Id, []int{userQa.Id}, teamBackend.Id, utcBeginLastMonth.Add(72*time.Hour), nil, task.NormalPriority)
 	assert.NoError(t, err)
 
-	task4Done, err := repo.CreateTask(ctx, "done", "Доработка тестов", "Последние правки", userDev2.Id, userDev2.Id, teamBackend.Id, now.AddDate(0, 0, -3))
+	task4Done, err := repo.CreateTask(ctx, "done", "Доработка тестов", "Последние правки", userDev2.Id, []int{userDev2.Id}, teamBackend.Id, now.AddDate(0, 0, -3), nil, task.NormalPriority)
 	assert.NoError(t, err)
 	assert.NotZero(t, task4Done.Id)
 
-	_, err = repo.CreateTask(ctx, "todo", "Frontend Feature #1", "Task 1", userDev1.Id, userDev1.Id, teamFrontend.Id, utcBeginLastMonth.Add(24*time.Hour))
+	_, err = repo.CreateTask(ctx, "todo", "Frontend Feature #1", "Task 1", userDev1.Id, []int{userDev1.Id}, teamFrontend.Id, utcBeginLastMonth.Add(24*time.Hour), nil, task.NormalPriority)
 	assert.NoError(t, err)
 
-	_, err = repo.CreateTask(ctx, "todo", "Frontend Feature #2", "Task 2", userDev1.Id, userLead.Id, teamFrontend.Id, utcBeginLastMonth.Add(48*time.Hour))
+	_, err = repo.CreateTask(ctx, "todo", "Frontend Feature #2", "Task 2", userDev1.Id, []int{userLead.Id}, teamFrontend.Id, utcBeginLastMonth.Add(48*time.Hour), nil, task.NormalPriority)
 	assert.NoError(t, err)
 
-	_, err = repo.CreateTask(ctx, "todo", "баг скинов на фронтэнде баннеров", "баннерная реклама не влазит в айфрейм 300x250", userLead.Id, userDev1.Id, teamFrontend.Id, utcBeginLastMonth.Add(90*time.Hour))
+	_, err = repo.CreateTask(ctx, "todo", "баг скинов на фронтэнде баннеров", "баннерная реклама не влазит в айфрейм 300x250", userLead.Id, []int{userDev1.Id}, teamFrontend.Id, utcBeginLastMonth.Add(90*time.Hour), nil, task.NormalPriority)
 	assert.NoError(t, err)
 
 	// QA в бэкенде, но может проверять и фронтэнд.
-	_, err = repo.CreateTask(ctx, "done", "багфикс чего-то там", "Что-то там важное", userDev1.Id, userQa.Id, teamFrontend.Id, now.AddDate(0, 0, -6))
+	_, err = repo.CreateTask(ctx, "done", "багфикс чего-то там", "Что-то там важное", userDev1.Id, []int{userQa.Id}, teamFrontend.Id, now.AddDate(0, 0, -6), nil, task.NormalPriority)
 	assert.NoError(t, err)
 
 	assert.NotEqual(t, task1.Id, task2.Id)
@@ -264,55 +276,518 @@ func TestMysqlRepositoryIntegration(t *testing.T) {
 	retrieved1, err := repo.GetTaskById(ctx, task1.Id)
 	assert.NoError(t, err)
 	assert.NotNil(t, retrieved1)
-	assert.Equal(t, userDev1.Id, retrieved1.AssigneeId)
+	assert.Equal(t, []int{userDev1.Id}, retrieved1.AssigneeIds)
 
 	retrieved2, err := repo.GetTaskById(ctx, task2.Id)
 	assert.NoError(t, err)
 	assert.NotNil(t, retrieved2)
-	assert.Equal(t, userDev2.Id, retrieved2.AssigneeId)
+	assert.Equal(t, []int{userDev2.Id}, retrieved2.AssigneeIds)
 
 	retrieved3, err := repo.GetTaskById(ctx, task3.Id)
 	assert.NoError(t, err)
 	assert.NotNil(t, retrieved3)
-	assert.Equal(t, userQa.Id, retrieved3.AssigneeId)
+	assert.Equal(t, []int{userQa.Id}, retrieved3.AssigneeIds)
 
-	comment1, err := repo.CreateTaskComment(ctx, userDev1.Id, task1.Id, "Начал работу над задачей.", now)
+	comment1, err := repo.CreateTaskComment(ctx, userDev1.Id, task1.Id, nil, "Начал работу над задачей.", nil, now)
 	assert.NoError(t, err)
 	assert.NotZero(t, comment1.Id)
 
-	_, err = repo.CreateTaskComment(ctx, userLead.Id, task1.Id, "какой прогресс по задаче", now)
+	// Ответ в ветке первого комментария с упоминанием его автора.
+	reply, err := repo.CreateTaskComment(ctx, userLead.Id, task1.Id, &comment1.Id, "@dev1 какой прогресс по задаче", []int{userDev1.Id}, now)
 	assert.NoError(t, err)
 
+	memberIds, err := repo.GetMemberIdsByUsernames(ctx, teamBackend.Id, []string{"dev1", "teamlead", "nobody"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"dev1": userDev1.Id, "teamlead": userLead.Id}, memberIds)
+
+	mentions, err := repo.ListUserMentions(ctx, userDev1.Id, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, mentions, 1)
+	assert.Equal(t, reply.Id, mentions[0].Comment.Id)
+	assert.Equal(t, &comment1.Id, mentions[0].Comment.ParentCommentId)
+	assert.Equal(t, teamBackend.Id, mentions[0].TeamId)
+	assert.Equal(t, "Реализовать вход", mentions[0].TaskTitle)
+
+	mentions, err = repo.ListUserMentions(ctx, userDev1.Id, reply.Id, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, mentions)
+
+	// При task.view = own упоминание в чужой задаче скрыто, а в задаче, где пользователь исполнитель, — нет.
+	foreign, err := repo.CreateTaskComment(ctx, userLead.Id, task4Done.Id, nil, "@dev1 глянь тесты", []int{userDev1.Id}, now)
+	assert.NoError(t, err)
+
+	err = repo.SetPermissionOverride(ctx, permission.Override{TeamId: teamBackend.Id, Role: member.NormalRole, Action: permission.TaskView, Scope: permission.OwnScope})
+	assert.NoError(t, err)
+
+	mentions, err = repo.ListUserMentions(ctx, userDev1.Id, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, mentions, 1)
+	assert.Equal(t, reply.Id, mentions[0].Comment.Id)
+
+	err = repo.SetPermissionOverride(ctx, permission.Override{TeamId: teamBackend.Id, Role: member.NormalRole, Action: permission.TaskView, Scope: permission.AnyScope})
+	assert.NoError(t, err)
+
+	mentions, err = repo.ListUserMentions(ctx, userDev1.Id, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, mentions, 2)
+	assert.Equal(t, foreign.Id, mentions[0].Comment.Id)
+
+	assert.NoError(t, repo.DeleteTaskComment(ctx, foreign.Id, userLead.Id, now))
+
 	comments, err := repo.ListTaskComments(ctx, task1.Id, 0, 10)
 	assert.NoError(t, err)
 	assert.Len(t, comments, 2)
 
-	hist1, err := repo.CreateHistory(ctx, retrieved1, userDev1.Id, now)
+	// Правка сохраняет прежний текст, удалённый комментарий остаётся в списке заглушкой.
+	editedComment, err := repo.UpdateTaskComment(ctx, comment1.Id, "Начал работу, жду ревью.", nil, userDev1.Id, now)
+	assert.NoError(t, err)
+	assert.Equal(t, "Начал работу, жду ревью.", editedComment.Text)
+	assert.NotNil(t, editedComment.UpdatedAt)
+
+	var versionText string
+	err = db.QueryRowContext(ctx, "SELECT text FROM task_comment_versions WHERE comment_id = ?", comment1.Id).Scan(&versionText)
+	assert.NoError(t, err)
+	assert.Equal(t, "Начал работу над задачей.", versionText)
+
+	secret, err := repo.CreateTaskComment(ctx, userDev1.Id, task1.Id, nil, "пароль от стенда: qwerty @teamlead", []int{userLead.Id}, now)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.DeleteTaskComment(ctx, secret.Id, userDev1.Id, now))
+	assert.NoError(t, repo.DeleteTaskComment(ctx, secret.Id, userLead.Id, now))
+
+	_, err = repo.UpdateTaskComment(ctx, secret.Id, "исправил", nil, userDev1.Id, now)
+	assert.ErrorIs(t, err, comment.ErrDeleted)
+
+	// Удалённый комментарий пропадает из упоминаний.
+	mentions, err = repo.ListUserMentions(ctx, userLead.Id, 0, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, mentions)
+
+	deletedComment, err := repo.GetTaskComment(ctx, secret.Id)
+	assert.NoError(t, err)
+	assert.True(t, deletedComment.IsDeleted())
+
+	_, err = repo.GetTaskComment(ctx, 0)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	comments, err = repo.ListTaskComments(ctx, task1.Id, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, comments, 3)
+	assert.Equal(t, "Начал работу, жду ревью.", comments[0].Text)
+	assert.Nil(t, comments[0].ParentCommentId)
+	assert.Equal(t, &comment1.Id, comments[1].ParentCommentId)
+	assert.False(t, comments[1].IsDeleted())
+	assert.True(t, comments[2].IsDeleted())
+
+	screenshot, err := repo.CreateTaskAttachment(ctx, attachment.Model{
+		TaskId: task1.Id, UploaderId: userDev1.Id, FileName: "screenshot.png", ContentType: "image/png",
+		Size: 1024, Checksum: strings.Repeat("a", 64), StorageKey: fmt.Sprintf("tasks/%d/screenshot", task1.Id), CreatedAt: now,
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, screenshot.Id)
+
+	gotScreenshot, err := repo.GetTaskAttachment(ctx, screenshot.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, screenshot.StorageKey, gotScreenshot.StorageKey)
+	assert.Nil(t, gotScreenshot.CommentId)
+
+	// Вложение удалённого комментария скрывается вместе с ним.
+	_, err = repo.CreateTaskAttachment(ctx, attachment.Model{
+		TaskId: task1.Id, CommentId: &secret.Id, UploaderId: userDev1.Id, FileName: "env.txt", ContentType: "text/plain; charset=utf-8",
+		Size: 10, Checksum: strings.Repeat("b", 64), StorageKey: fmt.Sprintf("tasks/%d/env", task1.Id), CreatedAt: now,
+	})
 	assert.NoError(t, err)
-	assert.NotZero(t, hist1.Id)
 
-	histories, err := repo.ListHistory(ctx, task1.Id, 0, 10)
+	attachments, err := repo.ListTaskAttachments(ctx, task1.Id)
 	assert.NoError(t, err)
-	assert.NotEmpty(t, histories)
+	assert.Len(t, attachments, 1)
+	assert.Equal(t, screenshot.Id, attachments[0].Id)
+
+	attachmentKeys, err := repo.ListTaskAttachmentKeys(ctx, task1.Id)
+	assert.NoError(t, err)
+	assert.Len(t, attachmentKeys, 2)
+
+	assert.NoError(t, repo.DeleteTaskAttachment(ctx, screenshot.Id))
+
+	_, err = repo.GetTaskAttachment(ctx, screenshot.Id)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	updated2, err := repo.UpdateTask(ctx, task.Model{
+		Id:          task2.Id,
+		Status:      "in-progress",
+		Title:       "Юнит-тесты",
+		Description: "Расширить покрытие тестами",
+		AssigneeIds: []int{userDev2.Id, userQa.Id},
+	}, 1, userDev1.Id, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, updated2.Version)
+	assert.Equal(t, task2.CreatorId, updated2.CreatorId)
+
+	// Правка поверх устаревшей версии не проходит и не оставляет записи в истории.
+	_, err = repo.UpdateTask(ctx, *updated2, 1, userDev1.Id, now)
+	assert.ErrorIs(t, err, task.ErrVersionMismatch)
 
-	err = repo.EditTask(ctx, task2.Id, "in-progress", "Юнит-тесты", "Расширить покрытие тестами", userDev2.Id)
+	histories, err := repo.ListHistory(ctx, task2.Id, 0, 10)
 	assert.NoError(t, err)
+	assert.Len(t, histories, 1)
+	assert.Equal(t, "todo", histories[0].Status)
+	assert.Equal(t, []int{userDev2.Id}, histories[0].AssigneeIds)
+	assert.Equal(t, userDev1.Id, histories[0].ChangedBy)
+
+	// На момент до правки задача была в состоянии из первой записи, после — в текущем.
+	before, err := repo.GetHistoryAfter(ctx, task2.Id, now.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, histories[0].Id, before.Id)
+
+	_, err = repo.GetHistoryAfter(ctx, task2.Id, now.Add(time.Hour))
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	entry, err := repo.GetHistoryById(ctx, histories[0].Id)
+	assert.NoError(t, err)
+	assert.Equal(t, histories[0], entry)
+
+	_, err = repo.GetHistoryById(ctx, 0)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
 
 	edited2, err := repo.GetTaskById(ctx, task2.Id)
 	assert.NoError(t, err)
-	assert.Equal(t, "Юнит-тесты — этап 2", edited2.Title)
+	assert.Equal(t, "Юнит-тесты", edited2.Title)
+	assert.ElementsMatch(t, []int{userDev2.Id, userQa.Id}, edited2.AssigneeIds)
+
+	// Фильтр по исполнителю находит задачу, если он среди её исполнителей.
+	qaTasks, err := repo.ListTasks(ctx, task.Filter{TeamId: teamBackend.Id, AssigneeIds: []int{userQa.Id}, Sort: task.SortById}, 10)
+	assert.NoError(t, err)
+	assert.Len(t, qaTasks, 2)
+	assert.Equal(t, task2.Id, qaTasks[0].Id)
+	assert.Equal(t, task3.Id, qaTasks[1].Id)
+
+	// Задачу можно создать без исполнителей.
+	backlog, err := repo.CreateTask(ctx, "todo", "Разобрать бэклог", "", userLead.Id, []int{}, teamBackend.Id, now, nil, task.LowPriority)
+	assert.NoError(t, err)
+
+	retrievedBacklog, err := repo.GetTaskById(ctx, backlog.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{}, retrievedBacklog.AssigneeIds)
+
+	assert.NoError(t, repo.AddTaskWatcher(ctx, backlog.Id, userQa.Id))
+	assert.NoError(t, repo.AddTaskWatcher(ctx, backlog.Id, userQa.Id))
+	assert.NoError(t, repo.AddTaskWatcher(ctx, backlog.Id, userDev1.Id))
+
+	watchers, err := repo.ListTaskWatchers(ctx, backlog.Id)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []int{userQa.Id, userDev1.Id}, watchers)
+
+	assert.NoError(t, repo.RemoveTaskWatcher(ctx, backlog.Id, userQa.Id))
+	assert.NoError(t, repo.RemoveTaskWatcher(ctx, backlog.Id, userQa.Id))
+
+	watchers, err = repo.ListTaskWatchers(ctx, backlog.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{userDev1.Id}, watchers)
+
+	_, err = repo.ArchiveTask(ctx, backlog.Id, 0, userLead.Id, now)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.PurgeTask(ctx, backlog.Id))
 	assert.Equal(t, "in-progress", edited2.Status)
+	assert.Equal(t, 2, edited2.Version)
+
+	archived2, err := repo.ArchiveTask(ctx, task2.Id, 2, userLead.Id, now)
+	assert.NoError(t, err)
+	assert.True(t, archived2.IsArchived())
+	assert.Equal(t, 3, archived2.Version)
+
+	_, err = repo.ArchiveTask(ctx, task2.Id, 0, userLead.Id, now)
+	assert.ErrorIs(t, err, task.ErrArchived)
+
+	// Архивная задача пропадает с доски, но история остаётся доступной.
+	boardTasks, err := repo.ListTasks(ctx, task.Filter{TeamId: teamBackend.Id, Sort: task.SortById}, 10)
+	assert.NoError(t, err)
+
+	for _, bt := range boardTasks {
+		assert.NotEqual(t, task2.Id, bt.Id)
+	}
+
+	// Ссылки #N на архивные и удалённые задачи не раскрываются.
+	refTasks, err := repo.GetTasksByIds(ctx, []int{task2.Id, task1.Id, backlog.Id})
+	assert.NoError(t, err)
+	assert.Len(t, refTasks, 1)
+	assert.Equal(t, task1.Id, refTasks[0].Id)
+	assert.Equal(t, task1.Title, refTasks[0].Title)
+
+	_, err = repo.UpdateTask(ctx, *archived2, 3, userDev1.Id, now)
+	assert.ErrorIs(t, err, task.ErrArchived)
+
+	restored2, err := repo.RestoreTask(ctx, task2.Id, 3, userLead.Id, now)
+	assert.NoError(t, err)
+	assert.False(t, restored2.IsArchived())
+	assert.Equal(t, 4, restored2.Version)
+
+	archiveHistory, err := repo.ListHistory(ctx, task2.Id, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, archiveHistory, 3)
+
+	var events []history.Event
+
+	for _, h := range archiveHistory {
+		events = append(events, h.Event)
+	}
+
+	assert.ElementsMatch(t, []history.Event{history.EditEvent, history.ArchiveEvent, history.RestoreEvent}, events)
+
+	assert.True(t, restored2.UpdatedAt.Equal(now))
+
+	latest, err := repo.ListLatestHistory(ctx, task2.Id, 2)
+	assert.NoError(t, err)
+	assert.Len(t, latest, 2)
+	assert.Equal(t, history.RestoreEvent, latest[0].Event)
+
+	commentsCount, err := repo.CountTaskComments(ctx, task1.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, commentsCount)
+
+	usernames, err := repo.GetUsernames(ctx, []int{userLead.Id, userDev1.Id, 0})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]string{userLead.Id: "teamlead", userDev1.Id: "dev1"}, usernames)
+
+	draft, err := repo.CreateTask(ctx, "todo", "Черновик", "Задача на удаление", userLead.Id, []int{userLead.Id}, teamBackend.Id, now, nil, task.NormalPriority)
+	assert.NoError(t, err)
+
+	_, err = repo.CreateTaskComment(ctx, userLead.Id, draft.Id, nil, "удалить", nil, now)
+	assert.NoError(t, err)
+
+	draftAttachment, err := repo.CreateTaskAttachment(ctx, attachment.Model{
+		TaskId: draft.Id, UploaderId: userLead.Id, FileName: "app.log", ContentType: "text/plain; charset=utf-8",
+		Size: 10, Checksum: strings.Repeat("c", 64), StorageKey: fmt.Sprintf("tasks/%d/log", draft.Id), CreatedAt: now,
+	})
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, repo.PurgeTask(ctx, draft.Id), task.ErrNotArchived)
+
+	_, err = repo.ArchiveTask(ctx, draft.Id, 0, userLead.Id, now)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.PurgeTask(ctx, draft.Id))
+
+	_, err = repo.GetTaskById(ctx, draft.Id)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = repo.GetTaskAttachment(ctx, draftAttachment.Id)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
 
 	userOutsider, err := repo.RegisterUser(ctx, "outsider", "пароль123")
 	assert.NoError(t, err)
 
-	_, err = repo.CreateTask(ctx, "todo", "Ошибочная задача", "Задача назначена не-участнику команды", userDev1.Id, userOutsider.Id, teamFrontend.Id, now)
+	_, err = repo.CreateTask(ctx, "todo", "Ошибочная задача", "Задача назначена не-участнику команды", userDev1.Id, []int{userOutsider.Id}, teamFrontend.Id, now, nil, task.NormalPriority)
 	assert.NoError(t, err)
 
-	allTasks, err := repo.ListTasks(ctx, teamBackend.Id, "", 0, 0, 10)
+	allTasks, err := repo.ListTasks(ctx, task.Filter{TeamId: teamBackend.Id, Sort: task.SortById}, 10)
 	assert.NoError(t, err)
 	assert.GreaterOrEqual(t, len(allTasks), 4)
 
+	byCreator, err := repo.ListTasks(ctx, task.Filter{
+		TeamId:     teamBackend.Id,
+		Statuses:   []string{"todo", "in-progress"},
+		CreatorIds: []int{userDev1.Id},
+		Sort:       task.SortById,
+	}, 10)
+	assert.NoError(t, err)
+	assert.Len(t, byCreator, 2)
+
+	found, err := repo.ListTasks(ctx, task.Filter{TeamId: teamBackend.Id, Query: "тест", Sort: task.SortById}, 10)
+	assert.NoError(t, err)
+	assert.Len(t, found, 3)
+
+	// Постраничный обход по created_at по убыванию не теряет и не повторяет задачи.
+	newestFirst := task.Filter{TeamId: teamBackend.Id, Sort: task.SortByCreatedAt, Desc: true}
+
+	firstPage, err := repo.ListTasks(ctx, newestFirst, 2)
+	assert.NoError(t, err)
+	assert.Len(t, firstPage, 2)
+
+	cursor := task.NewCursor(*firstPage[1], newestFirst.Sort, newestFirst.Desc)
+	newestFirst.After = &cursor
+
+	secondPage, err := repo.ListTasks(ctx, newestFirst, 10)
+	assert.NoError(t, err)
+	assert.Len(t, secondPage, len(allTasks)-2)
+	assert.False(t, secondPage[0].CreatedAt.After(firstPage[1].CreatedAt))
+
+	for _, p := range secondPage {
+		assert.NotEqual(t, firstPage[0].Id, p.Id)
+		assert.NotEqual(t, firstPage[1].Id, p.Id)
+	}
+
+	// Просроченной считается задача со сроком в прошлом, ещё не дошедшая до завершающего статуса.
+	yesterday := now.Add(-24 * time.Hour).Truncate(time.Second)
+
+	release, err := repo.CreateTask(ctx, "todo", "Срочный релиз", "Выкатить до вечера", userLead.Id, []int{userDev1.Id}, teamBackend.Id, now, &yesterday, task.UrgentPriority)
+	assert.NoError(t, err)
+
+	_, err = repo.CreateTask(ctx, "done", "Прошлый релиз", "Уже выкачен", userLead.Id, []int{userDev1.Id}, teamBackend.Id, now, &yesterday, task.HighPriority)
+	assert.NoError(t, err)
+
+	overdueTasks, err := repo.ListTasks(ctx, task.Filter{TeamId: teamBackend.Id, OverdueAt: &now, Sort: task.SortById}, 10)
+	assert.NoError(t, err)
+	assert.Len(t, overdueTasks, 1)
+	assert.Equal(t, release.Id, overdueTasks[0].Id)
+	assert.Equal(t, task.UrgentPriority, overdueTasks[0].Priority)
+	assert.True(t, overdueTasks[0].DueAt.Equal(yesterday))
+
+	byPriority, err := repo.ListTasks(ctx, task.Filter{TeamId: teamBackend.Id, Sort: task.SortByPriority, Desc: true}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, release.Id, byPriority[0].Id)
+
+	// Задачи без срока при сортировке по сроку идут последними.
+	byDueAt, err := repo.ListTasks(ctx, task.Filter{TeamId: teamBackend.Id, Sort: task.SortByDueAt}, 10)
+	assert.NoError(t, err)
+	assert.NotNil(t, byDueAt[0].DueAt)
+	assert.Nil(t, byDueAt[len(byDueAt)-1].DueAt)
+
+	// О каждой просроченной задаче уведомляем один раз.
+	marked, err := repo.MarkOverdueTasks(ctx, now, 10)
+	assert.NoError(t, err)
+	assert.Len(t, marked, 1)
+	assert.Equal(t, release.Id, marked[0].Id)
+
+	marked, err = repo.MarkOverdueTasks(ctx, now, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, marked)
+
+	// Перенос срока снимает отметку, и после нового срока задача снова попадает в выборку.
+	tomorrow := now.Add(24 * time.Hour).Truncate(time.Second)
+	release.DueAt = &tomorrow
+
+	moved, err := repo.UpdateTask(ctx, *release, release.Version, userLead.Id, now)
+	assert.NoError(t, err)
+	assert.True(t, moved.DueAt.Equal(tomorrow))
+	assert.Equal(t, task.UrgentPriority, moved.Priority)
+
+	releaseHistory, err := repo.ListHistory(ctx, release.Id, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, releaseHistory, 1)
+	assert.True(t, releaseHistory[0].DueAt.Equal(yesterday))
+	assert.Equal(t, task.UrgentPriority, releaseHistory[0].Priority)
+
+	marked, err = repo.MarkOverdueTasks(ctx, now, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, marked)
+
+	marked, err = repo.MarkOverdueTasks(ctx, now.Add(48*time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Len(t, marked, 1)
+
+	// Метки: каталог команды, фильтр и события в истории.
+	bugLabel, err := repo.CreateLabel(ctx, label.Model{TeamId: teamBackend.Id, Name: "bug", Color: "#d73a4a"})
+	assert.NoError(t, err)
+
+	_, err = repo.CreateLabel(ctx, label.Model{TeamId: teamBackend.Id, Name: "bug", Color: "#000000"})
+	assert.ErrorIs(t, err, label.ErrDuplicate)
+
+	// Имя уникально только в пределах команды.
+	_, err = repo.CreateLabel(ctx, label.Model{TeamId: teamFrontend.Id, Name: "bug", Color: "#d73a4a"})
+	assert.NoError(t, err)
+
+	uiLabel, err := repo.CreateLabel(ctx, label.Model{TeamId: teamBackend.Id, Name: "ui", Color: "#0e8a16"})
+	assert.NoError(t, err)
+
+	backendLabels, err := repo.ListLabels(ctx, teamBackend.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, []label.Model{*bugLabel, *uiLabel}, backendLabels)
+
+	assert.NoError(t, repo.AttachTaskLabel(ctx, task1.Id, *bugLabel, userLead.Id, now))
+	assert.NoError(t, repo.AttachTaskLabel(ctx, task1.Id, *bugLabel, userLead.Id, now))
+	assert.NoError(t, repo.AttachTaskLabel(ctx, task3.Id, *uiLabel, userLead.Id, now))
+
+	labeled, err := repo.ListTasks(ctx, task.Filter{TeamId: teamBackend.Id, Labels: []string{"bug"}, Sort: task.SortById}, 10)
+	assert.NoError(t, err)
+	assert.Len(t, labeled, 1)
+	assert.Equal(t, task1.Id, labeled[0].Id)
+
+	labeled, err = repo.ListTasks(ctx, task.Filter{TeamId: teamBackend.Id, Labels: []string{"bug", "ui"}, Sort: task.SortById}, 10)
+	assert.NoError(t, err)
+	assert.Len(t, labeled, 2)
+
+	task1Labels, err := repo.ListTaskLabels(ctx, task1.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, []label.Model{*bugLabel}, task1Labels)
+
+	assert.NoError(t, repo.DetachTaskLabel(ctx, task1.Id, *bugLabel, userLead.Id, now))
+	assert.NoError(t, repo.DetachTaskLabel(ctx, task1.Id, *bugLabel, userLead.Id, now))
+
+	// Повторные установка и снятие метки в историю не попадают.
+	labelHistory, err := repo.ListLatestHistory(ctx, task1.Id, 3)
+	assert.NoError(t, err)
+	assert.Len(t, labelHistory, 3)
+	assert.Equal(t, history.UnlabelEvent, labelHistory[0].Event)
+	assert.Equal(t, "bug", *labelHistory[0].Label)
+	assert.Equal(t, history.LabelEvent, labelHistory[1].Event)
+	assert.Equal(t, "bug", *labelHistory[1].Label)
+	assert.Nil(t, labelHistory[2].Label)
+
+	// Удаление метки из каталога снимает её с задач с записью в историю.
+	assert.NoError(t, repo.DeleteLabel(ctx, *uiLabel, userLead.Id, now))
+
+	_, err = repo.GetLabel(ctx, uiLabel.Id)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	labelHistory, err = repo.ListLatestHistory(ctx, task3.Id, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, history.UnlabelEvent, labelHistory[0].Event)
+	assert.Equal(t, "ui", *labelHistory[0].Label)
+
+	task3Labels, err := repo.ListTaskLabels(ctx, task3.Id)
+	assert.NoError(t, err)
+	assert.Empty(t, task3Labels)
+
+	// Подзадачи и блокировки: циклы не допускаются ни в одном из деревьев.
+	assert.NoError(t, repo.SetTaskParent(ctx, teamBackend.Id, task3.Id, task1.Id))
+	assert.NoError(t, repo.SetTaskParent(ctx, teamBackend.Id, task4Done.Id, task3.Id))
+	assert.ErrorIs(t, repo.SetTaskParent(ctx, teamBackend.Id, task1.Id, task4Done.Id), task.ErrLinkCycle)
+	assert.ErrorIs(t, repo.SetTaskParent(ctx, teamBackend.Id, task1.Id, task1.Id), task.ErrLinkCycle)
+
+	subtasks, err := repo.ListSubtasks(ctx, task1.Id, 10)
+	assert.NoError(t, err)
+	assert.Len(t, subtasks, 2)
+	assert.Equal(t, task1.Id, subtasks[0].ParentId)
+	assert.Equal(t, task3.Id, subtasks[0].Task.Id)
+	assert.Equal(t, task3.Id, subtasks[1].ParentId)
+	assert.Equal(t, task4Done.Id, subtasks[1].Task.Id)
+
+	parentOf3, err := repo.GetTaskParent(ctx, task3.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, task1.Id, parentOf3.Id)
+
+	_, err = repo.GetTaskParent(ctx, task1.Id)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Новый родитель заменяет прежнего, а отвязка от чужого родителя ничего не меняет.
+	assert.NoError(t, repo.SetTaskParent(ctx, teamBackend.Id, task4Done.Id, task1.Id))
+	assert.NoError(t, repo.RemoveTaskParent(ctx, task4Done.Id, task3.Id))
+
+	parentOf4, err := repo.GetTaskParent(ctx, task4Done.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, task1.Id, parentOf4.Id)
+
+	assert.NoError(t, repo.RemoveTaskParent(ctx, task4Done.Id, task1.Id))
+
+	_, err = repo.GetTaskParent(ctx, task4Done.Id)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	assert.NoError(t, repo.AddTaskBlocker(ctx, teamBackend.Id, task1.Id, task3.Id))
+	assert.NoError(t, repo.AddTaskBlocker(ctx, teamBackend.Id, task1.Id, task3.Id))
+	assert.NoError(t, repo.AddTaskBlocker(ctx, teamBackend.Id, task3.Id, task4Done.Id))
+	assert.ErrorIs(t, repo.AddTaskBlocker(ctx, teamBackend.Id, task4Done.Id, task1.Id), task.ErrLinkCycle)
+	assert.ErrorIs(t, repo.AddTaskBlocker(ctx, teamBackend.Id, task1.Id, task1.Id), task.ErrLinkCycle)
+
+	blockers, err := repo.ListTaskBlockers(ctx, task1.Id)
+	assert.NoError(t, err)
+	assert.Len(t, blockers, 1)
+	assert.Equal(t, task3.Id, blockers[0].Id)
+
+	assert.NoError(t, repo.RemoveTaskBlocker(ctx, task3.Id, task4Done.Id))
+	assert.NoError(t, repo.RemoveTaskBlocker(ctx, task3.Id, task4Done.Id))
+
+	blockers, err = repo.ListTaskBlockers(ctx, task3.Id)
+	assert.NoError(t, err)
+	assert.Empty(t, blockers)
+
 	rows, err := db.QueryContext(ctx, "SELECT user_id, team_id FROM team_members WHERE team_id = ?", teamFrontend.Id)
 	assert.NoError(t, err)
 
@@ -446,4 +921,217 @@ func TestMysqlRepositoryIntegration(t *testing.T) {
 
 	assert.True(t, foundBack)
 	assert.True(t, foundFront)
+
+	sess := session.Model{
+		Id:            "session-lead",
+		UserId:        userLead.Id,
+		AccessTokenId: "access-1",
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(time.Hour),
+	}
+
+	err = repo.CreateSession(ctx, sess, session.HashRefreshToken("refresh-1"))
+	assert.NoError(t, err)
+
+	active, err := repo.IsAccessTokenActive(ctx, sess.Id, "access-1", now)
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	err = repo.RotateRefreshToken(ctx, sess.Id, session.HashRefreshToken("refresh-1"), session.HashRefreshToken("refresh-2"), "access-2", now)
+	assert.NoError(t, err)
+
+	active, err = repo.IsAccessTokenActive(ctx, sess.Id, "access-1", now)
+	assert.NoError(t, err)
+	assert.False(t, active)
+
+	err = repo.RotateRefreshToken(ctx, sess.Id, session.HashRefreshToken("refresh-1"), session.HashRefreshToken("refresh-3"), "access-3", now)
+	assert.ErrorIs(t, err, session.ErrRefreshTokenReused)
+
+	err = repo.RevokeSession(ctx, sess.Id, now)
+	assert.NoError(t, err)
+
+	active, err = repo.IsAccessTokenActive(ctx, sess.Id, "access-2", now)
+	assert.NoError(t, err)
+	assert.False(t, active)
+
+	userNewbie, err := repo.RegisterUser(ctx, "newbie", "пароль5")
+	assert.NoError(t, err)
+
+	inv, err := repo.CreateInvitation(ctx, invitation.Model{
+		TeamId:          teamBackend.Id,
+		InviterId:       userLead.Id,
+		InviteeUsername: "newbie",
+		Role:            member.NormalRole,
+		Status:          invitation.PendingStatus,
+		TokenHash:       "invite-hash-1",
+		CreatedAt:       now,
+		ExpiresAt:       now.Add(time.Hour),
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, inv.Id)
+
+	gotInv, err := repo.GetInvitationByTokenHash(ctx, "invite-hash-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "newbie", gotInv.InviteeUsername)
+	assert.Zero(t, gotInv.InviteeUserId)
+	assert.Equal(t, invitation.PendingStatus, gotInv.Status)
+
+	gotInv, err = repo.GetInvitation(ctx, inv.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "invite-hash-1", gotInv.TokenHash)
+
+	// Приглашение по имени выписано до регистрации, но в списке зарегистрированного пользователя оно есть.
+	newbieInvitations, err := repo.ListUserInvitations(ctx, userNewbie.Id, "newbie", now)
+	assert.NoError(t, err)
+	assert.Len(t, newbieInvitations, 1)
+	assert.Equal(t, inv.Id, newbieInvitations[0].Id)
+
+	newbieInvitations, err = repo.ListUserInvitations(ctx, userNewbie.Id, "newbie", now.Add(2*time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, newbieInvitations)
+
+	err = repo.AcceptInvitation(ctx, *gotInv, userNewbie.Id, now)
+	assert.NoError(t, err)
+
+	newbieInvitations, err = repo.ListUserInvitations(ctx, userNewbie.Id, "newbie", now)
+	assert.NoError(t, err)
+	assert.Empty(t, newbieInvitations)
+
+	err = repo.AcceptInvitation(ctx, *gotInv, userNewbie.Id, now)
+	assert.ErrorIs(t, err, invitation.ErrNotPending)
+
+	newbieMember, err := repo.GetMember(ctx, teamBackend.Id, userNewbie.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, member.NormalRole, newbieMember.Role)
+
+	gotInv, err = repo.GetInvitationByTokenHash(ctx, "invite-hash-1")
+	assert.NoError(t, err)
+	assert.Equal(t, invitation.AcceptedStatus, gotInv.Status)
+	assert.Equal(t, userNewbie.Id, gotInv.InviteeUserId)
+	assert.NotNil(t, gotInv.RespondedAt)
+
+	err = repo.SetPermissionOverride(ctx, permission.Override{TeamId: teamBackend.Id, Role: member.NormalRole, Action: permission.TaskEdit, Scope: permission.AnyScope})
+	assert.NoError(t, err)
+
+	err = repo.SetPermissionOverride(ctx, permission.Override{TeamId: teamBackend.Id, Role: member.NormalRole, Action: permission.TaskEdit, Scope: permission.NoneScope})
+	assert.NoError(t, err)
+
+	overrides, err := repo.ListPermissionOverrides(ctx, teamBackend.Id)
+	assert.NoError(t, err)
+	assert.Len(t, overrides, 1)
+	assert.Equal(t, permission.NoneScope, overrides[0].Scope)
+
+	// «Мои задачи» лида: в бэкенде он owner, во фронтенде — normal.
+	leadTasks, err := repo.ListUserTasks(ctx, userLead.Id, task.AnyRelation, task.Filter{Sort: task.SortById}, 100)
+	assert.NoError(t, err)
+
+	leadTeams := map[int]bool{}
+
+	for _, lt := range leadTasks {
+		assert.True(t, lt.IsAssignee(userLead.Id) || lt.CreatorId == userLead.Id)
+
+		leadTeams[lt.TeamId] = true
+	}
+
+	assert.True(t, leadTeams[teamBackend.Id])
+	assert.True(t, leadTeams[teamFrontend.Id])
+
+	assignedToLead, err := repo.ListUserTasks(ctx, userLead.Id, task.AssigneeRelation, task.Filter{Sort: task.SortById}, 100)
+	assert.NoError(t, err)
+
+	for _, lt := range assignedToLead {
+		assert.True(t, lt.IsAssignee(userLead.Id))
+	}
+
+	// Команда, где роли пользователя запрещён просмотр задач, из выборки пропадает.
+	err = repo.SetPermissionOverride(ctx, permission.Override{TeamId: teamFrontend.Id, Role: member.NormalRole, Action: permission.TaskView, Scope: permission.NoneScope})
+	assert.NoError(t, err)
+
+	leadTasks, err = repo.ListUserTasks(ctx, userLead.Id, task.AnyRelation, task.Filter{Sort: task.SortById}, 100)
+	assert.NoError(t, err)
+
+	for _, lt := range leadTasks {
+		assert.NotEqual(t, teamFrontend.Id, lt.TeamId)
+	}
+
+	err = repo.SetPermissionOverride(ctx, permission.Override{TeamId: teamFrontend.Id, Role: member.NormalRole, Action: permission.TaskView, Scope: permission.AnyScope})
+	assert.NoError(t, err)
+
+	err = repo.CreateNotifications(ctx, []notification.Model{
+		{UserId: userDev2.Id, Kind: notification.TaskAssignedKind, ActorId: userLead.Id, TeamId: teamBackend.Id, TaskId: &task1.Id, CreatedAt: now},
+		{
+			UserId: userDev2.Id, Kind: notification.TaskStatusChangedKind, ActorId: userLead.Id, TeamId: teamBackend.Id, TaskId: &task1.Id,
+			Data: map[string]string{"from": "todo", "to": "done"}, CreatedAt: now,
+		},
+		// dev2 не состоит во фронтенд-команде — такое уведомление ему не показывается.
+		{UserId: userDev2.Id, Kind: notification.TaskAssignedKind, ActorId: userDev1.Id, TeamId: teamFrontend.Id, CreatedAt: now},
+	})
+	assert.NoError(t, err)
+
+	notifications, err := repo.ListUserNotifications(ctx, userDev2.Id, false, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, notifications, 2)
+	assert.Equal(t, notification.TaskStatusChangedKind, notifications[0].Kind)
+	assert.Equal(t, map[string]string{"from": "todo", "to": "done"}, notifications[0].Data)
+	assert.Nil(t, notifications[1].Data)
+	assert.False(t, notifications[1].IsRead())
+
+	unread, err := repo.CountUnreadNotifications(ctx, userDev2.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, unread)
+
+	assert.NoError(t, repo.MarkNotificationRead(ctx, userDev2.Id, notifications[1].Id, now))
+	assert.NoError(t, repo.MarkNotificationRead(ctx, userDev2.Id, notifications[1].Id, now))
+	assert.ErrorIs(t, repo.MarkNotificationRead(ctx, userDev1.Id, notifications[0].Id, now), sql.ErrNoRows)
+
+	notifications, err = repo.ListUserNotifications(ctx, userDev2.Id, true, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, notifications, 1)
+	assert.Equal(t, notification.TaskStatusChangedKind, notifications[0].Kind)
+
+	assert.NoError(t, repo.MarkAllNotificationsRead(ctx, userDev2.Id, now))
+
+	unread, err = repo.CountUnreadNotifications(ctx, userDev2.Id)
+	assert.NoError(t, err)
+	assert.Zero(t, unread)
+
+	// У просрочки нет автора: actor_id пишется как NULL и читается как 0.
+	err = repo.CreateNotifications(ctx, []notification.Model{
+		{UserId: userDev2.Id, Kind: notification.TaskOverdueKind, TeamId: teamBackend.Id, TaskId: &task1.Id, CreatedAt: now},
+	})
+	assert.NoError(t, err)
+
+	notifications, err = repo.ListUserNotifications(ctx, userDev2.Id, true, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, notifications, 1)
+	assert.Equal(t, notification.TaskOverdueKind, notifications[0].Kind)
+	assert.Zero(t, notifications[0].ActorId)
+
+	assert.NoError(t, repo.MarkAllNotificationsRead(ctx, userDev2.Id, now))
+
+	defaultWorkflow, err := repo.GetWorkflow(ctx, teamBackend.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, workflow.Default(teamBackend.Id), defaultWorkflow)
+
+	usedStatuses, err := repo.ListTaskStatuses(ctx, teamBackend.Id)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"todo", "in-progress", "done"}, usedStatuses)
+
+	qaWorkflow := &workflow.Model{
+		TeamId:      teamBackend.Id,
+		Statuses:    []workflow.Status{{Name: "todo"}, {Name: "in_progress"}, {Name: "qa"}, {Name: "done", Terminal: true}},
+		Transitions: []workflow.Transition{{From: "in_progress", To: "qa"}, {From: "qa", To: "done"}, {From: "todo", To: "in_progress"}},
+	}
+
+	err = repo.SetWorkflow(ctx, qaWorkflow)
+	assert.NoError(t, err)
+
+	// Повторная запись заменяет процесс целиком, а не дописывает к нему.
+	err = repo.SetWorkflow(ctx, qaWorkflow)
+	assert.NoError(t, err)
+
+	gotWorkflow, err := repo.GetWorkflow(ctx, teamBackend.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, qaWorkflow.Statuses, gotWorkflow.Statuses)
+	assert.ElementsMatch(t, qaWorkflow.Transitions, gotWorkflow.Transitions)
 }
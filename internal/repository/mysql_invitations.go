@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/team/invitation"
+)
+
+const (
+	queryInsertInvitation = `
+		INSERT INTO team_invitations (
+			team_id,
+			inviter_id,
+			invitee_user_id,
+			invitee_username,
+			invitee_email,
+			role,
+			status,
+			token_hash,
+			created_at,
+			expires_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	queryInvitationColumns = `
+		SELECT
+			id,
+			team_id,
+			inviter_id,
+			invitee_user_id,
+			invitee_username,
+			invitee_email,
+			role,
+			status,
+			token_hash,
+			created_at,
+			expires_at,
+			responded_at
+		FROM team_invitations
+	`
+
+	queryGetInvitation = queryInvitationColumns + `
+		WHERE id = ?
+	`
+
+	queryGetInvitationByTokenHash = queryInvitationColumns + `
+		WHERE token_hash = ?
+	`
+
+	// Приглашение по имени, выписанное до регистрации, находится по имени; по email — никак.
+	queryListUserInvitations = queryInvitationColumns + `
+		WHERE status = 'pending'
+			AND expires_at > ?
+			AND (invitee_user_id = ? OR (invitee_user_id IS NULL AND invitee_username = ?))
+		ORDER BY id DESC
+	`
+
+	queryUpdateInvitationStatus = `
+		UPDATE team_invitations
+		SET status = ?, responded_at = ?
+		WHERE id = ? AND status = 'pending'
+	`
+
+	queryAcceptInvitation = `
+		UPDATE team_invitations
+		SET status = 'accepted', responded_at = ?, invitee_user_id = ?
+		WHERE id = ? AND status = 'pending'
+	`
+)
+
+func (r *Mysql) CreateInvitation(
+	ctx context.Context,
+	inv invitation.Model,
+) (*invitation.Model, error) {
+	result, err := r.db.ExecContext(
+		ctx,
+		queryInsertInvitation,
+		inv.TeamId,
+		inv.InviterId,
+		sql.NullInt64{Int64: int64(inv.InviteeUserId), Valid: inv.InviteeUserId != 0},
+		sql.NullString{String: inv.InviteeUsername, Valid: inv.InviteeUsername != ""},
+		sql.NullString{String: inv.InviteeEmail, Valid: inv.InviteeEmail != ""},
+		inv.Role,
+		inv.Status,
+		inv.TokenHash,
+		inv.CreatedAt,
+		inv.ExpiresAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+
+	if err != nil {
+		return nil, err
+	}
+
+	inv.Id = int(id)
+
+	return &inv, nil
+}
+
+func (r *Mysql) GetInvitation(
+	ctx context.Context,
+	id int,
+) (*invitation.Model, error) {
+	return scanInvitation(r.db.QueryRowContext(ctx, queryGetInvitation, id))
+}
+
+func (r *Mysql) GetInvitationByTokenHash(
+	ctx context.Context,
+	hash string,
+) (*invitation.Model, error) {
+	return scanInvitation(r.db.QueryRowContext(ctx, queryGetInvitationByTokenHash, hash))
+}
+
+// ListUserInvitations возвращает действующие приглашения пользователя, новые первыми.
+func (r *Mysql) ListUserInvitations(
+	ctx context.Context,
+	userId int,
+	username string,
+	now time.Time,
+) ([]invitation.Model, error) {
+	rows, err := r.db.QueryContext(ctx, queryListUserInvitations, now, userId, username)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	invitations := []invitation.Model{}
+
+	for rows.Next() {
+		inv, err := scanInvitation(rows)
+
+		if err != nil {
+			return nil, err
+		}
+
+		invitations = append(invitations, *inv)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+func (r *Mysql) UpdateInvitationStatus(
+	ctx context.Context,
+	id int,
+	status invitation.Status,
+	respondedAt time.Time,
+) error {
+	result, err := r.db.ExecContext(ctx, queryUpdateInvitationStatus, status, respondedAt, id)
+
+	if err != nil {
+		return err
+	}
+
+	return checkInvitationPending(result)
+}
+
+func (r *Mysql) AcceptInvitation(
+	ctx context.Context,
+	inv invitation.Model,
+	userId int,
+	acceptedAt time.Time,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, queryAcceptInvitation, acceptedAt, userId, inv.Id)
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	// Токен одноразовый: второй accept того же приглашения сюда уже не дойдёт.
+	if err := checkInvitationPending(result); err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, queryInviteMember, userId, inv.TeamId, inv.Role)
+
+	if err != nil {
+		tx.Rollback()
+
+		if isDuplicateEntry(err) {
+			return apperror.Conflict("already a member")
+		}
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func checkInvitationPending(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return invitation.ErrNotPending
+	}
+
+	return nil
+}
+
+func scanInvitation(row rowScanner) (*invitation.Model, error) {
+	var inv invitation.Model
+	var inviteeUserId sql.NullInt64
+	var inviteeUsername, inviteeEmail sql.NullString
+	var respondedAt sql.NullTime
+
+	err := row.Scan(
+		&inv.Id,
+		&inv.TeamId,
+		&inv.InviterId,
+		&inviteeUserId,
+		&inviteeUsername,
+		&inviteeEmail,
+		&inv.Role,
+		&inv.Status,
+		&inv.TokenHash,
+		&inv.CreatedAt,
+		&inv.ExpiresAt,
+		&respondedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	inv.InviteeUserId = int(inviteeUserId.Int64)
+	inv.InviteeUsername = inviteeUsername.String
+	inv.InviteeEmail = inviteeEmail.String
+
+	if respondedAt.Valid {
+		inv.RespondedAt = &respondedAt.Time
+	}
+
+	return &inv, nil
+}
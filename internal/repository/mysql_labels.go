@@ -0,0 +1,325 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/history"
+	"mkk-luna-test-task/internal/team/label"
+)
+
+const (
+	queryInsertLabel = `
+		INSERT INTO team_labels (team_id, name, color)
+		VALUES (?, ?, ?)
+	`
+
+	queryListLabels = `
+		SELECT id, team_id, name, color
+		FROM team_labels
+		WHERE team_id = ?
+		ORDER BY name
+	`
+
+	queryGetLabel = `
+		SELECT id, team_id, name, color
+		FROM team_labels
+		WHERE id = ?
+	`
+
+	queryUpdateLabel = `
+		UPDATE team_labels
+		SET name = ?, color = ?
+		WHERE id = ?
+	`
+
+	queryDeleteLabel = `
+		DELETE FROM team_labels
+		WHERE id = ?
+	`
+
+	queryListLabelTaskIds = `
+		SELECT task_id
+		FROM task_labels
+		WHERE label_id = ?
+		ORDER BY task_id
+	`
+
+	queryListTaskLabels = `
+		SELECT l.id, l.team_id, l.name, l.color
+		FROM task_labels tl
+		JOIN team_labels l ON l.id = tl.label_id
+		WHERE tl.task_id = ?
+		ORDER BY l.name
+	`
+
+	queryAttachTaskLabel = `
+		INSERT IGNORE INTO task_labels (task_id, label_id)
+		VALUES (?, ?)
+	`
+
+	queryDetachTaskLabel = `
+		DELETE FROM task_labels
+		WHERE task_id = ? AND label_id = ?
+	`
+)
+
+func (r *Mysql) CreateLabel(
+	ctx context.Context,
+	l label.Model,
+) (*label.Model, error) {
+	result, err := r.db.ExecContext(ctx, queryInsertLabel, l.TeamId, l.Name, l.Color)
+
+	if isDuplicateEntry(err) {
+		return nil, label.ErrDuplicate
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+
+	if err != nil {
+		return nil, err
+	}
+
+	l.Id = int(id)
+
+	return &l, nil
+}
+
+func (r *Mysql) ListLabels(
+	ctx context.Context,
+	teamId int,
+) ([]label.Model, error) {
+	rows, err := r.db.QueryContext(ctx, queryListLabels, teamId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanLabels(rows)
+}
+
+func (r *Mysql) GetLabel(
+	ctx context.Context,
+	id int,
+) (*label.Model, error) {
+	var l label.Model
+
+	err := r.db.QueryRowContext(ctx, queryGetLabel, id).Scan(&l.Id, &l.TeamId, &l.Name, &l.Color)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &l, nil
+}
+
+func (r *Mysql) UpdateLabel(
+	ctx context.Context,
+	l label.Model,
+) error {
+	_, err := r.db.ExecContext(ctx, queryUpdateLabel, l.Name, l.Color, l.Id)
+
+	if isDuplicateEntry(err) {
+		return label.ErrDuplicate
+	}
+
+	return err
+}
+
+// DeleteLabel удаляет метку из каталога и снимает её со всех задач, записывая снятие в историю каждой.
+func (r *Mysql) DeleteLabel(
+	ctx context.Context,
+	l label.Model,
+	deletedBy int,
+	deletedAt time.Time,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, queryListLabelTaskIds, l.Id)
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	var taskIds []int
+
+	for rows.Next() {
+		var id int
+
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			tx.Rollback()
+
+			return err
+		}
+
+		taskIds = append(taskIds, id)
+	}
+
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	for _, id := range taskIds {
+		t, err := lockTask(ctx, tx, id, 0)
+
+		if err != nil {
+			tx.Rollback()
+
+			return err
+		}
+
+		if err := insertTaskHistoryWithLabel(ctx, tx, t, history.UnlabelEvent, &l.Name, deletedBy, deletedAt); err != nil {
+			tx.Rollback()
+
+			return err
+		}
+	}
+
+	// Связи с задачами удаляются каскадом.
+	_, err = tx.ExecContext(ctx, queryDeleteLabel, l.Id)
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *Mysql) ListTaskLabels(
+	ctx context.Context,
+	taskId int,
+) ([]label.Model, error) {
+	rows, err := r.db.QueryContext(ctx, queryListTaskLabels, taskId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanLabels(rows)
+}
+
+// AttachTaskLabel вешает метку на задачу. Повторная установка ничего не меняет и в историю не пишется.
+// Метки не меняют версию задачи, как и комментарии.
+func (r *Mysql) AttachTaskLabel(
+	ctx context.Context,
+	taskId int,
+	l label.Model,
+	changedBy int,
+	changedAt time.Time,
+) error {
+	return r.changeTaskLabel(ctx, queryAttachTaskLabel, history.LabelEvent, taskId, l, changedBy, changedAt)
+}
+
+// DetachTaskLabel снимает метку с задачи. Снятие отсутствующей метки ничего не меняет.
+func (r *Mysql) DetachTaskLabel(
+	ctx context.Context,
+	taskId int,
+	l label.Model,
+	changedBy int,
+	changedAt time.Time,
+) error {
+	return r.changeTaskLabel(ctx, queryDetachTaskLabel, history.UnlabelEvent, taskId, l, changedBy, changedAt)
+}
+
+func (r *Mysql) changeTaskLabel(
+	ctx context.Context,
+	query string,
+	event history.Event,
+	taskId int,
+	l label.Model,
+	changedBy int,
+	changedAt time.Time,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	t, err := lockTask(ctx, tx, taskId, 0)
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	if t.IsArchived() {
+		tx.Rollback()
+
+		return task.ErrArchived
+	}
+
+	result, err := tx.ExecContext(ctx, query, taskId, l.Id)
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	if affected == 0 {
+		tx.Rollback()
+
+		return nil
+	}
+
+	if err := insertTaskHistoryWithLabel(ctx, tx, t, event, &l.Name, changedBy, changedAt); err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func scanLabels(rows *sql.Rows) ([]label.Model, error) {
+	labels := []label.Model{}
+
+	for rows.Next() {
+		var l label.Model
+
+		if err := rows.Scan(&l.Id, &l.TeamId, &l.Name, &l.Color); err != nil {
+			return nil, err
+		}
+
+		labels = append(labels, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
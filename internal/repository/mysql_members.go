@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"mkk-luna-test-task/internal/team/member"
+)
+
+const (
+	queryListMembers = `
+		SELECT tm.user_id, tm.team_id, tm.role, u.username
+		FROM team_members tm
+		INNER JOIN users u ON u.id = tm.user_id
+		WHERE tm.team_id = ?
+		ORDER BY tm.id ASC
+	`
+
+	queryGetMember = `
+		SELECT tm.user_id, tm.team_id, tm.role, u.username
+		FROM team_members tm
+		INNER JOIN users u ON u.id = tm.user_id
+		WHERE tm.team_id = ? AND tm.user_id = ?
+	`
+
+	queryUpdateMemberRole = `
+		UPDATE team_members
+		SET role = ?
+		WHERE team_id = ? AND user_id = ?
+	`
+
+	queryRemoveMember = `
+		DELETE FROM team_members
+		WHERE team_id = ? AND user_id = ?
+	`
+
+	queryCountOwners = `
+		SELECT COUNT(*) FROM team_members WHERE team_id = ? AND role = 'owner'
+	`
+)
+
+func (r *Mysql) ListMembers(
+	ctx context.Context,
+	teamId int,
+) ([]*member.Model, error) {
+	rows, err := r.db.QueryContext(ctx, queryListMembers, teamId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	members := make([]*member.Model, 0)
+
+	for rows.Next() {
+		var m member.Model
+
+		if err := rows.Scan(&m.UserId, &m.TeamId, &m.Role, &m.Username); err != nil {
+			return nil, err
+		}
+
+		members = append(members, &m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (r *Mysql) GetMember(
+	ctx context.Context,
+	teamId, userId int,
+) (*member.Model, error) {
+	var m member.Model
+
+	err := r.db.QueryRowContext(ctx, queryGetMember, teamId, userId).Scan(&m.UserId, &m.TeamId, &m.Role, &m.Username)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+func (r *Mysql) UpdateMemberRole(
+	ctx context.Context,
+	teamId, userId int,
+	role member.Role,
+) error {
+	result, err := r.db.ExecContext(ctx, queryUpdateMemberRole, role, teamId, userId)
+
+	if err != nil {
+		return err
+	}
+
+	return checkMemberAffected(result)
+}
+
+func (r *Mysql) RemoveMember(
+	ctx context.Context,
+	teamId, userId int,
+) error {
+	result, err := r.db.ExecContext(ctx, queryRemoveMember, teamId, userId)
+
+	if err != nil {
+		return err
+	}
+
+	return checkMemberAffected(result)
+}
+
+func (r *Mysql) CountOwners(
+	ctx context.Context,
+	teamId int,
+) (int, error) {
+	count := 0
+
+	err := r.db.QueryRowContext(ctx, queryCountOwners, teamId).Scan(&count)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *Mysql) TransferOwnership(
+	ctx context.Context,
+	teamId, fromUserId, toUserId int,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, queryUpdateMemberRole, member.OwnerRole, teamId, toUserId)
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	if err := checkMemberAffected(result); err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	// Бывший владелец остаётся в команде админом, чтобы не терять доступ целиком.
+	result, err = tx.ExecContext(ctx, queryUpdateMemberRole, member.AdminRole, teamId, fromUserId)
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	if err := checkMemberAffected(result); err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func checkMemberAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
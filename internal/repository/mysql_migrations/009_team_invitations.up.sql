@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS team_invitations (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    team_id INT NOT NULL,
+    inviter_id INT NOT NULL,
+    invitee_user_id INT NULL,
+    invitee_username VARCHAR(255) NULL, -- аккаунта с таким именем ещё может не быть.
+    invitee_email VARCHAR(255) NULL,
+    role TEXT NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    responded_at DATETIME NULL,
+    FOREIGN KEY (team_id) REFERENCES teams(id),
+    FOREIGN KEY (inviter_id) REFERENCES users(id),
+    FOREIGN KEY (invitee_user_id) REFERENCES users(id)
+);
+
+CREATE INDEX idx_team_invitations_team_status ON team_invitations(team_id, status);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS team_permission_overrides (
+    team_id INT NOT NULL,
+    role VARCHAR(16) NOT NULL,
+    action VARCHAR(64) NOT NULL,
+    scope VARCHAR(8) NOT NULL, -- none, own или any.
+    PRIMARY KEY (team_id, role, action),
+    FOREIGN KEY (team_id) REFERENCES teams(id)
+);
@@ -0,0 +1,22 @@
+ALTER TABLE tasks MODIFY status VARCHAR(64) NOT NULL;
+
+CREATE INDEX idx_tasks_team_status ON tasks(team_id, status);
+
+-- Пока у команды нет строк в этих таблицах, действует процесс по умолчанию: todo -> in_progress -> done.
+CREATE TABLE IF NOT EXISTS team_workflow_statuses (
+    team_id INT NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    position INT NOT NULL,
+    terminal BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (team_id, name),
+    FOREIGN KEY (team_id) REFERENCES teams(id)
+);
+
+CREATE TABLE IF NOT EXISTS team_workflow_transitions (
+    team_id INT NOT NULL,
+    from_status VARCHAR(64) NOT NULL,
+    to_status VARCHAR(64) NOT NULL,
+    PRIMARY KEY (team_id, from_status, to_status),
+    FOREIGN KEY (team_id, from_status) REFERENCES team_workflow_statuses(team_id, name) ON DELETE CASCADE,
+    FOREIGN KEY (team_id, to_status) REFERENCES team_workflow_statuses(team_id, name) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+-- Версия растёт на каждое изменение задачи и отдаётся клиенту как ETag.
+ALTER TABLE tasks ADD COLUMN version INT NOT NULL DEFAULT 1;
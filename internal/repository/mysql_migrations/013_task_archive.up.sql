@@ -0,0 +1,7 @@
+-- Архивная задача пропадает с доски, но её история и комментарии остаются доступны.
+ALTER TABLE tasks ADD COLUMN archived_at DATETIME NULL;
+
+CREATE INDEX idx_tasks_team_archived_id ON tasks(team_id, archived_at, id);
+
+-- Что произошло с задачей: правка (edit), архивация (archive) или восстановление (restore).
+ALTER TABLE task_history ADD COLUMN event VARCHAR(16) NOT NULL DEFAULT 'edit';
@@ -0,0 +1,6 @@
+-- Время последнего изменения задачи; для уже существующих задач совпадает со временем создания.
+ALTER TABLE tasks ADD COLUMN updated_at DATETIME NULL;
+
+UPDATE tasks SET updated_at = created_at;
+
+ALTER TABLE tasks MODIFY COLUMN updated_at DATETIME NOT NULL;
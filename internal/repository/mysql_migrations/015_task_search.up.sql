@@ -0,0 +1,6 @@
+-- Поиск по названию и описанию задачи.
+ALTER TABLE tasks ADD FULLTEXT INDEX ft_tasks_title_description (title, description);
+
+-- Сортировки доски по времени создания и изменения.
+CREATE INDEX idx_tasks_team_created ON tasks(team_id, created_at, id);
+CREATE INDEX idx_tasks_team_updated ON tasks(team_id, updated_at, id);
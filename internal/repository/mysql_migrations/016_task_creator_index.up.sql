@@ -0,0 +1,2 @@
+-- Выборка «мои задачи» ищет задачи и по исполнителю, и по создателю.
+CREATE INDEX idx_tasks_creator_id ON tasks(creator_id);
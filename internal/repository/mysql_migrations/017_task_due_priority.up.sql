@@ -0,0 +1,13 @@
+-- Срок и приоритет задачи. Приоритет — число от -1 (low) до 2 (urgent), 0 — обычный.
+ALTER TABLE tasks
+    ADD COLUMN due_at DATETIME NULL,
+    ADD COLUMN priority TINYINT NOT NULL DEFAULT 0,
+    -- Когда о просрочке уже уведомили; сбрасывается при смене срока.
+    ADD COLUMN overdue_notified_at DATETIME NULL;
+
+CREATE INDEX idx_tasks_due_notified ON tasks(due_at, overdue_notified_at);
+CREATE INDEX idx_tasks_team_priority ON tasks(team_id, priority, id);
+
+ALTER TABLE task_history
+    ADD COLUMN due_at DATETIME NULL,
+    ADD COLUMN priority TINYINT NOT NULL DEFAULT 0;
@@ -0,0 +1,21 @@
+-- Каталог меток команды: имя уникально в пределах команды.
+CREATE TABLE IF NOT EXISTS team_labels (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    team_id INT NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    color CHAR(7) NOT NULL,
+    UNIQUE KEY uq_team_labels_name (team_id, name),
+    FOREIGN KEY (team_id) REFERENCES teams(id)
+);
+
+CREATE TABLE IF NOT EXISTS task_labels (
+    task_id INT NOT NULL,
+    label_id INT NOT NULL,
+    PRIMARY KEY (task_id, label_id),
+    INDEX idx_task_labels_label (label_id),
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    FOREIGN KEY (label_id) REFERENCES team_labels(id) ON DELETE CASCADE
+);
+
+-- Для событий label и unlabel — имя метки, которую повесили или сняли.
+ALTER TABLE task_history ADD COLUMN label VARCHAR(64) NULL;
@@ -0,0 +1,18 @@
+-- У задачи не больше одного родителя, поэтому ключ — сама подзадача.
+CREATE TABLE IF NOT EXISTS task_parents (
+    task_id INT NOT NULL PRIMARY KEY,
+    parent_id INT NOT NULL,
+    INDEX idx_task_parents_parent (parent_id),
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+-- blocker_id блокирует task_id: task_id нельзя завершить, пока blocker_id не завершена.
+CREATE TABLE IF NOT EXISTS task_blockers (
+    task_id INT NOT NULL,
+    blocker_id INT NOT NULL,
+    PRIMARY KEY (task_id, blocker_id),
+    INDEX idx_task_blockers_blocker (blocker_id),
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    FOREIGN KEY (blocker_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
@@ -0,0 +1,33 @@
+-- Исполнителей у задачи может быть несколько или ни одного.
+CREATE TABLE IF NOT EXISTS task_assignees (
+    task_id INT NOT NULL,
+    user_id INT NOT NULL,
+    PRIMARY KEY (task_id, user_id),
+    INDEX idx_task_assignees_user (user_id, task_id),
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- Наблюдатели сами подписываются на задачу и отписываются от неё.
+CREATE TABLE IF NOT EXISTS task_watchers (
+    task_id INT NOT NULL,
+    user_id INT NOT NULL,
+    PRIMARY KEY (task_id, user_id),
+    INDEX idx_task_watchers_user (user_id, task_id),
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+INSERT INTO task_assignees (task_id, user_id)
+SELECT id, assignee_id FROM tasks;
+
+-- Внешние ключи в 003_tasks и 005_task_history объявлены без имён, MySQL пронумеровал их по порядку объявления.
+ALTER TABLE tasks DROP FOREIGN KEY tasks_ibfk_2;
+ALTER TABLE tasks DROP COLUMN assignee_id;
+
+-- История хранит исполнителей на момент изменения списком id.
+ALTER TABLE task_history ADD COLUMN assignee_ids JSON NULL;
+UPDATE task_history SET assignee_ids = JSON_ARRAY(assignee_id);
+ALTER TABLE task_history MODIFY assignee_ids JSON NOT NULL;
+ALTER TABLE task_history DROP FOREIGN KEY task_history_ibfk_3;
+ALTER TABLE task_history DROP COLUMN assignee_id;
@@ -0,0 +1,17 @@
+-- Правленый комментарий помнит время последней правки, удалённый остаётся в списке заглушкой.
+ALTER TABLE task_comments ADD COLUMN updated_at DATETIME NULL;
+ALTER TABLE task_comments ADD COLUMN deleted_at DATETIME NULL;
+ALTER TABLE task_comments ADD COLUMN deleted_by INT NULL;
+ALTER TABLE task_comments ADD FOREIGN KEY (deleted_by) REFERENCES users(id);
+
+-- Прежние тексты комментария: каждая правка сохраняет текст, который был до неё.
+CREATE TABLE IF NOT EXISTS task_comment_versions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    comment_id INT NOT NULL,
+    text TEXT NOT NULL,
+    edited_by INT NOT NULL,
+    edited_at DATETIME NOT NULL,
+    INDEX idx_task_comment_versions_comment (comment_id, id),
+    FOREIGN KEY (comment_id) REFERENCES task_comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (edited_by) REFERENCES users(id)
+);
@@ -0,0 +1,13 @@
+-- Ответ в ветке ссылается на комментарий той же задачи.
+ALTER TABLE task_comments ADD COLUMN parent_comment_id INT NULL;
+ALTER TABLE task_comments ADD FOREIGN KEY (parent_comment_id) REFERENCES task_comments(id) ON DELETE CASCADE;
+
+-- Упоминания @username, найденные среди участников команды задачи.
+CREATE TABLE IF NOT EXISTS task_comment_mentions (
+    comment_id INT NOT NULL,
+    user_id INT NOT NULL,
+    PRIMARY KEY (comment_id, user_id),
+    INDEX idx_task_comment_mentions_user (user_id, comment_id),
+    FOREIGN KEY (comment_id) REFERENCES task_comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
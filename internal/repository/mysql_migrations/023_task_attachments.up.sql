@@ -0,0 +1,18 @@
+-- Вложения задач и комментариев. Само содержимое лежит в хранилище по storage_key, здесь только описание.
+CREATE TABLE IF NOT EXISTS task_attachments (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    task_id INT NOT NULL,
+    comment_id INT NULL,
+    uploader_id INT NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    checksum_sha256 CHAR(64) NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL,
+    UNIQUE KEY uq_task_attachments_storage_key (storage_key),
+    INDEX idx_task_attachments_task (task_id, id),
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    FOREIGN KEY (comment_id) REFERENCES task_comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (uploader_id) REFERENCES users(id)
+);
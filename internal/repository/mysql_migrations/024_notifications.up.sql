@@ -0,0 +1,22 @@
+-- Уведомления пользователей. Удаляются вместе с задачей, комментарием или приглашением, о которых они.
+CREATE TABLE IF NOT EXISTS notifications (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    actor_id INT NULL, -- NULL — событие без автора, например просрочка.
+    team_id INT NOT NULL,
+    task_id INT NULL,
+    comment_id INT NULL,
+    invitation_id INT NULL,
+    data JSON NULL,
+    created_at DATETIME NOT NULL,
+    read_at DATETIME NULL,
+    INDEX idx_notifications_user (user_id, id),
+    INDEX idx_notifications_user_unread (user_id, read_at),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (actor_id) REFERENCES users(id),
+    FOREIGN KEY (team_id) REFERENCES teams(id),
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    FOREIGN KEY (comment_id) REFERENCES task_comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (invitation_id) REFERENCES team_invitations(id) ON DELETE CASCADE
+);
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"mkk-luna-test-task/internal/user/notification"
+)
+
+const (
+	notificationColumns = `n.id, n.user_id, n.kind, n.actor_id, n.team_id, n.task_id, n.comment_id, n.invitation_id, n.data, n.created_at, n.read_at`
+
+	// Уведомления команды, из которой пользователь ушёл, скрыты; приглашения адресованы ещё не участникам.
+	visibleNotificationCondition = `(n.invitation_id IS NOT NULL OR EXISTS (
+		SELECT 1 FROM team_members tm WHERE tm.team_id = n.team_id AND tm.user_id = n.user_id
+	))`
+
+	queryInsertNotifications = `
+		INSERT INTO notifications (user_id, kind, actor_id, team_id, task_id, comment_id, invitation_id, data, created_at)
+		VALUES %s
+	`
+
+	// Сначала идут свежие, start_from_id продолжает список с уведомлений старше него.
+	queryListUserNotifications = `
+		SELECT ` + notificationColumns + `
+		FROM notifications n
+		WHERE n.user_id = ?
+			AND ` + visibleNotificationCondition + `
+			AND (? = FALSE OR n.read_at IS NULL)
+			AND (? = 0 OR n.id < ?)
+		ORDER BY n.id DESC
+		LIMIT ?
+	`
+
+	queryCountUnreadNotifications = `
+		SELECT COUNT(*)
+		FROM notifications n
+		WHERE n.user_id = ?
+			AND n.read_at IS NULL
+			AND ` + visibleNotificationCondition + `
+	`
+
+	queryMarkNotificationRead = `
+		UPDATE notifications
+		SET read_at = ?
+		WHERE id = ? AND user_id = ? AND read_at IS NULL
+	`
+
+	queryNotificationExists = `
+		SELECT 1
+		FROM notifications
+		WHERE id = ? AND user_id = ?
+	`
+
+	queryMarkAllNotificationsRead = `
+		UPDATE notifications
+		SET read_at = ?
+		WHERE user_id = ? AND read_at IS NULL
+	`
+)
+
+// notificationData читает и пишет подробности уведомления как JSON-объект; пустые подробности — NULL.
+type notificationData map[string]string
+
+func (d *notificationData) Scan(src any) error {
+	var raw []byte
+
+	switch v := src.(type) {
+	case nil:
+		*d = nil
+
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return fmt.Errorf("unsupported notification data type %T", src)
+	}
+
+	return json.Unmarshal(raw, (*map[string]string)(d))
+}
+
+func (d notificationData) Value() (driver.Value, error) {
+	if len(d) == 0 {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(map[string]string(d))
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Строкой, а не []byte: двоичное значение MySQL не принимает в JSON-колонку.
+	return string(b), nil
+}
+
+func scanNotification(row rowScanner) (*notification.Model, error) {
+	var n notification.Model
+	var actorId sql.NullInt64
+	var data notificationData
+
+	if err := row.Scan(
+		&n.Id,
+		&n.UserId,
+		&n.Kind,
+		&actorId,
+		&n.TeamId,
+		&n.TaskId,
+		&n.CommentId,
+		&n.InvitationId,
+		&data,
+		&n.CreatedAt,
+		&n.ReadAt,
+	); err != nil {
+		return nil, err
+	}
+
+	n.ActorId = int(actorId.Int64)
+	n.Data = data
+
+	return &n, nil
+}
+
+// CreateNotifications сохраняет уведомления одним запросом.
+func (r *Mysql) CreateNotifications(
+	ctx context.Context,
+	notifications []notification.Model,
+) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(notifications))
+	args := make([]any, 0, len(notifications)*9)
+
+	for _, n := range notifications {
+		values = append(values, "("+placeholders(9)+")")
+		actorId := sql.NullInt64{Int64: int64(n.ActorId), Valid: n.ActorId != 0}
+
+		args = append(args, n.UserId, n.Kind, actorId, n.TeamId, n.TaskId, n.CommentId, n.InvitationId, notificationData(n.Data), n.CreatedAt)
+	}
+
+	_, err := r.db.ExecContext(ctx, fmt.Sprintf(queryInsertNotifications, strings.Join(values, ", ")), args...)
+
+	return err
+}
+
+// ListUserNotifications возвращает уведомления пользователя от новых к старым; unreadOnly — только непрочитанные.
+func (r *Mysql) ListUserNotifications(
+	ctx context.Context,
+	userId int,
+	unreadOnly bool,
+	startFromId int,
+	limit int,
+) ([]notification.Model, error) {
+	rows, err := r.db.QueryContext(ctx, queryListUserNotifications, userId, unreadOnly, startFromId, startFromId, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	notifications := []notification.Model{}
+
+	for rows.Next() {
+		n, err := scanNotification(rows)
+
+		if err != nil {
+			return nil, err
+		}
+
+		notifications = append(notifications, *n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (r *Mysql) CountUnreadNotifications(
+	ctx context.Context,
+	userId int,
+) (int, error) {
+	var count int
+
+	err := r.db.QueryRowContext(ctx, queryCountUnreadNotifications, userId).Scan(&count)
+
+	return count, err
+}
+
+// MarkNotificationRead отмечает уведомление пользователя прочитанным. Повторная отметка не меняет время прочтения.
+// Чужое или несуществующее уведомление — sql.ErrNoRows.
+func (r *Mysql) MarkNotificationRead(
+	ctx context.Context,
+	userId, id int,
+	readAt time.Time,
+) error {
+	res, err := r.db.ExecContext(ctx, queryMarkNotificationRead, readAt, id, userId)
+
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if affected > 0 {
+		return nil
+	}
+
+	var exists int
+
+	return r.db.QueryRowContext(ctx, queryNotificationExists, id, userId).Scan(&exists)
+}
+
+func (r *Mysql) MarkAllNotificationsRead(
+	ctx context.Context,
+	userId int,
+	readAt time.Time,
+) error {
+	_, err := r.db.ExecContext(ctx, queryMarkAllNotificationsRead, readAt, userId)
+
+	return err
+}
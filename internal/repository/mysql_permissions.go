@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+const (
+	queryListPermissionOverrides = `
+		SELECT team_id, role, action, scope
+		FROM team_permission_overrides
+		WHERE team_id = ?
+	`
+
+	queryUpsertPermissionOverride = `
+		INSERT INTO team_permission_overrides (team_id, role, action, scope)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE scope = VALUES(scope)
+	`
+)
+
+func (r *Mysql) ListPermissionOverrides(
+	ctx context.Context,
+	teamId int,
+) ([]permission.Override, error) {
+	rows, err := r.db.QueryContext(ctx, queryListPermissionOverrides, teamId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var overrides []permission.Override
+
+	for rows.Next() {
+		var o permission.Override
+
+		if err := rows.Scan(&o.TeamId, &o.Role, &o.Action, &o.Scope); err != nil {
+			return nil, err
+		}
+
+		overrides = append(overrides, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return overrides, nil
+}
+
+func (r *Mysql) SetPermissionOverride(
+	ctx context.Context,
+	o permission.Override,
+) error {
+	_, err := r.db.ExecContext(ctx, queryUpsertPermissionOverride, o.TeamId, o.Role, o.Action, o.Scope)
+
+	return err
+}
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"mkk-luna-test-task/internal/user/session"
+)
+
+const (
+	queryInsertSession = `
+		INSERT INTO user_sessions (id, user_id, access_token_id, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	queryInsertRefreshToken = `
+		INSERT INTO refresh_tokens (token_hash, session_id, created_at)
+		VALUES (?, ?, ?)
+	`
+
+	queryGetSession = `
+		SELECT id, user_id, access_token_id, created_at, expires_at, revoked_at
+		FROM user_sessions
+		WHERE id = ?
+	`
+
+	queryGetRefreshToken = `
+		SELECT token_hash, session_id, created_at, used_at
+		FROM refresh_tokens
+		WHERE token_hash = ?
+	`
+
+	queryMarkRefreshTokenUsed = `
+		UPDATE refresh_tokens
+		SET used_at = ?
+		WHERE token_hash = ? AND session_id = ? AND used_at IS NULL
+	`
+
+	queryUpdateSessionAccessToken = `
+		UPDATE user_sessions
+		SET access_token_id = ?
+		WHERE id = ? AND revoked_at IS NULL
+	`
+
+	queryRevokeSession = `
+		UPDATE user_sessions
+		SET revoked_at = ?
+		WHERE id = ? AND revoked_at IS NULL
+	`
+
+	queryIsAccessTokenActive = `
+		SELECT EXISTS(
+			SELECT 1
+			FROM user_sessions
+			WHERE id = ? AND access_token_id = ? AND revoked_at IS NULL AND expires_at > ?
+			LIMIT 1
+		)
+	`
+)
+
+func (r *Mysql) CreateSession(
+	ctx context.Context,
+	s session.Model,
+	refreshTokenHash string,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, queryInsertSession, s.Id, s.UserId, s.AccessTokenId, s.CreatedAt, s.ExpiresAt)
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, queryInsertRefreshToken, refreshTokenHash, s.Id, s.CreatedAt)
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *Mysql) GetSession(
+	ctx context.Context,
+	id string,
+) (*session.Model, error) {
+	var s session.Model
+	var revokedAt sql.NullTime
+
+	err := r.db.QueryRowContext(ctx, queryGetSession, id).Scan(
+		&s.Id,
+		&s.UserId,
+		&s.AccessTokenId,
+		&s.CreatedAt,
+		&s.ExpiresAt,
+		&revokedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if revokedAt.Valid {
+		s.RevokedAt = &revokedAt.Time
+	}
+
+	return &s, nil
+}
+
+func (r *Mysql) GetRefreshToken(
+	ctx context.Context,
+	hash string,
+) (*session.RefreshToken, error) {
+	var t session.RefreshToken
+	var usedAt sql.NullTime
+
+	err := r.db.QueryRowContext(ctx, queryGetRefreshToken, hash).Scan(
+		&t.Hash,
+		&t.SessionId,
+		&t.CreatedAt,
+		&usedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if usedAt.Valid {
+		t.UsedAt = &usedAt.Time
+	}
+
+	return &t, nil
+}
+
+func (r *Mysql) RotateRefreshToken(
+	ctx context.Context,
+	sessionId string,
+	oldHash, newHash string,
+	accessTokenId string,
+	now time.Time,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, queryMarkRefreshTokenUsed, now, oldHash, sessionId)
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	// Параллельный запрос успел использовать этот же токен раньше нас.
+	if rowsAffected == 0 {
+		tx.Rollback()
+
+		return session.ErrRefreshTokenReused
+	}
+
+	_, err = tx.ExecContext(ctx, queryInsertRefreshToken, newHash, sessionId, now)
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	result, err = tx.ExecContext(ctx, queryUpdateSessionAccessToken, accessTokenId, sessionId)
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	rowsAffected, err = result.RowsAffected()
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	if rowsAffected == 0 {
+		tx.Rollback()
+
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+func (r *Mysql) RevokeSession(
+	ctx context.Context,
+	sessionId string,
+	revokedAt time.Time,
+) error {
+	_, err := r.db.ExecContext(ctx, queryRevokeSession, revokedAt, sessionId)
+
+	return err
+}
+
+func (r *Mysql) IsAccessTokenActive(
+	ctx context.Context,
+	sessionId, tokenId string,
+	now time.Time,
+) (bool, error) {
+	active := false
+
+	err := r.db.QueryRowContext(ctx, queryIsAccessTokenActive, sessionId, tokenId, now).Scan(&active)
+
+	if err != nil {
+		return false, err
+	}
+
+	return active, nil
+}
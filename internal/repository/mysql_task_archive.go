@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/history"
+)
+
+const (
+	queryArchiveTask = `
+		UPDATE tasks
+		SET archived_at = ?, updated_at = ?, version = version + 1
+		WHERE id = ?
+	`
+
+	queryRestoreTask = `
+		UPDATE tasks
+		SET archived_at = NULL, updated_at = ?, version = version + 1
+		WHERE id = ?
+	`
+
+	queryPurgeTaskComments = `
+		DELETE FROM task_comments
+		WHERE task_id = ?
+	`
+
+	queryPurgeTaskHistory = `
+		DELETE FROM task_history
+		WHERE task_id = ?
+	`
+
+	queryPurgeTask = `
+		DELETE FROM tasks
+		WHERE id = ? AND archived_at IS NOT NULL
+	`
+)
+
+// ArchiveTask убирает задачу с доски, записывая событие в историю.
+// expectedVersion == 0 означает, что версия не проверяется.
+func (r *Mysql) ArchiveTask(
+	ctx context.Context,
+	id int,
+	expectedVersion int,
+	archivedBy int,
+	archivedAt time.Time,
+) (*task.Model, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := lockTask(ctx, tx, id, expectedVersion)
+
+	if err != nil {
+		tx.Rollback()
+
+		return nil, err
+	}
+
+	if t.IsArchived() {
+		tx.Rollback()
+
+		return nil, task.ErrArchived
+	}
+
+	if err := insertTaskHistory(ctx, tx, t, history.ArchiveEvent, archivedBy, archivedAt); err != nil {
+		tx.Rollback()
+
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, queryArchiveTask, archivedAt, archivedAt, id)
+
+	if err != nil {
+		tx.Rollback()
+
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	t.ArchivedAt = &archivedAt
+	t.UpdatedAt = archivedAt
+	t.Version++
+
+	return t, nil
+}
+
+// RestoreTask возвращает архивную задачу на доску, записывая событие в историю.
+func (r *Mysql) RestoreTask(
+	ctx context.Context,
+	id int,
+	expectedVersion int,
+	restoredBy int,
+	restoredAt time.Time,
+) (*task.Model, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := lockTask(ctx, tx, id, expectedVersion)
+
+	if err != nil {
+		tx.Rollback()
+
+		return nil, err
+	}
+
+	if !t.IsArchived() {
+		tx.Rollback()
+
+		return nil, task.ErrNotArchived
+	}
+
+	if err := insertTaskHistory(ctx, tx, t, history.RestoreEvent, restoredBy, restoredAt); err != nil {
+		tx.Rollback()
+
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, queryRestoreTask, restoredAt, id)
+
+	if err != nil {
+		tx.Rollback()
+
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	t.ArchivedAt = nil
+	t.UpdatedAt = restoredAt
+	t.Version++
+
+	return t, nil
+}
+
+// PurgeTask удаляет архивную задачу навсегда вместе с её историей и комментариями.
+func (r *Mysql) PurgeTask(
+	ctx context.Context,
+	id int,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	t, err := lockTask(ctx, tx, id, 0)
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	// Удалять можно только из архива: так случайный DELETE не сотрёт живую задачу.
+	if !t.IsArchived() {
+		tx.Rollback()
+
+		return task.ErrNotArchived
+	}
+
+	for _, query := range []string{queryPurgeTaskComments, queryPurgeTaskHistory, queryPurgeTask} {
+		_, err = tx.ExecContext(ctx, query, id)
+
+		if err != nil {
+			tx.Rollback()
+
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+const (
+	// Исполнители задачи t одной колонкой: JSON-массив id или NULL, если их нет.
+	assigneeIdsColumn = "(SELECT JSON_ARRAYAGG(a.user_id) FROM task_assignees a WHERE a.task_id = t.id)"
+
+	isAssigneeCondition = "EXISTS (SELECT 1 FROM task_assignees a WHERE a.task_id = t.id AND a.user_id = ?)"
+
+	queryInsertTaskWatcher = `
+		INSERT IGNORE INTO task_watchers (task_id, user_id)
+		VALUES (?, ?)
+	`
+
+	queryDeleteTaskWatcher = `
+		DELETE FROM task_watchers
+		WHERE task_id = ? AND user_id = ?
+	`
+
+	queryListTaskWatchers = `
+		SELECT user_id
+		FROM task_watchers
+		WHERE task_id = ?
+		ORDER BY user_id
+	`
+)
+
+// idList читает и пишет список id как JSON-массив. Прочитанный список отсортирован и не nil.
+type idList []int
+
+func (l *idList) Scan(src any) error {
+	ids := []int{}
+
+	switch v := src.(type) {
+	case nil:
+	case []byte:
+		if err := json.Unmarshal(v, &ids); err != nil {
+			return err
+		}
+	case string:
+		if err := json.Unmarshal([]byte(v), &ids); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported id list type %T", src)
+	}
+
+	slices.Sort(ids)
+
+	*l = ids
+
+	return nil
+}
+
+func (l idList) Value() (driver.Value, error) {
+	// nil-срез json.Marshal превратил бы в null.
+	if l == nil {
+		return "[]", nil
+	}
+
+	b, err := json.Marshal([]int(l))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+func insertTaskAssignees(ctx context.Context, tx *sql.Tx, taskId int, assigneeIds []int) error {
+	for _, userId := range assigneeIds {
+		if _, err := tx.ExecContext(ctx, queryInsertTaskAssignee, taskId, userId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func replaceTaskAssignees(ctx context.Context, tx *sql.Tx, taskId int, assigneeIds []int) error {
+	if _, err := tx.ExecContext(ctx, queryDeleteTaskAssignees, taskId); err != nil {
+		return err
+	}
+
+	return insertTaskAssignees(ctx, tx, taskId, assigneeIds)
+}
+
+// AddTaskWatcher подписывает пользователя на задачу. Повторная подписка ничего не меняет.
+func (r *Mysql) AddTaskWatcher(
+	ctx context.Context,
+	taskId, userId int,
+) error {
+	_, err := r.db.ExecContext(ctx, queryInsertTaskWatcher, taskId, userId)
+
+	return err
+}
+
+// RemoveTaskWatcher отписывает пользователя от задачи. Отписка без подписки — не ошибка.
+func (r *Mysql) RemoveTaskWatcher(
+	ctx context.Context,
+	taskId, userId int,
+) error {
+	_, err := r.db.ExecContext(ctx, queryDeleteTaskWatcher, taskId, userId)
+
+	return err
+}
+
+// ListTaskWatchers возвращает id наблюдателей задачи по возрастанию.
+func (r *Mysql) ListTaskWatchers(
+	ctx context.Context,
+	taskId int,
+) ([]int, error) {
+	rows, err := r.db.QueryContext(ctx, queryListTaskWatchers, taskId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	watchers := []int{}
+
+	for rows.Next() {
+		var userId int
+
+		if err := rows.Scan(&userId); err != nil {
+			return nil, err
+		}
+
+		watchers = append(watchers, userId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return watchers, nil
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+
+	"mkk-luna-test-task/internal/task/attachment"
+)
+
+const (
+	attachmentColumns = `a.id, a.task_id, a.comment_id, a.uploader_id, a.file_name, a.content_type, a.size, a.checksum_sha256, a.storage_key, a.created_at`
+
+	// Вложения удалённого комментария скрыты вместе с его текстом.
+	visibleAttachmentCondition = `(a.comment_id IS NULL OR c.deleted_at IS NULL)`
+
+	queryInsertTaskAttachment = `
+		INSERT INTO task_attachments (task_id, comment_id, uploader_id, file_name, content_type, size, checksum_sha256, storage_key, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	queryGetTaskAttachment = `
+		SELECT ` + attachmentColumns + `
+		FROM task_attachments a
+		LEFT JOIN task_comments c ON c.id = a.comment_id
+		WHERE a.id = ? AND ` + visibleAttachmentCondition + `
+	`
+
+	queryListTaskAttachments = `
+		SELECT ` + attachmentColumns + `
+		FROM task_attachments a
+		LEFT JOIN task_comments c ON c.id = a.comment_id
+		WHERE a.task_id = ? AND ` + visibleAttachmentCondition + `
+		ORDER BY a.id
+	`
+
+	queryListTaskAttachmentKeys = `
+		SELECT storage_key
+		FROM task_attachments
+		WHERE task_id = ?
+	`
+
+	queryDeleteTaskAttachment = `
+		DELETE FROM task_attachments
+		WHERE id = ?
+	`
+)
+
+func scanTaskAttachment(row rowScanner) (*attachment.Model, error) {
+	var a attachment.Model
+
+	if err := row.Scan(
+		&a.Id,
+		&a.TaskId,
+		&a.CommentId,
+		&a.UploaderId,
+		&a.FileName,
+		&a.ContentType,
+		&a.Size,
+		&a.Checksum,
+		&a.StorageKey,
+		&a.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// CreateTaskAttachment сохраняет описание уже загруженного в хранилище файла.
+func (r *Mysql) CreateTaskAttachment(
+	ctx context.Context,
+	a attachment.Model,
+) (*attachment.Model, error) {
+	res, err := r.db.ExecContext(
+		ctx,
+		queryInsertTaskAttachment,
+		a.TaskId,
+		a.CommentId,
+		a.UploaderId,
+		a.FileName,
+		a.ContentType,
+		a.Size,
+		a.Checksum,
+		a.StorageKey,
+		a.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+
+	if err != nil {
+		return nil, err
+	}
+
+	a.Id = int(id)
+
+	return &a, nil
+}
+
+// GetTaskAttachment возвращает вложение или sql.ErrNoRows, в том числе если его комментарий удалён.
+func (r *Mysql) GetTaskAttachment(
+	ctx context.Context,
+	id int,
+) (*attachment.Model, error) {
+	return scanTaskAttachment(r.db.QueryRowContext(ctx, queryGetTaskAttachment, id))
+}
+
+// ListTaskAttachments возвращает вложения задачи и её комментариев в порядке загрузки.
+func (r *Mysql) ListTaskAttachments(
+	ctx context.Context,
+	taskId int,
+) ([]attachment.Model, error) {
+	rows, err := r.db.QueryContext(ctx, queryListTaskAttachments, taskId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	attachments := []attachment.Model{}
+
+	for rows.Next() {
+		a, err := scanTaskAttachment(rows)
+
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, *a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// ListTaskAttachmentKeys возвращает ключи всех файлов задачи, включая скрытые, — чтобы убрать их из хранилища.
+func (r *Mysql) ListTaskAttachmentKeys(
+	ctx context.Context,
+	taskId int,
+) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, queryListTaskAttachmentKeys, taskId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	keys := []string{}
+
+	for rows.Next() {
+		var key string
+
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// DeleteTaskAttachment удаляет описание вложения. Отсутствующее вложение — не ошибка.
+func (r *Mysql) DeleteTaskAttachment(
+	ctx context.Context,
+	id int,
+) error {
+	_, err := r.db.ExecContext(ctx, queryDeleteTaskAttachment, id)
+
+	return err
+}
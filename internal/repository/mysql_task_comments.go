@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task/comment"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+const (
+	commentColumns = `id, created_at, commenter_id, task_id, text, updated_at, deleted_at, parent_comment_id`
+
+	queryGetTaskComment = `
+		SELECT ` + commentColumns + `
+		FROM task_comments
+		WHERE id = ?
+	`
+
+	queryLockTaskComment = `
+		SELECT ` + commentColumns + `
+		FROM task_comments
+		WHERE id = ?
+		FOR UPDATE
+	`
+
+	queryInsertTaskCommentVersion = `
+		INSERT INTO task_comment_versions (comment_id, text, edited_by, edited_at)
+		VALUES (?, ?, ?, ?)
+	`
+
+	queryUpdateTaskComment = `
+		UPDATE task_comments
+		SET text = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	queryDeleteTaskComment = `
+		UPDATE task_comments
+		SET deleted_at = ?, deleted_by = ?
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	queryInsertCommentMention = `
+		INSERT INTO task_comment_mentions (comment_id, user_id)
+		VALUES (?, ?)
+	`
+
+	queryDeleteCommentMentions = `
+		DELETE FROM task_comment_mentions
+		WHERE comment_id = ?
+	`
+
+	queryGetMemberIdsByUsernames = `
+		SELECT u.username, u.id
+		FROM users u
+		INNER JOIN team_members tm ON tm.user_id = u.id AND tm.team_id = ?
+		WHERE u.username IN (%s)
+	`
+
+	// Упоминания видны, пока пользователь в команде и ему можно смотреть задачу: при task.view = own —
+	// только если он её создатель или исполнитель, как в permission.TaskResource.
+	// Сначала идут свежие, start_from_id продолжает список с комментариев старше него.
+	queryListUserMentions = `
+		SELECT
+			c.id, c.created_at, c.commenter_id, c.task_id, c.text, c.updated_at, c.deleted_at, c.parent_comment_id,
+			t.team_id,
+			t.title
+		FROM task_comment_mentions m
+		INNER JOIN task_comments c ON c.id = m.comment_id
+		INNER JOIN tasks t ON t.id = c.task_id
+		INNER JOIN team_members tm ON tm.team_id = t.team_id AND tm.user_id = m.user_id
+		LEFT JOIN team_permission_overrides po
+			ON po.team_id = t.team_id AND po.role = tm.role AND po.action = ?
+		WHERE m.user_id = ?
+			AND c.deleted_at IS NULL
+			AND t.archived_at IS NULL
+			AND (
+				po.scope IS NULL
+				OR po.scope = ?
+				OR (po.scope = ? AND (
+					t.creator_id = m.user_id
+					OR EXISTS (SELECT 1 FROM task_assignees a WHERE a.task_id = t.id AND a.user_id = m.user_id)
+				))
+			)
+			AND (? = 0 OR c.id < ?)
+		ORDER BY c.id DESC
+		LIMIT ?
+	`
+)
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTaskComment(row rowScanner) (*comment.Model, error) {
+	var c comment.Model
+
+	if err := row.Scan(
+		&c.Id,
+		&c.CreatedAt,
+		&c.CommenterId,
+		&c.TaskId,
+		&c.Text,
+		&c.UpdatedAt,
+		&c.DeletedAt,
+		&c.ParentCommentId,
+	); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// GetTaskComment возвращает комментарий, в том числе удалённый, или sql.ErrNoRows.
+func (r *Mysql) GetTaskComment(
+	ctx context.Context,
+	id int,
+) (*comment.Model, error) {
+	return scanTaskComment(r.db.QueryRowContext(ctx, queryGetTaskComment, id))
+}
+
+// UpdateTaskComment меняет текст комментария, сохраняя прежний в task_comment_versions,
+// и заменяет упоминания на mentionIds. Если комментарий успели удалить, возвращает comment.ErrDeleted.
+func (r *Mysql) UpdateTaskComment(
+	ctx context.Context,
+	id int,
+	text string,
+	mentionIds []int,
+	editedBy int,
+	editedAt time.Time,
+) (*comment.Model, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := scanTaskComment(tx.QueryRowContext(ctx, queryLockTaskComment, id))
+
+	if err != nil {
+		tx.Rollback()
+
+		return nil, err
+	}
+
+	if c.IsDeleted() {
+		tx.Rollback()
+
+		return nil, comment.ErrDeleted
+	}
+
+	_, err = tx.ExecContext(ctx, queryInsertTaskCommentVersion, id, c.Text, editedBy, editedAt)
+
+	if err != nil {
+		tx.Rollback()
+
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, queryUpdateTaskComment, text, editedAt, id)
+
+	if err != nil {
+		tx.Rollback()
+
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, queryDeleteCommentMentions, id)
+
+	if err != nil {
+		tx.Rollback()
+
+		return nil, err
+	}
+
+	if err := insertCommentMentions(ctx, tx, id, mentionIds); err != nil {
+		tx.Rollback()
+
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	c.Text = text
+	c.UpdatedAt = &editedAt
+
+	return c, nil
+}
+
+// DeleteTaskComment помечает комментарий удалённым. Повторное удаление ничего не меняет.
+func (r *Mysql) DeleteTaskComment(
+	ctx context.Context,
+	id int,
+	deletedBy int,
+	deletedAt time.Time,
+) error {
+	_, err := r.db.ExecContext(ctx, queryDeleteTaskComment, deletedAt, deletedBy, id)
+
+	return err
+}
+
+func insertCommentMentions(ctx context.Context, tx *sql.Tx, commentId int, userIds []int) error {
+	for _, userId := range userIds {
+		if _, err := tx.ExecContext(ctx, queryInsertCommentMention, commentId, userId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetMemberIdsByUsernames возвращает id участников команды по именам; имён не из команды в ответе нет.
+func (r *Mysql) GetMemberIdsByUsernames(
+	ctx context.Context,
+	teamId int,
+	usernames []string,
+) (map[string]int, error) {
+	ids := make(map[string]int, len(usernames))
+
+	if len(usernames) == 0 {
+		return ids, nil
+	}
+
+	args := make([]any, 0, len(usernames)+1)
+	args = append(args, teamId)
+
+	for _, username := range usernames {
+		args = append(args, username)
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(queryGetMemberIdsByUsernames, placeholders(len(usernames))), args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			username string
+			id       int
+		)
+
+		if err := rows.Scan(&username, &id); err != nil {
+			return nil, err
+		}
+
+		ids[username] = id
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// ListUserMentions возвращает комментарии, где упомянули пользователя, от новых к старым.
+func (r *Mysql) ListUserMentions(
+	ctx context.Context,
+	userId int,
+	startFromId int,
+	limit int,
+) ([]comment.Mention, error) {
+	if limit <= 0 {
+		return nil, apperror.Validation("invalid limit")
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		queryListUserMentions,
+		permission.TaskView,
+		userId,
+		permission.AnyScope,
+		permission.OwnScope,
+		startFromId,
+		startFromId,
+		limit,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	mentions := []comment.Mention{}
+
+	for rows.Next() {
+		var m comment.Mention
+
+		if err := rows.Scan(
+			&m.Comment.Id,
+			&m.Comment.CreatedAt,
+			&m.Comment.CommenterId,
+			&m.Comment.TaskId,
+			&m.Comment.Text,
+			&m.Comment.UpdatedAt,
+			&m.Comment.DeletedAt,
+			&m.Comment.ParentCommentId,
+			&m.TeamId,
+			&m.TaskTitle,
+		); err != nil {
+			return nil, err
+		}
+
+		mentions = append(mentions, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return mentions, nil
+}
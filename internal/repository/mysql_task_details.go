@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task/history"
+)
+
+const (
+	queryCountTaskComments = `
+		SELECT COUNT(*)
+		FROM task_comments
+		WHERE task_id = ? AND deleted_at IS NULL
+	`
+
+	queryListLatestHistory = `
+		SELECT
+			id,
+			task_id,
+			status,
+			title,
+			description,
+			creator_id,
+			assignee_ids,
+			team_id,
+			changed_by,
+			changed_at,
+			event,
+			due_at,
+			priority,
+			label
+		FROM task_history
+		WHERE task_id = ?
+		ORDER BY id DESC
+		LIMIT ?
+	`
+
+	queryGetHistoryAfter = `
+		SELECT
+			id,
+			task_id,
+			status,
+			title,
+			description,
+			creator_id,
+			assignee_ids,
+			team_id,
+			changed_by,
+			changed_at,
+			event,
+			due_at,
+			priority,
+			label
+		FROM task_history
+		WHERE task_id = ? AND changed_at > ?
+		ORDER BY id ASC
+		LIMIT 1
+	`
+
+	queryGetHistoryById = `
+		SELECT
+			id,
+			task_id,
+			status,
+			title,
+			description,
+			creator_id,
+			assignee_ids,
+			team_id,
+			changed_by,
+			changed_at,
+			event,
+			due_at,
+			priority,
+			label
+		FROM task_history
+		WHERE id = ?
+	`
+
+	queryGetUsernames = `
+		SELECT id, username
+		FROM users
+		WHERE id IN (%s)
+	`
+)
+
+func (r *Mysql) CountTaskComments(
+	ctx context.Context,
+	taskId int,
+) (int, error) {
+	var count int
+
+	err := r.db.QueryRowContext(ctx, queryCountTaskComments, taskId).Scan(&count)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// ListLatestHistory возвращает последние записи истории задачи, начиная с самой свежей.
+func (r *Mysql) ListLatestHistory(
+	ctx context.Context,
+	taskId int,
+	limit int,
+) ([]*history.Model, error) {
+	if limit <= 0 {
+		return nil, apperror.Validation("invalid limit")
+	}
+
+	rows, err := r.db.QueryContext(ctx, queryListLatestHistory, taskId, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanHistory(rows)
+}
+
+// GetHistoryAfter возвращает первую запись истории, сделанную позже at, или sql.ErrNoRows,
+// если после at задачу не меняли. В записи — состояние задачи на момент at.
+func (r *Mysql) GetHistoryAfter(
+	ctx context.Context,
+	taskId int,
+	at time.Time,
+) (*history.Model, error) {
+	rows, err := r.db.QueryContext(ctx, queryGetHistoryAfter, taskId, at)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	histories, err := scanHistory(rows)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(histories) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return histories[0], nil
+}
+
+func (r *Mysql) GetHistoryById(
+	ctx context.Context,
+	id int,
+) (*history.Model, error) {
+	rows, err := r.db.QueryContext(ctx, queryGetHistoryById, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	histories, err := scanHistory(rows)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(histories) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return histories[0], nil
+}
+
+// GetUsernames возвращает имена пользователей по id; неизвестных id в ответе нет.
+func (r *Mysql) GetUsernames(
+	ctx context.Context,
+	ids []int,
+) (map[int]string, error) {
+	usernames := make(map[int]string, len(ids))
+
+	if len(ids) == 0 {
+		return usernames, nil
+	}
+
+	args := make([]any, 0, len(ids))
+
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(queryGetUsernames, placeholders(len(ids))), args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id       int
+			username string
+		)
+
+		if err := rows.Scan(&id, &username); err != nil {
+			return nil, err
+		}
+
+		usernames[id] = username
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return usernames, nil
+}
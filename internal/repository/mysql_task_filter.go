@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/workflow"
+)
+
+// taskListColumns — колонки задачи для списков, в порядке scanTasks.
+const taskListColumns = "t.id, t.status, t.title, t.description, t.creator_id, " + assigneeIdsColumn + ", t.team_id, t.created_at, t.updated_at, t.version, t.due_at, t.priority"
+
+// notTerminalCondition — статус задачи t не завершающий в процессе её команды.
+// Пока у команды нет своего процесса, завершающие статусы берутся из процесса по умолчанию (параметры после запроса).
+const notTerminalCondition = `
+	NOT EXISTS (
+		SELECT 1 FROM team_workflow_statuses ws
+		WHERE ws.team_id = t.team_id AND ws.name = t.status AND ws.terminal
+	)
+	AND (
+		EXISTS (SELECT 1 FROM team_workflow_statuses ws WHERE ws.team_id = t.team_id)
+		OR t.status NOT IN (%s)
+	)`
+
+// appendTaskFilter дописывает к выборке задач (под псевдонимом t) условия фильтра, курсор и порядок сортировки.
+func appendTaskFilter(sb *strings.Builder, args []any, f task.Filter) []any {
+	if len(f.Statuses) > 0 {
+		sb.WriteString(" AND t.status IN (" + placeholders(len(f.Statuses)) + ")")
+
+		for _, s := range f.Statuses {
+			args = append(args, s)
+		}
+	}
+
+	if len(f.AssigneeIds) > 0 {
+		// Задача подходит, если среди её исполнителей есть хотя бы один из перечисленных.
+		sb.WriteString(" AND EXISTS (SELECT 1 FROM task_assignees a WHERE a.task_id = t.id AND a.user_id IN (" + placeholders(len(f.AssigneeIds)) + "))")
+
+		for _, id := range f.AssigneeIds {
+			args = append(args, id)
+		}
+	}
+
+	if len(f.CreatorIds) > 0 {
+		sb.WriteString(" AND t.creator_id IN (" + placeholders(len(f.CreatorIds)) + ")")
+
+		for _, id := range f.CreatorIds {
+			args = append(args, id)
+		}
+	}
+
+	if len(f.Labels) > 0 {
+		sb.WriteString(" AND EXISTS (SELECT 1 FROM task_labels tl JOIN team_labels l ON l.id = tl.label_id")
+		sb.WriteString(" WHERE tl.task_id = t.id AND l.name IN (" + placeholders(len(f.Labels)) + "))")
+
+		for _, name := range f.Labels {
+			args = append(args, name)
+		}
+	}
+
+	if f.CreatedFrom != nil {
+		sb.WriteString(" AND t.created_at >= ?")
+
+		args = append(args, *f.CreatedFrom)
+	}
+
+	if f.CreatedTo != nil {
+		sb.WriteString(" AND t.created_at < ?")
+
+		args = append(args, *f.CreatedTo)
+	}
+
+	if f.UpdatedFrom != nil {
+		sb.WriteString(" AND t.updated_at >= ?")
+
+		args = append(args, *f.UpdatedFrom)
+	}
+
+	if f.UpdatedTo != nil {
+		sb.WriteString(" AND t.updated_at < ?")
+
+		args = append(args, *f.UpdatedTo)
+	}
+
+	if q := fulltextQuery(f.Query); q != "" {
+		sb.WriteString(" AND MATCH(t.title, t.description) AGAINST (? IN BOOLEAN MODE)")
+
+		args = append(args, q)
+	}
+
+	if f.OverdueAt != nil {
+		sb.WriteString(" AND t.due_at < ? AND")
+
+		args = append(args, *f.OverdueAt)
+		args = appendNotTerminal(sb, args)
+	}
+
+	column := sortColumn(f.Sort)
+
+	cmp, dir := ">", "ASC"
+
+	if f.Desc {
+		cmp, dir = "<", "DESC"
+	}
+
+	// Keyset-пагинация: строго после последней выданной задачи в порядке (поле сортировки, id).
+	if f.After != nil {
+		if column == "t.id" {
+			sb.WriteString(" AND t.id " + cmp + " ?")
+
+			args = append(args, f.After.Id)
+		} else {
+			key := cursorKey(f.After)
+
+			sb.WriteString(" AND (" + column + " " + cmp + " ? OR (" + column + " = ? AND t.id " + cmp + " ?))")
+
+			args = append(args, key, key, f.After.Id)
+		}
+	}
+
+	if column == "t.id" {
+		sb.WriteString(" ORDER BY t.id " + dir)
+	} else {
+		sb.WriteString(" ORDER BY " + column + " " + dir + ", t.id " + dir)
+	}
+
+	return args
+}
+
+// sortColumn сопоставляет поле сортировки колонке; в запрос попадают только известные имена.
+func sortColumn(f task.SortField) string {
+	switch f {
+	case task.SortByCreatedAt:
+		return "t.created_at"
+	case task.SortByUpdatedAt:
+		return "t.updated_at"
+	case task.SortByPriority:
+		return "t.priority"
+	case task.SortByDueAt:
+		return "COALESCE(t.due_at, '" + task.NoDueAt.Format(time.DateTime) + "')"
+	default:
+		return "t.id"
+	}
+}
+
+// cursorKey приводит ключ курсора к типу колонки сортировки; курсор уже проверен при разборе.
+func cursorKey(c *task.Cursor) any {
+	if c.Sort == task.SortByPriority {
+		p, _ := c.KeyPriority()
+
+		return p
+	}
+
+	key, _ := c.KeyTime()
+
+	return key
+}
+
+// appendNotTerminal дописывает notTerminalCondition вместе с завершающими статусами процесса по умолчанию.
+func appendNotTerminal(sb *strings.Builder, args []any) []any {
+	var terminal []string
+
+	for _, s := range workflow.Default(0).Statuses {
+		if s.Terminal {
+			terminal = append(terminal, s.Name)
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf(notTerminalCondition, placeholders(len(terminal))))
+
+	for _, s := range terminal {
+		args = append(args, s)
+	}
+
+	return args
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// fulltextQuery превращает пользовательскую строку в запрос BOOLEAN MODE:
+// каждое слово обязательно и ищется по префиксу, операторы MySQL из ввода убираются.
+func fulltextQuery(q string) string {
+	words := strings.FieldsFunc(q, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+
+	terms := make([]string, 0, len(words))
+
+	for _, w := range words {
+		terms = append(terms, "+"+w+"*")
+	}
+
+	return strings.Join(terms, " ")
+}
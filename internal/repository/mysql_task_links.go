@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"mkk-luna-test-task/internal/task"
+)
+
+const (
+	queryLockTeam = `
+		SELECT id
+		FROM teams
+		WHERE id = ?
+		FOR UPDATE
+	`
+
+	// Цепочка предков задачи, включая её саму.
+	queryCountAncestor = `
+		WITH RECURSIVE ancestors (id) AS (
+			SELECT CAST(? AS SIGNED)
+			UNION
+			SELECT p.parent_id
+			FROM task_parents p
+			INNER JOIN ancestors a ON p.task_id = a.id
+		)
+		SELECT COUNT(*) FROM ancestors WHERE id = ?
+	`
+
+	// Все задачи, которые прямо или через другие блокируют задачу, включая её саму.
+	queryCountBlocker = `
+		WITH RECURSIVE chain (id) AS (
+			SELECT CAST(? AS SIGNED)
+			UNION
+			SELECT b.blocker_id
+			FROM task_blockers b
+			INNER JOIN chain c ON b.task_id = c.id
+		)
+		SELECT COUNT(*) FROM chain WHERE id = ?
+	`
+
+	queryUpsertTaskParent = `
+		INSERT INTO task_parents (task_id, parent_id)
+		VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE parent_id = VALUES(parent_id)
+	`
+
+	queryDeleteTaskParent = `
+		DELETE FROM task_parents
+		WHERE task_id = ? AND parent_id = ?
+	`
+
+	queryInsertTaskBlocker = `
+		INSERT IGNORE INTO task_blockers (task_id, blocker_id)
+		VALUES (?, ?)
+	`
+
+	queryDeleteTaskBlocker = `
+		DELETE FROM task_blockers
+		WHERE task_id = ? AND blocker_id = ?
+	`
+
+	queryGetTaskParent = `
+		SELECT ` + taskListColumns + `
+		FROM task_parents p
+		INNER JOIN tasks t ON t.id = p.parent_id
+		WHERE p.task_id = ? AND t.archived_at IS NULL
+	`
+
+	// Архивная подзадача пропадает из дерева вместе со своими подзадачами.
+	// Сначала идут ближние уровни, поэтому limit обрезает дерево снизу.
+	queryListSubtasks = `
+		WITH RECURSIVE tree (id, parent_id, depth) AS (
+			SELECT p.task_id, p.parent_id, 1
+			FROM task_parents p
+			INNER JOIN tasks t ON t.id = p.task_id
+			WHERE p.parent_id = ? AND t.archived_at IS NULL
+			UNION ALL
+			SELECT p.task_id, p.parent_id, tree.depth + 1
+			FROM task_parents p
+			INNER JOIN tree ON p.parent_id = tree.id
+			INNER JOIN tasks t ON t.id = p.task_id
+			WHERE t.archived_at IS NULL
+		)
+		SELECT tree.parent_id, ` + taskListColumns + `
+		FROM tree
+		INNER JOIN tasks t ON t.id = tree.id
+		ORDER BY tree.depth, t.id
+		LIMIT ?
+	`
+
+	queryListTaskBlockers = `
+		SELECT ` + taskListColumns + `
+		FROM task_blockers b
+		INNER JOIN tasks t ON t.id = b.blocker_id
+		WHERE b.task_id = ? AND t.archived_at IS NULL
+		ORDER BY t.id
+	`
+)
+
+// SetTaskParent делает subtaskId подзадачей parentId; прежний родитель, если был, заменяется.
+// Обе задачи должны быть из команды teamId.
+func (r *Mysql) SetTaskParent(
+	ctx context.Context,
+	teamId, subtaskId, parentId int,
+) error {
+	// Цикл будет, если подзадача уже среди предков нового родителя.
+	return r.linkTasks(ctx, teamId, queryCountAncestor, parentId, subtaskId, queryUpsertTaskParent, subtaskId, parentId)
+}
+
+// RemoveTaskParent отвязывает подзадачу от родителя. Если родитель другой, ничего не меняется.
+func (r *Mysql) RemoveTaskParent(
+	ctx context.Context,
+	subtaskId, parentId int,
+) error {
+	_, err := r.db.ExecContext(ctx, queryDeleteTaskParent, subtaskId, parentId)
+
+	return err
+}
+
+// AddTaskBlocker отмечает, что blockerId блокирует taskId. Обе задачи должны быть из команды teamId.
+func (r *Mysql) AddTaskBlocker(
+	ctx context.Context,
+	teamId, taskId, blockerId int,
+) error {
+	// Цикл будет, если задача уже прямо или через другие блокирует свой новый блокер.
+	return r.linkTasks(ctx, teamId, queryCountBlocker, blockerId, taskId, queryInsertTaskBlocker, taskId, blockerId)
+}
+
+// RemoveTaskBlocker снимает блокировку. Отсутствующая блокировка — не ошибка.
+func (r *Mysql) RemoveTaskBlocker(
+	ctx context.Context,
+	taskId, blockerId int,
+) error {
+	_, err := r.db.ExecContext(ctx, queryDeleteTaskBlocker, taskId, blockerId)
+
+	return err
+}
+
+// linkTasks добавляет связь queryInsert(taskId, linkedId), если от start по queryReach нельзя дойти до target.
+// Связи внутри команды меняются под блокировкой её строки: иначе две параллельные связи могли бы вместе замкнуть цикл.
+func (r *Mysql) linkTasks(
+	ctx context.Context,
+	teamId int,
+	queryReach string,
+	start, target int,
+	queryInsert string,
+	taskId, linkedId int,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	var lockedId int
+
+	err = tx.QueryRowContext(ctx, queryLockTeam, teamId).Scan(&lockedId)
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	var reachable int
+
+	err = tx.QueryRowContext(ctx, queryReach, start, target).Scan(&reachable)
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	if reachable > 0 {
+		tx.Rollback()
+
+		return task.ErrLinkCycle
+	}
+
+	_, err = tx.ExecContext(ctx, queryInsert, taskId, linkedId)
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetTaskParent возвращает родителя задачи или sql.ErrNoRows, если его нет или он в архиве.
+func (r *Mysql) GetTaskParent(
+	ctx context.Context,
+	taskId int,
+) (*task.Model, error) {
+	rows, err := r.db.QueryContext(ctx, queryGetTaskParent, taskId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	parents, err := scanTasks(rows, 1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parents) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return parents[0], nil
+}
+
+// ListSubtasks возвращает всё дерево подзадач плоским списком: от ближних уровней к дальним, не больше limit задач.
+func (r *Mysql) ListSubtasks(
+	ctx context.Context,
+	taskId int,
+	limit int,
+) ([]task.Subtask, error) {
+	rows, err := r.db.QueryContext(ctx, queryListSubtasks, taskId, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	subtasks := []task.Subtask{}
+
+	for rows.Next() {
+		var s task.Subtask
+
+		if err := rows.Scan(
+			&s.ParentId,
+			&s.Task.Id,
+			&s.Task.Status,
+			&s.Task.Title,
+			&s.Task.Description,
+			&s.Task.CreatorId,
+			(*idList)(&s.Task.AssigneeIds),
+			&s.Task.TeamId,
+			&s.Task.CreatedAt,
+			&s.Task.UpdatedAt,
+			&s.Task.Version,
+			&s.Task.DueAt,
+			&s.Task.Priority,
+		); err != nil {
+			return nil, err
+		}
+
+		subtasks = append(subtasks, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return subtasks, nil
+}
+
+// ListTaskBlockers возвращает задачи, которые блокируют taskId, кроме архивных.
+func (r *Mysql) ListTaskBlockers(
+	ctx context.Context,
+	taskId int,
+) ([]*task.Model, error) {
+	rows, err := r.db.QueryContext(ctx, queryListTaskBlockers, taskId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanTasks(rows, 0)
+}
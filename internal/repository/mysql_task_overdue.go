@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+)
+
+// Условие на due_at не даёт отметить задачу, срок которой успели перенести после выборки.
+const queryMarkOverdueNotified = `
+	UPDATE tasks
+	SET overdue_notified_at = ?
+	WHERE id = ? AND overdue_notified_at IS NULL AND due_at = ?
+`
+
+// MarkOverdueTasks отмечает до limit просроченных задач, о которых ещё не уведомляли, и возвращает отмеченные.
+// Каждая задача забирается отдельным условным UPDATE, поэтому несколько экземпляров сервиса не уведомят о ней дважды.
+// Отметка сбрасывается, когда у задачи меняется срок.
+func (r *Mysql) MarkOverdueTasks(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]*task.Model, error) {
+	if limit <= 0 {
+		return nil, apperror.Validation("invalid limit")
+	}
+
+	sb := strings.Builder{}
+
+	sb.WriteString("SELECT " + taskListColumns + " FROM tasks t")
+	sb.WriteString(" WHERE t.archived_at IS NULL AND t.overdue_notified_at IS NULL AND t.due_at < ? AND")
+
+	args := appendNotTerminal(&sb, []any{now})
+
+	sb.WriteString(" ORDER BY t.due_at, t.id LIMIT ?")
+
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, sb.String(), args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := scanTasks(rows, limit)
+
+	rows.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	marked := make([]*task.Model, 0, len(candidates))
+
+	for _, t := range candidates {
+		result, err := r.db.ExecContext(ctx, queryMarkOverdueNotified, now, t.Id, t.DueAt)
+
+		if err != nil {
+			return marked, err
+		}
+
+		affected, err := result.RowsAffected()
+
+		if err != nil {
+			return marked, err
+		}
+
+		if affected == 1 {
+			marked = append(marked, t)
+		}
+	}
+
+	return marked, nil
+}
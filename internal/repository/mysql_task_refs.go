@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"mkk-luna-test-task/internal/task"
+)
+
+const queryGetTasksByIds = `
+	SELECT ` + taskListColumns + `
+	FROM tasks t
+	WHERE t.id IN (%s) AND t.archived_at IS NULL
+	ORDER BY t.id
+`
+
+// GetTasksByIds возвращает найденные задачи из ids, кроме архивных; отсутствующие просто пропускаются.
+func (r *Mysql) GetTasksByIds(
+	ctx context.Context,
+	ids []int,
+) ([]*task.Model, error) {
+	if len(ids) == 0 {
+		return []*task.Model{}, nil
+	}
+
+	args := make([]any, 0, len(ids))
+
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(queryGetTasksByIds, placeholders(len(ids))), args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanTasks(rows, len(ids))
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+// Задачи пользователя во всех его командах. Право task.view у всех ролей по умолчанию any,
+// поэтому отсекаем только команды, где роли пользователя его переопределили в none.
+const queryListUserTasks = `
+	SELECT ` + taskListColumns + `
+	FROM tasks t
+	INNER JOIN team_members tm ON tm.team_id = t.team_id AND tm.user_id = ?
+	LEFT JOIN team_permission_overrides po
+		ON po.team_id = t.team_id AND po.role = tm.role AND po.action = ?
+	WHERE t.archived_at IS NULL AND (po.scope IS NULL OR po.scope <> ?)`
+
+func (r *Mysql) ListUserTasks(
+	ctx context.Context,
+	userId int,
+	relation task.Relation,
+	f task.Filter,
+	limit int,
+) ([]*task.Model, error) {
+	if limit <= 0 {
+		return nil, apperror.Validation("invalid limit")
+	}
+
+	sb := strings.Builder{}
+
+	sb.WriteString(queryListUserTasks)
+
+	args := []any{userId, permission.TaskView, permission.NoneScope}
+
+	switch relation {
+	case task.AssigneeRelation:
+		sb.WriteString(" AND " + isAssigneeCondition)
+
+		args = append(args, userId)
+	case task.CreatorRelation:
+		sb.WriteString(" AND t.creator_id = ?")
+
+		args = append(args, userId)
+	default:
+		sb.WriteString(" AND (" + isAssigneeCondition + " OR t.creator_id = ?)")
+
+		args = append(args, userId, userId)
+	}
+
+	if f.TeamId != 0 {
+		sb.WriteString(" AND t.team_id = ?")
+
+		args = append(args, f.TeamId)
+	}
+
+	args = appendTaskFilter(&sb, args, f)
+
+	sb.WriteString(" LIMIT ?")
+
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, sb.String(), args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanTasks(rows, limit)
+}
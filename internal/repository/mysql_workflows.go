@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+
+	"mkk-luna-test-task/internal/team/workflow"
+)
+
+const (
+	queryListWorkflowStatuses = `
+		SELECT name, terminal
+		FROM team_workflow_statuses
+		WHERE team_id = ?
+		ORDER BY position
+	`
+
+	queryListWorkflowTransitions = `
+		SELECT from_status, to_status
+		FROM team_workflow_transitions
+		WHERE team_id = ?
+		ORDER BY from_status, to_status
+	`
+
+	queryDeleteWorkflowTransitions = `
+		DELETE FROM team_workflow_transitions
+		WHERE team_id = ?
+	`
+
+	queryDeleteWorkflowStatuses = `
+		DELETE FROM team_workflow_statuses
+		WHERE team_id = ?
+	`
+
+	queryInsertWorkflowStatus = `
+		INSERT INTO team_workflow_statuses (team_id, name, position, terminal)
+		VALUES (?, ?, ?, ?)
+	`
+
+	queryInsertWorkflowTransition = `
+		INSERT INTO team_workflow_transitions (team_id, from_status, to_status)
+		VALUES (?, ?, ?)
+	`
+
+	queryListTaskStatuses = `
+		SELECT DISTINCT status
+		FROM tasks
+		WHERE team_id = ?
+	`
+)
+
+// GetWorkflow возвращает процесс команды, а если команда его не настраивала — процесс по умолчанию.
+func (r *Mysql) GetWorkflow(
+	ctx context.Context,
+	teamId int,
+) (*workflow.Model, error) {
+	rows, err := r.db.QueryContext(ctx, queryListWorkflowStatuses, teamId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	wf := &workflow.Model{TeamId: teamId}
+
+	for rows.Next() {
+		var s workflow.Status
+
+		if err := rows.Scan(&s.Name, &s.Terminal); err != nil {
+			return nil, err
+		}
+
+		wf.Statuses = append(wf.Statuses, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(wf.Statuses) == 0 {
+		return workflow.Default(teamId), nil
+	}
+
+	transitionRows, err := r.db.QueryContext(ctx, queryListWorkflowTransitions, teamId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer transitionRows.Close()
+
+	for transitionRows.Next() {
+		var t workflow.Transition
+
+		if err := transitionRows.Scan(&t.From, &t.To); err != nil {
+			return nil, err
+		}
+
+		wf.Transitions = append(wf.Transitions, t)
+	}
+
+	if err := transitionRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return wf, nil
+}
+
+// SetWorkflow целиком заменяет процесс команды.
+func (r *Mysql) SetWorkflow(
+	ctx context.Context,
+	wf *workflow.Model,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, queryDeleteWorkflowTransitions, wf.TeamId)
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, queryDeleteWorkflowStatuses, wf.TeamId)
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	for i, s := range wf.Statuses {
+		_, err = tx.ExecContext(ctx, queryInsertWorkflowStatus, wf.TeamId, s.Name, i, s.Terminal)
+
+		if err != nil {
+			tx.Rollback()
+
+			return err
+		}
+	}
+
+	for _, t := range wf.Transitions {
+		_, err = tx.ExecContext(ctx, queryInsertWorkflowTransition, wf.TeamId, t.From, t.To)
+
+		if err != nil {
+			tx.Rollback()
+
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *Mysql) ListTaskStatuses(
+	ctx context.Context,
+	teamId int,
+) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, queryListTaskStatuses, teamId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var statuses []string
+
+	for rows.Next() {
+		var s string
+
+		if err := rows.Scan(&s); err != nil {
+			return nil, err
+		}
+
+		statuses = append(statuses, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
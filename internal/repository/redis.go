@@ -4,13 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"strconv"
 	"time"
 
-	"mkk-luna-test-task/internal/task"
-
 	goredis "github.com/redis/go-redis/v9"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
 )
 
 type redis struct {
@@ -77,7 +77,7 @@ func (r *redis) CacheTasks(ctx context.Context, teamId int, tasks []task.Model)
 
 func (r *redis) ListTasksFromCache(ctx context.Context, teamId int, limit int, startFromID *int) (tasks []task.Model, hit bool, err error) {
 	if limit <= 0 {
-		return nil, false, fmt.Errorf("invalid limit")
+		return nil, false, apperror.Validation("invalid limit")
 	}
 
 	zKey := teamTasksKey(teamId)
@@ -175,3 +175,21 @@ func (r *redis) UpdateTaskInCache(ctx context.Context, t task.Model) error {
 
 	return err
 }
+
+// RemoveTaskFromCache убирает задачу из кэша доски, например после архивации.
+func (r *redis) RemoveTaskFromCache(ctx context.Context, teamId int, taskId int) error {
+	pipe := r.client.TxPipeline()
+
+	pipe.Del(ctx, taskKey(taskId))
+	pipe.ZRem(ctx, teamTasksKey(teamId), strconv.Itoa(taskId))
+
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+// InvalidateTeamTasksCache сбрасывает кэш доски команды целиком: задача вернулась в середину списка,
+// и кэшированные страницы больше не отражают порядок.
+func (r *redis) InvalidateTeamTasksCache(ctx context.Context, teamId int) error {
+	return r.client.Del(ctx, teamTasksKey(teamId)).Err()
+}
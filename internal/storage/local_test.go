@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStorage(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	s, err := NewLocal(filepath.Join(dir, "blobs"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Put(ctx, "tasks/1/abc.png", strings.NewReader("png data"), 8, "image/png"))
+
+	body, err := s.Get(ctx, "tasks/1/abc.png")
+	assert.NoError(t, err)
+
+	data, err := io.ReadAll(body)
+	assert.NoError(t, err)
+	assert.NoError(t, body.Close())
+	assert.Equal(t, "png data", string(data))
+
+	// Размер не совпал — по ключу ничего не появляется, временный файл убран.
+	assert.Error(t, s.Put(ctx, "tasks/1/short", strings.NewReader("abc"), 10, "text/plain"))
+
+	_, err = s.Get(ctx, "tasks/1/short")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	entries, err := os.ReadDir(filepath.Join(dir, "blobs", "tasks", "1"))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	assert.NoError(t, s.Delete(ctx, "tasks/1/abc.png"))
+	assert.NoError(t, s.Delete(ctx, "tasks/1/abc.png"))
+
+	_, err = s.Get(ctx, "tasks/1/abc.png")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestValidKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{key: "tasks/1/0f3a.log", want: true},
+		{key: "", want: false},
+		{key: "/etc/passwd", want: false},
+		{key: "tasks/../../etc/passwd", want: false},
+		{key: "tasks//1", want: false},
+		{key: "tasks/1/", want: false},
+		{key: `tasks\1`, want: false},
+		{key: "tasks/файл", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			assert.Equal(t, tt.want, validKey(tt.key))
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Package storage хранит содержимое вложений вне базы: на локальном диске или в S3-совместимом хранилище.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+var errInvalidKey = errors.New("invalid blob key")
+
+// Storage — хранилище содержимого по ключу. Ключи выдаёт сервис, а не клиент.
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get возвращает ErrNotFound, если по ключу ничего нет.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete отсутствующего ключа — не ошибка.
+	Delete(ctx context.Context, key string) error
+}
+
+// validKey пропускает только относительные пути из латиницы, цифр и -_. без переходов вверх.
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.HasSuffix(key, "/") {
+		return false
+	}
+
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+
+	for _, c := range key {
+		ok := c == '/' || c == '-' || c == '_' || c == '.' ||
+			(c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
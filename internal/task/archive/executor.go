@@ -0,0 +1,88 @@
+package archive
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type taskGetter interface {
+	GetTaskById(ctx context.Context, id int) (*task.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type taskArchiver interface {
+	ArchiveTask(ctx context.Context, id int, expectedVersion int, archivedBy int, archivedAt time.Time) (*task.Model, error)
+}
+
+type cacheRemover interface {
+	RemoveTaskFromCache(ctx context.Context, teamId int, taskId int) error
+}
+
+type executor struct {
+	taskGetter   taskGetter
+	authorizer   authorizer
+	taskArchiver taskArchiver
+	cacheRemover cacheRemover
+}
+
+func NewExecutor(taskGetter taskGetter, authorizer authorizer, taskArchiver taskArchiver, cacheRemover cacheRemover) *executor {
+	return &executor{
+		taskGetter:   taskGetter,
+		authorizer:   authorizer,
+		taskArchiver: taskArchiver,
+		cacheRemover: cacheRemover,
+	}
+}
+
+type ArchiveInput struct {
+	UserId  int
+	TaskId  int
+	IfMatch int
+}
+
+type ArchiveResult struct {
+	Version int
+}
+
+func (e *executor) Execute(ctx context.Context, in ArchiveInput) (*ArchiveResult, error) {
+	t, err := e.taskGetter.GetTaskById(ctx, in.TaskId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("task not found")
+		}
+
+		return nil, err
+	}
+
+	_, err = e.authorizer.Authorize(ctx, in.UserId, permission.TaskDelete, permission.TaskResource(*t))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if t.IsArchived() {
+		return nil, task.ErrArchived
+	}
+
+	archived, err := e.taskArchiver.ArchiveTask(ctx, in.TaskId, in.IfMatch, in.UserId, time.Now())
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Если не получилось, задача пропадёт из кэша по ttl.
+	_ = e.cacheRemover.RemoveTaskFromCache(ctx, archived.TeamId, archived.Id)
+
+	return &ArchiveResult{Version: archived.Version}, nil
+}
@@ -0,0 +1,157 @@
+package archive
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubTaskGetter struct {
+	task *task.Model
+	err  error
+}
+
+func (s *stubTaskGetter) GetTaskById(ctx context.Context, id int) (*task.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	t := *s.task
+
+	return &t, nil
+}
+
+type stubAuthorizer struct {
+	err error
+}
+
+func (s *stubAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
+}
+
+type mockTaskArchiver struct {
+	err    error
+	called bool
+}
+
+func (m *mockTaskArchiver) ArchiveTask(ctx context.Context, id int, expectedVersion int, archivedBy int, archivedAt time.Time) (*task.Model, error) {
+	m.called = true
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &task.Model{Id: id, TeamId: 10, Version: 4, ArchivedAt: &archivedAt}, nil
+}
+
+type mockCacheRemover struct {
+	teamId int
+	taskId int
+}
+
+func (m *mockCacheRemover) RemoveTaskFromCache(ctx context.Context, teamId int, taskId int) error {
+	m.teamId = teamId
+	m.taskId = taskId
+
+	return nil
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	archivedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	activeTask := &task.Model{Id: 1, TeamId: 10, Version: 3}
+	archivedTask := &task.Model{Id: 1, TeamId: 10, Version: 4, ArchivedAt: &archivedAt}
+
+	tests := []struct {
+		name        string
+		taskGetter  *stubTaskGetter
+		authorizer  *stubAuthorizer
+		archiver    *mockTaskArchiver
+		wantVersion int
+		wantArchive bool
+		expectedErr string
+	}{
+		{
+			name:        "success",
+			taskGetter:  &stubTaskGetter{task: activeTask},
+			authorizer:  &stubAuthorizer{},
+			archiver:    &mockTaskArchiver{},
+			wantVersion: 4,
+			wantArchive: true,
+		},
+		{
+			name:        "task not found",
+			taskGetter:  &stubTaskGetter{err: sql.ErrNoRows},
+			authorizer:  &stubAuthorizer{},
+			archiver:    &mockTaskArchiver{},
+			expectedErr: "task not found",
+		},
+		{
+			name:        "forbidden",
+			taskGetter:  &stubTaskGetter{task: activeTask},
+			authorizer:  &stubAuthorizer{err: permission.ErrForbidden},
+			archiver:    &mockTaskArchiver{},
+			expectedErr: "forbidden",
+		},
+		{
+			name:        "already archived",
+			taskGetter:  &stubTaskGetter{task: archivedTask},
+			authorizer:  &stubAuthorizer{},
+			archiver:    &mockTaskArchiver{},
+			expectedErr: "task is archived",
+		},
+		{
+			name:        "stale version",
+			taskGetter:  &stubTaskGetter{task: activeTask},
+			authorizer:  &stubAuthorizer{},
+			archiver:    &mockTaskArchiver{err: task.ErrVersionMismatch},
+			wantArchive: true,
+			expectedErr: "task has been modified",
+		},
+		{
+			name:        "archiver error",
+			taskGetter:  &stubTaskGetter{task: activeTask},
+			authorizer:  &stubAuthorizer{},
+			archiver:    &mockTaskArchiver{err: errors.New("db error")},
+			wantArchive: true,
+			expectedErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := &mockCacheRemover{}
+
+			e := NewExecutor(tt.taskGetter, tt.authorizer, tt.archiver, cache)
+
+			got, err := e.Execute(context.Background(), ArchiveInput{UserId: 5, TaskId: 1})
+
+			assert.Equal(t, tt.wantArchive, tt.archiver.called)
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Nil(t, got)
+				assert.Zero(t, cache.taskId)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantVersion, got.Version)
+			assert.Equal(t, 10, cache.teamId)
+			assert.Equal(t, 1, cache.taskId)
+		})
+	}
+}
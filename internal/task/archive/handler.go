@@ -0,0 +1,65 @@
+package archive
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+)
+
+type Executor interface {
+	Execute(ctx context.Context, in ArchiveInput) (*ArchiveResult, error)
+}
+
+type handler struct {
+	exec Executor
+}
+
+func NewHandler(exec Executor) *handler {
+	return &handler{
+		exec: exec,
+	}
+}
+
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	userIdAny := r.Context().Value("userId")
+
+	userId, ok := userIdAny.(int)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
+	taskId, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("invalid task id"))
+
+		return
+	}
+
+	ifMatch, err := task.ParseIfMatch(r.Header.Get("If-Match"))
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	result, err := h.exec.Execute(r.Context(), ArchiveInput{UserId: userId, TaskId: taskId, IfMatch: ifMatch})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	w.Header().Set("ETag", task.ETag(result.Version))
+	w.WriteHeader(http.StatusNoContent)
+}
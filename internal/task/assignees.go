@@ -0,0 +1,39 @@
+package task
+
+import (
+	"slices"
+	"strconv"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+// MaxAssignees — сколько исполнителей можно назначить на одну задачу.
+const MaxAssignees = 20
+
+// NormalizeAssignees сортирует id исполнителей и убирает повторы, чтобы списки можно было сравнивать.
+func NormalizeAssignees(ids []int) ([]int, error) {
+	normalized := make([]int, 0, len(ids))
+
+	for _, id := range ids {
+		if id <= 0 {
+			return nil, apperror.InvalidField("assignee_ids", "must contain positive ids")
+		}
+
+		normalized = append(normalized, id)
+	}
+
+	slices.Sort(normalized)
+
+	normalized = slices.Compact(normalized)
+
+	if len(normalized) > MaxAssignees {
+		return nil, apperror.InvalidField("assignee_ids", "must contain at most "+strconv.Itoa(MaxAssignees)+" users")
+	}
+
+	return normalized, nil
+}
+
+// IsAssignee — пользователь среди исполнителей задачи.
+func (m Model) IsAssignee(userId int) bool {
+	return slices.Contains(m.AssigneeIds, userId)
+}
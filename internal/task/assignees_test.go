@@ -0,0 +1,43 @@
+package task
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeAssignees(t *testing.T) {
+	tooMany := make([]int, 0, MaxAssignees+1)
+
+	for i := 1; i <= MaxAssignees+1; i++ {
+		tooMany = append(tooMany, i)
+	}
+
+	tests := []struct {
+		name        string
+		ids         []int
+		want        []int
+		expectedErr string
+	}{
+		{name: "nil", ids: nil, want: []int{}},
+		{name: "sorted without duplicates", ids: []int{5, 2, 5, 3}, want: []int{2, 3, 5}},
+		{name: "duplicates do not count against the limit", ids: append(tooMany[:MaxAssignees:MaxAssignees], 1), want: tooMany[:MaxAssignees]},
+		{name: "non-positive id", ids: []int{2, 0}, expectedErr: "validation failed"},
+		{name: "too many", ids: tooMany, expectedErr: "validation failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeAssignees(tt.ids)
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package download
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/storage"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/attachment"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type taskGetter interface {
+	GetTaskById(ctx context.Context, id int) (*task.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type attachmentGetter interface {
+	GetTaskAttachment(ctx context.Context, id int) (*attachment.Model, error)
+}
+
+type blobGetter interface {
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+type executor struct {
+	taskGetter       taskGetter
+	authorizer       authorizer
+	attachmentGetter attachmentGetter
+	blobs            blobGetter
+}
+
+func NewExecutor(taskGetter taskGetter, authorizer authorizer, attachmentGetter attachmentGetter, blobs blobGetter) *executor {
+	return &executor{
+		taskGetter:       taskGetter,
+		authorizer:       authorizer,
+		attachmentGetter: attachmentGetter,
+		blobs:            blobs,
+	}
+}
+
+type DownloadInput struct {
+	UserId       int
+	TaskId       int
+	AttachmentId int
+}
+
+// DownloadResult — описание файла и его содержимое; Body закрывает вызывающий.
+type DownloadResult struct {
+	Attachment attachment.Model
+	Body       io.ReadCloser
+}
+
+// Execute отдаёт файл любому, кому можно смотреть задачу.
+func (e *executor) Execute(ctx context.Context, in DownloadInput) (*DownloadResult, error) {
+	t, err := e.taskGetter.GetTaskById(ctx, in.TaskId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("task not found")
+		}
+
+		return nil, err
+	}
+
+	_, err = e.authorizer.Authorize(ctx, in.UserId, permission.TaskView, permission.TaskResource(*t))
+
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := e.attachmentGetter.GetTaskAttachment(ctx, in.AttachmentId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, attachment.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	if a.TaskId != t.Id {
+		return nil, attachment.ErrNotFound
+	}
+
+	body, err := e.blobs.Get(ctx, a.StorageKey)
+
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, attachment.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return &DownloadResult{Attachment: *a, Body: body}, nil
+}
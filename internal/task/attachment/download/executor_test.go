@@ -0,0 +1,156 @@
+package download
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"mkk-luna-test-task/internal/storage"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/attachment"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubTaskGetter struct{}
+
+func (s *stubTaskGetter) GetTaskById(ctx context.Context, id int) (*task.Model, error) {
+	if id > 2 {
+		return nil, sql.ErrNoRows
+	}
+
+	return &task.Model{Id: id, TeamId: 10}, nil
+}
+
+type mockAuthorizer struct {
+	err    error
+	action permission.Action
+}
+
+func (m *mockAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	m.action = action
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
+}
+
+type stubAttachmentGetter struct{}
+
+func (s *stubAttachmentGetter) GetTaskAttachment(ctx context.Context, id int) (*attachment.Model, error) {
+	if id != 3 {
+		return nil, sql.ErrNoRows
+	}
+
+	return &attachment.Model{Id: 3, TaskId: 1, FileName: "app.log", StorageKey: "tasks/1/abc"}, nil
+}
+
+type stubBlobs struct {
+	blobs map[string]string
+	err   error
+}
+
+func (s *stubBlobs) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	data, ok := s.blobs[key]
+
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+
+	return io.NopCloser(strings.NewReader(data)), nil
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		authorizer  *mockAuthorizer
+		blobs       *stubBlobs
+		in          DownloadInput
+		wantData    string
+		expectedErr string
+	}{
+		{
+			name:       "downloads for anyone who can view the task",
+			authorizer: &mockAuthorizer{},
+			blobs:      &stubBlobs{blobs: map[string]string{"tasks/1/abc": "log line"}},
+			in:         DownloadInput{UserId: 5, TaskId: 1, AttachmentId: 3},
+			wantData:   "log line",
+		},
+		{
+			name:        "task not found",
+			authorizer:  &mockAuthorizer{},
+			blobs:       &stubBlobs{},
+			in:          DownloadInput{UserId: 5, TaskId: 9, AttachmentId: 3},
+			expectedErr: "task not found",
+		},
+		{
+			name:        "cannot view task",
+			authorizer:  &mockAuthorizer{err: permission.ErrForbidden},
+			blobs:       &stubBlobs{},
+			in:          DownloadInput{UserId: 5, TaskId: 1, AttachmentId: 3},
+			expectedErr: "forbidden",
+		},
+		{
+			name:        "attachment not found",
+			authorizer:  &mockAuthorizer{},
+			blobs:       &stubBlobs{},
+			in:          DownloadInput{UserId: 5, TaskId: 1, AttachmentId: 4},
+			expectedErr: "attachment not found",
+		},
+		{
+			name:        "attachment of another task",
+			authorizer:  &mockAuthorizer{},
+			blobs:       &stubBlobs{blobs: map[string]string{"tasks/1/abc": "log line"}},
+			in:          DownloadInput{UserId: 5, TaskId: 2, AttachmentId: 3},
+			expectedErr: "attachment not found",
+		},
+		{
+			name:        "content missing in storage",
+			authorizer:  &mockAuthorizer{},
+			blobs:       &stubBlobs{},
+			in:          DownloadInput{UserId: 5, TaskId: 1, AttachmentId: 3},
+			expectedErr: "attachment not found",
+		},
+		{
+			name:        "storage error",
+			authorizer:  &mockAuthorizer{},
+			blobs:       &stubBlobs{err: errors.New("storage error")},
+			in:          DownloadInput{UserId: 5, TaskId: 1, AttachmentId: 3},
+			expectedErr: "storage error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(&stubTaskGetter{}, tt.authorizer, &stubAttachmentGetter{}, tt.blobs)
+
+			got, err := e.Execute(context.Background(), tt.in)
+
+			if tt.expectedErr != "" {
+				assert.Nil(t, got)
+				assert.EqualError(t, err, tt.expectedErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, permission.TaskView, tt.authorizer.action)
+			assert.Equal(t, "app.log", got.Attachment.FileName)
+
+			data, err := io.ReadAll(got.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantData, string(data))
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package download
+
+import (
+	"context"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+type Executor interface {
+	Execute(ctx context.Context, in DownloadInput) (*DownloadResult, error)
+}
+
+type handler struct {
+	exec Executor
+}
+
+func NewHandler(exec Executor) *handler {
+	return &handler{
+		exec: exec,
+	}
+}
+
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	userIdAny := r.Context().Value("userId")
+
+	userId, ok := userIdAny.(int)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
+	taskId, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("invalid task id"))
+
+		return
+	}
+
+	attachmentId, err := strconv.Atoi(chi.URLParam(r, "attachmentId"))
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("invalid attachment id"))
+
+		return
+	}
+
+	result, err := h.exec.Execute(r.Context(), DownloadInput{UserId: userId, TaskId: taskId, AttachmentId: attachmentId})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	defer result.Body.Close()
+
+	a := result.Attachment
+
+	// Файл всегда скачивается, а не открывается в браузере, и тип не угадывается заново.
+	w.Header().Set("Content-Type", a.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(a.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("X-Checksum-Sha256", a.Checksum)
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, result.Body); err != nil {
+		log.Printf("attachment %d download error: %v", a.Id, err)
+	}
+}
@@ -0,0 +1,65 @@
+package list
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/attachment"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type taskGetter interface {
+	GetTaskById(ctx context.Context, id int) (*task.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type attachmentLister interface {
+	ListTaskAttachments(ctx context.Context, taskId int) ([]attachment.Model, error)
+}
+
+type executor struct {
+	taskGetter       taskGetter
+	authorizer       authorizer
+	attachmentLister attachmentLister
+}
+
+func NewExecutor(taskGetter taskGetter, authorizer authorizer, attachmentLister attachmentLister) *executor {
+	return &executor{
+		taskGetter:       taskGetter,
+		authorizer:       authorizer,
+		attachmentLister: attachmentLister,
+	}
+}
+
+type ListInput struct {
+	UserId int
+	TaskId int
+}
+
+// Execute возвращает вложения задачи вместе с вложениями её комментариев.
+func (e *executor) Execute(ctx context.Context, in ListInput) ([]attachment.Model, error) {
+	t, err := e.taskGetter.GetTaskById(ctx, in.TaskId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("task not found")
+		}
+
+		return nil, err
+	}
+
+	_, err = e.authorizer.Authorize(ctx, in.UserId, permission.TaskView, permission.TaskResource(*t))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return e.attachmentLister.ListTaskAttachments(ctx, t.Id)
+}
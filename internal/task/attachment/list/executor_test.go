@@ -0,0 +1,110 @@
+package list
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/attachment"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubTaskGetter struct{}
+
+func (s *stubTaskGetter) GetTaskById(ctx context.Context, id int) (*task.Model, error) {
+	if id != 1 {
+		return nil, sql.ErrNoRows
+	}
+
+	return &task.Model{Id: 1, TeamId: 10}, nil
+}
+
+type stubAuthorizer struct {
+	err error
+}
+
+func (s *stubAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
+}
+
+type stubAttachmentLister struct {
+	attachments []attachment.Model
+	err         error
+}
+
+func (s *stubAttachmentLister) ListTaskAttachments(ctx context.Context, taskId int) ([]attachment.Model, error) {
+	return s.attachments, s.err
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	commentId := 4
+	attachments := []attachment.Model{
+		{Id: 1, TaskId: 1, UploaderId: 5, FileName: "screenshot.png"},
+		{Id: 2, TaskId: 1, CommentId: &commentId, UploaderId: 6, FileName: "app.log"},
+	}
+
+	tests := []struct {
+		name        string
+		authorizer  *stubAuthorizer
+		lister      *stubAttachmentLister
+		taskId      int
+		want        []attachment.Model
+		expectedErr string
+	}{
+		{
+			name:       "task and comment attachments",
+			authorizer: &stubAuthorizer{},
+			lister:     &stubAttachmentLister{attachments: attachments},
+			taskId:     1,
+			want:       attachments,
+		},
+		{
+			name:        "task not found",
+			authorizer:  &stubAuthorizer{},
+			lister:      &stubAttachmentLister{},
+			taskId:      2,
+			expectedErr: "task not found",
+		},
+		{
+			name:        "cannot view task",
+			authorizer:  &stubAuthorizer{err: permission.ErrForbidden},
+			lister:      &stubAttachmentLister{attachments: attachments},
+			taskId:      1,
+			expectedErr: "forbidden",
+		},
+		{
+			name:        "db error",
+			authorizer:  &stubAuthorizer{},
+			lister:      &stubAttachmentLister{err: errors.New("db error")},
+			taskId:      1,
+			expectedErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(&stubTaskGetter{}, tt.authorizer, tt.lister)
+
+			got, err := e.Execute(context.Background(), ListInput{UserId: 5, TaskId: tt.taskId})
+
+			if tt.expectedErr != "" {
+				assert.Nil(t, got)
+				assert.EqualError(t, err, tt.expectedErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package list
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task/attachment"
+)
+
+type response struct {
+	Attachments []attachment.Model `json:"attachments"`
+}
+
+type Executor interface {
+	Execute(ctx context.Context, in ListInput) ([]attachment.Model, error)
+}
+
+type handler struct {
+	exec Executor
+}
+
+func NewHandler(exec Executor) *handler {
+	return &handler{
+		exec: exec,
+	}
+}
+
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	userIdAny := r.Context().Value("userId")
+
+	userId, ok := userIdAny.(int)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
+	taskId, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("invalid task id"))
+
+		return
+	}
+
+	attachments, err := h.exec.Execute(r.Context(), ListInput{UserId: userId, TaskId: taskId})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	body, err := json.Marshal(response{Attachments: attachments})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
@@ -0,0 +1,105 @@
+package attachment
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+// DefaultMaxSize — предел размера файла, если он не задан в окружении.
+const DefaultMaxSize int64 = 20 << 20
+
+const maxFileNameLength = 255
+
+var (
+	ErrNotFound       = apperror.NotFound("attachment not found")
+	ErrTooLarge       = apperror.TooLarge("file is too large")
+	ErrTypeNotAllowed = apperror.UnsupportedMediaType("file type is not allowed")
+)
+
+// Тип определяется по содержимому, а не по заголовку клиента. HTML, SVG и прочее,
+// что браузер может исполнить, сюда не входит.
+var allowedTypes = []string{
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"video/mp4",
+	"video/webm",
+	"application/pdf",
+	"application/zip",
+	"application/x-gzip",
+	"text/plain",
+}
+
+type Model struct {
+	Id     int `json:"id"`
+	TaskId int `json:"task_id"`
+	// CommentId — комментарий, к которому приложен файл; nil у вложений самой задачи.
+	CommentId   *int      `json:"comment_id"`
+	UploaderId  int       `json:"uploader_id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Checksum    string    `json:"checksum_sha256"`
+	CreatedAt   time.Time `json:"created_at"`
+	StorageKey  string    `json:"-"`
+}
+
+// DetectType определяет тип по первым байтам файла (до 512).
+func DetectType(head []byte) string {
+	return http.DetectContentType(head)
+}
+
+// IsAllowedType проверяет тип без параметров: text/plain; charset=utf-8 — это text/plain.
+func IsAllowedType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+
+	return err == nil && slices.Contains(allowedTypes, mediaType)
+}
+
+// CleanFileName оставляет от имени файла клиента только последнюю часть пути без управляющих символов.
+func CleanFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == utf8.RuneError {
+			return -1
+		}
+
+		return r
+	}, name)
+
+	name = strings.TrimSpace(name)
+
+	if name == "" || name == "." || name == ".." || name == "/" {
+		return "file"
+	}
+
+	if utf8.RuneCountInString(name) > maxFileNameLength {
+		name = string([]rune(name)[:maxFileNameLength])
+	}
+
+	return name
+}
+
+// NewStorageKey выдаёт случайный ключ в хранилище; имя файла клиента в ключ не попадает.
+func NewStorageKey(taskId int) (string, error) {
+	buf := make([]byte, 16)
+
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return "tasks/" + strconv.Itoa(taskId) + "/" + hex.EncodeToString(buf), nil
+}
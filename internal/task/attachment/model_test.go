@@ -0,0 +1,62 @@
+package attachment
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{name: "plain", raw: "screenshot.png", want: "screenshot.png"},
+		{name: "unix path", raw: "/home/dev/logs/app.log", want: "app.log"},
+		{name: "windows path", raw: `C:\Users\dev\Desktop\отчёт.pdf`, want: "отчёт.pdf"},
+		{name: "control characters", raw: "bad\r\nname\x00.txt", want: "badname.txt"},
+		{name: "empty", raw: "  ", want: "file"},
+		{name: "only dots", raw: "../..", want: "file"},
+		{name: "too long", raw: strings.Repeat("я", 300), want: strings.Repeat("я", 255)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CleanFileName(tt.raw))
+		})
+	}
+}
+
+func TestDetectType(t *testing.T) {
+	tests := []struct {
+		name        string
+		head        []byte
+		wantAllowed bool
+	}{
+		{name: "png", head: []byte("\x89PNG\x0D\x0A\x1A\x0A rest"), wantAllowed: true},
+		{name: "log", head: []byte("2024-03-01 12:00:00 ERROR db timeout\n"), wantAllowed: true},
+		{name: "pdf", head: []byte("%PDF-1.7\n"), wantAllowed: true},
+		{name: "html", head: []byte("<!DOCTYPE html><script>alert(1)</script>"), wantAllowed: false},
+		{name: "svg", head: []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"></svg>`), wantAllowed: false},
+		{name: "binary", head: []byte{0x7f, 'E', 'L', 'F', 2, 1, 1, 0}, wantAllowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantAllowed, IsAllowedType(DetectType(tt.head)))
+		})
+	}
+}
+
+func TestNewStorageKey(t *testing.T) {
+	first, err := NewStorageKey(7)
+	assert.NoError(t, err)
+
+	second, err := NewStorageKey(7)
+	assert.NoError(t, err)
+
+	assert.Regexp(t, `^tasks/7/[0-9a-f]{32}$`, first)
+	assert.NotEqual(t, first, second)
+}
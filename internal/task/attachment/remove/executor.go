@@ -0,0 +1,121 @@
+package remove
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/attachment"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type taskGetter interface {
+	GetTaskById(ctx context.Context, id int) (*task.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type attachmentGetter interface {
+	GetTaskAttachment(ctx context.Context, id int) (*attachment.Model, error)
+}
+
+type attachmentRemover interface {
+	DeleteTaskAttachment(ctx context.Context, id int) error
+}
+
+type blobDeleter interface {
+	Delete(ctx context.Context, key string) error
+}
+
+type executor struct {
+	taskGetter        taskGetter
+	authorizer        authorizer
+	attachmentGetter  attachmentGetter
+	attachmentRemover attachmentRemover
+	blobs             blobDeleter
+}
+
+func NewExecutor(
+	taskGetter taskGetter,
+	authorizer authorizer,
+	attachmentGetter attachmentGetter,
+	attachmentRemover attachmentRemover,
+	blobs blobDeleter,
+) *executor {
+	return &executor{
+		taskGetter:        taskGetter,
+		authorizer:        authorizer,
+		attachmentGetter:  attachmentGetter,
+		attachmentRemover: attachmentRemover,
+		blobs:             blobs,
+	}
+}
+
+type RemoveInput struct {
+	UserId       int
+	TaskId       int
+	AttachmentId int
+}
+
+// Execute удаляет вложение. Право то же, что на правку того, к чему файл приложен:
+// task.edit для вложений задачи и comment.edit для вложений комментария; «своё» — загруженное самим пользователем.
+func (e *executor) Execute(ctx context.Context, in RemoveInput) error {
+	t, err := e.taskGetter.GetTaskById(ctx, in.TaskId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.NotFound("task not found")
+		}
+
+		return err
+	}
+
+	a, err := e.attachmentGetter.GetTaskAttachment(ctx, in.AttachmentId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return attachment.ErrNotFound
+		}
+
+		return err
+	}
+
+	if a.TaskId != t.Id {
+		return attachment.ErrNotFound
+	}
+
+	action := permission.TaskEdit
+
+	if a.CommentId != nil {
+		action = permission.CommentEdit
+	}
+
+	_, err = e.authorizer.Authorize(ctx, in.UserId, action, permission.AttachmentResource(t.TeamId, *a))
+
+	if err != nil {
+		return err
+	}
+
+	if t.IsArchived() {
+		return task.ErrArchived
+	}
+
+	err = e.attachmentRemover.DeleteTaskAttachment(ctx, a.Id)
+
+	if err != nil {
+		return err
+	}
+
+	// Описание уже удалено, файл никому не виден; неудачу только пишем в лог.
+	if err := e.blobs.Delete(ctx, a.StorageKey); err != nil {
+		log.Printf("attachment blob %s cleanup error: %v", a.StorageKey, err)
+	}
+
+	return nil
+}
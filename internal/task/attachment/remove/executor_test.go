@@ -0,0 +1,226 @@
+package remove
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/attachment"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubTaskGetter struct {
+	archived bool
+}
+
+func (s *stubTaskGetter) GetTaskById(ctx context.Context, id int) (*task.Model, error) {
+	if id != 1 {
+		return nil, sql.ErrNoRows
+	}
+
+	t := &task.Model{Id: 1, TeamId: 10}
+
+	if s.archived {
+		archivedAt := time.Now()
+		t.ArchivedAt = &archivedAt
+	}
+
+	return t, nil
+}
+
+type mockAuthorizer struct {
+	err      error
+	action   permission.Action
+	resource permission.Resource
+}
+
+func (m *mockAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	m.action = action
+	m.resource = res
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
+}
+
+type stubAttachmentGetter struct {
+	attachments map[int]attachment.Model
+}
+
+func (s *stubAttachmentGetter) GetTaskAttachment(ctx context.Context, id int) (*attachment.Model, error) {
+	a, ok := s.attachments[id]
+
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return &a, nil
+}
+
+type mockRemover struct {
+	err     error
+	removed []int
+}
+
+func (m *mockRemover) DeleteTaskAttachment(ctx context.Context, id int) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.removed = append(m.removed, id)
+
+	return nil
+}
+
+type mockBlobs struct {
+	err     error
+	deleted []string
+}
+
+func (m *mockBlobs) Delete(ctx context.Context, key string) error {
+	m.deleted = append(m.deleted, key)
+
+	return m.err
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	commentId := 4
+
+	attachments := map[int]attachment.Model{
+		1: {Id: 1, TaskId: 1, UploaderId: 5, StorageKey: "tasks/1/a"},
+		2: {Id: 2, TaskId: 1, CommentId: &commentId, UploaderId: 6, StorageKey: "tasks/1/b"},
+		3: {Id: 3, TaskId: 2, UploaderId: 5, StorageKey: "tasks/2/c"},
+	}
+
+	tests := []struct {
+		name         string
+		taskGetter   *stubTaskGetter
+		authorizer   *mockAuthorizer
+		remover      *mockRemover
+		blobs        *mockBlobs
+		attachmentId int
+		wantAction   permission.Action
+		wantOwner    int
+		wantRemoved  bool
+		expectedErr  string
+	}{
+		{
+			name:         "task attachment",
+			taskGetter:   &stubTaskGetter{},
+			authorizer:   &mockAuthorizer{},
+			remover:      &mockRemover{},
+			blobs:        &mockBlobs{},
+			attachmentId: 1,
+			wantAction:   permission.TaskEdit,
+			wantOwner:    5,
+			wantRemoved:  true,
+		},
+		{
+			name:         "comment attachment",
+			taskGetter:   &stubTaskGetter{},
+			authorizer:   &mockAuthorizer{},
+			remover:      &mockRemover{},
+			blobs:        &mockBlobs{},
+			attachmentId: 2,
+			wantAction:   permission.CommentEdit,
+			wantOwner:    6,
+			wantRemoved:  true,
+		},
+		{
+			name:         "storage error is not returned",
+			taskGetter:   &stubTaskGetter{},
+			authorizer:   &mockAuthorizer{},
+			remover:      &mockRemover{},
+			blobs:        &mockBlobs{err: errors.New("storage error")},
+			attachmentId: 1,
+			wantAction:   permission.TaskEdit,
+			wantOwner:    5,
+			wantRemoved:  true,
+		},
+		{
+			name:         "attachment of another task",
+			taskGetter:   &stubTaskGetter{},
+			authorizer:   &mockAuthorizer{},
+			remover:      &mockRemover{},
+			blobs:        &mockBlobs{},
+			attachmentId: 3,
+			expectedErr:  "attachment not found",
+		},
+		{
+			name:         "attachment not found",
+			taskGetter:   &stubTaskGetter{},
+			authorizer:   &mockAuthorizer{},
+			remover:      &mockRemover{},
+			blobs:        &mockBlobs{},
+			attachmentId: 9,
+			expectedErr:  "attachment not found",
+		},
+		{
+			name:         "forbidden",
+			taskGetter:   &stubTaskGetter{},
+			authorizer:   &mockAuthorizer{err: permission.ErrForbidden},
+			remover:      &mockRemover{},
+			blobs:        &mockBlobs{},
+			attachmentId: 1,
+			wantAction:   permission.TaskEdit,
+			wantOwner:    5,
+			expectedErr:  "forbidden",
+		},
+		{
+			name:         "archived task",
+			taskGetter:   &stubTaskGetter{archived: true},
+			authorizer:   &mockAuthorizer{},
+			remover:      &mockRemover{},
+			blobs:        &mockBlobs{},
+			attachmentId: 1,
+			wantAction:   permission.TaskEdit,
+			wantOwner:    5,
+			expectedErr:  "task is archived",
+		},
+		{
+			name:         "db error keeps the blob",
+			taskGetter:   &stubTaskGetter{},
+			authorizer:   &mockAuthorizer{},
+			remover:      &mockRemover{err: errors.New("db error")},
+			blobs:        &mockBlobs{},
+			attachmentId: 1,
+			wantAction:   permission.TaskEdit,
+			wantOwner:    5,
+			expectedErr:  "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(tt.taskGetter, tt.authorizer, &stubAttachmentGetter{attachments: attachments}, tt.remover, tt.blobs)
+
+			err := e.Execute(context.Background(), RemoveInput{UserId: 5, TaskId: 1, AttachmentId: tt.attachmentId})
+
+			assert.Equal(t, tt.wantAction, tt.authorizer.action)
+
+			if tt.wantOwner != 0 {
+				assert.Equal(t, []int{tt.wantOwner}, tt.authorizer.resource.OwnerIds)
+			}
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Empty(t, tt.remover.removed)
+				assert.Empty(t, tt.blobs.deleted)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantRemoved, len(tt.remover.removed) == 1)
+			assert.Equal(t, []string{attachments[tt.attachmentId].StorageKey}, tt.blobs.deleted)
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package upload
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/attachment"
+	"mkk-luna-test-task/internal/task/comment"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+// По стольким первым байтам определяется тип файла.
+const sniffLength = 512
+
+type taskGetter interface {
+	GetTaskById(ctx context.Context, id int) (*task.Model, error)
+}
+
+type commentGetter interface {
+	GetTaskComment(ctx context.Context, id int) (*comment.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type blobStorage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Delete(ctx context.Context, key string) error
+}
+
+type attachmentCreator interface {
+	CreateTaskAttachment(ctx context.Context, a attachment.Model) (*attachment.Model, error)
+}
+
+type executor struct {
+	taskGetter        taskGetter
+	commentGetter     commentGetter
+	authorizer        authorizer
+	blobs             blobStorage
+	attachmentCreator attachmentCreator
+	maxSize           int64
+}
+
+func NewExecutor(
+	taskGetter taskGetter,
+	commentGetter commentGetter,
+	authorizer authorizer,
+	blobs blobStorage,
+	attachmentCreator attachmentCreator,
+	maxSize int64,
+) *executor {
+	return &executor{
+		taskGetter:        taskGetter,
+		commentGetter:     commentGetter,
+		authorizer:        authorizer,
+		blobs:             blobs,
+		attachmentCreator: attachmentCreator,
+		maxSize:           maxSize,
+	}
+}
+
+type UploadInput struct {
+	UserId int
+	TaskId int
+	// CommentId — приложить файл к комментарию задачи, а не к самой задаче.
+	CommentId *int
+	FileName  string
+	Size      int64
+	Body      io.ReadSeeker
+}
+
+// Execute кладёт файл в хранилище и сохраняет его описание. К задаче прикладывает тот, кто может её править,
+// к комментарию — тот, кто может править комментарий.
+func (e *executor) Execute(ctx context.Context, in UploadInput) (*attachment.Model, error) {
+	t, err := e.taskGetter.GetTaskById(ctx, in.TaskId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("task not found")
+		}
+
+		return nil, err
+	}
+
+	if in.CommentId == nil {
+		_, err = e.authorizer.Authorize(ctx, in.UserId, permission.TaskEdit, permission.TaskResource(*t))
+	} else {
+		err = e.authorizeComment(ctx, in.UserId, *t, *in.CommentId)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if t.IsArchived() {
+		return nil, task.ErrArchived
+	}
+
+	if in.Size > e.maxSize {
+		return nil, attachment.ErrTooLarge
+	}
+
+	if in.Size == 0 {
+		return nil, apperror.InvalidField("file", "must not be empty")
+	}
+
+	contentType, checksum, err := inspect(in.Body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !attachment.IsAllowedType(contentType) {
+		return nil, attachment.ErrTypeNotAllowed
+	}
+
+	key, err := attachment.NewStorageKey(t.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = e.blobs.Put(ctx, key, in.Body, in.Size, contentType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := e.attachmentCreator.CreateTaskAttachment(ctx, attachment.Model{
+		TaskId:      t.Id,
+		CommentId:   in.CommentId,
+		UploaderId:  in.UserId,
+		FileName:    attachment.CleanFileName(in.FileName),
+		ContentType: contentType,
+		Size:        in.Size,
+		Checksum:    checksum,
+		StorageKey:  key,
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+	})
+
+	if err != nil {
+		// Без описания файл никому не виден, поэтому убираем его из хранилища.
+		if deleteErr := e.blobs.Delete(ctx, key); deleteErr != nil {
+			log.Printf("attachment blob %s cleanup error: %v", key, deleteErr)
+		}
+
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func (e *executor) authorizeComment(ctx context.Context, userId int, t task.Model, commentId int) error {
+	c, err := e.commentGetter.GetTaskComment(ctx, commentId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.NotFound("comment not found")
+		}
+
+		return err
+	}
+
+	if c.TaskId != t.Id {
+		return apperror.NotFound("comment not found")
+	}
+
+	_, err = e.authorizer.Authorize(ctx, userId, permission.CommentEdit, permission.CommentResource(t.TeamId, *c))
+
+	if err != nil {
+		return err
+	}
+
+	if c.IsDeleted() {
+		return comment.ErrDeleted
+	}
+
+	return nil
+}
+
+// inspect читает файл целиком: определяет тип по началу и считает SHA-256, затем возвращается в начало.
+func inspect(body io.ReadSeeker) (contentType string, checksum string, err error) {
+	head := make([]byte, sniffLength)
+
+	n, err := io.ReadFull(body, head)
+
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", "", err
+	}
+
+	hash := sha256.New()
+	hash.Write(head[:n])
+
+	if _, err := io.Copy(hash, body); err != nil {
+		return "", "", err
+	}
+
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return "", "", err
+	}
+
+	return attachment.DetectType(head[:n]), hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,315 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/attachment"
+	"mkk-luna-test-task/internal/task/comment"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubTaskGetter struct {
+	archived bool
+}
+
+func (s *stubTaskGetter) GetTaskById(ctx context.Context, id int) (*task.Model, error) {
+	if id != 1 {
+		return nil, sql.ErrNoRows
+	}
+
+	t := &task.Model{Id: 1, TeamId: 10, CreatorId: 5}
+
+	if s.archived {
+		archivedAt := time.Now()
+		t.ArchivedAt = &archivedAt
+	}
+
+	return t, nil
+}
+
+type stubCommentGetter struct {
+	comments map[int]comment.Model
+}
+
+func (s *stubCommentGetter) GetTaskComment(ctx context.Context, id int) (*comment.Model, error) {
+	c, ok := s.comments[id]
+
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return &c, nil
+}
+
+type mockAuthorizer struct {
+	err    error
+	action permission.Action
+}
+
+func (m *mockAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	m.action = action
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
+}
+
+type mockBlobs struct {
+	putErr      error
+	stored      map[string]string
+	contentType string
+	deleted     []string
+}
+
+func (m *mockBlobs) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	if m.putErr != nil {
+		return m.putErr
+	}
+
+	data, err := io.ReadAll(body)
+
+	if err != nil {
+		return err
+	}
+
+	m.stored = map[string]string{key: string(data)}
+	m.contentType = contentType
+
+	return nil
+}
+
+func (m *mockBlobs) Delete(ctx context.Context, key string) error {
+	m.deleted = append(m.deleted, key)
+
+	return nil
+}
+
+type mockCreator struct {
+	err error
+}
+
+func (m *mockCreator) CreateTaskAttachment(ctx context.Context, a attachment.Model) (*attachment.Model, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	a.Id = 3
+
+	return &a, nil
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	logData := "2024-03-01 12:00:00 ERROR db timeout\n"
+	// SHA-256 от logData.
+	logChecksum := "174422ad432e0eb1a841eaa3e08ac70c83f1143d69de8670079433ab5022e996"
+
+	commentId := 7
+	otherTaskCommentId := 8
+	deletedCommentId := 9
+	deletedAt := time.Now()
+
+	comments := map[int]comment.Model{
+		commentId:          {Id: commentId, TaskId: 1, CommenterId: 5},
+		otherTaskCommentId: {Id: otherTaskCommentId, TaskId: 2, CommenterId: 5},
+		deletedCommentId:   {Id: deletedCommentId, TaskId: 1, CommenterId: 5, DeletedAt: &deletedAt},
+	}
+
+	tests := []struct {
+		name        string
+		taskGetter  *stubTaskGetter
+		authorizer  *mockAuthorizer
+		blobs       *mockBlobs
+		creator     *mockCreator
+		in          UploadInput
+		data        string
+		wantAction  permission.Action
+		wantRemoved bool
+		expectedErr string
+	}{
+		{
+			name:       "task attachment",
+			taskGetter: &stubTaskGetter{},
+			authorizer: &mockAuthorizer{},
+			blobs:      &mockBlobs{},
+			creator:    &mockCreator{},
+			in:         UploadInput{UserId: 5, TaskId: 1, FileName: `C:\logs\app.log`},
+			data:       logData,
+			wantAction: permission.TaskEdit,
+		},
+		{
+			name:       "comment attachment",
+			taskGetter: &stubTaskGetter{},
+			authorizer: &mockAuthorizer{},
+			blobs:      &mockBlobs{},
+			creator:    &mockCreator{},
+			in:         UploadInput{UserId: 5, TaskId: 1, CommentId: &commentId, FileName: "app.log"},
+			data:       logData,
+			wantAction: permission.CommentEdit,
+		},
+		{
+			name:        "task not found",
+			taskGetter:  &stubTaskGetter{},
+			authorizer:  &mockAuthorizer{},
+			blobs:       &mockBlobs{},
+			creator:     &mockCreator{},
+			in:          UploadInput{UserId: 5, TaskId: 2, FileName: "app.log"},
+			data:        logData,
+			expectedErr: "task not found",
+		},
+		{
+			name:        "comment of another task",
+			taskGetter:  &stubTaskGetter{},
+			authorizer:  &mockAuthorizer{},
+			blobs:       &mockBlobs{},
+			creator:     &mockCreator{},
+			in:          UploadInput{UserId: 5, TaskId: 1, CommentId: &otherTaskCommentId, FileName: "app.log"},
+			data:        logData,
+			expectedErr: "comment not found",
+		},
+		{
+			name:        "deleted comment",
+			taskGetter:  &stubTaskGetter{},
+			authorizer:  &mockAuthorizer{},
+			blobs:       &mockBlobs{},
+			creator:     &mockCreator{},
+			in:          UploadInput{UserId: 5, TaskId: 1, CommentId: &deletedCommentId, FileName: "app.log"},
+			data:        logData,
+			wantAction:  permission.CommentEdit,
+			expectedErr: "comment is deleted",
+		},
+		{
+			name:        "forbidden",
+			taskGetter:  &stubTaskGetter{},
+			authorizer:  &mockAuthorizer{err: permission.ErrForbidden},
+			blobs:       &mockBlobs{},
+			creator:     &mockCreator{},
+			in:          UploadInput{UserId: 6, TaskId: 1, FileName: "app.log"},
+			data:        logData,
+			wantAction:  permission.TaskEdit,
+			expectedErr: "forbidden",
+		},
+		{
+			name:        "archived task",
+			taskGetter:  &stubTaskGetter{archived: true},
+			authorizer:  &mockAuthorizer{},
+			blobs:       &mockBlobs{},
+			creator:     &mockCreator{},
+			in:          UploadInput{UserId: 5, TaskId: 1, FileName: "app.log"},
+			data:        logData,
+			wantAction:  permission.TaskEdit,
+			expectedErr: "task is archived",
+		},
+		{
+			name:        "too large",
+			taskGetter:  &stubTaskGetter{},
+			authorizer:  &mockAuthorizer{},
+			blobs:       &mockBlobs{},
+			creator:     &mockCreator{},
+			in:          UploadInput{UserId: 5, TaskId: 1, FileName: "app.log"},
+			data:        string(make([]byte, 101)),
+			wantAction:  permission.TaskEdit,
+			expectedErr: "file is too large",
+		},
+		{
+			name:        "empty file",
+			taskGetter:  &stubTaskGetter{},
+			authorizer:  &mockAuthorizer{},
+			blobs:       &mockBlobs{},
+			creator:     &mockCreator{},
+			in:          UploadInput{UserId: 5, TaskId: 1, FileName: "app.log"},
+			data:        "",
+			wantAction:  permission.TaskEdit,
+			expectedErr: "validation failed",
+		},
+		{
+			name:        "type not allowed",
+			taskGetter:  &stubTaskGetter{},
+			authorizer:  &mockAuthorizer{},
+			blobs:       &mockBlobs{},
+			creator:     &mockCreator{},
+			in:          UploadInput{UserId: 5, TaskId: 1, FileName: "report.png"},
+			data:        "<html><script>alert(1)</script></html>",
+			wantAction:  permission.TaskEdit,
+			expectedErr: "file type is not allowed",
+		},
+		{
+			name:        "storage error",
+			taskGetter:  &stubTaskGetter{},
+			authorizer:  &mockAuthorizer{},
+			blobs:       &mockBlobs{putErr: errors.New("storage error")},
+			creator:     &mockCreator{},
+			in:          UploadInput{UserId: 5, TaskId: 1, FileName: "app.log"},
+			data:        logData,
+			wantAction:  permission.TaskEdit,
+			expectedErr: "storage error",
+		},
+		{
+			name:        "db error removes the blob",
+			taskGetter:  &stubTaskGetter{},
+			authorizer:  &mockAuthorizer{},
+			blobs:       &mockBlobs{},
+			creator:     &mockCreator{err: errors.New("db error")},
+			in:          UploadInput{UserId: 5, TaskId: 1, FileName: "app.log"},
+			data:        logData,
+			wantAction:  permission.TaskEdit,
+			wantRemoved: true,
+			expectedErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(tt.taskGetter, &stubCommentGetter{comments: comments}, tt.authorizer, tt.blobs, tt.creator, 100)
+
+			in := tt.in
+			in.Size = int64(len(tt.data))
+			in.Body = bytes.NewReader([]byte(tt.data))
+
+			got, err := e.Execute(context.Background(), in)
+
+			assert.Equal(t, tt.wantAction, tt.authorizer.action)
+
+			if tt.expectedErr != "" {
+				assert.Nil(t, got)
+				assert.EqualError(t, err, tt.expectedErr)
+
+				if tt.wantRemoved {
+					assert.Len(t, tt.blobs.stored, 1)
+
+					for key := range tt.blobs.stored {
+						assert.Equal(t, []string{key}, tt.blobs.deleted)
+					}
+				} else {
+					assert.Empty(t, tt.blobs.deleted)
+				}
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, 3, got.Id)
+			assert.Equal(t, 1, got.TaskId)
+			assert.Equal(t, tt.in.CommentId, got.CommentId)
+			assert.Equal(t, 5, got.UploaderId)
+			assert.Equal(t, "app.log", got.FileName)
+			assert.Equal(t, "text/plain; charset=utf-8", got.ContentType)
+			assert.Equal(t, int64(len(logData)), got.Size)
+			assert.Equal(t, logChecksum, got.Checksum)
+			assert.Regexp(t, `^tasks/1/[0-9a-f]{32}$`, got.StorageKey)
+			assert.Equal(t, map[string]string{got.StorageKey: logData}, tt.blobs.stored)
+			assert.Equal(t, got.ContentType, tt.blobs.contentType)
+		})
+	}
+}
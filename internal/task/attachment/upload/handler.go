@@ -0,0 +1,128 @@
+package upload
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task/attachment"
+)
+
+const (
+	// Сверх самого файла в теле есть границы и заголовки частей multipart.
+	multipartOverhead = 1 << 20
+	// Части до этого размера держатся в памяти, остальное net/http сбрасывает во временные файлы.
+	multipartMemory = 1 << 20
+)
+
+type Executor interface {
+	Execute(ctx context.Context, in UploadInput) (*attachment.Model, error)
+}
+
+type handler struct {
+	exec    Executor
+	maxSize int64
+}
+
+func NewHandler(exec Executor, maxSize int64) *handler {
+	return &handler{
+		exec:    exec,
+		maxSize: maxSize,
+	}
+}
+
+// Handle принимает multipart/form-data с файлом в поле file.
+// Маршрут с {commentId} прикладывает файл к комментарию.
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	userIdAny := r.Context().Value("userId")
+
+	userId, ok := userIdAny.(int)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
+	taskId, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("invalid task id"))
+
+		return
+	}
+
+	var commentId *int
+
+	if raw := chi.URLParam(r, "commentId"); raw != "" {
+		id, err := strconv.Atoi(raw)
+
+		if err != nil {
+			apperror.Write(w, apperror.Validation("invalid comment id"))
+
+			return
+		}
+
+		commentId = &id
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxSize+multipartOverhead)
+
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+
+		switch {
+		case errors.As(err, &tooLarge):
+			apperror.Write(w, attachment.ErrTooLarge)
+		case errors.Is(err, http.ErrNotMultipart):
+			apperror.Write(w, apperror.UnsupportedMediaType("expected multipart/form-data"))
+		default:
+			apperror.Write(w, apperror.Validation("invalid multipart form"))
+		}
+
+		return
+	}
+
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+
+	if err != nil {
+		apperror.Write(w, apperror.InvalidField("file", "is required"))
+
+		return
+	}
+
+	defer file.Close()
+
+	result, err := h.exec.Execute(r.Context(), UploadInput{
+		UserId:    userId,
+		TaskId:    taskId,
+		CommentId: commentId,
+		FileName:  header.Filename,
+		Size:      header.Size,
+		Body:      file,
+	})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	body, err := json.Marshal(result)
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write(body)
+}
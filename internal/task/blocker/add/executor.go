@@ -0,0 +1,89 @@
+package add
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+var errBlockerNotFound = apperror.NotFound("blocker not found")
+
+type taskGetter interface {
+	GetTaskById(ctx context.Context, id int) (*task.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type blockerAdder interface {
+	AddTaskBlocker(ctx context.Context, teamId, taskId, blockerId int) error
+}
+
+type executor struct {
+	taskGetter   taskGetter
+	authorizer   authorizer
+	blockerAdder blockerAdder
+}
+
+func NewExecutor(taskGetter taskGetter, authorizer authorizer, blockerAdder blockerAdder) *executor {
+	return &executor{
+		taskGetter:   taskGetter,
+		authorizer:   authorizer,
+		blockerAdder: blockerAdder,
+	}
+}
+
+type AddInput struct {
+	UserId    int
+	TaskId    int
+	BlockerId int
+}
+
+// Execute отмечает, что задача BlockerId из той же команды блокирует TaskId. Повторная отметка — не ошибка.
+func (e *executor) Execute(ctx context.Context, in AddInput) error {
+	t, err := e.taskGetter.GetTaskById(ctx, in.TaskId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.NotFound("task not found")
+		}
+
+		return err
+	}
+
+	_, err = e.authorizer.Authorize(ctx, in.UserId, permission.TaskEdit, permission.TaskResource(*t))
+
+	if err != nil {
+		return err
+	}
+
+	if t.IsArchived() {
+		return task.ErrArchived
+	}
+
+	blocker, err := e.taskGetter.GetTaskById(ctx, in.BlockerId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errBlockerNotFound
+		}
+
+		return err
+	}
+
+	if blocker.TeamId != t.TeamId {
+		return errBlockerNotFound
+	}
+
+	if blocker.IsArchived() {
+		return task.ErrArchived
+	}
+
+	return e.blockerAdder.AddTaskBlocker(ctx, t.TeamId, t.Id, blocker.Id)
+}
@@ -0,0 +1,152 @@
+package add
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubTaskGetter struct {
+	tasks map[int]*task.Model
+}
+
+func (s *stubTaskGetter) GetTaskById(ctx context.Context, id int) (*task.Model, error) {
+	t, ok := s.tasks[id]
+
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	copied := *t
+
+	return &copied, nil
+}
+
+type stubAuthorizer struct {
+	err error
+}
+
+func (s *stubAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
+}
+
+type mockBlockerAdder struct {
+	err    error
+	called bool
+}
+
+func (m *mockBlockerAdder) AddTaskBlocker(ctx context.Context, teamId, taskId, blockerId int) error {
+	m.called = true
+
+	return m.err
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	archivedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	blocked := &task.Model{Id: 1, TeamId: 10, CreatorId: 5}
+	blocker := &task.Model{Id: 2, TeamId: 10}
+
+	tests := []struct {
+		name        string
+		tasks       map[int]*task.Model
+		authorizer  *stubAuthorizer
+		adder       *mockBlockerAdder
+		wantCalled  bool
+		expectedErr string
+	}{
+		{
+			name:       "added",
+			tasks:      map[int]*task.Model{1: blocked, 2: blocker},
+			authorizer: &stubAuthorizer{},
+			adder:      &mockBlockerAdder{},
+			wantCalled: true,
+		},
+		{
+			name:        "task not found",
+			tasks:       map[int]*task.Model{2: blocker},
+			authorizer:  &stubAuthorizer{},
+			adder:       &mockBlockerAdder{},
+			expectedErr: "task not found",
+		},
+		{
+			name:        "cannot edit task",
+			tasks:       map[int]*task.Model{1: blocked, 2: blocker},
+			authorizer:  &stubAuthorizer{err: permission.ErrForbidden},
+			adder:       &mockBlockerAdder{},
+			expectedErr: "forbidden",
+		},
+		{
+			name:        "archived task",
+			tasks:       map[int]*task.Model{1: {Id: 1, TeamId: 10, ArchivedAt: &archivedAt}, 2: blocker},
+			authorizer:  &stubAuthorizer{},
+			adder:       &mockBlockerAdder{},
+			expectedErr: "task is archived",
+		},
+		{
+			name:        "blocker not found",
+			tasks:       map[int]*task.Model{1: blocked},
+			authorizer:  &stubAuthorizer{},
+			adder:       &mockBlockerAdder{},
+			expectedErr: "blocker not found",
+		},
+		{
+			name:        "blocker of another team",
+			tasks:       map[int]*task.Model{1: blocked, 2: {Id: 2, TeamId: 11}},
+			authorizer:  &stubAuthorizer{},
+			adder:       &mockBlockerAdder{},
+			expectedErr: "blocker not found",
+		},
+		{
+			name:        "archived blocker",
+			tasks:       map[int]*task.Model{1: blocked, 2: {Id: 2, TeamId: 10, ArchivedAt: &archivedAt}},
+			authorizer:  &stubAuthorizer{},
+			adder:       &mockBlockerAdder{},
+			expectedErr: "task is archived",
+		},
+		{
+			name:        "cycle",
+			tasks:       map[int]*task.Model{1: blocked, 2: blocker},
+			authorizer:  &stubAuthorizer{},
+			adder:       &mockBlockerAdder{err: task.ErrLinkCycle},
+			wantCalled:  true,
+			expectedErr: "link would create a cycle",
+		},
+		{
+			name:        "db error",
+			tasks:       map[int]*task.Model{1: blocked, 2: blocker},
+			authorizer:  &stubAuthorizer{},
+			adder:       &mockBlockerAdder{err: errors.New("db error")},
+			wantCalled:  true,
+			expectedErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(&stubTaskGetter{tasks: tt.tasks}, tt.authorizer, tt.adder)
+
+			err := e.Execute(context.Background(), AddInput{UserId: 5, TaskId: 1, BlockerId: 2})
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantCalled, tt.adder.called)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package add
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+type Executor interface {
+	Execute(ctx context.Context, in AddInput) error
+}
+
+type handler struct {
+	exec Executor
+}
+
+func NewHandler(exec Executor) *handler {
+	return &handler{
+		exec: exec,
+	}
+}
+
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	userIdAny := r.Context().Value("userId")
+
+	userId, ok := userIdAny.(int)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
+	taskId, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("invalid task id"))
+
+		return
+	}
+
+	blockerId, err := strconv.Atoi(chi.URLParam(r, "blockerId"))
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("invalid blocker id"))
+
+		return
+	}
+
+	err = h.exec.Execute(r.Context(), AddInput{
+		UserId:    userId,
+		TaskId:    taskId,
+		BlockerId: blockerId,
+	})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,69 @@
+package remove
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type taskGetter interface {
+	GetTaskById(ctx context.Context, id int) (*task.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type blockerRemover interface {
+	RemoveTaskBlocker(ctx context.Context, taskId, blockerId int) error
+}
+
+type executor struct {
+	taskGetter     taskGetter
+	authorizer     authorizer
+	blockerRemover blockerRemover
+}
+
+func NewExecutor(taskGetter taskGetter, authorizer authorizer, blockerRemover blockerRemover) *executor {
+	return &executor{
+		taskGetter:     taskGetter,
+		authorizer:     authorizer,
+		blockerRemover: blockerRemover,
+	}
+}
+
+type RemoveInput struct {
+	UserId    int
+	TaskId    int
+	BlockerId int
+}
+
+// Execute снимает блокировку TaskId задачей BlockerId. Снять отсутствующую блокировку — не ошибка.
+func (e *executor) Execute(ctx context.Context, in RemoveInput) error {
+	t, err := e.taskGetter.GetTaskById(ctx, in.TaskId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.NotFound("task not found")
+		}
+
+		return err
+	}
+
+	_, err = e.authorizer.Authorize(ctx, in.UserId, permission.TaskEdit, permission.TaskResource(*t))
+
+	if err != nil {
+		return err
+	}
+
+	if t.IsArchived() {
+		return task.ErrArchived
+	}
+
+	return e.blockerRemover.RemoveTaskBlocker(ctx, t.Id, in.BlockerId)
+}
@@ -0,0 +1,121 @@
+package remove
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubTaskGetter struct {
+	task *task.Model
+}
+
+func (s *stubTaskGetter) GetTaskById(ctx context.Context, id int) (*task.Model, error) {
+	if s.task == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	t := *s.task
+
+	return &t, nil
+}
+
+type stubAuthorizer struct {
+	err error
+}
+
+func (s *stubAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
+}
+
+type mockBlockerRemover struct {
+	err    error
+	called bool
+}
+
+func (m *mockBlockerRemover) RemoveTaskBlocker(ctx context.Context, taskId, blockerId int) error {
+	m.called = true
+
+	return m.err
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	archivedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	activeTask := &task.Model{Id: 1, TeamId: 10, CreatorId: 5}
+	archivedTask := &task.Model{Id: 1, TeamId: 10, CreatorId: 5, ArchivedAt: &archivedAt}
+
+	tests := []struct {
+		name        string
+		taskGetter  *stubTaskGetter
+		authorizer  *stubAuthorizer
+		remover     *mockBlockerRemover
+		wantCalled  bool
+		expectedErr string
+	}{
+		{
+			name:       "removed",
+			taskGetter: &stubTaskGetter{task: activeTask},
+			authorizer: &stubAuthorizer{},
+			remover:    &mockBlockerRemover{},
+			wantCalled: true,
+		},
+		{
+			name:        "task not found",
+			taskGetter:  &stubTaskGetter{},
+			authorizer:  &stubAuthorizer{},
+			remover:     &mockBlockerRemover{},
+			expectedErr: "task not found",
+		},
+		{
+			name:        "cannot edit task",
+			taskGetter:  &stubTaskGetter{task: activeTask},
+			authorizer:  &stubAuthorizer{err: permission.ErrForbidden},
+			remover:     &mockBlockerRemover{},
+			expectedErr: "forbidden",
+		},
+		{
+			name:        "archived task",
+			taskGetter:  &stubTaskGetter{task: archivedTask},
+			authorizer:  &stubAuthorizer{},
+			remover:     &mockBlockerRemover{},
+			expectedErr: "task is archived",
+		},
+		{
+			name:        "db error",
+			taskGetter:  &stubTaskGetter{task: activeTask},
+			authorizer:  &stubAuthorizer{},
+			remover:     &mockBlockerRemover{err: errors.New("db error")},
+			wantCalled:  true,
+			expectedErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(tt.taskGetter, tt.authorizer, tt.remover)
+
+			err := e.Execute(context.Background(), RemoveInput{UserId: 5, TaskId: 1, BlockerId: 2})
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantCalled, tt.remover.called)
+		})
+	}
+}
@@ -2,57 +2,180 @@ package create
 
 import (
 	"context"
-	"fmt"
+	"database/sql"
+	"errors"
+	"log"
 	"time"
 
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
 	"mkk-luna-test-task/internal/task/comment"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+	"mkk-luna-test-task/internal/user/notification"
 )
 
 type taskCommentCreator interface {
-	CreateTaskComment(ctx context.Context, commenterId int, taskId int, text string, createdAt time.Time) (*comment.Model, error)
+	CreateTaskComment(
+		ctx context.Context,
+		commenterId int,
+		taskId int,
+		parentCommentId *int,
+		text string,
+		mentionIds []int,
+		createdAt time.Time,
+	) (*comment.Model, error)
 }
 
-type teamMembershipChecker interface {
-	IsPartOfTeamByTaskId(userId, taskId int) (bool, error)
+type taskGetter interface {
+	GetTaskById(ctx context.Context, id int) (*task.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type commentGetter interface {
+	GetTaskComment(ctx context.Context, id int) (*comment.Model, error)
+}
+
+type memberResolver interface {
+	GetMemberIdsByUsernames(ctx context.Context, teamId int, usernames []string) (map[string]int, error)
+}
+
+type watcherLister interface {
+	ListTaskWatchers(ctx context.Context, taskId int) ([]int, error)
+}
+
+type notificationCreator interface {
+	CreateNotifications(ctx context.Context, notifications []notification.Model) error
 }
 
 type executor struct {
-	taskCommentCreator    taskCommentCreator
-	teamMembershipChecker teamMembershipChecker
+	taskCommentCreator  taskCommentCreator
+	taskGetter          taskGetter
+	authorizer          authorizer
+	commentGetter       commentGetter
+	memberResolver      memberResolver
+	watcherLister       watcherLister
+	notificationCreator notificationCreator
 }
 
 func NewExecutor(
 	taskCommentCreator taskCommentCreator,
-	teamMembershipChecker teamMembershipChecker,
+	taskGetter taskGetter,
+	authorizer authorizer,
+	commentGetter commentGetter,
+	memberResolver memberResolver,
+	watcherLister watcherLister,
+	notificationCreator notificationCreator,
 ) *executor {
 	return &executor{
-		taskCommentCreator:    taskCommentCreator,
-		teamMembershipChecker: teamMembershipChecker,
+		taskCommentCreator:  taskCommentCreator,
+		taskGetter:          taskGetter,
+		authorizer:          authorizer,
+		commentGetter:       commentGetter,
+		memberResolver:      memberResolver,
+		watcherLister:       watcherLister,
+		notificationCreator: notificationCreator,
 	}
 }
 
 type CreateInput struct {
 	CommenterId int
 	TaskId      int
-	Text        string
+	// ParentCommentId — комментарий той же задачи, на который это ответ; nil — новая ветка.
+	ParentCommentId *int
+	Text            string
 }
 
 func (e *executor) Execute(ctx context.Context, in CreateInput) (id int, err error) {
-	isMember, err := e.teamMembershipChecker.IsPartOfTeamByTaskId(in.CommenterId, in.TaskId)
+	t, err := e.taskGetter.GetTaskById(ctx, in.TaskId)
 
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, apperror.NotFound("task not found")
+		}
+
 		return 0, err
 	}
 
-	if !isMember {
-		return 0, fmt.Errorf("forbidden")
+	_, err = e.authorizer.Authorize(ctx, in.CommenterId, permission.CommentCreate, permission.TaskResource(*t))
+
+	if err != nil {
+		return 0, err
+	}
+
+	// Архивная задача доступна только для чтения.
+	if t.IsArchived() {
+		return 0, task.ErrArchived
+	}
+
+	if in.ParentCommentId != nil {
+		if err := e.checkParent(ctx, in.TaskId, *in.ParentCommentId); err != nil {
+			return 0, err
+		}
+	}
+
+	// Упоминания ищем только среди участников команды задачи, остальные @слова — просто текст.
+	members, err := e.memberResolver.GetMemberIdsByUsernames(ctx, t.TeamId, comment.ParseMentions(in.Text))
+
+	if err != nil {
+		return 0, err
 	}
 
-	model, err := e.taskCommentCreator.CreateTaskComment(ctx, in.CommenterId, in.TaskId, in.Text, time.Now())
+	mentionIds := comment.MentionIds(members, in.CommenterId)
+
+	model, err := e.taskCommentCreator.CreateTaskComment(
+		ctx,
+		in.CommenterId,
+		in.TaskId,
+		in.ParentCommentId,
+		in.Text,
+		mentionIds,
+		time.Now(),
+	)
 
 	if err != nil {
 		return 0, err
 	}
 
+	e.notify(ctx, *t, *model, mentionIds)
+
 	return model.Id, nil
 }
+
+// notify сообщает о комментарии упомянутым, наблюдателям и исполнителям задачи.
+// Комментарий уже сохранён, поэтому ошибки уведомлений только пишутся в лог.
+func (e *executor) notify(ctx context.Context, t task.Model, c comment.Model, mentionIds []int) {
+	watcherIds, err := e.watcherLister.ListTaskWatchers(ctx, t.Id)
+
+	if err != nil {
+		log.Printf("task %d watchers for notifications error: %v", t.Id, err)
+	}
+
+	notifications := notification.NewComment(t, c.Id, watcherIds, mentionIds, c.CommenterId, c.CreatedAt)
+
+	if err := e.notificationCreator.CreateNotifications(ctx, notifications); err != nil {
+		log.Printf("comment %d notifications error: %v", c.Id, err)
+	}
+}
+
+// checkParent — отвечать можно только на неудалённый комментарий той же задачи.
+func (e *executor) checkParent(ctx context.Context, taskId, parentId int) error {
+	parent, err := e.commentGetter.GetTaskComment(ctx, parentId)
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if err != nil || parent.TaskId != taskId {
+		return apperror.InvalidField("parent_comment_id", "must be a comment of the same task")
+	}
+
+	if parent.IsDeleted() {
+		return comment.ErrDeleted
+	}
+
+	return nil
+}
@@ -2,55 +2,170 @@ package create
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
 	"time"
 
+	"mkk-luna-test-task/internal/task"
 	"mkk-luna-test-task/internal/task/comment"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+	"mkk-luna-test-task/internal/user/notification"
 
 	"github.com/stretchr/testify/assert"
 )
 
 type stubTaskCommentCreatorSuccess struct{}
 
-func (s *stubTaskCommentCreatorSuccess) CreateTaskComment(ctx context.Context, commenterId int, taskId int, text string, createdAt time.Time) (*comment.Model, error) {
+func (s *stubTaskCommentCreatorSuccess) CreateTaskComment(
+	ctx context.Context,
+	commenterId int,
+	taskId int,
+	parentCommentId *int,
+	text string,
+	mentionIds []int,
+	createdAt time.Time,
+) (*comment.Model, error) {
 	return &comment.Model{
-		Id:          42,
-		TaskId:      taskId,
-		CommenterId: commenterId,
-		Text:        text,
-		CreatedAt:   createdAt,
+		Id:              42,
+		TaskId:          taskId,
+		CommenterId:     commenterId,
+		Text:            text,
+		CreatedAt:       createdAt,
+		ParentCommentId: parentCommentId,
 	}, nil
 }
 
 type stubTaskCommentCreatorError struct{}
 
-func (s *stubTaskCommentCreatorError) CreateTaskComment(ctx context.Context, commenterId int, taskId int, text string, createdAt time.Time) (*comment.Model, error) {
+func (s *stubTaskCommentCreatorError) CreateTaskComment(
+	ctx context.Context,
+	commenterId int,
+	taskId int,
+	parentCommentId *int,
+	text string,
+	mentionIds []int,
+	createdAt time.Time,
+) (*comment.Model, error) {
 	return nil, errors.New("db error")
 }
 
-type stubTeamMembershipCheckerMember struct{}
+type stubTaskGetter struct{}
 
-func (s *stubTeamMembershipCheckerMember) IsPartOfTeamByTaskId(userId, taskId int) (bool, error) {
-	return true, nil
+func (s *stubTaskGetter) GetTaskById(ctx context.Context, id int) (*task.Model, error) {
+	if id == 404 {
+		return nil, sql.ErrNoRows
+	}
+
+	if id == 410 {
+		archivedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		return &task.Model{Id: id, TeamId: 42, CreatorId: 1, AssigneeIds: []int{2}, ArchivedAt: &archivedAt}, nil
+	}
+
+	return &task.Model{Id: id, TeamId: 42, CreatorId: 1, AssigneeIds: []int{2}}, nil
+}
+
+// stubCommentGetter знает комментарии 10 (задача 2), 11 (задача 3) и удалённый 12 (задача 2).
+type stubCommentGetter struct{}
+
+func (s *stubCommentGetter) GetTaskComment(ctx context.Context, id int) (*comment.Model, error) {
+	deletedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	switch id {
+	case 10:
+		return &comment.Model{Id: 10, TaskId: 2, CommenterId: 3}, nil
+	case 11:
+		return &comment.Model{Id: 11, TaskId: 3, CommenterId: 3}, nil
+	case 12:
+		return &comment.Model{Id: 12, TaskId: 2, CommenterId: 3, DeletedAt: &deletedAt}, nil
+	}
+
+	return nil, sql.ErrNoRows
+}
+
+// stubMemberResolver — в команде состоят dev1 (3), qa (7) и author (1).
+type stubMemberResolver struct{}
+
+func (s *stubMemberResolver) GetMemberIdsByUsernames(ctx context.Context, teamId int, usernames []string) (map[string]int, error) {
+	members := map[string]int{"dev1": 3, "qa": 7, "author": 1}
+	ids := make(map[string]int)
+
+	for _, username := range usernames {
+		if id, ok := members[username]; ok {
+			ids[username] = id
+		}
+	}
+
+	return ids, nil
+}
+
+type mockTaskCommentCreator struct {
+	parentCommentId *int
+	mentionIds      []int
+}
+
+func (m *mockTaskCommentCreator) CreateTaskComment(
+	ctx context.Context,
+	commenterId int,
+	taskId int,
+	parentCommentId *int,
+	text string,
+	mentionIds []int,
+	createdAt time.Time,
+) (*comment.Model, error) {
+	m.parentCommentId = parentCommentId
+	m.mentionIds = mentionIds
+
+	return &comment.Model{Id: 42, TaskId: taskId, CommenterId: commenterId, Text: text, ParentCommentId: parentCommentId}, nil
+}
+
+// stubWatcherLister — за задачей наблюдают author (1), 5 и qa (7).
+type stubWatcherLister struct {
+	err error
+}
+
+func (s *stubWatcherLister) ListTaskWatchers(ctx context.Context, taskId int) ([]int, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return []int{1, 5, 7}, nil
+}
+
+type mockNotificationCreator struct {
+	notifications []notification.Model
+}
+
+func (m *mockNotificationCreator) CreateNotifications(ctx context.Context, notifications []notification.Model) error {
+	m.notifications = append(m.notifications, notifications...)
+
+	return nil
 }
 
-type stubTeamMembershipCheckerNotMember struct{}
+type stubAuthorizerMember struct{}
 
-func (s *stubTeamMembershipCheckerNotMember) IsPartOfTeamByTaskId(userId, taskId int) (bool, error) {
-	return false, nil
+func (s *stubAuthorizerMember) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
 }
 
-type stubTeamMembershipCheckerError struct{}
+type stubAuthorizerNotMember struct{}
 
-func (s *stubTeamMembershipCheckerError) IsPartOfTeamByTaskId(userId, taskId int) (bool, error) {
-	return false, errors.New("membership error")
+func (s *stubAuthorizerNotMember) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	return nil, permission.ErrForbidden
+}
+
+type stubAuthorizerError struct{}
+
+func (s *stubAuthorizerError) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	return nil, errors.New("membership error")
 }
 
 func TestExecutor_Execute(t *testing.T) {
 	type fields struct {
-		taskCommentCreator    taskCommentCreator
-		teamMembershipChecker teamMembershipChecker
+		taskCommentCreator taskCommentCreator
+		authorizer         authorizer
 	}
 	type args struct {
 		ctx context.Context
@@ -67,8 +182,8 @@ func TestExecutor_Execute(t *testing.T) {
 		{
 			name: "success - creates comment",
 			fields: fields{
-				taskCommentCreator:    &stubTaskCommentCreatorSuccess{},
-				teamMembershipChecker: &stubTeamMembershipCheckerMember{},
+				taskCommentCreator: &stubTaskCommentCreatorSuccess{},
+				authorizer:         &stubAuthorizerMember{},
 			},
 			args: args{
 				ctx: context.Background(),
@@ -84,8 +199,8 @@ func TestExecutor_Execute(t *testing.T) {
 		{
 			name: "not a member - forbidden",
 			fields: fields{
-				taskCommentCreator:    &stubTaskCommentCreatorSuccess{},
-				teamMembershipChecker: &stubTeamMembershipCheckerNotMember{},
+				taskCommentCreator: &stubTaskCommentCreatorSuccess{},
+				authorizer:         &stubAuthorizerNotMember{},
 			},
 			args: args{
 				ctx: context.Background(),
@@ -102,8 +217,8 @@ func TestExecutor_Execute(t *testing.T) {
 		{
 			name: "membership checker error",
 			fields: fields{
-				taskCommentCreator:    &stubTaskCommentCreatorSuccess{},
-				teamMembershipChecker: &stubTeamMembershipCheckerError{},
+				taskCommentCreator: &stubTaskCommentCreatorSuccess{},
+				authorizer:         &stubAuthorizerError{},
 			},
 			args: args{
 				ctx: context.Background(),
@@ -117,11 +232,29 @@ func TestExecutor_Execute(t *testing.T) {
 			wantErr:     true,
 			expectedErr: errors.New("membership error"),
 		},
+		{
+			name: "archived task - read only",
+			fields: fields{
+				taskCommentCreator: &stubTaskCommentCreatorSuccess{},
+				authorizer:         &stubAuthorizerMember{},
+			},
+			args: args{
+				ctx: context.Background(),
+				in: CreateInput{
+					CommenterId: 1,
+					TaskId:      410,
+					Text:        "hi",
+				},
+			},
+			wantId:      0,
+			wantErr:     true,
+			expectedErr: errors.New("task is archived"),
+		},
 		{
 			name: "comment creator error",
 			fields: fields{
-				taskCommentCreator:    &stubTaskCommentCreatorError{},
-				teamMembershipChecker: &stubTeamMembershipCheckerMember{},
+				taskCommentCreator: &stubTaskCommentCreatorError{},
+				authorizer:         &stubAuthorizerMember{},
 			},
 			args: args{
 				ctx: context.Background(),
@@ -139,8 +272,13 @@ func TestExecutor_Execute(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			e := &executor{
-				taskCommentCreator:    tt.fields.taskCommentCreator,
-				teamMembershipChecker: tt.fields.teamMembershipChecker,
+				taskCommentCreator:  tt.fields.taskCommentCreator,
+				taskGetter:          &stubTaskGetter{},
+				authorizer:          tt.fields.authorizer,
+				commentGetter:       &stubCommentGetter{},
+				memberResolver:      &stubMemberResolver{},
+				watcherLister:       &stubWatcherLister{},
+				notificationCreator: &mockNotificationCreator{},
 			}
 
 			gotId, err := e.Execute(tt.args.ctx, tt.args.in)
@@ -159,3 +297,130 @@ func TestExecutor_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestExecutor_Execute_RepliesAndMentions(t *testing.T) {
+	parentId := func(id int) *int { return &id }
+
+	tests := []struct {
+		name           string
+		in             CreateInput
+		wantParentId   *int
+		wantMentionIds []int
+		expectedErr    string
+	}{
+		{
+			name:           "mentions of team members only, author excluded",
+			in:             CreateInput{CommenterId: 1, TaskId: 2, Text: "@qa и @dev1, гляньте; @author, @stranger"},
+			wantMentionIds: []int{3, 7},
+		},
+		{
+			name:           "reply in thread",
+			in:             CreateInput{CommenterId: 1, TaskId: 2, ParentCommentId: parentId(10), Text: "согласен"},
+			wantParentId:   parentId(10),
+			wantMentionIds: []int{},
+		},
+		{
+			name:        "reply to comment of another task",
+			in:          CreateInput{CommenterId: 1, TaskId: 2, ParentCommentId: parentId(11), Text: "согласен"},
+			expectedErr: "validation failed",
+		},
+		{
+			name:        "reply to unknown comment",
+			in:          CreateInput{CommenterId: 1, TaskId: 2, ParentCommentId: parentId(99), Text: "согласен"},
+			expectedErr: "validation failed",
+		},
+		{
+			name:        "reply to deleted comment",
+			in:          CreateInput{CommenterId: 1, TaskId: 2, ParentCommentId: parentId(12), Text: "согласен"},
+			expectedErr: "comment is deleted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creator := &mockTaskCommentCreator{}
+
+			e := NewExecutor(creator, &stubTaskGetter{}, &stubAuthorizerMember{}, &stubCommentGetter{}, &stubMemberResolver{}, &stubWatcherLister{}, &mockNotificationCreator{})
+
+			id, err := e.Execute(context.Background(), tt.in)
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Zero(t, id)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, 42, id)
+			assert.Equal(t, tt.wantParentId, creator.parentCommentId)
+			assert.Equal(t, tt.wantMentionIds, creator.mentionIds)
+		})
+	}
+}
+
+func TestExecutor_Execute_Notifications(t *testing.T) {
+	type sent struct {
+		UserId int
+		Kind   notification.Kind
+	}
+
+	tests := []struct {
+		name     string
+		in       CreateInput
+		watchers *stubWatcherLister
+		want     []sent
+	}{
+		{
+			name:     "watchers and assignees",
+			in:       CreateInput{CommenterId: 1, TaskId: 2, Text: "готово"},
+			watchers: &stubWatcherLister{},
+			want: []sent{
+				{UserId: 5, Kind: notification.CommentCreatedKind},
+				{UserId: 7, Kind: notification.CommentCreatedKind},
+				{UserId: 2, Kind: notification.CommentCreatedKind},
+			},
+		},
+		{
+			name:     "mentioned get a mention instead",
+			in:       CreateInput{CommenterId: 1, TaskId: 2, Text: "@qa @dev1 гляньте"},
+			watchers: &stubWatcherLister{},
+			want: []sent{
+				{UserId: 3, Kind: notification.CommentMentionKind},
+				{UserId: 7, Kind: notification.CommentMentionKind},
+				{UserId: 5, Kind: notification.CommentCreatedKind},
+				{UserId: 2, Kind: notification.CommentCreatedKind},
+			},
+		},
+		{
+			name:     "watchers error keeps the rest",
+			in:       CreateInput{CommenterId: 1, TaskId: 2, Text: "@dev1 глянь"},
+			watchers: &stubWatcherLister{err: errors.New("db error")},
+			want: []sent{
+				{UserId: 3, Kind: notification.CommentMentionKind},
+				{UserId: 2, Kind: notification.CommentCreatedKind},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier := &mockNotificationCreator{}
+
+			e := NewExecutor(&mockTaskCommentCreator{}, &stubTaskGetter{}, &stubAuthorizerMember{}, &stubCommentGetter{}, &stubMemberResolver{}, tt.watchers, notifier)
+
+			_, err := e.Execute(context.Background(), tt.in)
+			assert.NoError(t, err)
+
+			var got []sent
+
+			for _, n := range notifier.notifications {
+				got = append(got, sent{UserId: n.UserId, Kind: n.Kind})
+				assert.Equal(t, 42, *n.CommentId)
+				assert.Equal(t, 2, *n.TaskId)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
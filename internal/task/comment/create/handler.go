@@ -8,10 +8,14 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/validation"
 )
 
 type request struct {
-	Text string `json:"text"`
+	Text            string `json:"text" validate:"required,max=5000"`
+	ParentCommentId *int   `json:"parent_comment_id"`
 }
 
 type response struct {
@@ -40,7 +44,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	userId, ok := userIdAny.(int)
 
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
 
 		return
 	}
@@ -50,7 +54,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	taskId, err := strconv.Atoi(taskIdStr)
 
 	if err != nil {
-		http.Error(w, "invalid task id", http.StatusBadRequest)
+		apperror.Write(w, apperror.Validation("invalid task id"))
 
 		return
 	}
@@ -59,7 +63,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
 	if err != nil {
-		http.Error(w, "error", http.StatusInternalServerError)
+		apperror.Write(w, err)
 
 		return
 	}
@@ -67,19 +71,26 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	var req request
 
 	if err := json.Unmarshal(body, &req); err != nil {
-		http.Error(w, "json error", http.StatusBadRequest)
+		apperror.Write(w, apperror.Validation("invalid json"))
+
+		return
+	}
+
+	if err := validation.Struct(req); err != nil {
+		apperror.Write(w, err)
 
 		return
 	}
 
 	id, err := h.exec.Execute(r.Context(), CreateInput{
-		CommenterId: userId,
-		TaskId:      taskId,
-		Text:        req.Text,
+		CommenterId:     userId,
+		TaskId:          taskId,
+		ParentCommentId: req.ParentCommentId,
+		Text:            req.Text,
 	})
 
 	if err != nil {
-		http.Error(w, "error", http.StatusBadRequest)
+		apperror.Write(w, err)
 
 		return
 	}
@@ -89,7 +100,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	respBody, err := json.Marshal(resp)
 
 	if err != nil {
-		http.Error(w, "json error", http.StatusInternalServerError)
+		apperror.Write(w, err)
 
 		return
 	}
@@ -0,0 +1,155 @@
+package edit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/comment"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type commentGetter interface {
+	GetTaskComment(ctx context.Context, id int) (*comment.Model, error)
+}
+
+type commentUpdater interface {
+	UpdateTaskComment(ctx context.Context, id int, text string, mentionIds []int, editedBy int, editedAt time.Time) (*comment.Model, error)
+}
+
+type taskGetter interface {
+	GetTaskById(ctx context.Context, id int) (*task.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type memberResolver interface {
+	GetMemberIdsByUsernames(ctx context.Context, teamId int, usernames []string) (map[string]int, error)
+}
+
+type executor struct {
+	commentGetter  commentGetter
+	commentUpdater commentUpdater
+	taskGetter     taskGetter
+	authorizer     authorizer
+	memberResolver memberResolver
+}
+
+func NewExecutor(
+	commentGetter commentGetter,
+	commentUpdater commentUpdater,
+	taskGetter taskGetter,
+	authorizer authorizer,
+	memberResolver memberResolver,
+) *executor {
+	return &executor{
+		commentGetter:  commentGetter,
+		commentUpdater: commentUpdater,
+		taskGetter:     taskGetter,
+		authorizer:     authorizer,
+		memberResolver: memberResolver,
+	}
+}
+
+type EditInput struct {
+	UserId    int
+	TaskId    int
+	CommentId int
+	Text      string
+}
+
+type EditResult struct {
+	Id              int
+	CreatedAt       time.Time
+	CommenterId     int
+	TaskId          int
+	Text            string
+	UpdatedAt       *time.Time
+	ParentCommentId *int
+}
+
+// Execute меняет текст комментария: автор правит свои, admin — любые. Прежний текст сохраняется.
+func (e *executor) Execute(ctx context.Context, in EditInput) (*EditResult, error) {
+	c, err := e.commentGetter.GetTaskComment(ctx, in.CommentId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("comment not found")
+		}
+
+		return nil, err
+	}
+
+	if c.TaskId != in.TaskId {
+		return nil, apperror.NotFound("comment not found")
+	}
+
+	t, err := e.taskGetter.GetTaskById(ctx, in.TaskId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("task not found")
+		}
+
+		return nil, err
+	}
+
+	_, err = e.authorizer.Authorize(ctx, in.UserId, permission.CommentEdit, permission.CommentResource(t.TeamId, *c))
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Архивная задача доступна только для чтения.
+	if t.IsArchived() {
+		return nil, task.ErrArchived
+	}
+
+	if c.IsDeleted() {
+		return nil, comment.ErrDeleted
+	}
+
+	if c.Text == in.Text {
+		return newEditResult(c), nil
+	}
+
+	// Упоминания пересчитываются по новому тексту; упоминания принадлежат автору, даже если правит admin.
+	members, err := e.memberResolver.GetMemberIdsByUsernames(ctx, t.TeamId, comment.ParseMentions(in.Text))
+
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := e.commentUpdater.UpdateTaskComment(
+		ctx,
+		c.Id,
+		in.Text,
+		comment.MentionIds(members, c.CommenterId),
+		in.UserId,
+		time.Now(),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newEditResult(updated), nil
+}
+
+func newEditResult(c *comment.Model) *EditResult {
+	return &EditResult{
+		Id:              c.Id,
+		CreatedAt:       c.CreatedAt,
+		CommenterId:     c.CommenterId,
+		TaskId:          c.TaskId,
+		Text:            c.Text,
+		UpdatedAt:       c.UpdatedAt,
+		ParentCommentId: c.ParentCommentId,
+	}
+}
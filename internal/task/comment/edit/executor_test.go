@@ -0,0 +1,241 @@
+package edit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/comment"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubCommentGetter struct {
+	comment *comment.Model
+}
+
+func (s *stubCommentGetter) GetTaskComment(ctx context.Context, id int) (*comment.Model, error) {
+	if s.comment == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	c := *s.comment
+
+	return &c, nil
+}
+
+type mockCommentUpdater struct {
+	err        error
+	called     bool
+	mentionIds []int
+}
+
+func (m *mockCommentUpdater) UpdateTaskComment(ctx context.Context, id int, text string, mentionIds []int, editedBy int, editedAt time.Time) (*comment.Model, error) {
+	m.called = true
+	m.mentionIds = mentionIds
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &comment.Model{Id: id, TaskId: 1, CommenterId: 5, Text: text, UpdatedAt: &editedAt}, nil
+}
+
+type stubTaskGetter struct {
+	task *task.Model
+}
+
+func (s *stubTaskGetter) GetTaskById(ctx context.Context, id int) (*task.Model, error) {
+	if s.task == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	t := *s.task
+
+	return &t, nil
+}
+
+// stubMemberResolver — в команде состоят author (5) и qa (7).
+type stubMemberResolver struct{}
+
+func (s *stubMemberResolver) GetMemberIdsByUsernames(ctx context.Context, teamId int, usernames []string) (map[string]int, error) {
+	members := map[string]int{"author": 5, "qa": 7}
+	ids := make(map[string]int)
+
+	for _, username := range usernames {
+		if id, ok := members[username]; ok {
+			ids[username] = id
+		}
+	}
+
+	return ids, nil
+}
+
+// stubAuthorizer разрешает действие, только если пользователь среди владельцев ресурса или isAdmin.
+type stubAuthorizer struct {
+	isAdmin bool
+	action  permission.Action
+}
+
+func (s *stubAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	s.action = action
+
+	if s.isAdmin {
+		return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.AdminRole}, nil
+	}
+
+	for _, ownerId := range res.OwnerIds {
+		if ownerId == userId {
+			return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
+		}
+	}
+
+	return nil, permission.ErrForbidden
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	archivedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	deletedAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	activeTask := &task.Model{Id: 1, TeamId: 10, CreatorId: 7}
+	archivedTask := &task.Model{Id: 1, TeamId: 10, CreatorId: 7, ArchivedAt: &archivedAt}
+	own := &comment.Model{Id: 3, TaskId: 1, CommenterId: 5, Text: "опечтака"}
+	deleted := &comment.Model{Id: 3, TaskId: 1, CommenterId: 5, Text: "опечтака", DeletedAt: &deletedAt}
+
+	tests := []struct {
+		name          string
+		commentGetter *stubCommentGetter
+		updater       *mockCommentUpdater
+		taskGetter    *stubTaskGetter
+		authorizer    *stubAuthorizer
+		in            EditInput
+		wantText      string
+		wantMentions  []int
+		wantCalled    bool
+		expectedErr   string
+	}{
+		{
+			name:          "author edits own comment",
+			commentGetter: &stubCommentGetter{comment: own},
+			updater:       &mockCommentUpdater{},
+			taskGetter:    &stubTaskGetter{task: activeTask},
+			authorizer:    &stubAuthorizer{},
+			in:            EditInput{UserId: 5, TaskId: 1, CommentId: 3, Text: "опечатка"},
+			wantText:      "опечатка",
+			wantMentions:  []int{},
+			wantCalled:    true,
+		},
+		{
+			name:          "admin moderates someone else's comment",
+			commentGetter: &stubCommentGetter{comment: own},
+			updater:       &mockCommentUpdater{},
+			taskGetter:    &stubTaskGetter{task: activeTask},
+			authorizer:    &stubAuthorizer{isAdmin: true},
+			in:            EditInput{UserId: 9, TaskId: 1, CommentId: 3, Text: "[удалено модератором]"},
+			wantText:      "[удалено модератором]",
+			wantMentions:  []int{},
+			wantCalled:    true,
+		},
+		{
+			name:          "mentions follow the new text",
+			commentGetter: &stubCommentGetter{comment: own},
+			updater:       &mockCommentUpdater{},
+			taskGetter:    &stubTaskGetter{task: activeTask},
+			authorizer:    &stubAuthorizer{},
+			in:            EditInput{UserId: 5, TaskId: 1, CommentId: 3, Text: "@qa, @author, @stranger — проверьте"},
+			wantText:      "@qa, @author, @stranger — проверьте",
+			wantMentions:  []int{7},
+			wantCalled:    true,
+		},
+		{
+			name:          "same text is not saved",
+			commentGetter: &stubCommentGetter{comment: own},
+			updater:       &mockCommentUpdater{},
+			taskGetter:    &stubTaskGetter{task: activeTask},
+			authorizer:    &stubAuthorizer{},
+			in:            EditInput{UserId: 5, TaskId: 1, CommentId: 3, Text: "опечтака"},
+			wantText:      "опечтака",
+		},
+		{
+			name:          "someone else's comment",
+			commentGetter: &stubCommentGetter{comment: own},
+			updater:       &mockCommentUpdater{},
+			taskGetter:    &stubTaskGetter{task: activeTask},
+			authorizer:    &stubAuthorizer{},
+			in:            EditInput{UserId: 7, TaskId: 1, CommentId: 3, Text: "чужой текст"},
+			expectedErr:   "forbidden",
+		},
+		{
+			name:          "comment not found",
+			commentGetter: &stubCommentGetter{},
+			updater:       &mockCommentUpdater{},
+			taskGetter:    &stubTaskGetter{task: activeTask},
+			authorizer:    &stubAuthorizer{},
+			in:            EditInput{UserId: 5, TaskId: 1, CommentId: 3, Text: "текст"},
+			expectedErr:   "comment not found",
+		},
+		{
+			name:          "comment of another task",
+			commentGetter: &stubCommentGetter{comment: own},
+			updater:       &mockCommentUpdater{},
+			taskGetter:    &stubTaskGetter{task: activeTask},
+			authorizer:    &stubAuthorizer{},
+			in:            EditInput{UserId: 5, TaskId: 2, CommentId: 3, Text: "текст"},
+			expectedErr:   "comment not found",
+		},
+		{
+			name:          "archived task",
+			commentGetter: &stubCommentGetter{comment: own},
+			updater:       &mockCommentUpdater{},
+			taskGetter:    &stubTaskGetter{task: archivedTask},
+			authorizer:    &stubAuthorizer{},
+			in:            EditInput{UserId: 5, TaskId: 1, CommentId: 3, Text: "текст"},
+			expectedErr:   "task is archived",
+		},
+		{
+			name:          "deleted comment",
+			commentGetter: &stubCommentGetter{comment: deleted},
+			updater:       &mockCommentUpdater{},
+			taskGetter:    &stubTaskGetter{task: activeTask},
+			authorizer:    &stubAuthorizer{},
+			in:            EditInput{UserId: 5, TaskId: 1, CommentId: 3, Text: "текст"},
+			expectedErr:   "comment is deleted",
+		},
+		{
+			name:          "db error",
+			commentGetter: &stubCommentGetter{comment: own},
+			updater:       &mockCommentUpdater{err: errors.New("db error")},
+			taskGetter:    &stubTaskGetter{task: activeTask},
+			authorizer:    &stubAuthorizer{},
+			in:            EditInput{UserId: 5, TaskId: 1, CommentId: 3, Text: "текст"},
+			wantCalled:    true,
+			wantMentions:  []int{},
+			expectedErr:   "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(tt.commentGetter, tt.updater, tt.taskGetter, tt.authorizer, &stubMemberResolver{})
+
+			got, err := e.Execute(context.Background(), tt.in)
+
+			if tt.expectedErr != "" {
+				assert.Nil(t, got)
+				assert.EqualError(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantText, got.Text)
+				assert.Equal(t, permission.CommentEdit, tt.authorizer.action)
+			}
+
+			assert.Equal(t, tt.wantCalled, tt.updater.called)
+			assert.Equal(t, tt.wantMentions, tt.updater.mentionIds)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package edit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/validation"
+)
+
+type request struct {
+	Text string `json:"text" validate:"required,max=5000"`
+}
+
+type response struct {
+	Id              int        `json:"id"`
+	CreatedAt       time.Time  `json:"created_at"`
+	CommenterId     int        `json:"commenter_id"`
+	TaskId          int        `json:"task_id"`
+	Text            string     `json:"text"`
+	UpdatedAt       *time.Time `json:"updated_at"`
+	ParentCommentId *int       `json:"parent_comment_id"`
+}
+
+type Executor interface {
+	Execute(ctx context.Context, in EditInput) (*EditResult, error)
+}
+
+type handler struct {
+	exec Executor
+}
+
+func NewHandler(exec Executor) *handler {
+	return &handler{
+		exec: exec,
+	}
+}
+
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	userIdAny := r.Context().Value("userId")
+
+	userId, ok := userIdAny.(int)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
+	taskId, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("invalid task id"))
+
+		return
+	}
+
+	commentId, err := strconv.Atoi(chi.URLParam(r, "commentId"))
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("invalid comment id"))
+
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	defer r.Body.Close()
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	var req request
+
+	if err := json.Unmarshal(body, &req); err != nil {
+		apperror.Write(w, apperror.Validation("invalid json"))
+
+		return
+	}
+
+	if err := validation.Struct(req); err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	result, err := h.exec.Execute(r.Context(), EditInput{
+		UserId:    userId,
+		TaskId:    taskId,
+		CommentId: commentId,
+		Text:      req.Text,
+	})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	respBody, err := json.Marshal(response{
+		Id:              result.Id,
+		CreatedAt:       result.CreatedAt,
+		CommenterId:     result.CommenterId,
+		TaskId:          result.TaskId,
+		Text:            result.Text,
+		UpdatedAt:       result.UpdatedAt,
+		ParentCommentId: result.ParentCommentId,
+	})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
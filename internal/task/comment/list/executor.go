@@ -2,41 +2,86 @@ package list
 
 import (
 	"context"
-	"time"
+	"database/sql"
+	"errors"
 
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
 	"mkk-luna-test-task/internal/task/comment"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
 )
 
 const defaultLimit = 100
 
 type ListResult struct {
-	Comments        []CommentItem
+	Comments        []comment.View
 	NextStartFromId int
 	HasMore         bool
 }
 
-type CommentItem struct {
-	Id          int       `json:"id"`
-	CreatedAt   time.Time `json:"created_at"`
-	CommenterId int       `json:"commenter_id"`
-	TaskId      int       `json:"task_id"`
-	Text        string    `json:"text"`
-}
-
 type taskCommentLister interface {
 	ListTaskComments(ctx context.Context, taskId int, startFromId int, limit int) ([]comment.Model, error)
 }
 
+type taskGetter interface {
+	GetTaskById(ctx context.Context, id int) (*task.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type htmlRenderer interface {
+	HTML(ctx context.Context, userId int, sources ...string) ([]string, error)
+}
+
 type executor struct {
 	taskCommentLister taskCommentLister
+	taskGetter        taskGetter
+	authorizer        authorizer
+	renderer          htmlRenderer
+}
+
+func NewExecutor(
+	taskCommentLister taskCommentLister,
+	taskGetter taskGetter,
+	authorizer authorizer,
+	renderer htmlRenderer,
+) *executor {
+	return &executor{
+		taskCommentLister: taskCommentLister,
+		taskGetter:        taskGetter,
+		authorizer:        authorizer,
+		renderer:          renderer,
+	}
 }
 
-func NewExecutor(taskCommentLister taskCommentLister) *executor {
-	return &executor{taskCommentLister: taskCommentLister}
+type ListInput struct {
+	UserId      int
+	TaskId      int
+	StartFromId int
+	RenderHTML  bool
 }
 
-func (e *executor) Execute(ctx context.Context, taskId, startFromId int) (*ListResult, error) {
-	comments, err := e.taskCommentLister.ListTaskComments(ctx, taskId, startFromId, defaultLimit+1)
+func (e *executor) Execute(ctx context.Context, in ListInput) (*ListResult, error) {
+	t, err := e.taskGetter.GetTaskById(ctx, in.TaskId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("task not found")
+		}
+
+		return nil, err
+	}
+
+	_, err = e.authorizer.Authorize(ctx, in.UserId, permission.TaskView, permission.TaskResource(*t))
+
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := e.taskCommentLister.ListTaskComments(ctx, in.TaskId, in.StartFromId, defaultLimit+1)
 
 	if err != nil {
 		return nil, err
@@ -48,20 +93,32 @@ func (e *executor) Execute(ctx context.Context, taskId, startFromId int) (*ListR
 
 	if len(comments) > defaultLimit {
 		hasMore = true
-		nextStartFromId = comments[defaultLimit].Id
+		nextStartFromId = comments[defaultLimit-1].Id
 		results = comments[:defaultLimit]
 	}
 
-	items := make([]CommentItem, 0, len(results))
+	items := make([]comment.View, 0, len(results))
 
 	for _, c := range results {
-		items = append(items, CommentItem{
-			Id:          c.Id,
-			CreatedAt:   c.CreatedAt,
-			CommenterId: c.CommenterId,
-			TaskId:      c.TaskId,
-			Text:        c.Text,
-		})
+		items = append(items, comment.NewView(c))
+	}
+
+	if in.RenderHTML && len(items) > 0 {
+		sources := make([]string, 0, len(items))
+
+		for _, item := range items {
+			sources = append(sources, item.Text)
+		}
+
+		rendered, err := e.renderer.HTML(ctx, in.UserId, sources...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range items {
+			items[i].TextHTML = &rendered[i]
+		}
 	}
 
 	return &ListResult{
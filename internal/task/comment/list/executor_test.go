@@ -2,11 +2,15 @@ package list
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
 	"time"
 
+	"mkk-luna-test-task/internal/task"
 	"mkk-luna-test-task/internal/task/comment"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -60,9 +64,32 @@ func (s *stubTaskCommentListerError) ListTaskComments(ctx context.Context, taskI
 	return nil, errors.New("db error")
 }
 
+type stubTaskGetter struct{}
+
+func (s *stubTaskGetter) GetTaskById(ctx context.Context, id int) (*task.Model, error) {
+	if id == 404 {
+		return nil, sql.ErrNoRows
+	}
+
+	return &task.Model{Id: id, TeamId: 42, CreatorId: 1, AssigneeIds: []int{2}}, nil
+}
+
+type stubAuthorizer struct {
+	err error
+}
+
+func (s *stubAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
+}
+
 func TestExecutor_Execute(t *testing.T) {
 	type fields struct {
 		taskCommentLister taskCommentLister
+		authorizer        authorizer
 	}
 	type args struct {
 		ctx         context.Context
@@ -84,6 +111,7 @@ func TestExecutor_Execute(t *testing.T) {
 			name: "success - returns comments",
 			fields: fields{
 				taskCommentLister: &stubTaskCommentListerSuccess{},
+				authorizer:        &stubAuthorizer{},
 			},
 			args: args{
 				ctx:         context.Background(),
@@ -99,6 +127,7 @@ func TestExecutor_Execute(t *testing.T) {
 			name: "success - paginated with hasMore",
 			fields: fields{
 				taskCommentLister: &stubTaskCommentListerPaginate{},
+				authorizer:        &stubAuthorizer{},
 			},
 			args: args{
 				ctx:         context.Background(),
@@ -106,7 +135,7 @@ func TestExecutor_Execute(t *testing.T) {
 				startFromId: 0,
 			},
 			wantHasMore:     true,
-			wantNextFromId:  200,
+			wantNextFromId:  199,
 			wantErr:         false,
 			wantCommentsLen: 100,
 		},
@@ -114,6 +143,7 @@ func TestExecutor_Execute(t *testing.T) {
 			name: "success - empty result",
 			fields: fields{
 				taskCommentLister: &stubTaskCommentListerEmpty{},
+				authorizer:        &stubAuthorizer{},
 			},
 			args: args{
 				ctx:         context.Background(),
@@ -129,6 +159,7 @@ func TestExecutor_Execute(t *testing.T) {
 			name: "error - lister returns error",
 			fields: fields{
 				taskCommentLister: &stubTaskCommentListerError{},
+				authorizer:        &stubAuthorizer{},
 			},
 			args: args{
 				ctx:         context.Background(),
@@ -141,15 +172,47 @@ func TestExecutor_Execute(t *testing.T) {
 			expectedErr:     errors.New("db error"),
 			wantCommentsLen: 0,
 		},
+		{
+			name: "error - not a team member",
+			fields: fields{
+				taskCommentLister: &stubTaskCommentListerSuccess{},
+				authorizer:        &stubAuthorizer{err: permission.ErrForbidden},
+			},
+			args: args{
+				ctx:    context.Background(),
+				taskId: 1,
+			},
+			wantErr:     true,
+			expectedErr: errors.New("forbidden"),
+		},
+		{
+			name: "error - task not found",
+			fields: fields{
+				taskCommentLister: &stubTaskCommentListerSuccess{},
+				authorizer:        &stubAuthorizer{},
+			},
+			args: args{
+				ctx:    context.Background(),
+				taskId: 404,
+			},
+			wantErr:     true,
+			expectedErr: errors.New("task not found"),
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			e := &executor{
 				taskCommentLister: tt.fields.taskCommentLister,
+				taskGetter:        &stubTaskGetter{},
+				authorizer:        tt.fields.authorizer,
 			}
 
-			got, err := e.Execute(tt.args.ctx, tt.args.taskId, tt.args.startFromId)
+			got, err := e.Execute(tt.args.ctx, ListInput{
+				UserId:      1,
+				TaskId:      tt.args.taskId,
+				StartFromId: tt.args.startFromId,
+			})
 
 			if tt.wantErr {
 				assert.Nil(t, got)
@@ -168,3 +231,75 @@ func TestExecutor_Execute(t *testing.T) {
 		})
 	}
 }
+
+type stubTaskCommentListerEdited struct {
+	comments []comment.Model
+}
+
+func (s *stubTaskCommentListerEdited) ListTaskComments(ctx context.Context, taskId int, startFromId int, limit int) ([]comment.Model, error) {
+	return s.comments, nil
+}
+
+func TestExecutor_Execute_EditedAndDeleted(t *testing.T) {
+	createdAt := time.Date(2023, 4, 1, 13, 0, 0, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+	deletedAt := createdAt.Add(2 * time.Hour)
+
+	lister := &stubTaskCommentListerEdited{comments: []comment.Model{
+		{Id: 1, TaskId: 42, CommenterId: 5, Text: "исправленный текст", CreatedAt: createdAt, UpdatedAt: &updatedAt},
+		{Id: 2, TaskId: 42, CommenterId: 6, Text: "пароль от базы", CreatedAt: createdAt, DeletedAt: &deletedAt},
+	}}
+
+	e := NewExecutor(lister, &stubTaskGetter{}, &stubAuthorizer{}, &stubRenderer{})
+
+	got, err := e.Execute(context.Background(), ListInput{UserId: 5, TaskId: 42})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []comment.View{
+		{Id: 1, CreatedAt: createdAt, CommenterId: 5, TaskId: 42, Text: "исправленный текст", UpdatedAt: &updatedAt},
+		{Id: 2, CreatedAt: createdAt, CommenterId: 6, TaskId: 42, Deleted: true},
+	}, got.Comments)
+}
+
+type stubRenderer struct {
+	sources []string
+}
+
+func (s *stubRenderer) HTML(ctx context.Context, userId int, sources ...string) ([]string, error) {
+	s.sources = sources
+
+	rendered := make([]string, 0, len(sources))
+
+	for _, src := range sources {
+		if src == "" {
+			rendered = append(rendered, "")
+
+			continue
+		}
+
+		rendered = append(rendered, "<p>"+src+"</p>")
+	}
+
+	return rendered, nil
+}
+
+func TestExecutor_Execute_RenderHTML(t *testing.T) {
+	createdAt := time.Date(2023, 4, 1, 13, 0, 0, 0, time.UTC)
+	deletedAt := createdAt.Add(time.Hour)
+
+	lister := &stubTaskCommentListerEdited{comments: []comment.Model{
+		{Id: 1, TaskId: 42, CommenterId: 5, Text: "см. #7", CreatedAt: createdAt},
+		{Id: 2, TaskId: 42, CommenterId: 6, Text: "пароль от базы", CreatedAt: createdAt, DeletedAt: &deletedAt},
+	}}
+	renderer := &stubRenderer{}
+
+	e := NewExecutor(lister, &stubTaskGetter{}, &stubAuthorizer{}, renderer)
+
+	got, err := e.Execute(context.Background(), ListInput{UserId: 5, TaskId: 42, RenderHTML: true})
+
+	assert.NoError(t, err)
+	// Удалённый текст в рендер не попадает.
+	assert.Equal(t, []string{"см. #7", ""}, renderer.sources)
+	assert.Equal(t, "<p>см. #7</p>", *got.Comments[0].TextHTML)
+	assert.Equal(t, "", *got.Comments[1].TextHTML)
+}
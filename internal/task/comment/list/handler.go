@@ -7,16 +7,20 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task/comment"
+	"mkk-luna-test-task/internal/task/render"
 )
 
 type response struct {
-	Comments        []CommentItem `json:"comments"`
-	NextStartFromId int           `json:"next_start_from_id,omitempty"`
-	HasMore         bool          `json:"has_more"`
+	Comments        []comment.View `json:"comments"`
+	NextStartFromId int            `json:"next_start_from_id,omitempty"`
+	HasMore         bool           `json:"has_more"`
 }
 
 type Executor interface {
-	Execute(ctx context.Context, taskId, startFromId int) (*ListResult, error)
+	Execute(ctx context.Context, in ListInput) (*ListResult, error)
 }
 
 func NewHandler(exec Executor) *handler {
@@ -28,12 +32,22 @@ type handler struct {
 }
 
 func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	userIdAny := r.Context().Value("userId")
+
+	userId, ok := userIdAny.(int)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
 	taskIdStr := chi.URLParam(r, "id")
 
 	taskId, err := strconv.Atoi(taskIdStr)
 
 	if err != nil {
-		http.Error(w, "invalid task_id", http.StatusBadRequest)
+		apperror.Write(w, apperror.Validation("invalid task_id"))
 
 		return
 	}
@@ -44,16 +58,29 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 		startFromId, err = strconv.Atoi(s)
 
 		if err != nil {
-			http.Error(w, "invalid start_from_id", http.StatusBadRequest)
+			apperror.Write(w, apperror.Validation("invalid start_from_id"))
 
 			return
 		}
 	}
 
-	result, err := h.exec.Execute(r.Context(), taskId, startFromId)
+	renderHTML, err := render.WantHTML(r.URL.Query())
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	result, err := h.exec.Execute(r.Context(), ListInput{
+		UserId:      userId,
+		TaskId:      taskId,
+		StartFromId: startFromId,
+		RenderHTML:  renderHTML,
+	})
 
 	if err != nil {
-		http.Error(w, "error", http.StatusBadRequest)
+		apperror.Write(w, err)
 
 		return
 	}
@@ -67,7 +94,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	body, err := json.Marshal(resp)
 
 	if err != nil {
-		http.Error(w, "json error", http.StatusInternalServerError)
+		apperror.Write(w, err)
 
 		return
 	}
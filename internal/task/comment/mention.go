@@ -0,0 +1,51 @@
+package comment
+
+import (
+	"regexp"
+	"slices"
+)
+
+// MaxMentions — сколько разных имён из одного комментария пытаемся найти среди участников.
+const MaxMentions = 50
+
+// Упоминание — @ в начале текста или после символа, который не может быть частью имени или адреса почты.
+// Имя заканчивается буквой, цифрой или _, чтобы точка в конце предложения не попадала в него.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_.@-])@([\p{L}\p{N}_](?:[\p{L}\p{N}_.-]*[\p{L}\p{N}_])?)`)
+
+// ParseMentions возвращает имена из @упоминаний в порядке появления, без повторов.
+func ParseMentions(text string) []string {
+	usernames := []string{}
+	seen := make(map[string]bool)
+
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		username := match[1]
+
+		if seen[username] {
+			continue
+		}
+
+		seen[username] = true
+		usernames = append(usernames, username)
+
+		if len(usernames) == MaxMentions {
+			break
+		}
+	}
+
+	return usernames
+}
+
+// MentionIds — id найденных участников по возрастанию; автор, упомянувший себя, не считается.
+func MentionIds(members map[string]int, authorId int) []int {
+	ids := make([]int, 0, len(members))
+
+	for _, id := range members {
+		if id != authorId {
+			ids = append(ids, id)
+		}
+	}
+
+	slices.Sort(ids)
+
+	return ids
+}
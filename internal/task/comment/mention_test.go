@@ -0,0 +1,53 @@
+package comment
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMentions(t *testing.T) {
+	many := make([]string, 0, MaxMentions+1)
+
+	for i := 0; i <= MaxMentions; i++ {
+		many = append(many, fmt.Sprintf("@user%d", i))
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "no mentions", text: "просто текст", want: []string{}},
+		{name: "start of text", text: "@dev1 посмотри", want: []string{"dev1"}},
+		{name: "punctuation after name", text: "Готово, @team.lead. Спасибо, @qa_1!", want: []string{"team.lead", "qa_1"}},
+		{name: "cyrillic name", text: "(@иван) глянь", want: []string{"иван"}},
+		{name: "repeated", text: "@dev1 и снова @dev1", want: []string{"dev1"}},
+		{name: "email is not a mention", text: "пишите на support@example.com", want: []string{}},
+		{name: "lone at sign", text: "встреча @ 15:00", want: []string{}},
+		{name: "too many", text: strings.Join(many, " "), want: func() []string {
+			want := make([]string, 0, MaxMentions)
+
+			for _, m := range many[:MaxMentions] {
+				want = append(want, strings.TrimPrefix(m, "@"))
+			}
+
+			return want
+		}()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseMentions(tt.text))
+		})
+	}
+}
+
+func TestMentionIds(t *testing.T) {
+	members := map[string]int{"qa": 7, "dev1": 3, "lead": 5}
+
+	assert.Equal(t, []int{3, 7}, MentionIds(members, 5))
+	assert.Equal(t, []int{}, MentionIds(nil, 5))
+}
@@ -0,0 +1,84 @@
+package mentions
+
+import (
+	"context"
+	"time"
+
+	"mkk-luna-test-task/internal/task/comment"
+)
+
+const defaultLimit = 100
+
+type ListResult struct {
+	Mentions        []MentionItem
+	NextStartFromId int
+	HasMore         bool
+}
+
+type MentionItem struct {
+	CommentId       int        `json:"comment_id"`
+	TaskId          int        `json:"task_id"`
+	TaskTitle       string     `json:"task_title"`
+	TeamId          int        `json:"team_id"`
+	CommenterId     int        `json:"commenter_id"`
+	Text            string     `json:"text"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       *time.Time `json:"updated_at"`
+	ParentCommentId *int       `json:"parent_comment_id"`
+}
+
+type mentionLister interface {
+	ListUserMentions(ctx context.Context, userId int, startFromId int, limit int) ([]comment.Mention, error)
+}
+
+type executor struct {
+	mentionLister mentionLister
+}
+
+func NewExecutor(mentionLister mentionLister) *executor {
+	return &executor{
+		mentionLister: mentionLister,
+	}
+}
+
+type ListInput struct {
+	UserId int
+	// StartFromId — id последнего комментария предыдущей страницы; 0 — с самых свежих.
+	StartFromId int
+}
+
+// Execute не проверяет права по каждой команде отдельно: членство и task.view учитываются в самом запросе.
+func (e *executor) Execute(ctx context.Context, in ListInput) (*ListResult, error) {
+	mentions, err := e.mentionLister.ListUserMentions(ctx, in.UserId, in.StartFromId, defaultLimit+1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ListResult{}
+
+	if len(mentions) > defaultLimit {
+		mentions = mentions[:defaultLimit]
+
+		result.HasMore = true
+		result.NextStartFromId = mentions[defaultLimit-1].Comment.Id
+	}
+
+	result.Mentions = make([]MentionItem, 0, len(mentions))
+
+	for _, m := range mentions {
+		result.Mentions = append(result.Mentions, MentionItem{
+			CommentId:       m.Comment.Id,
+			TaskId:          m.Comment.TaskId,
+			TaskTitle:       m.TaskTitle,
+			TeamId:          m.TeamId,
+			CommenterId:     m.Comment.CommenterId,
+			Text:            m.Comment.Text,
+			CreatedAt:       m.Comment.CreatedAt,
+			UpdatedAt:       m.Comment.UpdatedAt,
+			ParentCommentId: m.Comment.ParentCommentId,
+		})
+	}
+
+	return result, nil
+}
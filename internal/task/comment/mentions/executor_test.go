@@ -0,0 +1,132 @@
+package mentions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/task/comment"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubMentionLister struct {
+	mentions []comment.Mention
+	err      error
+
+	gotUserId      int
+	gotStartFromId int
+	gotLimit       int
+}
+
+func (s *stubMentionLister) ListUserMentions(ctx context.Context, userId int, startFromId int, limit int) ([]comment.Mention, error) {
+	s.gotUserId = userId
+	s.gotStartFromId = startFromId
+	s.gotLimit = limit
+
+	return s.mentions, s.err
+}
+
+func makeMention(id int) comment.Mention {
+	return comment.Mention{
+		Comment: comment.Model{
+			Id:          id,
+			TaskId:      7,
+			CommenterId: 3,
+			Text:        "@dev1 глянь",
+			CreatedAt:   time.Unix(int64(id), 0),
+		},
+		TeamId:    2,
+		TaskTitle: "Отчёт",
+	}
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	parentId := 4
+
+	reply := makeMention(9)
+	reply.Comment.ParentCommentId = &parentId
+
+	full := make([]comment.Mention, 0, defaultLimit+1)
+
+	for id := defaultLimit + 1; id >= 1; id-- {
+		full = append(full, makeMention(id))
+	}
+
+	tests := []struct {
+		name        string
+		lister      *stubMentionLister
+		in          ListInput
+		wantLen     int
+		wantFirst   *MentionItem
+		wantNextId  int
+		wantHasMore bool
+		expectedErr string
+	}{
+		{
+			name:   "single mention",
+			lister: &stubMentionLister{mentions: []comment.Mention{reply}},
+			in:     ListInput{UserId: 5},
+			wantFirst: &MentionItem{
+				CommentId:       9,
+				TaskId:          7,
+				TaskTitle:       "Отчёт",
+				TeamId:          2,
+				CommenterId:     3,
+				Text:            "@dev1 глянь",
+				CreatedAt:       time.Unix(9, 0),
+				ParentCommentId: &parentId,
+			},
+			wantLen: 1,
+		},
+		{
+			name:    "empty",
+			lister:  &stubMentionLister{mentions: []comment.Mention{}},
+			in:      ListInput{UserId: 5, StartFromId: 3},
+			wantLen: 0,
+		},
+		{
+			name:        "has more",
+			lister:      &stubMentionLister{mentions: full},
+			in:          ListInput{UserId: 5},
+			wantLen:     defaultLimit,
+			wantNextId:  2,
+			wantHasMore: true,
+		},
+		{
+			name:        "db error",
+			lister:      &stubMentionLister{err: errors.New("db error")},
+			in:          ListInput{UserId: 5},
+			expectedErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(tt.lister)
+
+			got, err := e.Execute(context.Background(), tt.in)
+
+			assert.Equal(t, tt.in.UserId, tt.lister.gotUserId)
+			assert.Equal(t, tt.in.StartFromId, tt.lister.gotStartFromId)
+			assert.Equal(t, defaultLimit+1, tt.lister.gotLimit)
+
+			if tt.expectedErr != "" {
+				assert.Nil(t, got)
+				assert.EqualError(t, err, tt.expectedErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, got.Mentions, tt.wantLen)
+			assert.Equal(t, tt.wantNextId, got.NextStartFromId)
+			assert.Equal(t, tt.wantHasMore, got.HasMore)
+
+			if tt.wantFirst != nil {
+				assert.Equal(t, *tt.wantFirst, got.Mentions[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package mentions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+type response struct {
+	Mentions        []MentionItem `json:"mentions"`
+	NextStartFromId int           `json:"next_start_from_id,omitempty"`
+	HasMore         bool          `json:"has_more"`
+}
+
+type Executor interface {
+	Execute(ctx context.Context, in ListInput) (*ListResult, error)
+}
+
+type handler struct {
+	exec Executor
+}
+
+func NewHandler(exec Executor) *handler {
+	return &handler{
+		exec: exec,
+	}
+}
+
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	userIdAny := r.Context().Value("userId")
+
+	userId, ok := userIdAny.(int)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
+	startFromId := 0
+
+	if s := r.URL.Query().Get("start_from_id"); s != "" {
+		var err error
+
+		startFromId, err = strconv.Atoi(s)
+
+		if err != nil {
+			apperror.Write(w, apperror.Validation("invalid start_from_id"))
+
+			return
+		}
+	}
+
+	result, err := h.exec.Execute(r.Context(), ListInput{
+		UserId:      userId,
+		StartFromId: startFromId,
+	})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	respBody, err := json.Marshal(response{
+		Mentions:        result.Mentions,
+		NextStartFromId: result.NextStartFromId,
+		HasMore:         result.HasMore,
+	})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
@@ -2,12 +2,43 @@ package comment
 
 import (
 	"time"
+
+	"mkk-luna-test-task/internal/apperror"
 )
 
+// ErrDeleted — удалённый комментарий нельзя править.
+var ErrDeleted = apperror.Conflict("comment is deleted")
+
 type Model struct {
 	Id          int
 	CreatedAt   time.Time
 	CommenterId int
 	TaskId      int
 	Text        string
+	// UpdatedAt — время последней правки; nil, если комментарий не правили.
+	UpdatedAt *time.Time
+	// DeletedAt — удалённый комментарий остаётся в списке заглушкой без текста.
+	DeletedAt *time.Time
+	// ParentCommentId — комментарий, на который это ответ; nil у комментариев верхнего уровня.
+	ParentCommentId *int
+}
+
+// Mention — комментарий, где упомянули пользователя, вместе с задачей, к которой он написан.
+type Mention struct {
+	Comment   Model
+	TeamId    int
+	TaskTitle string
+}
+
+func (m Model) IsDeleted() bool {
+	return m.DeletedAt != nil
+}
+
+// VisibleText — текст для ответа: у удалённого комментария он скрыт.
+func (m Model) VisibleText() string {
+	if m.IsDeleted() {
+		return ""
+	}
+
+	return m.Text
 }
@@ -0,0 +1,101 @@
+package remove
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/comment"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type commentGetter interface {
+	GetTaskComment(ctx context.Context, id int) (*comment.Model, error)
+}
+
+type commentDeleter interface {
+	DeleteTaskComment(ctx context.Context, id int, deletedBy int, deletedAt time.Time) error
+}
+
+type taskGetter interface {
+	GetTaskById(ctx context.Context, id int) (*task.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type executor struct {
+	commentGetter  commentGetter
+	commentDeleter commentDeleter
+	taskGetter     taskGetter
+	authorizer     authorizer
+}
+
+func NewExecutor(
+	commentGetter commentGetter,
+	commentDeleter commentDeleter,
+	taskGetter taskGetter,
+	authorizer authorizer,
+) *executor {
+	return &executor{
+		commentGetter:  commentGetter,
+		commentDeleter: commentDeleter,
+		taskGetter:     taskGetter,
+		authorizer:     authorizer,
+	}
+}
+
+type RemoveInput struct {
+	UserId    int
+	TaskId    int
+	CommentId int
+}
+
+// Execute удаляет комментарий: автор удаляет свои, admin — любые. В списке остаётся заглушка без текста.
+func (e *executor) Execute(ctx context.Context, in RemoveInput) error {
+	c, err := e.commentGetter.GetTaskComment(ctx, in.CommentId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.NotFound("comment not found")
+		}
+
+		return err
+	}
+
+	if c.TaskId != in.TaskId {
+		return apperror.NotFound("comment not found")
+	}
+
+	t, err := e.taskGetter.GetTaskById(ctx, in.TaskId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.NotFound("task not found")
+		}
+
+		return err
+	}
+
+	_, err = e.authorizer.Authorize(ctx, in.UserId, permission.CommentDelete, permission.CommentResource(t.TeamId, *c))
+
+	if err != nil {
+		return err
+	}
+
+	// Архивная задача доступна только для чтения.
+	if t.IsArchived() {
+		return task.ErrArchived
+	}
+
+	if c.IsDeleted() {
+		return nil
+	}
+
+	return e.commentDeleter.DeleteTaskComment(ctx, c.Id, in.UserId, time.Now())
+}
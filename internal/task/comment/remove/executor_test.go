@@ -0,0 +1,188 @@
+package remove
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/comment"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubCommentGetter struct {
+	comment *comment.Model
+}
+
+func (s *stubCommentGetter) GetTaskComment(ctx context.Context, id int) (*comment.Model, error) {
+	if s.comment == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	c := *s.comment
+
+	return &c, nil
+}
+
+type mockCommentDeleter struct {
+	err    error
+	called bool
+}
+
+func (m *mockCommentDeleter) DeleteTaskComment(ctx context.Context, id int, deletedBy int, deletedAt time.Time) error {
+	m.called = true
+
+	return m.err
+}
+
+type stubTaskGetter struct {
+	task *task.Model
+}
+
+func (s *stubTaskGetter) GetTaskById(ctx context.Context, id int) (*task.Model, error) {
+	if s.task == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	t := *s.task
+
+	return &t, nil
+}
+
+// stubAuthorizer разрешает действие, только если пользователь среди владельцев ресурса или isAdmin.
+type stubAuthorizer struct {
+	isAdmin bool
+}
+
+func (s *stubAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	if action != permission.CommentDelete {
+		return nil, permission.ErrForbidden
+	}
+
+	if s.isAdmin {
+		return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.AdminRole}, nil
+	}
+
+	for _, ownerId := range res.OwnerIds {
+		if ownerId == userId {
+			return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
+		}
+	}
+
+	return nil, permission.ErrForbidden
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	archivedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	deletedAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	activeTask := &task.Model{Id: 1, TeamId: 10, CreatorId: 7}
+	archivedTask := &task.Model{Id: 1, TeamId: 10, CreatorId: 7, ArchivedAt: &archivedAt}
+	own := &comment.Model{Id: 3, TaskId: 1, CommenterId: 5, Text: "секрет"}
+	deleted := &comment.Model{Id: 3, TaskId: 1, CommenterId: 5, Text: "секрет", DeletedAt: &deletedAt}
+
+	tests := []struct {
+		name          string
+		commentGetter *stubCommentGetter
+		deleter       *mockCommentDeleter
+		taskGetter    *stubTaskGetter
+		authorizer    *stubAuthorizer
+		in            RemoveInput
+		wantCalled    bool
+		expectedErr   string
+	}{
+		{
+			name:          "author deletes own comment",
+			commentGetter: &stubCommentGetter{comment: own},
+			deleter:       &mockCommentDeleter{},
+			taskGetter:    &stubTaskGetter{task: activeTask},
+			authorizer:    &stubAuthorizer{},
+			in:            RemoveInput{UserId: 5, TaskId: 1, CommentId: 3},
+			wantCalled:    true,
+		},
+		{
+			name:          "admin deletes someone else's comment",
+			commentGetter: &stubCommentGetter{comment: own},
+			deleter:       &mockCommentDeleter{},
+			taskGetter:    &stubTaskGetter{task: activeTask},
+			authorizer:    &stubAuthorizer{isAdmin: true},
+			in:            RemoveInput{UserId: 9, TaskId: 1, CommentId: 3},
+			wantCalled:    true,
+		},
+		{
+			name:          "already deleted",
+			commentGetter: &stubCommentGetter{comment: deleted},
+			deleter:       &mockCommentDeleter{},
+			taskGetter:    &stubTaskGetter{task: activeTask},
+			authorizer:    &stubAuthorizer{},
+			in:            RemoveInput{UserId: 5, TaskId: 1, CommentId: 3},
+		},
+		{
+			name:          "someone else's comment",
+			commentGetter: &stubCommentGetter{comment: own},
+			deleter:       &mockCommentDeleter{},
+			taskGetter:    &stubTaskGetter{task: activeTask},
+			authorizer:    &stubAuthorizer{},
+			in:            RemoveInput{UserId: 7, TaskId: 1, CommentId: 3},
+			expectedErr:   "forbidden",
+		},
+		{
+			name:          "comment of another task",
+			commentGetter: &stubCommentGetter{comment: own},
+			deleter:       &mockCommentDeleter{},
+			taskGetter:    &stubTaskGetter{task: activeTask},
+			authorizer:    &stubAuthorizer{},
+			in:            RemoveInput{UserId: 5, TaskId: 2, CommentId: 3},
+			expectedErr:   "comment not found",
+		},
+		{
+			name:          "task not found",
+			commentGetter: &stubCommentGetter{comment: own},
+			deleter:       &mockCommentDeleter{},
+			taskGetter:    &stubTaskGetter{},
+			authorizer:    &stubAuthorizer{},
+			in:            RemoveInput{UserId: 5, TaskId: 1, CommentId: 3},
+			expectedErr:   "task not found",
+		},
+		{
+			name:          "archived task",
+			commentGetter: &stubCommentGetter{comment: own},
+			deleter:       &mockCommentDeleter{},
+			taskGetter:    &stubTaskGetter{task: archivedTask},
+			authorizer:    &stubAuthorizer{},
+			in:            RemoveInput{UserId: 5, TaskId: 1, CommentId: 3},
+			expectedErr:   "task is archived",
+		},
+		{
+			name:          "db error",
+			commentGetter: &stubCommentGetter{comment: own},
+			deleter:       &mockCommentDeleter{err: errors.New("db error")},
+			taskGetter:    &stubTaskGetter{task: activeTask},
+			authorizer:    &stubAuthorizer{},
+			in:            RemoveInput{UserId: 5, TaskId: 1, CommentId: 3},
+			wantCalled:    true,
+			expectedErr:   "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(tt.commentGetter, tt.deleter, tt.taskGetter, tt.authorizer)
+
+			err := e.Execute(context.Background(), tt.in)
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantCalled, tt.deleter.called)
+		})
+	}
+}
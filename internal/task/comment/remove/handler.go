@@ -0,0 +1,61 @@
+package remove
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+type Executor interface {
+	Execute(ctx context.Context, in RemoveInput) error
+}
+
+type handler struct {
+	exec Executor
+}
+
+func NewHandler(exec Executor) *handler {
+	return &handler{
+		exec: exec,
+	}
+}
+
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	userIdAny := r.Context().Value("userId")
+
+	userId, ok := userIdAny.(int)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
+	taskId, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("invalid task id"))
+
+		return
+	}
+
+	commentId, err := strconv.Atoi(chi.URLParam(r, "commentId"))
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("invalid comment id"))
+
+		return
+	}
+
+	if err := h.exec.Execute(r.Context(), RemoveInput{UserId: userId, TaskId: taskId, CommentId: commentId}); err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
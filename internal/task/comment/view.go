@@ -0,0 +1,29 @@
+package comment
+
+import "time"
+
+// View — комментарий в ответе API; удалённый остаётся на своём месте с пустым текстом и Deleted, равным true.
+type View struct {
+	Id              int        `json:"id"`
+	CreatedAt       time.Time  `json:"created_at"`
+	CommenterId     int        `json:"commenter_id"`
+	TaskId          int        `json:"task_id"`
+	Text            string     `json:"text"`
+	UpdatedAt       *time.Time `json:"updated_at"`
+	Deleted         bool       `json:"deleted"`
+	ParentCommentId *int       `json:"parent_comment_id"`
+	TextHTML        *string    `json:"text_html,omitempty"`
+}
+
+func NewView(m Model) View {
+	return View{
+		Id:              m.Id,
+		CreatedAt:       m.CreatedAt,
+		CommenterId:     m.CommenterId,
+		TaskId:          m.TaskId,
+		Text:            m.VisibleText(),
+		UpdatedAt:       m.UpdatedAt,
+		Deleted:         m.IsDeleted(),
+		ParentCommentId: m.ParentCommentId,
+	}
+}
@@ -2,29 +2,60 @@ package create
 
 import (
 	"context"
-	"fmt"
+	"database/sql"
+	"errors"
+	"log"
 	"time"
 
+	"mkk-luna-test-task/internal/apperror"
 	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+	"mkk-luna-test-task/internal/team/workflow"
+	"mkk-luna-test-task/internal/user/notification"
 )
 
 type taskCreator interface {
-	CreateTask(ctx context.Context, status, title, description string, creatorId, assigneeId, teamId int, createdAt time.Time) (*task.Model, error)
+	CreateTask(ctx context.Context, status, title, description string, creatorId int, assigneeIds []int, teamId int, createdAt time.Time, dueAt *time.Time, priority task.Priority) (*task.Model, error)
 }
 
-type teamMembershipChecker interface {
-	CheckUserIsInTeam(userId, teamId int) (bool, error)
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type memberGetter interface {
+	GetMember(ctx context.Context, teamId, userId int) (*member.Model, error)
+}
+
+type workflowGetter interface {
+	GetWorkflow(ctx context.Context, teamId int) (*workflow.Model, error)
+}
+
+type notificationCreator interface {
+	CreateNotifications(ctx context.Context, notifications []notification.Model) error
 }
 
 type executor struct {
-	taskCreator           taskCreator
-	teamMembershipChecker teamMembershipChecker
+	taskCreator         taskCreator
+	authorizer          authorizer
+	memberGetter        memberGetter
+	workflowGetter      workflowGetter
+	notificationCreator notificationCreator
 }
 
-func NewExecutor(taskCreator taskCreator, teamMembershipChecker teamMembershipChecker) *executor {
+func NewExecutor(
+	taskCreator taskCreator,
+	authorizer authorizer,
+	memberGetter memberGetter,
+	workflowGetter workflowGetter,
+	notificationCreator notificationCreator,
+) *executor {
 	return &executor{
-		taskCreator:           taskCreator,
-		teamMembershipChecker: teamMembershipChecker,
+		taskCreator:         taskCreator,
+		authorizer:          authorizer,
+		memberGetter:        memberGetter,
+		workflowGetter:      workflowGetter,
+		notificationCreator: notificationCreator,
 	}
 }
 
@@ -33,40 +64,82 @@ type CreateInput struct {
 	Status      string
 	Title       string
 	Description string
-	AssigneeId  int
+	// AssigneeIds может быть пустым: задача создаётся неназначенной.
+	AssigneeIds []int
 	TeamId      int
+	DueAt       *time.Time
+	Priority    task.Priority
 }
 
 type CreateResult struct {
-	Id          int       `json:"id"`
-	Status      string    `json:"status"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	CreatorId   int       `json:"creator_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	AssigneeId  int       `json:"assignee_id"`
-	TeamId      int       `json:"team_id"`
+	Id          int           `json:"id"`
+	Status      string        `json:"status"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	CreatorId   int           `json:"creator_id"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	AssigneeIds []int         `json:"assignee_ids"`
+	TeamId      int           `json:"team_id"`
+	Version     int           `json:"version"`
+	DueAt       *time.Time    `json:"due_at"`
+	Priority    task.Priority `json:"priority"`
 }
 
 func (e *executor) Execute(ctx context.Context, in CreateInput) (*CreateResult, error) {
-	isMember, err := e.teamMembershipChecker.CheckUserIsInTeam(in.CreatorId, in.TeamId)
+	_, err := e.authorizer.Authorize(ctx, in.CreatorId, permission.TaskCreate, permission.TeamResource(in.TeamId))
+
+	if err != nil {
+		return nil, err
+	}
+
+	assigneeIds, err := task.NormalizeAssignees(in.AssigneeIds)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, assigneeId := range assigneeIds {
+		_, err = e.memberGetter.GetMember(ctx, in.TeamId, assigneeId)
+
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, apperror.InvalidField("assignee_ids", "must be members of the team")
+			}
+
+			return nil, err
+		}
+	}
+
+	wf, err := e.workflowGetter.GetWorkflow(ctx, in.TeamId)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if !isMember {
-		return nil, fmt.Errorf("forbidden")
+	status := in.Status
+
+	if status == "" {
+		status = wf.Initial()
+	}
+
+	if err := wf.CheckNew(status); err != nil {
+		return nil, err
 	}
 
 	now := time.Now()
 
-	model, err := e.taskCreator.CreateTask(ctx, in.Status, in.Title, in.Description, in.CreatorId, in.AssigneeId, in.TeamId, now)
+	model, err := e.taskCreator.CreateTask(ctx, status, in.Title, in.Description, in.CreatorId, assigneeIds, in.TeamId, now, in.DueAt, in.Priority)
 
 	if err != nil {
 		return nil, err
 	}
 
+	// Задача уже создана, поэтому ошибка уведомлений только пишется в лог.
+	if err := e.notificationCreator.CreateNotifications(ctx, notification.AssignmentChanges(nil, *model, in.CreatorId, now)); err != nil {
+		log.Printf("task %d notifications error: %v", model.Id, err)
+	}
+
 	return &CreateResult{
 		Id:          model.Id,
 		Status:      model.Status,
@@ -74,7 +147,11 @@ func (e *executor) Execute(ctx context.Context, in CreateInput) (*CreateResult,
 		Description: model.Description,
 		CreatorId:   model.CreatorId,
 		CreatedAt:   model.CreatedAt,
-		AssigneeId:  model.AssigneeId,
+		UpdatedAt:   model.UpdatedAt,
+		AssigneeIds: model.AssigneeIds,
 		TeamId:      model.TeamId,
+		Version:     model.Version,
+		DueAt:       model.DueAt,
+		Priority:    model.Priority,
 	}, nil
 }
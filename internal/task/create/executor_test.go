@@ -2,18 +2,23 @@ package create
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
 	"time"
 
 	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+	"mkk-luna-test-task/internal/team/workflow"
+	"mkk-luna-test-task/internal/user/notification"
 
 	"github.com/stretchr/testify/assert"
 )
 
 type stubTaskCreatorSuccess struct{}
 
-func (s *stubTaskCreatorSuccess) CreateTask(ctx context.Context, status, title, description string, creatorId, assigneeId, teamId int, createdAt time.Time) (*task.Model, error) {
+func (s *stubTaskCreatorSuccess) CreateTask(ctx context.Context, status, title, description string, creatorId int, assigneeIds []int, teamId int, createdAt time.Time, dueAt *time.Time, priority task.Priority) (*task.Model, error) {
 	return &task.Model{
 		Id:          11,
 		Status:      status,
@@ -21,95 +26,197 @@ func (s *stubTaskCreatorSuccess) CreateTask(ctx context.Context, status, title,
 		Description: description,
 		CreatorId:   creatorId,
 		CreatedAt:   createdAt,
-		AssigneeId:  assigneeId,
+		AssigneeIds: assigneeIds,
 		TeamId:      teamId,
+		DueAt:       dueAt,
+		Priority:    priority,
 	}, nil
 }
 
 type stubTaskCreatorError struct{}
 
-func (s *stubTaskCreatorError) CreateTask(ctx context.Context, status, title, description string, creatorId, assigneeId, teamId int, createdAt time.Time) (*task.Model, error) {
+func (s *stubTaskCreatorError) CreateTask(ctx context.Context, status, title, description string, creatorId int, assigneeIds []int, teamId int, createdAt time.Time, dueAt *time.Time, priority task.Priority) (*task.Model, error) {
 	return nil, errors.New("some creation error")
 }
 
-type stubTeamMembershipCheckerAllowed struct{}
+type stubAuthorizerAllowed struct{}
 
-func (s *stubTeamMembershipCheckerAllowed) CheckUserIsInTeam(userId, teamId int) (bool, error) {
-	return true, nil
+func (s *stubAuthorizerAllowed) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
 }
 
-type stubTeamMembershipCheckerForbidden struct{}
+type stubAuthorizerForbidden struct{}
 
-func (s *stubTeamMembershipCheckerForbidden) CheckUserIsInTeam(userId, teamId int) (bool, error) {
-	return false, nil
+func (s *stubAuthorizerForbidden) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	return nil, permission.ErrForbidden
 }
 
-type stubTeamMembershipCheckerError struct{}
+type stubAuthorizerError struct{}
 
-func (s *stubTeamMembershipCheckerError) CheckUserIsInTeam(userId, teamId int) (bool, error) {
-	return false, errors.New("membership check failed")
+func (s *stubAuthorizerError) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	return nil, errors.New("membership check failed")
+}
+
+type stubMemberGetterFound struct{}
+
+func (s *stubMemberGetterFound) GetMember(ctx context.Context, teamId, userId int) (*member.Model, error) {
+	return &member.Model{UserId: userId, TeamId: teamId, Role: member.NormalRole}, nil
+}
+
+type stubMemberGetterNotFound struct{}
+
+func (s *stubMemberGetterNotFound) GetMember(ctx context.Context, teamId, userId int) (*member.Model, error) {
+	return nil, sql.ErrNoRows
+}
+
+type stubMemberGetterError struct{}
+
+func (s *stubMemberGetterError) GetMember(ctx context.Context, teamId, userId int) (*member.Model, error) {
+	return nil, errors.New("member lookup failed")
+}
+
+type stubWorkflowGetter struct {
+	wf *workflow.Model
+}
+
+func (s *stubWorkflowGetter) GetWorkflow(ctx context.Context, teamId int) (*workflow.Model, error) {
+	if s.wf != nil {
+		return s.wf, nil
+	}
+
+	return workflow.Default(teamId), nil
+}
+
+type mockNotificationCreator struct {
+	userIds []int
+}
+
+func (m *mockNotificationCreator) CreateNotifications(ctx context.Context, notifications []notification.Model) error {
+	for _, n := range notifications {
+		m.userIds = append(m.userIds, n.UserId)
+	}
+
+	return errors.New("notifications are not critical")
 }
 
 func TestExecutor_Execute(t *testing.T) {
 	type fields struct {
-		taskCreator           taskCreator
-		teamMembershipChecker teamMembershipChecker
+		taskCreator    taskCreator
+		authorizer     authorizer
+		memberGetter   memberGetter
+		workflowGetter workflowGetter
 	}
 	type args struct {
 		ctx context.Context
 		in  CreateInput
 	}
 
+	dueAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
 	tests := []struct {
-		name        string
-		fields      fields
-		args        args
-		want        *CreateResult
-		wantErr     bool
-		expectedErr string
+		name   string
+		fields fields
+		args   args
+		want   *CreateResult
+		// wantNotified — кому ушло уведомление о назначении.
+		wantNotified []int
+		wantErr      bool
+		expectedErr  string
 	}{
 		{
 			name: "success",
 			fields: fields{
-				taskCreator:           &stubTaskCreatorSuccess{},
-				teamMembershipChecker: &stubTeamMembershipCheckerAllowed{},
+				taskCreator:    &stubTaskCreatorSuccess{},
+				authorizer:     &stubAuthorizerAllowed{},
+				memberGetter:   &stubMemberGetterFound{},
+				workflowGetter: &stubWorkflowGetter{},
 			},
 			args: args{
 				ctx: context.Background(),
 				in: CreateInput{
 					CreatorId:   1001,
-					Status:      "open",
+					Status:      "todo",
 					Title:       "Test Title",
 					Description: "Test Description",
-					AssigneeId:  2002,
+					AssigneeIds: []int{2002, 1001, 2002},
 					TeamId:      3003,
 				},
 			},
 			want: &CreateResult{
 				Id:          11,
-				Status:      "open",
+				Status:      "todo",
 				Title:       "Test Title",
 				Description: "Test Description",
 				CreatorId:   1001,
-				AssigneeId:  2002,
+				AssigneeIds: []int{1001, 2002},
+				TeamId:      3003,
+			},
+			wantNotified: []int{2002},
+			wantErr:      false,
+		},
+		{
+			name: "unassigned task",
+			fields: fields{
+				taskCreator:    &stubTaskCreatorSuccess{},
+				authorizer:     &stubAuthorizerAllowed{},
+				memberGetter:   &stubMemberGetterNotFound{},
+				workflowGetter: &stubWorkflowGetter{},
+			},
+			args: args{
+				ctx: context.Background(),
+				in: CreateInput{
+					CreatorId: 1001,
+					Title:     "Backlog item",
+					TeamId:    3003,
+				},
+			},
+			want: &CreateResult{
+				Id:          11,
+				Status:      "todo",
+				Title:       "Backlog item",
+				CreatorId:   1001,
+				AssigneeIds: []int{},
 				TeamId:      3003,
 			},
 			wantErr: false,
 		},
+		{
+			name: "invalid assignee id",
+			fields: fields{
+				taskCreator:    &stubTaskCreatorSuccess{},
+				authorizer:     &stubAuthorizerAllowed{},
+				memberGetter:   &stubMemberGetterFound{},
+				workflowGetter: &stubWorkflowGetter{},
+			},
+			args: args{
+				ctx: context.Background(),
+				in: CreateInput{
+					CreatorId:   1001,
+					Title:       "Test Title",
+					AssigneeIds: []int{2002, -1},
+					TeamId:      3003,
+				},
+			},
+			want:        nil,
+			wantErr:     true,
+			expectedErr: "validation failed",
+		},
 		{
 			name: "forbidden membership",
 			fields: fields{
-				taskCreator:           &stubTaskCreatorSuccess{},
-				teamMembershipChecker: &stubTeamMembershipCheckerForbidden{},
+				taskCreator:    &stubTaskCreatorSuccess{},
+				authorizer:     &stubAuthorizerForbidden{},
+				memberGetter:   &stubMemberGetterFound{},
+				workflowGetter: &stubWorkflowGetter{},
 			},
 			args: args{
 				ctx: context.Background(),
 				in: CreateInput{
 					CreatorId:   1,
-					Status:      "open",
+					Status:      "todo",
 					Title:       "Try",
 					Description: "Try Desc",
-					AssigneeId:  2,
+					AssigneeIds: []int{2},
 					TeamId:      3,
 				},
 			},
@@ -120,8 +227,10 @@ func TestExecutor_Execute(t *testing.T) {
 		{
 			name: "error in membership checker",
 			fields: fields{
-				taskCreator:           &stubTaskCreatorSuccess{},
-				teamMembershipChecker: &stubTeamMembershipCheckerError{},
+				taskCreator:    &stubTaskCreatorSuccess{},
+				authorizer:     &stubAuthorizerError{},
+				memberGetter:   &stubMemberGetterFound{},
+				workflowGetter: &stubWorkflowGetter{},
 			},
 			args: args{
 				ctx: context.Background(),
@@ -130,7 +239,7 @@ func TestExecutor_Execute(t *testing.T) {
 					Status:      "X",
 					Title:       "Y",
 					Description: "Z",
-					AssigneeId:  13,
+					AssigneeIds: []int{13},
 					TeamId:      21,
 				},
 			},
@@ -138,20 +247,172 @@ func TestExecutor_Execute(t *testing.T) {
 			wantErr:     true,
 			expectedErr: "membership check failed",
 		},
+		{
+			name: "assignee is not a team member",
+			fields: fields{
+				taskCreator:    &stubTaskCreatorSuccess{},
+				authorizer:     &stubAuthorizerAllowed{},
+				memberGetter:   &stubMemberGetterNotFound{},
+				workflowGetter: &stubWorkflowGetter{},
+			},
+			args: args{
+				ctx: context.Background(),
+				in: CreateInput{
+					CreatorId:   1,
+					Status:      "todo",
+					Title:       "Title",
+					Description: "Desc",
+					AssigneeIds: []int{77},
+					TeamId:      3,
+				},
+			},
+			want:        nil,
+			wantErr:     true,
+			expectedErr: "validation failed",
+		},
+		{
+			name: "assignee lookup fails",
+			fields: fields{
+				taskCreator:    &stubTaskCreatorSuccess{},
+				authorizer:     &stubAuthorizerAllowed{},
+				memberGetter:   &stubMemberGetterError{},
+				workflowGetter: &stubWorkflowGetter{},
+			},
+			args: args{
+				ctx: context.Background(),
+				in: CreateInput{
+					CreatorId:   1,
+					Status:      "todo",
+					Title:       "Title",
+					Description: "Desc",
+					AssigneeIds: []int{2},
+					TeamId:      3,
+				},
+			},
+			want:        nil,
+			wantErr:     true,
+			expectedErr: "member lookup failed",
+		},
+		{
+			name: "empty status starts in the initial one",
+			fields: fields{
+				taskCreator:    &stubTaskCreatorSuccess{},
+				authorizer:     &stubAuthorizerAllowed{},
+				memberGetter:   &stubMemberGetterFound{},
+				workflowGetter: &stubWorkflowGetter{},
+			},
+			args: args{
+				ctx: context.Background(),
+				in: CreateInput{
+					CreatorId:   1,
+					Title:       "Title",
+					AssigneeIds: []int{2},
+					TeamId:      3,
+				},
+			},
+			want: &CreateResult{
+				Id:          11,
+				Status:      "todo",
+				Title:       "Title",
+				CreatorId:   1,
+				AssigneeIds: []int{2},
+				TeamId:      3,
+			},
+			wantNotified: []int{2},
+			wantErr:      false,
+		},
+		{
+			name: "due date and priority are stored",
+			fields: fields{
+				taskCreator:    &stubTaskCreatorSuccess{},
+				authorizer:     &stubAuthorizerAllowed{},
+				memberGetter:   &stubMemberGetterFound{},
+				workflowGetter: &stubWorkflowGetter{},
+			},
+			args: args{
+				ctx: context.Background(),
+				in: CreateInput{
+					CreatorId:   1,
+					Title:       "Title",
+					AssigneeIds: []int{2},
+					TeamId:      3,
+					DueAt:       &dueAt,
+					Priority:    task.UrgentPriority,
+				},
+			},
+			want: &CreateResult{
+				Id:          11,
+				Status:      "todo",
+				Title:       "Title",
+				CreatorId:   1,
+				AssigneeIds: []int{2},
+				TeamId:      3,
+				DueAt:       &dueAt,
+				Priority:    task.UrgentPriority,
+			},
+			wantNotified: []int{2},
+			wantErr:      false,
+		},
+		{
+			name: "terminal status",
+			fields: fields{
+				taskCreator:    &stubTaskCreatorSuccess{},
+				authorizer:     &stubAuthorizerAllowed{},
+				memberGetter:   &stubMemberGetterFound{},
+				workflowGetter: &stubWorkflowGetter{},
+			},
+			args: args{
+				ctx: context.Background(),
+				in: CreateInput{
+					CreatorId:   1,
+					Status:      "done",
+					Title:       "Title",
+					AssigneeIds: []int{2},
+					TeamId:      3,
+				},
+			},
+			want:        nil,
+			wantErr:     true,
+			expectedErr: "validation failed",
+		},
+		{
+			name: "unknown status",
+			fields: fields{
+				taskCreator:    &stubTaskCreatorSuccess{},
+				authorizer:     &stubAuthorizerAllowed{},
+				memberGetter:   &stubMemberGetterFound{},
+				workflowGetter: &stubWorkflowGetter{},
+			},
+			args: args{
+				ctx: context.Background(),
+				in: CreateInput{
+					CreatorId:   1,
+					Status:      "archived",
+					Title:       "Title",
+					AssigneeIds: []int{2},
+					TeamId:      3,
+				},
+			},
+			want:        nil,
+			wantErr:     true,
+			expectedErr: "validation failed",
+		},
 		{
 			name: "creation fails",
 			fields: fields{
-				taskCreator:           &stubTaskCreatorError{},
-				teamMembershipChecker: &stubTeamMembershipCheckerAllowed{},
+				taskCreator:    &stubTaskCreatorError{},
+				authorizer:     &stubAuthorizerAllowed{},
+				memberGetter:   &stubMemberGetterFound{},
+				workflowGetter: &stubWorkflowGetter{},
 			},
 			args: args{
 				ctx: context.Background(),
 				in: CreateInput{
 					CreatorId:   404,
-					Status:      "fail",
+					Status:      "todo",
 					Title:       "nope",
 					Description: "should fail",
-					AssigneeId:  505,
+					AssigneeIds: []int{505},
 					TeamId:      606,
 				},
 			},
@@ -163,9 +424,14 @@ func TestExecutor_Execute(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			notifier := &mockNotificationCreator{}
+
 			e := &executor{
-				taskCreator:           tt.fields.taskCreator,
-				teamMembershipChecker: tt.fields.teamMembershipChecker,
+				taskCreator:         tt.fields.taskCreator,
+				authorizer:          tt.fields.authorizer,
+				memberGetter:        tt.fields.memberGetter,
+				workflowGetter:      tt.fields.workflowGetter,
+				notificationCreator: notifier,
 			}
 
 			got, err := e.Execute(tt.args.ctx, tt.args.in)
@@ -185,10 +451,14 @@ func TestExecutor_Execute(t *testing.T) {
 				assert.Equal(t, tt.want.Title, got.Title)
 				assert.Equal(t, tt.want.Description, got.Description)
 				assert.Equal(t, tt.want.CreatorId, got.CreatorId)
-				assert.Equal(t, tt.want.AssigneeId, got.AssigneeId)
+				assert.Equal(t, tt.want.AssigneeIds, got.AssigneeIds)
 				assert.Equal(t, tt.want.TeamId, got.TeamId)
+				assert.Equal(t, tt.want.DueAt, got.DueAt)
+				assert.Equal(t, tt.want.Priority, got.Priority)
 				assert.False(t, got.CreatedAt.IsZero())
 			}
+
+			assert.Equal(t, tt.wantNotified, notifier.userIds)
 		})
 	}
 }
@@ -6,25 +6,35 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/validation"
 )
 
 type request struct {
-	Status      string `json:"status"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	AssigneeId  int    `json:"assignee_id"`
-	TeamId      int    `json:"team_id"`
+	Status      string     `json:"status" validate:"max=64"`
+	Title       string     `json:"title" validate:"required,max=255"`
+	Description string     `json:"description" validate:"max=10000"`
+	AssigneeIds []int      `json:"assignee_ids"`
+	TeamId      int        `json:"team_id" validate:"required,min=1"`
+	DueAt       *time.Time `json:"due_at"`
+	Priority    string     `json:"priority" validate:"oneof=low normal high urgent"`
 }
 
 type response struct {
-	Id          int       `json:"id"`
-	Status      string    `json:"status"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	CreatorId   int       `json:"creator_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	AssigneeId  int       `json:"assignee_id"`
-	TeamId      int       `json:"team_id"`
+	Id          int           `json:"id"`
+	Status      string        `json:"status"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	CreatorId   int           `json:"creator_id"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	AssigneeIds []int         `json:"assignee_ids"`
+	TeamId      int           `json:"team_id"`
+	Version     int           `json:"version"`
+	DueAt       *time.Time    `json:"due_at"`
+	Priority    task.Priority `json:"priority"`
 }
 
 type Executor interface {
@@ -47,7 +57,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	userId, ok := userIdAny.(int)
 
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
 
 		return
 	}
@@ -56,7 +66,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
 	if err != nil {
-		http.Error(w, "error", http.StatusInternalServerError)
+		apperror.Write(w, err)
 
 		return
 	}
@@ -64,22 +74,36 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	var req request
 
 	if err := json.Unmarshal(body, &req); err != nil {
-		http.Error(w, "json error", http.StatusBadRequest)
+		apperror.Write(w, apperror.Validation("invalid json"))
+
+		return
+	}
+
+	if err := validation.Struct(req); err != nil {
+		apperror.Write(w, err)
 
 		return
 	}
 
+	priority := task.NormalPriority
+
+	if req.Priority != "" {
+		priority, _ = task.ParsePriority(req.Priority)
+	}
+
 	result, err := h.exec.Execute(r.Context(), CreateInput{
 		CreatorId:   userId,
 		Status:      req.Status,
 		Title:       req.Title,
 		Description: req.Description,
-		AssigneeId:  req.AssigneeId,
+		AssigneeIds: req.AssigneeIds,
 		TeamId:      req.TeamId,
+		DueAt:       req.DueAt,
+		Priority:    priority,
 	})
 
 	if err != nil {
-		http.Error(w, "execution error", http.StatusBadRequest)
+		apperror.Write(w, err)
 
 		return
 	}
@@ -91,18 +115,23 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 		Description: result.Description,
 		CreatorId:   result.CreatorId,
 		CreatedAt:   result.CreatedAt,
-		AssigneeId:  result.AssigneeId,
+		UpdatedAt:   result.UpdatedAt,
+		AssigneeIds: result.AssigneeIds,
 		TeamId:      result.TeamId,
+		Version:     result.Version,
+		DueAt:       result.DueAt,
+		Priority:    result.Priority,
 	}
 
 	respBody, err := json.Marshal(resp)
 
 	if err != nil {
-		http.Error(w, "error", http.StatusInternalServerError)
+		apperror.Write(w, err)
 
 		return
 	}
 
+	w.Header().Set("ETag", task.ETag(result.Version))
 	w.WriteHeader(http.StatusCreated)
 	w.Write(respBody)
 }
@@ -4,108 +4,320 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"fmt"
+	"log"
+	"reflect"
+	"slices"
 	"time"
 
+	"mkk-luna-test-task/internal/apperror"
 	"mkk-luna-test-task/internal/task"
-	"mkk-luna-test-task/internal/task/history"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+	"mkk-luna-test-task/internal/team/workflow"
+	"mkk-luna-test-task/internal/user/notification"
 )
 
-type taskEditor interface {
-	EditTask(ctx context.Context, id int, status, title, description string, assigneeId int) error
+// Сколько раз перечитываем задачу, если её изменили между чтением и записью, а клиент не прислал If-Match.
+const maxAttempts = 3
+
+type taskUpdater interface {
+	UpdateTask(ctx context.Context, t task.Model, expectedVersion int, changedBy int, changedAt time.Time) (*task.Model, error)
 }
 
 type taskGetter interface {
 	GetTaskById(ctx context.Context, id int) (*task.Model, error)
 }
 
-type historySaver interface {
-	CreateHistory(ctx context.Context, task *task.Model, changedBy int, changedAt time.Time) (*history.Model, error)
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type memberGetter interface {
+	GetMember(ctx context.Context, teamId, userId int) (*member.Model, error)
 }
 
-type taskMembershipChecker interface {
-	IsPartOfTeamByTaskId(userId, taskId int) (bool, error)
+type workflowGetter interface {
+	GetWorkflow(ctx context.Context, teamId int) (*workflow.Model, error)
+}
+
+type blockerLister interface {
+	ListTaskBlockers(ctx context.Context, taskId int) ([]*task.Model, error)
 }
 
 type cacheUpdater interface {
 	UpdateTaskInCache(ctx context.Context, t task.Model) error
 }
 
+type watcherLister interface {
+	ListTaskWatchers(ctx context.Context, taskId int) ([]int, error)
+}
+
+type notificationCreator interface {
+	CreateNotifications(ctx context.Context, notifications []notification.Model) error
+}
+
 type executor struct {
-	taskEditor            taskEditor
-	taskGetter            taskGetter
-	historySaver          historySaver
-	taskMembershipChecker taskMembershipChecker
-	cacheUpdater          cacheUpdater
+	taskUpdater         taskUpdater
+	taskGetter          taskGetter
+	authorizer          authorizer
+	memberGetter        memberGetter
+	workflowGetter      workflowGetter
+	blockerLister       blockerLister
+	cacheUpdater        cacheUpdater
+	watcherLister       watcherLister
+	notificationCreator notificationCreator
 }
 
 func NewExecutor(
-	taskEditor taskEditor,
+	taskUpdater taskUpdater,
 	taskGetter taskGetter,
-	historySaver historySaver,
-	taskMembershipChecker taskMembershipChecker,
+	authorizer authorizer,
+	memberGetter memberGetter,
+	workflowGetter workflowGetter,
+	blockerLister blockerLister,
 	cacheUpdater cacheUpdater,
+	watcherLister watcherLister,
+	notificationCreator notificationCreator,
 ) *executor {
 	return &executor{
-		taskEditor:            taskEditor,
-		taskGetter:            taskGetter,
-		historySaver:          historySaver,
-		taskMembershipChecker: taskMembershipChecker,
-		cacheUpdater:          cacheUpdater,
+		taskUpdater:         taskUpdater,
+		taskGetter:          taskGetter,
+		authorizer:          authorizer,
+		memberGetter:        memberGetter,
+		workflowGetter:      workflowGetter,
+		blockerLister:       blockerLister,
+		cacheUpdater:        cacheUpdater,
+		watcherLister:       watcherLister,
+		notificationCreator: notificationCreator,
 	}
 }
 
+// EditInput — частичное обновление: nil-поля остаются как есть.
 type EditInput struct {
 	UserId      int
 	TaskId      int
+	Status      *string
+	Title       *string
+	Description *string
+	// AssigneeIds заменяет исполнителей целиком; пустой список снимает всех.
+	AssigneeIds *[]int
+	// DueAt.Set с nil-значением снимает срок.
+	DueAt    task.Optional[*time.Time]
+	Priority *task.Priority
+	// IfMatch — версия задачи, которую видел клиент; 0 — клиент версию не проверяет.
+	IfMatch int
+}
+
+type EditResult struct {
+	Id          int
 	Status      string
 	Title       string
 	Description string
-	AssigneeId  int
+	CreatorId   int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	AssigneeIds []int
+	TeamId      int
+	Version     int
+	DueAt       *time.Time
+	Priority    task.Priority
 }
 
-func (e *executor) Execute(ctx context.Context, in EditInput) (bool, error) {
+func (e *executor) Execute(ctx context.Context, in EditInput) (*EditResult, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := e.tryEdit(ctx, in)
+
+		// Без If-Match клиенту всё равно, на какую версию ложится правка, поэтому просто перечитываем задачу.
+		if errors.Is(err, task.ErrVersionMismatch) && in.IfMatch == 0 && attempt < maxAttempts {
+			continue
+		}
+
+		return result, err
+	}
+}
+
+func (e *executor) tryEdit(ctx context.Context, in EditInput) (*EditResult, error) {
 	oldTask, err := e.taskGetter.GetTaskById(ctx, in.TaskId)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return false, fmt.Errorf("task not found")
+			return nil, apperror.NotFound("task not found")
 		}
 
-		return false, err
+		return nil, err
 	}
 
-	isMember, err := e.taskMembershipChecker.IsPartOfTeamByTaskId(in.UserId, in.TaskId)
+	// Обычный участник может править только свои задачи: созданные им или назначенные на него.
+	_, err = e.authorizer.Authorize(ctx, in.UserId, permission.TaskEdit, permission.TaskResource(*oldTask))
 
 	if err != nil {
-		return false, fmt.Errorf("failed to check team membership: %w", err)
+		return nil, err
 	}
 
-	if !isMember {
-		return false, fmt.Errorf("forbidden")
+	if oldTask.IsArchived() {
+		return nil, task.ErrArchived
 	}
 
-	_, err = e.historySaver.CreateHistory(ctx, oldTask, in.UserId, time.Now())
+	if in.IfMatch != 0 && in.IfMatch != oldTask.Version {
+		return nil, task.ErrVersionMismatch
+	}
 
-	if err != nil {
-		return false, fmt.Errorf("failed to save task history: %w", err)
+	updated := *oldTask
+
+	if in.Status != nil {
+		updated.Status = *in.Status
 	}
 
-	err = e.taskEditor.EditTask(ctx, in.TaskId, in.Status, in.Title, in.Description, in.AssigneeId)
+	if in.Title != nil {
+		updated.Title = *in.Title
+	}
 
-	if err != nil {
-		return false, err
+	if in.Description != nil {
+		updated.Description = *in.Description
+	}
+
+	if in.AssigneeIds != nil {
+		assigneeIds, err := task.NormalizeAssignees(*in.AssigneeIds)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// Срез заменяем, только если состав исполнителей другой, — как и срок ниже.
+		if !slices.Equal(assigneeIds, oldTask.AssigneeIds) {
+			updated.AssigneeIds = assigneeIds
+		}
+	}
+
+	// Указатель заменяем, только если срок действительно другой, иначе сравнение ниже увидит изменение на ровном месте.
+	if in.DueAt.Set && !sameTime(in.DueAt.Value, oldTask.DueAt) {
+		updated.DueAt = in.DueAt.Value
+	}
+
+	if in.Priority != nil {
+		updated.Priority = *in.Priority
+	}
+
+	// Задачу со срезом исполнителей нельзя сравнить через ==; неизменённые поля остались общими со старой версией.
+	if reflect.DeepEqual(updated, *oldTask) {
+		return newEditResult(oldTask), nil
+	}
+
+	if updated.Status != oldTask.Status {
+		wf, err := e.workflowGetter.GetWorkflow(ctx, oldTask.TeamId)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := wf.CheckTransition(oldTask.Status, updated.Status); err != nil {
+			return nil, err
+		}
+
+		if wf.IsTerminal(updated.Status) {
+			if err := e.checkBlockers(ctx, wf, oldTask.Id); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	updatedTask := *oldTask
-	updatedTask.Status = in.Status
-	updatedTask.Title = in.Title
-	updatedTask.Description = in.Description
-	updatedTask.AssigneeId = in.AssigneeId
+	// Прежних исполнителей не перепроверяем: задачу ушедшего из команды участника всё ещё можно править.
+	for _, assigneeId := range updated.AssigneeIds {
+		if oldTask.IsAssignee(assigneeId) {
+			continue
+		}
+
+		_, err = e.memberGetter.GetMember(ctx, oldTask.TeamId, assigneeId)
+
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, apperror.InvalidField("assignee_ids", "must be members of the team")
+			}
+
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+
+	saved, err := e.taskUpdater.UpdateTask(ctx, updated, oldTask.Version, in.UserId, now)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("task not found")
+		}
+
+		return nil, err
+	}
 
 	// В худшем случае (если не получилось) старая версия просто улетит из кэша по ttl.
 	// Если нужны жёсткие гарантии консистентности, то нужно думать над архитектурой.
-	_ = e.cacheUpdater.UpdateTaskInCache(ctx, updatedTask)
+	_ = e.cacheUpdater.UpdateTaskInCache(ctx, *saved)
+
+	e.notify(ctx, *oldTask, *saved, in.UserId, now)
+
+	return newEditResult(saved), nil
+}
+
+// notify сообщает о смене исполнителей и, наблюдателям, о смене статуса.
+// Правка уже сохранена, поэтому ошибки уведомлений только пишутся в лог.
+func (e *executor) notify(ctx context.Context, before, after task.Model, actorId int, now time.Time) {
+	notifications := notification.AssignmentChanges(before.AssigneeIds, after, actorId, now)
+
+	if after.Status != before.Status {
+		watcherIds, err := e.watcherLister.ListTaskWatchers(ctx, after.Id)
+
+		if err != nil {
+			log.Printf("task %d watchers for notifications error: %v", after.Id, err)
+		}
+
+		notifications = append(notifications, notification.StatusChange(before.Status, after, watcherIds, actorId, now)...)
+	}
+
+	if err := e.notificationCreator.CreateNotifications(ctx, notifications); err != nil {
+		log.Printf("task %d notifications error: %v", after.Id, err)
+	}
+}
+
+// checkBlockers не даёт завершить задачу, пока не завершены её блокеры.
+// Блокеры из той же команды, поэтому их статусы проверяются по тому же процессу.
+func (e *executor) checkBlockers(ctx context.Context, wf *workflow.Model, taskId int) error {
+	blockers, err := e.blockerLister.ListTaskBlockers(ctx, taskId)
+
+	if err != nil {
+		return err
+	}
+
+	for _, b := range blockers {
+		if !wf.IsTerminal(b.Status) {
+			return task.ErrOpenBlockers
+		}
+	}
+
+	return nil
+}
+
+func newEditResult(t *task.Model) *EditResult {
+	return &EditResult{
+		Id:          t.Id,
+		Status:      t.Status,
+		Title:       t.Title,
+		Description: t.Description,
+		CreatorId:   t.CreatorId,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+		AssigneeIds: t.AssigneeIds,
+		TeamId:      t.TeamId,
+		Version:     t.Version,
+		DueAt:       t.DueAt,
+		Priority:    t.Priority,
+	}
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
 
-	return true, nil
+	return a.Equal(*b)
 }
@@ -8,21 +8,37 @@ import (
 	"time"
 
 	"mkk-luna-test-task/internal/task"
-	"mkk-luna-test-task/internal/task/history"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+	"mkk-luna-test-task/internal/team/workflow"
+	"mkk-luna-test-task/internal/user/notification"
 
 	"github.com/stretchr/testify/assert"
 )
 
-type stubTaskEditorSuccess struct{}
-
-func (s *stubTaskEditorSuccess) EditTask(ctx context.Context, id int, status, title, description string, assigneeId int) error {
-	return nil
+type mockTaskUpdater struct {
+	// mismatches — сколько первых вызовов вернут конфликт версий, как при параллельной правке.
+	mismatches int
+	err        error
+	calls      int
+	saved      *task.Model
 }
 
-type stubTaskEditorError struct{}
+func (m *mockTaskUpdater) UpdateTask(ctx context.Context, t task.Model, expectedVersion int, changedBy int, changedAt time.Time) (*task.Model, error) {
+	m.calls++
 
-func (s *stubTaskEditorError) EditTask(ctx context.Context, id int, status, title, description string, assigneeId int) error {
-	return errors.New("edit task error")
+	if m.calls <= m.mismatches {
+		return nil, task.ErrVersionMismatch
+	}
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	t.Version = expectedVersion + 1
+	m.saved = &t
+
+	return &t, nil
 }
 
 type stubTaskGetterSuccess struct {
@@ -30,7 +46,9 @@ type stubTaskGetterSuccess struct {
 }
 
 func (s *stubTaskGetterSuccess) GetTaskById(ctx context.Context, id int) (*task.Model, error) {
-	return s.task, nil
+	t := *s.task
+
+	return &t, nil
 }
 
 type stubTaskGetterNotFound struct{}
@@ -45,34 +63,54 @@ func (s *stubTaskGetterError) GetTaskById(ctx context.Context, id int) (*task.Mo
 	return nil, errors.New("db error")
 }
 
-type stubHistorySaverSuccess struct{}
+type stubAuthorizerAllowed struct{}
 
-func (s *stubHistorySaverSuccess) CreateHistory(ctx context.Context, t *task.Model, changedBy int, changedAt time.Time) (*history.Model, error) {
-	return &history.Model{}, nil
+func (s *stubAuthorizerAllowed) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
 }
 
-type stubHistorySaverError struct{}
+type stubAuthorizerForbidden struct{}
 
-func (s *stubHistorySaverError) CreateHistory(ctx context.Context, t *task.Model, changedBy int, changedAt time.Time) (*history.Model, error) {
-	return nil, errors.New("history error")
+func (s *stubAuthorizerForbidden) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	return nil, permission.ErrForbidden
 }
 
-type stubTaskMembershipCheckerAllowed struct{}
+type stubAuthorizerError struct{}
+
+func (s *stubAuthorizerError) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	return nil, errors.New("membership error")
+}
+
+type stubMemberGetterFound struct{}
+
+func (s *stubMemberGetterFound) GetMember(ctx context.Context, teamId, userId int) (*member.Model, error) {
+	return &member.Model{UserId: userId, TeamId: teamId, Role: member.NormalRole}, nil
+}
+
+type stubMemberGetterNotFound struct{}
+
+func (s *stubMemberGetterNotFound) GetMember(ctx context.Context, teamId, userId int) (*member.Model, error) {
+	return nil, sql.ErrNoRows
+}
 
-func (s *stubTaskMembershipCheckerAllowed) IsPartOfTeamByTaskId(userId, taskId int) (bool, error) {
-	return true, nil
+type stubWorkflowGetter struct {
+	wf *workflow.Model
 }
 
-type stubTaskMembershipCheckerForbidden struct{}
+func (s *stubWorkflowGetter) GetWorkflow(ctx context.Context, teamId int) (*workflow.Model, error) {
+	if s.wf != nil {
+		return s.wf, nil
+	}
 
-func (s *stubTaskMembershipCheckerForbidden) IsPartOfTeamByTaskId(userId, taskId int) (bool, error) {
-	return false, nil
+	return workflow.Default(teamId), nil
 }
 
-type stubTaskMembershipCheckerError struct{}
+type stubBlockerLister struct {
+	blockers []*task.Model
+}
 
-func (s *stubTaskMembershipCheckerError) IsPartOfTeamByTaskId(userId, taskId int) (bool, error) {
-	return false, errors.New("membership error")
+func (s *stubBlockerLister) ListTaskBlockers(ctx context.Context, taskId int) ([]*task.Model, error) {
+	return s.blockers, nil
 }
 
 type mockCacheUpdater struct {
@@ -84,189 +122,391 @@ func (m *mockCacheUpdater) UpdateTaskInCache(ctx context.Context, t task.Model)
 	return nil
 }
 
+type stubWatcherLister struct {
+	watchers []int
+	err      error
+}
+
+func (s *stubWatcherLister) ListTaskWatchers(ctx context.Context, taskId int) ([]int, error) {
+	return s.watchers, s.err
+}
+
+type mockNotificationCreator struct {
+	err           error
+	notifications []notification.Model
+}
+
+func (m *mockNotificationCreator) CreateNotifications(ctx context.Context, notifications []notification.Model) error {
+	m.notifications = append(m.notifications, notifications...)
+
+	return m.err
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
 func TestExecutor_Execute(t *testing.T) {
-	type fields struct {
-		taskEditor            taskEditor
-		taskGetter            taskGetter
-		historySaver          historySaver
-		taskMembershipChecker taskMembershipChecker
-		cacheUpdater          *mockCacheUpdater
-	}
-	type args struct {
-		ctx context.Context
-		in  EditInput
-	}
+	archivedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
 	baseTask := &task.Model{
 		Id:          1,
-		Status:      "old",
+		Status:      "todo",
 		Title:       "old title",
 		Description: "old desc",
-		AssigneeId:  7,
+		AssigneeIds: []int{7},
 		TeamId:      10,
 		CreatorId:   100,
+		Version:     3,
+	}
+
+	dueAt := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	datedTask := *baseTask
+	datedTask.DueAt = &dueAt
+
+	archivedTask := &task.Model{Id: 1, Status: "todo", TeamId: 10, Version: 4, ArchivedAt: &archivedAt}
+
+	qaWorkflow := &workflow.Model{
+		Statuses:    []workflow.Status{{Name: "todo"}, {Name: "qa"}, {Name: "done", Terminal: true}},
+		Transitions: []workflow.Transition{{From: "todo", To: "qa"}, {From: "qa", To: "done"}},
 	}
 
 	tests := []struct {
-		name        string
-		fields      fields
-		args        args
-		want        bool
-		wantErr     bool
-		expectedErr string
+		name         string
+		taskGetter   taskGetter
+		authorizer   authorizer
+		memberGetter memberGetter
+		workflow     *workflow.Model
+		blockers     []*task.Model
+		// wantAssignees — исполнители после правки; nil — не проверяются.
+		wantAssignees []int
+		updater       *mockTaskUpdater
+		in            EditInput
+		wantVersion   int
+		wantSaves     int
+		expectedErr   string
 	}{
 		{
-			name: "success",
-			fields: fields{
-				taskEditor:            &stubTaskEditorSuccess{},
-				taskGetter:            &stubTaskGetterSuccess{task: baseTask},
-				historySaver:          &stubHistorySaverSuccess{},
-				taskMembershipChecker: &stubTaskMembershipCheckerAllowed{},
-				cacheUpdater:          &mockCacheUpdater{},
-			},
-			args: args{
-				ctx: context.Background(),
-				in: EditInput{
-					UserId:      5,
-					TaskId:      1,
-					Status:      "inprogress",
-					Title:       "new",
-					Description: "desc",
-					AssigneeId:  8,
-				},
-			},
-			want:    true,
-			wantErr: false,
-		},
-		{
-			name: "task not found",
-			fields: fields{
-				taskEditor:            &stubTaskEditorSuccess{},
-				taskGetter:            &stubTaskGetterNotFound{},
-				historySaver:          &stubHistorySaverSuccess{},
-				taskMembershipChecker: &stubTaskMembershipCheckerAllowed{},
-				cacheUpdater:          &mockCacheUpdater{},
-			},
-			args: args{
-				ctx: context.Background(),
-				in:  EditInput{TaskId: 42, UserId: 1},
-			},
-			want:        false,
-			wantErr:     true,
+			name:        "partial update keeps omitted fields",
+			in:          EditInput{UserId: 5, TaskId: 1, Title: ptr("new title")},
+			wantVersion: 4,
+			wantSaves:   1,
+		},
+		{
+			name:        "full update with matching version",
+			in:          EditInput{UserId: 5, TaskId: 1, Status: ptr("in_progress"), Title: ptr("new"), Description: ptr("desc"), AssigneeIds: ptr([]int{8}), IfMatch: 3},
+			wantVersion: 4,
+			wantSaves:   1,
+		},
+		{
+			name:        "nothing changed",
+			in:          EditInput{UserId: 5, TaskId: 1, Title: ptr("old title")},
+			wantVersion: 3,
+			wantSaves:   0,
+		},
+		{
+			name:        "priority change is saved",
+			in:          EditInput{UserId: 5, TaskId: 1, Priority: ptr(task.HighPriority)},
+			wantVersion: 4,
+			wantSaves:   1,
+		},
+		{
+			name:        "due date is set",
+			in:          EditInput{UserId: 5, TaskId: 1, DueAt: task.Some(ptr(dueAt))},
+			wantVersion: 4,
+			wantSaves:   1,
+		},
+		{
+			name:        "same due date is not a change",
+			taskGetter:  &stubTaskGetterSuccess{task: &datedTask},
+			in:          EditInput{UserId: 5, TaskId: 1, DueAt: task.Some(ptr(dueAt.In(time.Local)))},
+			wantVersion: 3,
+			wantSaves:   0,
+		},
+		{
+			name:        "due date is cleared",
+			taskGetter:  &stubTaskGetterSuccess{task: &datedTask},
+			in:          EditInput{UserId: 5, TaskId: 1, DueAt: task.Some[*time.Time](nil)},
+			wantVersion: 4,
+			wantSaves:   1,
+		},
+		{
+			name:          "assignees are replaced",
+			in:            EditInput{UserId: 5, TaskId: 1, AssigneeIds: ptr([]int{8, 7, 8})},
+			wantVersion:   4,
+			wantSaves:     1,
+			wantAssignees: []int{7, 8},
+		},
+		{
+			name:          "all assignees are removed",
+			in:            EditInput{UserId: 5, TaskId: 1, AssigneeIds: ptr([]int{})},
+			wantVersion:   4,
+			wantSaves:     1,
+			wantAssignees: []int{},
+		},
+		{
+			name:        "same assignees are not a change",
+			in:          EditInput{UserId: 5, TaskId: 1, AssigneeIds: ptr([]int{7, 7})},
+			wantVersion: 3,
+			wantSaves:   0,
+		},
+		{
+			name:        "invalid assignee id",
+			in:          EditInput{UserId: 5, TaskId: 1, AssigneeIds: ptr([]int{0})},
+			expectedErr: "validation failed",
+		},
+		{
+			name:        "task not found",
+			taskGetter:  &stubTaskGetterNotFound{},
+			in:          EditInput{UserId: 1, TaskId: 42, Title: ptr("x")},
 			expectedErr: "task not found",
 		},
 		{
-			name: "get task db error",
-			fields: fields{
-				taskEditor:            &stubTaskEditorSuccess{},
-				taskGetter:            &stubTaskGetterError{},
-				historySaver:          &stubHistorySaverSuccess{},
-				taskMembershipChecker: &stubTaskMembershipCheckerAllowed{},
-				cacheUpdater:          &mockCacheUpdater{},
-			},
-			args: args{
-				ctx: context.Background(),
-				in:  EditInput{TaskId: 13, UserId: 1},
-			},
-			want:        false,
-			wantErr:     true,
+			name:        "get task db error",
+			taskGetter:  &stubTaskGetterError{},
+			in:          EditInput{UserId: 1, TaskId: 13, Title: ptr("x")},
 			expectedErr: "db error",
 		},
 		{
-			name: "membership forbidden",
-			fields: fields{
-				taskEditor:            &stubTaskEditorSuccess{},
-				taskGetter:            &stubTaskGetterSuccess{task: baseTask},
-				historySaver:          &stubHistorySaverSuccess{},
-				taskMembershipChecker: &stubTaskMembershipCheckerForbidden{},
-				cacheUpdater:          &mockCacheUpdater{},
-			},
-			args: args{
-				ctx: context.Background(),
-				in:  EditInput{TaskId: 1, UserId: 11},
-			},
-			want:        false,
-			wantErr:     true,
+			name:        "membership forbidden",
+			authorizer:  &stubAuthorizerForbidden{},
+			in:          EditInput{UserId: 11, TaskId: 1, Title: ptr("x")},
 			expectedErr: "forbidden",
 		},
 		{
-			name: "membership checker error",
-			fields: fields{
-				taskEditor:            &stubTaskEditorSuccess{},
-				taskGetter:            &stubTaskGetterSuccess{task: baseTask},
-				historySaver:          &stubHistorySaverSuccess{},
-				taskMembershipChecker: &stubTaskMembershipCheckerError{},
-				cacheUpdater:          &mockCacheUpdater{},
-			},
-			args: args{
-				ctx: context.Background(),
-				in:  EditInput{TaskId: 1, UserId: 99},
-			},
-			want:        false,
-			wantErr:     true,
-			expectedErr: "failed to check team membership: membership error",
-		},
-		{
-			name: "history saver error",
-			fields: fields{
-				taskEditor:            &stubTaskEditorSuccess{},
-				taskGetter:            &stubTaskGetterSuccess{task: baseTask},
-				historySaver:          &stubHistorySaverError{},
-				taskMembershipChecker: &stubTaskMembershipCheckerAllowed{},
-				cacheUpdater:          &mockCacheUpdater{},
-			},
-			args: args{
-				ctx: context.Background(),
-				in:  EditInput{TaskId: 1, UserId: 5},
-			},
-			want:        false,
-			wantErr:     true,
-			expectedErr: "failed to save task history: history error",
-		},
-		{
-			name: "edit task error",
-			fields: fields{
-				taskEditor:            &stubTaskEditorError{},
-				taskGetter:            &stubTaskGetterSuccess{task: baseTask},
-				historySaver:          &stubHistorySaverSuccess{},
-				taskMembershipChecker: &stubTaskMembershipCheckerAllowed{},
-				cacheUpdater:          &mockCacheUpdater{},
-			},
-			args: args{
-				ctx: context.Background(),
-				in:  EditInput{TaskId: 1, UserId: 5},
-			},
-			want:        false,
-			wantErr:     true,
+			name:        "membership checker error",
+			authorizer:  &stubAuthorizerError{},
+			in:          EditInput{UserId: 99, TaskId: 1, Title: ptr("x")},
+			expectedErr: "membership error",
+		},
+		{
+			name:        "archived task is read-only",
+			taskGetter:  &stubTaskGetterSuccess{task: archivedTask},
+			in:          EditInput{UserId: 5, TaskId: 1, Title: ptr("x")},
+			expectedErr: "task is archived",
+		},
+		{
+			name:        "stale If-Match",
+			in:          EditInput{UserId: 5, TaskId: 1, Title: ptr("x"), IfMatch: 2},
+			expectedErr: "task has been modified",
+		},
+		{
+			name:        "transition not allowed by team workflow",
+			workflow:    qaWorkflow,
+			in:          EditInput{UserId: 5, TaskId: 1, Status: ptr("done")},
+			expectedErr: "transition from todo to done is not allowed",
+		},
+		{
+			name:        "open blocker prevents finishing",
+			blockers:    []*task.Model{{Id: 2, Status: "done"}, {Id: 3, Status: "in_progress"}},
+			in:          EditInput{UserId: 5, TaskId: 1, Status: ptr("done")},
+			expectedErr: "task has open blockers",
+		},
+		{
+			name:        "finished blockers allow finishing",
+			blockers:    []*task.Model{{Id: 2, Status: "done"}},
+			in:          EditInput{UserId: 5, TaskId: 1, Status: ptr("done")},
+			wantVersion: 4,
+			wantSaves:   1,
+		},
+		{
+			name:        "open blocker does not prevent other transitions",
+			blockers:    []*task.Model{{Id: 3, Status: "todo"}},
+			in:          EditInput{UserId: 5, TaskId: 1, Status: ptr("in_progress")},
+			wantVersion: 4,
+			wantSaves:   1,
+		},
+		{
+			name:        "unknown status",
+			in:          EditInput{UserId: 5, TaskId: 1, Status: ptr("blocked")},
+			expectedErr: "validation failed",
+		},
+		{
+			name:         "new assignee is not a team member",
+			memberGetter: &stubMemberGetterNotFound{},
+			in:           EditInput{UserId: 5, TaskId: 1, AssigneeIds: ptr([]int{7, 99})},
+			expectedErr:  "validation failed",
+		},
+		{
+			name:         "unchanged assignee is not rechecked",
+			memberGetter: &stubMemberGetterNotFound{},
+			in:           EditInput{UserId: 5, TaskId: 1, Title: ptr("new"), AssigneeIds: ptr([]int{7})},
+			wantVersion:  4,
+			wantSaves:    1,
+		},
+		{
+			name:        "update error",
+			updater:     &mockTaskUpdater{err: errors.New("edit task error")},
+			in:          EditInput{UserId: 5, TaskId: 1, Title: ptr("x")},
 			expectedErr: "edit task error",
 		},
+		{
+			name:        "concurrent edit without If-Match is retried",
+			updater:     &mockTaskUpdater{mismatches: 1},
+			in:          EditInput{UserId: 5, TaskId: 1, Title: ptr("x")},
+			wantVersion: 4,
+			wantSaves:   1,
+		},
+		{
+			name:        "concurrent edit with If-Match is not retried",
+			updater:     &mockTaskUpdater{mismatches: 1},
+			in:          EditInput{UserId: 5, TaskId: 1, Title: ptr("x"), IfMatch: 3},
+			expectedErr: "task has been modified",
+		},
+		{
+			name:        "retries are limited",
+			updater:     &mockTaskUpdater{mismatches: maxAttempts},
+			in:          EditInput{UserId: 5, TaskId: 1, Title: ptr("x")},
+			expectedErr: "task has been modified",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			e := &executor{
-				taskEditor:            tt.fields.taskEditor,
-				taskGetter:            tt.fields.taskGetter,
-				historySaver:          tt.fields.historySaver,
-				taskMembershipChecker: tt.fields.taskMembershipChecker,
-				cacheUpdater:          tt.fields.cacheUpdater,
+			if tt.taskGetter == nil {
+				tt.taskGetter = &stubTaskGetterSuccess{task: baseTask}
+			}
+
+			if tt.authorizer == nil {
+				tt.authorizer = &stubAuthorizerAllowed{}
+			}
+
+			if tt.memberGetter == nil {
+				tt.memberGetter = &stubMemberGetterFound{}
+			}
+
+			if tt.updater == nil {
+				tt.updater = &mockTaskUpdater{}
+			}
+
+			cache := &mockCacheUpdater{}
+
+			e := NewExecutor(tt.updater, tt.taskGetter, tt.authorizer, tt.memberGetter, &stubWorkflowGetter{wf: tt.workflow}, &stubBlockerLister{blockers: tt.blockers}, cache, &stubWatcherLister{}, &mockNotificationCreator{})
+
+			got, err := e.Execute(context.Background(), tt.in)
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Nil(t, got)
+				assert.False(t, cache.called, "expected cacheUpdater not to be called")
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantVersion, got.Version)
+			assert.Equal(t, tt.wantSaves == 1, cache.called)
+
+			if tt.wantSaves == 0 {
+				assert.Nil(t, tt.updater.saved)
+
+				return
 			}
 
-			got, err := e.Execute(tt.args.ctx, tt.args.in)
+			// Непереданные поля берутся из текущей версии задачи.
+			assert.Equal(t, baseTask.CreatorId, tt.updater.saved.CreatorId)
 
-			if tt.wantErr {
-				assert.Error(t, err)
-				assert.False(t, got)
+			if tt.in.Description == nil {
+				assert.Equal(t, baseTask.Description, got.Description)
+			}
 
-				if tt.expectedErr != "" {
-					assert.EqualError(t, err, tt.expectedErr)
-				}
+			if tt.in.Title != nil {
+				assert.Equal(t, *tt.in.Title, got.Title)
+			}
 
-				assert.False(t, tt.fields.cacheUpdater.called, "expected cacheUpdater not to be called")
-			} else {
-				assert.NoError(t, err)
-				assert.True(t, got)
-				assert.True(t, tt.fields.cacheUpdater.called, "expected cacheUpdater to be called")
+			if tt.in.DueAt.Set {
+				assert.Equal(t, tt.in.DueAt.Value, got.DueAt)
 			}
+
+			if tt.in.Priority != nil {
+				assert.Equal(t, *tt.in.Priority, got.Priority)
+			}
+
+			if tt.wantAssignees != nil {
+				assert.Equal(t, tt.wantAssignees, got.AssigneeIds)
+			}
+		})
+	}
+}
+
+func TestExecutor_Execute_Notifications(t *testing.T) {
+	baseTask := &task.Model{Id: 1, Status: "todo", Title: "title", AssigneeIds: []int{7}, TeamId: 10, CreatorId: 100, Version: 3}
+
+	type sent struct {
+		UserId int
+		Kind   notification.Kind
+	}
+
+	tests := []struct {
+		name     string
+		in       EditInput
+		watchers *stubWatcherLister
+		creator  *mockNotificationCreator
+		want     []sent
+	}{
+		{
+			name:     "assignees are notified",
+			in:       EditInput{UserId: 5, TaskId: 1, AssigneeIds: &[]int{8, 5}},
+			watchers: &stubWatcherLister{watchers: []int{9}},
+			creator:  &mockNotificationCreator{},
+			want:     []sent{{UserId: 8, Kind: notification.TaskAssignedKind}, {UserId: 7, Kind: notification.TaskUnassignedKind}},
+		},
+		{
+			name:     "watchers are notified about status",
+			in:       EditInput{UserId: 5, TaskId: 1, Status: ptr("done")},
+			watchers: &stubWatcherLister{watchers: []int{5, 9}},
+			creator:  &mockNotificationCreator{},
+			want:     []sent{{UserId: 9, Kind: notification.TaskStatusChangedKind}},
+		},
+		{
+			name:     "other fields do not notify",
+			in:       EditInput{UserId: 5, TaskId: 1, Title: ptr("new title")},
+			watchers: &stubWatcherLister{watchers: []int{9}},
+			creator:  &mockNotificationCreator{},
+		},
+		{
+			name:     "watchers error keeps assignment notifications",
+			in:       EditInput{UserId: 5, TaskId: 1, Status: ptr("done"), AssigneeIds: &[]int{7, 8}},
+			watchers: &stubWatcherLister{err: errors.New("db error")},
+			creator:  &mockNotificationCreator{},
+			want:     []sent{{UserId: 8, Kind: notification.TaskAssignedKind}},
+		},
+		{
+			name:     "notification error does not fail the edit",
+			in:       EditInput{UserId: 5, TaskId: 1, AssigneeIds: &[]int{8}},
+			watchers: &stubWatcherLister{},
+			creator:  &mockNotificationCreator{err: errors.New("db error")},
+			want:     []sent{{UserId: 8, Kind: notification.TaskAssignedKind}, {UserId: 7, Kind: notification.TaskUnassignedKind}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(
+				&mockTaskUpdater{},
+				&stubTaskGetterSuccess{task: baseTask},
+				&stubAuthorizerAllowed{},
+				&stubMemberGetterFound{},
+				&stubWorkflowGetter{},
+				&stubBlockerLister{},
+				&mockCacheUpdater{},
+				tt.watchers,
+				tt.creator,
+			)
+
+			_, err := e.Execute(context.Background(), tt.in)
+			assert.NoError(t, err)
+
+			var got []sent
+
+			for _, n := range tt.creator.notifications {
+				got = append(got, sent{UserId: n.UserId, Kind: n.Kind})
+				assert.Equal(t, 5, n.ActorId)
+				assert.Equal(t, 1, *n.TaskId)
+			}
+
+			assert.Equal(t, tt.want, got)
 		})
 	}
 }
@@ -6,15 +6,24 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/validation"
 )
 
+// PUT заменяет задачу целиком, поэтому все поля обязательны.
+// Исключение — исполнители, срок и приоритет: без них задача остаётся неназначенной, без срока и с обычным приоритетом.
 type request struct {
-	Status      string `json:"status"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	AssigneeId  int    `json:"assignee_id"`
+	Status      string     `json:"status" validate:"required,max=64"`
+	Title       string     `json:"title" validate:"required,max=255"`
+	Description string     `json:"description" validate:"max=10000"`
+	AssigneeIds []int      `json:"assignee_ids"`
+	DueAt       *time.Time `json:"due_at"`
+	Priority    string     `json:"priority" validate:"oneof=low normal high urgent"`
 }
 
 type response struct {
@@ -22,7 +31,7 @@ type response struct {
 }
 
 type Executor interface {
-	Execute(ctx context.Context, in EditInput) (bool, error)
+	Execute(ctx context.Context, in EditInput) (*EditResult, error)
 }
 
 type handler struct {
@@ -41,7 +50,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	userId, ok := userIdAny.(int)
 
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
 
 		return
 	}
@@ -51,7 +60,15 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	taskId, err := strconv.Atoi(idStr)
 
 	if err != nil {
-		http.Error(w, "invalid task id", http.StatusBadRequest)
+		apperror.Write(w, apperror.Validation("invalid task id"))
+
+		return
+	}
+
+	ifMatch, err := task.ParseIfMatch(r.Header.Get("If-Match"))
+
+	if err != nil {
+		apperror.Write(w, err)
 
 		return
 	}
@@ -60,7 +77,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
 	if err != nil {
-		http.Error(w, "error", http.StatusInternalServerError)
+		apperror.Write(w, err)
 
 		return
 	}
@@ -68,46 +85,50 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	var req request
 
 	if err := json.Unmarshal(body, &req); err != nil {
-		http.Error(w, "error", http.StatusBadRequest)
+		apperror.Write(w, apperror.Validation("invalid json"))
 
 		return
 	}
 
-	ok, err = h.exec.Execute(r.Context(), EditInput{
-		UserId:      userId,
-		TaskId:      taskId,
-		Status:      req.Status,
-		Title:       req.Title,
-		Description: req.Description,
-		AssigneeId:  req.AssigneeId,
-	})
+	if err := validation.Struct(req); err != nil {
+		apperror.Write(w, err)
 
-	if err != nil || !ok {
-		resp := response{Success: false}
+		return
+	}
 
-		respBody, marshalErr := json.Marshal(resp)
+	priority := task.NormalPriority
 
-		if marshalErr != nil {
-			http.Error(w, "error", http.StatusInternalServerError)
+	if req.Priority != "" {
+		priority, _ = task.ParsePriority(req.Priority)
+	}
 
-			return
-		}
+	result, err := h.exec.Execute(r.Context(), EditInput{
+		UserId:      userId,
+		TaskId:      taskId,
+		Status:      &req.Status,
+		Title:       &req.Title,
+		Description: &req.Description,
+		AssigneeIds: &req.AssigneeIds,
+		DueAt:       task.Some(req.DueAt),
+		Priority:    &priority,
+		IfMatch:     ifMatch,
+	})
 
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write(respBody)
+	if err != nil {
+		apperror.Write(w, err)
 
 		return
 	}
 
-	resp := response{Success: true}
-	respBody, err := json.Marshal(resp)
+	respBody, err := json.Marshal(response{Success: true})
 
 	if err != nil {
-		http.Error(w, "json error", http.StatusInternalServerError)
+		apperror.Write(w, err)
 
 		return
 	}
 
+	w.Header().Set("ETag", task.ETag(result.Version))
 	w.WriteHeader(http.StatusOK)
 	w.Write(respBody)
 }
@@ -0,0 +1,159 @@
+package edit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/validation"
+)
+
+// PATCH меняет только переданные поля; отсутствующее в теле поле остаётся прежним.
+type patchRequest struct {
+	Status      *string                   `json:"status" validate:"required,max=64"`
+	Title       *string                   `json:"title" validate:"required,max=255"`
+	Description *string                   `json:"description" validate:"max=10000"`
+	AssigneeIds *[]int                    `json:"assignee_ids"`
+	DueAt       task.Optional[*time.Time] `json:"due_at"`
+	Priority    *string                   `json:"priority" validate:"oneof=low normal high urgent"`
+}
+
+type patchResponse struct {
+	Id          int           `json:"id"`
+	Status      string        `json:"status"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	CreatorId   int           `json:"creator_id"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	AssigneeIds []int         `json:"assignee_ids"`
+	TeamId      int           `json:"team_id"`
+	Version     int           `json:"version"`
+	DueAt       *time.Time    `json:"due_at"`
+	Priority    task.Priority `json:"priority"`
+}
+
+type patchHandler struct {
+	exec Executor
+}
+
+func NewPatchHandler(exec Executor) *patchHandler {
+	return &patchHandler{
+		exec: exec,
+	}
+}
+
+func (h *patchHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	userIdAny := r.Context().Value("userId")
+
+	userId, ok := userIdAny.(int)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
+	taskId, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("invalid task id"))
+
+		return
+	}
+
+	ifMatch, err := task.ParseIfMatch(r.Header.Get("If-Match"))
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	defer r.Body.Close()
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	var req patchRequest
+
+	if err := json.Unmarshal(body, &req); err != nil {
+		apperror.Write(w, apperror.Validation("invalid json"))
+
+		return
+	}
+
+	if err := validation.Struct(req); err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	var priority *task.Priority
+
+	if req.Priority != nil {
+		p, err := task.ParsePriority(*req.Priority)
+
+		if err != nil {
+			apperror.Write(w, err)
+
+			return
+		}
+
+		priority = &p
+	}
+
+	result, err := h.exec.Execute(r.Context(), EditInput{
+		UserId:      userId,
+		TaskId:      taskId,
+		Status:      req.Status,
+		Title:       req.Title,
+		Description: req.Description,
+		AssigneeIds: req.AssigneeIds,
+		DueAt:       req.DueAt,
+		Priority:    priority,
+		IfMatch:     ifMatch,
+	})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	respBody, err := json.Marshal(patchResponse{
+		Id:          result.Id,
+		Status:      result.Status,
+		Title:       result.Title,
+		Description: result.Description,
+		CreatorId:   result.CreatorId,
+		CreatedAt:   result.CreatedAt,
+		UpdatedAt:   result.UpdatedAt,
+		AssigneeIds: result.AssigneeIds,
+		TeamId:      result.TeamId,
+		Version:     result.Version,
+		DueAt:       result.DueAt,
+		Priority:    result.Priority,
+	})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", task.ETag(result.Version))
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
@@ -0,0 +1,33 @@
+package task
+
+import (
+	"strconv"
+	"strings"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+// ETag — версия задачи в формате заголовка ETag.
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// ParseIfMatch достаёт ожидаемую версию из заголовка If-Match.
+// Пустой заголовок и "*" означают, что клиент версию не проверяет, — тогда возвращается 0.
+func ParseIfMatch(header string) (int, error) {
+	header = strings.TrimSpace(header)
+
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	tag := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+
+	version, err := strconv.Atoi(tag)
+
+	if err != nil || version <= 0 {
+		return 0, apperror.Validation("invalid If-Match header")
+	}
+
+	return version, nil
+}
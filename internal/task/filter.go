@@ -0,0 +1,191 @@
+package task
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+type SortField string
+
+const (
+	SortById        SortField = "id"
+	SortByCreatedAt SortField = "created_at"
+	SortByUpdatedAt SortField = "updated_at"
+	SortByPriority  SortField = "priority"
+	SortByDueAt     SortField = "due_at"
+)
+
+// NoDueAt — ключ сортировки для задач без срока: при сортировке по сроку они идут после всех остальных.
+var NoDueAt = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+func (f SortField) Valid() bool {
+	switch f {
+	case SortById, SortByCreatedAt, SortByUpdatedAt, SortByPriority, SortByDueAt:
+		return true
+	}
+
+	return false
+}
+
+// Relation — как задача связана с пользователем в выборке «мои задачи».
+type Relation string
+
+const (
+	AnyRelation      Relation = ""
+	AssigneeRelation Relation = "assignee"
+	CreatorRelation  Relation = "creator"
+)
+
+// Filter — условия выборки задач команды. Пустые поля не ограничивают выборку.
+// Диапазоны дат полуоткрытые: From включительно, To — нет.
+// Labels — имена меток: задача проходит, если на ней есть хотя бы одна из них.
+// OverdueAt оставляет задачи со сроком раньше этого момента, ещё не дошедшие до завершающего статуса.
+type Filter struct {
+	TeamId      int
+	Statuses    []string
+	AssigneeIds []int
+	CreatorIds  []int
+	Labels      []string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+	Query       string
+	OverdueAt   *time.Time
+	Sort        SortField
+	Desc        bool
+	After       *Cursor
+}
+
+// IsDefault — выборка всей доски в порядке id, такую можно отдавать из кэша.
+func (f Filter) IsDefault() bool {
+	return len(f.Statuses) == 0 &&
+		len(f.AssigneeIds) == 0 &&
+		len(f.CreatorIds) == 0 &&
+		len(f.Labels) == 0 &&
+		f.CreatedFrom == nil &&
+		f.CreatedTo == nil &&
+		f.UpdatedFrom == nil &&
+		f.UpdatedTo == nil &&
+		f.Query == "" &&
+		f.OverdueAt == nil &&
+		f.Sort == SortById &&
+		!f.Desc
+}
+
+// Validate проверяет согласованность фильтра: курсор должен быть выдан для того же порядка сортировки.
+func (f Filter) Validate() error {
+	var fields []apperror.FieldError
+
+	if !f.Sort.Valid() {
+		fields = append(fields, apperror.FieldError{Field: "sort", Message: "unknown sort field"})
+	}
+
+	if f.CreatedFrom != nil && f.CreatedTo != nil && !f.CreatedFrom.Before(*f.CreatedTo) {
+		fields = append(fields, apperror.FieldError{Field: "created_to", Message: "must be after created_from"})
+	}
+
+	if f.UpdatedFrom != nil && f.UpdatedTo != nil && !f.UpdatedFrom.Before(*f.UpdatedTo) {
+		fields = append(fields, apperror.FieldError{Field: "updated_to", Message: "must be after updated_from"})
+	}
+
+	if f.After != nil && (f.After.Sort != f.Sort || f.After.Desc != f.Desc) {
+		fields = append(fields, apperror.FieldError{Field: "cursor", Message: "cursor does not match sort order"})
+	}
+
+	if len(fields) > 0 {
+		return apperror.InvalidFields(fields...)
+	}
+
+	return nil
+}
+
+// Cursor — позиция последней выданной задачи: значение поля сортировки и id для равных значений.
+type Cursor struct {
+	Sort SortField `json:"s"`
+	Desc bool      `json:"d,omitempty"`
+	Key  string    `json:"k,omitempty"`
+	Id   int       `json:"i"`
+}
+
+// NewCursor строит курсор, указывающий на задачу t в заданном порядке.
+func NewCursor(t Model, sort SortField, desc bool) Cursor {
+	c := Cursor{Sort: sort, Desc: desc, Id: t.Id}
+
+	switch sort {
+	case SortByCreatedAt:
+		c.Key = t.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case SortByUpdatedAt:
+		c.Key = t.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	case SortByPriority:
+		c.Key = strconv.Itoa(int(t.Priority))
+	case SortByDueAt:
+		due := NoDueAt
+
+		if t.DueAt != nil {
+			due = *t.DueAt
+		}
+
+		c.Key = due.UTC().Format(time.RFC3339Nano)
+	}
+
+	return c
+}
+
+// KeyTime — значение поля сортировки для сортировок по времени и сроку.
+func (c Cursor) KeyTime() (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, c.Key)
+}
+
+// KeyPriority — значение поля сортировки для сортировки по приоритету.
+func (c Cursor) KeyPriority() (Priority, error) {
+	p, err := strconv.Atoi(c.Key)
+
+	return Priority(p), err
+}
+
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+var errInvalidCursor = apperror.InvalidField("cursor", "invalid cursor")
+
+// DecodeCursor разбирает курсор из ответа предыдущей страницы.
+func DecodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	var c Cursor
+
+	if err := json.Unmarshal(b, &c); err != nil || !c.Sort.Valid() || c.Id <= 0 {
+		return nil, errInvalidCursor
+	}
+
+	switch c.Sort {
+	case SortById:
+	case SortByPriority:
+		if _, err := c.KeyPriority(); err != nil {
+			return nil, errInvalidCursor
+		}
+	default:
+		if _, err := c.KeyTime(); err != nil {
+			return nil, errInvalidCursor
+		}
+	}
+
+	return &c, nil
+}
+
+// IdCursor — курсор старого вида start_from_id для сортировки по id.
+func IdCursor(id int) *Cursor {
+	return &Cursor{Sort: SortById, Id: id}
+}
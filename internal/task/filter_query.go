@@ -0,0 +1,175 @@
+package task
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+const (
+	maxFilterValues = 50
+	maxQueryLength  = 200
+)
+
+var (
+	errTooManyValues = errors.New("too many values")
+	errInvalidId     = errors.New("must be a comma-separated list of ids")
+)
+
+// ParseFilterQuery собирает фильтр из query-параметров списка задач; ошибки копятся по всем полям сразу.
+// TeamId не заполняется: откуда он берётся, решает вызывающий.
+func ParseFilterQuery(query url.Values) (Filter, error) {
+	var (
+		f      Filter
+		fields []apperror.FieldError
+		err    error
+	)
+
+	fail := func(field, message string) {
+		fields = append(fields, apperror.FieldError{Field: field, Message: message})
+	}
+
+	f.Statuses = splitValues(query["status"])
+
+	if len(f.Statuses) > maxFilterValues {
+		fail("status", "too many values")
+	}
+
+	f.Labels = splitValues(query["label"])
+
+	if len(f.Labels) > maxFilterValues {
+		fail("label", "too many values")
+	}
+
+	if f.AssigneeIds, err = parseIds(query["assignee_id"]); err != nil {
+		fail("assignee_id", err.Error())
+	}
+
+	if f.CreatorIds, err = parseIds(query["creator_id"]); err != nil {
+		fail("creator_id", err.Error())
+	}
+
+	for _, p := range []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"created_from", &f.CreatedFrom},
+		{"created_to", &f.CreatedTo},
+		{"updated_from", &f.UpdatedFrom},
+		{"updated_to", &f.UpdatedTo},
+	} {
+		if *p.dst, err = parseTime(query.Get(p.name)); err != nil {
+			fail(p.name, "must be a date (2006-01-02) or RFC 3339 time")
+		}
+	}
+
+	f.Query = strings.TrimSpace(query.Get("q"))
+
+	if utf8.RuneCountInString(f.Query) > maxQueryLength {
+		fail("q", "must be at most "+strconv.Itoa(maxQueryLength)+" characters")
+	}
+
+	switch query.Get("overdue") {
+	case "", "false":
+	case "true":
+		now := time.Now().UTC()
+
+		f.OverdueAt = &now
+	default:
+		fail("overdue", "must be one of: true false")
+	}
+
+	f.Sort = SortById
+
+	if s := query.Get("sort"); s != "" {
+		f.Sort = SortField(s)
+	}
+
+	switch query.Get("order") {
+	case "", "asc":
+	case "desc":
+		f.Desc = true
+	default:
+		fail("order", "must be one of: asc desc")
+	}
+
+	if s := query.Get("cursor"); s != "" {
+		if f.After, err = DecodeCursor(s); err != nil {
+			return Filter{}, err
+		}
+	} else if s := query.Get("start_from_id"); s != "" {
+		startFromId, err := strconv.Atoi(s)
+
+		if err != nil {
+			return Filter{}, apperror.Validation("invalid start_from_id")
+		}
+
+		f.After = IdCursor(startFromId)
+	}
+
+	if len(fields) > 0 {
+		return Filter{}, apperror.InvalidFields(fields...)
+	}
+
+	return f, nil
+}
+
+// splitValues принимает как повторяющиеся параметры, так и значения через запятую.
+func splitValues(values []string) []string {
+	var result []string
+
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+
+	return result
+}
+
+func parseIds(values []string) ([]int, error) {
+	parts := splitValues(values)
+
+	if len(parts) > maxFilterValues {
+		return nil, errTooManyValues
+	}
+
+	var ids []int
+
+	for _, p := range parts {
+		id, err := strconv.Atoi(p)
+
+		if err != nil || id <= 0 {
+			return nil, errInvalidId
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func parseTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
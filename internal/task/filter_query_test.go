@@ -0,0 +1,105 @@
+package task
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilterQuery(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		query      string
+		want       Filter
+		wantFields []string
+	}{
+		{
+			name:  "empty query",
+			query: "",
+			want:  Filter{Sort: SortById},
+		},
+		{
+			name:  "multi-value filters",
+			query: "status=todo,in_progress&status=qa&assignee_id=1,2&creator_id=3&label=bug,срочно",
+			want: Filter{
+				Statuses:    []string{"todo", "in_progress", "qa"},
+				AssigneeIds: []int{1, 2},
+				CreatorIds:  []int{3},
+				Labels:      []string{"bug", "срочно"},
+				Sort:        SortById,
+			},
+		},
+		{
+			name:  "dates, search and sorting",
+			query: "created_from=2024-03-01&updated_to=2024-03-01T00:00:00Z&q=+отчёт+&sort=updated_at&order=desc",
+			want: Filter{
+				CreatedFrom: &day,
+				UpdatedTo:   &day,
+				Query:       "отчёт",
+				Sort:        SortByUpdatedAt,
+				Desc:        true,
+			},
+		},
+		{
+			name:  "legacy start_from_id",
+			query: "start_from_id=42",
+			want:  Filter{Sort: SortById, After: IdCursor(42)},
+		},
+		{
+			name:       "all invalid fields are reported",
+			query:      "assignee_id=x&creator_id=0&created_to=yesterday&overdue=maybe&order=up",
+			wantFields: []string{"assignee_id", "creator_id", "created_to", "overdue", "order"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+
+			assert.NoError(t, err)
+
+			got, err := ParseFilterQuery(values)
+
+			if tt.wantFields != nil {
+				var appErr *apperror.Error
+
+				assert.ErrorAs(t, err, &appErr)
+
+				fields := make([]string, 0, len(appErr.Fields))
+
+				for _, f := range appErr.Fields {
+					fields = append(fields, f.Field)
+				}
+
+				assert.Equal(t, tt.wantFields, fields)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseFilterQuery_Overdue(t *testing.T) {
+	before := time.Now()
+
+	f, err := ParseFilterQuery(url.Values{"overdue": {"true"}, "sort": {"due_at"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, SortByDueAt, f.Sort)
+	assert.NotNil(t, f.OverdueAt)
+	assert.False(t, f.OverdueAt.Before(before.Truncate(time.Second)))
+
+	f, err = ParseFilterQuery(url.Values{"overdue": {"false"}})
+
+	assert.NoError(t, err)
+	assert.Nil(t, f.OverdueAt)
+}
@@ -0,0 +1,93 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor_EncodeDecode(t *testing.T) {
+	m := Model{Id: 42, CreatedAt: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), Priority: HighPriority}
+
+	for _, sort := range []SortField{SortById, SortByCreatedAt, SortByUpdatedAt, SortByPriority, SortByDueAt} {
+		c := NewCursor(m, sort, true)
+
+		got, err := DecodeCursor(c.Encode())
+
+		assert.NoError(t, err)
+		assert.Equal(t, &c, got)
+	}
+
+	created, err := NewCursor(m, SortByCreatedAt, false).KeyTime()
+
+	assert.NoError(t, err)
+	assert.True(t, created.Equal(m.CreatedAt))
+
+	priority, err := NewCursor(m, SortByPriority, false).KeyPriority()
+
+	assert.NoError(t, err)
+	assert.Equal(t, HighPriority, priority)
+
+	// Задачи без срока при сортировке по сроку стоят последними.
+	due, err := NewCursor(m, SortByDueAt, false).KeyTime()
+
+	assert.NoError(t, err)
+	assert.True(t, due.Equal(NoDueAt))
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	for _, raw := range []string{
+		"not base64!",
+		"e30", // {}
+		Cursor{Sort: "title", Id: 1}.Encode(),
+		Cursor{Sort: SortByPriority, Key: "high", Id: 1}.Encode(),
+		Cursor{Sort: SortByCreatedAt, Key: "yesterday", Id: 1}.Encode(),
+		Cursor{Sort: SortById}.Encode(),
+	} {
+		_, err := DecodeCursor(raw)
+
+		assert.EqualError(t, err, "validation failed", raw)
+	}
+}
+
+func TestFilter_Validate(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	nextDay := day.AddDate(0, 0, 1)
+
+	tests := []struct {
+		name    string
+		filter  Filter
+		wantErr bool
+	}{
+		{name: "default", filter: Filter{Sort: SortById}},
+		{name: "date range", filter: Filter{Sort: SortById, CreatedFrom: &day, CreatedTo: &nextDay}},
+		{name: "matching cursor", filter: Filter{Sort: SortByUpdatedAt, Desc: true, After: &Cursor{Sort: SortByUpdatedAt, Desc: true, Id: 1}}},
+		{name: "unknown sort", filter: Filter{Sort: "title"}, wantErr: true},
+		{name: "empty range", filter: Filter{Sort: SortById, UpdatedFrom: &nextDay, UpdatedTo: &day}, wantErr: true},
+		{name: "cursor for another order", filter: Filter{Sort: SortById, Desc: true, After: IdCursor(3)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+
+			if tt.wantErr {
+				assert.EqualError(t, err, "validation failed")
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestFilter_IsDefault(t *testing.T) {
+	assert.True(t, Filter{TeamId: 1, Sort: SortById, After: IdCursor(10)}.IsDefault())
+	assert.False(t, Filter{TeamId: 1, Sort: SortById, Desc: true}.IsDefault())
+	assert.False(t, Filter{TeamId: 1, Sort: SortById, Query: "отчёт"}.IsDefault())
+	assert.False(t, Filter{TeamId: 1, Sort: SortById, Labels: []string{"bug"}}.IsDefault())
+	assert.False(t, Filter{TeamId: 1, Sort: SortByCreatedAt}.IsDefault())
+	assert.False(t, Filter{TeamId: 1, Sort: SortById, OverdueAt: &time.Time{}}.IsDefault())
+}
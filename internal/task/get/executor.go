@@ -0,0 +1,499 @@
+package get
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/attachment"
+	"mkk-luna-test-task/internal/task/comment"
+	"mkk-luna-test-task/internal/task/history"
+	"mkk-luna-test-task/internal/team/label"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+	"mkk-luna-test-task/internal/team/workflow"
+)
+
+const (
+	historyLimit  = 5
+	commentsLimit = 20
+	subtasksLimit = 500
+)
+
+type taskGetter interface {
+	GetTaskById(ctx context.Context, id int) (*task.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type usernameGetter interface {
+	GetUsernames(ctx context.Context, ids []int) (map[int]string, error)
+}
+
+type commentCounter interface {
+	CountTaskComments(ctx context.Context, taskId int) (int, error)
+}
+
+type historyLister interface {
+	ListLatestHistory(ctx context.Context, taskId int, limit int) ([]*history.Model, error)
+}
+
+type commentLister interface {
+	ListTaskComments(ctx context.Context, taskId int, startFromId int, limit int) ([]comment.Model, error)
+}
+
+type labelLister interface {
+	ListTaskLabels(ctx context.Context, taskId int) ([]label.Model, error)
+}
+
+type linkLister interface {
+	GetTaskParent(ctx context.Context, taskId int) (*task.Model, error)
+	ListSubtasks(ctx context.Context, taskId int, limit int) ([]task.Subtask, error)
+	ListTaskBlockers(ctx context.Context, taskId int) ([]*task.Model, error)
+}
+
+type watcherLister interface {
+	ListTaskWatchers(ctx context.Context, taskId int) ([]int, error)
+}
+
+type attachmentLister interface {
+	ListTaskAttachments(ctx context.Context, taskId int) ([]attachment.Model, error)
+}
+
+type workflowGetter interface {
+	GetWorkflow(ctx context.Context, teamId int) (*workflow.Model, error)
+}
+
+type htmlRenderer interface {
+	HTML(ctx context.Context, userId int, sources ...string) ([]string, error)
+}
+
+type executor struct {
+	taskGetter       taskGetter
+	authorizer       authorizer
+	usernameGetter   usernameGetter
+	commentCounter   commentCounter
+	historyLister    historyLister
+	commentLister    commentLister
+	labelLister      labelLister
+	linkLister       linkLister
+	watcherLister    watcherLister
+	attachmentLister attachmentLister
+	workflowGetter   workflowGetter
+	renderer         htmlRenderer
+}
+
+func NewExecutor(
+	taskGetter taskGetter,
+	authorizer authorizer,
+	usernameGetter usernameGetter,
+	commentCounter commentCounter,
+	historyLister historyLister,
+	commentLister commentLister,
+	labelLister labelLister,
+	linkLister linkLister,
+	watcherLister watcherLister,
+	attachmentLister attachmentLister,
+	workflowGetter workflowGetter,
+	renderer htmlRenderer,
+) *executor {
+	return &executor{
+		taskGetter:       taskGetter,
+		authorizer:       authorizer,
+		usernameGetter:   usernameGetter,
+		commentCounter:   commentCounter,
+		historyLister:    historyLister,
+		commentLister:    commentLister,
+		labelLister:      labelLister,
+		linkLister:       linkLister,
+		watcherLister:    watcherLister,
+		attachmentLister: attachmentLister,
+		workflowGetter:   workflowGetter,
+		renderer:         renderer,
+	}
+}
+
+type GetInput struct {
+	UserId  int
+	TaskId  int
+	Include Include
+
+	// RenderHTML — вернуть рядом с описанием и комментариями их HTML.
+	RenderHTML bool
+}
+
+type UserRef struct {
+	Id       int    `json:"id"`
+	Username string `json:"username"`
+}
+
+type CommentsPage struct {
+	Comments        []comment.View `json:"comments"`
+	NextStartFromId int            `json:"next_start_from_id,omitempty"`
+	HasMore         bool           `json:"has_more"`
+}
+
+// TaskRef — ссылка на связанную задачу. Если читателю её смотреть нельзя, остаётся только Id и Hidden.
+type TaskRef struct {
+	Id     int    `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	Hidden bool   `json:"hidden,omitempty"`
+}
+
+// SubtaskNode — узел дерева подзадач. У недоступной читателю подзадачи остаются только Id, Hidden
+// и её собственные подзадачи: среди них могут быть доступные.
+type SubtaskNode struct {
+	Id          int            `json:"id"`
+	Title       string         `json:"title"`
+	Status      string         `json:"status"`
+	AssigneeIds []int          `json:"assignee_ids"`
+	Priority    task.Priority  `json:"priority"`
+	DueAt       *time.Time     `json:"due_at"`
+	Hidden      bool           `json:"hidden,omitempty"`
+	Subtasks    []*SubtaskNode `json:"subtasks"`
+}
+
+// BlockerItem — задача, которая блокирует текущую; пока Open, текущую нельзя завершить.
+// У недоступного читателю блокера остаются только Id, Open и Hidden.
+type BlockerItem struct {
+	Id     int    `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	Open   bool   `json:"open"`
+	Hidden bool   `json:"hidden,omitempty"`
+}
+
+type GetResult struct {
+	Task task.Model
+
+	// DescriptionHTML заполняется только при RenderHTML.
+	DescriptionHTML *string
+
+	// Встроенные части заполняются только если запрошены в Include.
+	Creator       *UserRef
+	Assignees     []UserRef
+	CommentsCount *int
+	History       []*history.Model
+	Comments      *CommentsPage
+	Labels        []label.Model
+	Parent        *TaskRef
+	Subtasks      []*SubtaskNode
+	Blockers      []BlockerItem
+	Watchers      []UserRef
+	Attachments   []attachment.Model
+}
+
+func (e *executor) Execute(ctx context.Context, in GetInput) (*GetResult, error) {
+	t, err := e.taskGetter.GetTaskById(ctx, in.TaskId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("task not found")
+		}
+
+		return nil, err
+	}
+
+	_, err = e.authorizer.Authorize(ctx, in.UserId, permission.TaskView, permission.TaskResource(*t))
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetResult{Task: *t}
+
+	if in.Include.Users {
+		usernames, err := e.usernameGetter.GetUsernames(ctx, append([]int{t.CreatorId}, t.AssigneeIds...))
+
+		if err != nil {
+			return nil, err
+		}
+
+		result.Creator = &UserRef{Id: t.CreatorId, Username: usernames[t.CreatorId]}
+		result.Assignees = userRefs(t.AssigneeIds, usernames)
+	}
+
+	if in.Include.CommentsCount {
+		count, err := e.commentCounter.CountTaskComments(ctx, t.Id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result.CommentsCount = &count
+	}
+
+	if in.Include.History {
+		histories, err := e.historyLister.ListLatestHistory(ctx, t.Id, historyLimit)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// nil означает «не запрашивали», поэтому пустая история — пустой срез.
+		result.History = append([]*history.Model{}, histories...)
+	}
+
+	if in.Include.Comments {
+		page, err := e.firstCommentsPage(ctx, t.Id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result.Comments = page
+	}
+
+	if in.Include.Labels {
+		labels, err := e.labelLister.ListTaskLabels(ctx, t.Id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result.Labels = append([]label.Model{}, labels...)
+	}
+
+	if in.Include.Subtasks {
+		parent, subtasks, err := e.subtaskTree(ctx, in.UserId, t.Id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result.Parent = parent
+		result.Subtasks = subtasks
+	}
+
+	if in.Include.Blockers {
+		blockers, err := e.blockers(ctx, in.UserId, *t)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result.Blockers = blockers
+	}
+
+	if in.Include.Watchers {
+		watcherIds, err := e.watcherLister.ListTaskWatchers(ctx, t.Id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		usernames := map[int]string{}
+
+		if len(watcherIds) > 0 {
+			usernames, err = e.usernameGetter.GetUsernames(ctx, watcherIds)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		result.Watchers = userRefs(watcherIds, usernames)
+	}
+
+	if in.Include.Attachments {
+		attachments, err := e.attachmentLister.ListTaskAttachments(ctx, t.Id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result.Attachments = append([]attachment.Model{}, attachments...)
+	}
+
+	if in.RenderHTML {
+		if err := e.renderHTML(ctx, in.UserId, result); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// renderHTML рендерит описание и встроенные комментарии одним вызовом, чтобы ссылки на задачи проверялись один раз.
+func (e *executor) renderHTML(ctx context.Context, userId int, result *GetResult) error {
+	sources := []string{result.Task.Description}
+
+	if result.Comments != nil {
+		for _, c := range result.Comments.Comments {
+			sources = append(sources, c.Text)
+		}
+	}
+
+	rendered, err := e.renderer.HTML(ctx, userId, sources...)
+
+	if err != nil {
+		return err
+	}
+
+	result.DescriptionHTML = &rendered[0]
+
+	if result.Comments != nil {
+		for i := range result.Comments.Comments {
+			result.Comments.Comments[i].TextHTML = &rendered[i+1]
+		}
+	}
+
+	return nil
+}
+
+func userRefs(ids []int, usernames map[int]string) []UserRef {
+	refs := make([]UserRef, 0, len(ids))
+
+	for _, id := range ids {
+		refs = append(refs, UserRef{Id: id, Username: usernames[id]})
+	}
+
+	return refs
+}
+
+// canView проверяет, можно ли читателю смотреть связанную задачу.
+func (e *executor) canView(ctx context.Context, userId int, t task.Model) (bool, error) {
+	_, err := e.authorizer.Authorize(ctx, userId, permission.TaskView, permission.TaskResource(t))
+
+	if errors.Is(err, permission.ErrForbidden) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// subtaskTree возвращает родителя задачи (nil, если его нет) и дерево её подзадач.
+func (e *executor) subtaskTree(ctx context.Context, userId, taskId int) (*TaskRef, []*SubtaskNode, error) {
+	var parentRef *TaskRef
+
+	parent, err := e.linkLister.GetTaskParent(ctx, taskId)
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, err
+	}
+
+	if parent != nil {
+		visible, err := e.canView(ctx, userId, *parent)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		parentRef = &TaskRef{Id: parent.Id, Hidden: !visible}
+
+		if visible {
+			parentRef.Title = parent.Title
+			parentRef.Status = parent.Status
+		}
+	}
+
+	subtasks, err := e.linkLister.ListSubtasks(ctx, taskId, subtasksLimit)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Список идёт от ближних уровней к дальним, поэтому родитель узла всегда встречается раньше него.
+	nodes := map[int]*SubtaskNode{taskId: {Subtasks: []*SubtaskNode{}}}
+
+	for _, s := range subtasks {
+		parentNode, ok := nodes[s.ParentId]
+
+		if !ok {
+			continue
+		}
+
+		visible, err := e.canView(ctx, userId, s.Task)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		node := &SubtaskNode{Id: s.Task.Id, Hidden: !visible, Subtasks: []*SubtaskNode{}}
+
+		if visible {
+			node.Title = s.Task.Title
+			node.Status = s.Task.Status
+			node.AssigneeIds = s.Task.AssigneeIds
+			node.Priority = s.Task.Priority
+			node.DueAt = s.Task.DueAt
+		}
+
+		parentNode.Subtasks = append(parentNode.Subtasks, node)
+		nodes[node.Id] = node
+	}
+
+	return parentRef, nodes[taskId].Subtasks, nil
+}
+
+func (e *executor) blockers(ctx context.Context, userId int, t task.Model) ([]BlockerItem, error) {
+	blockers, err := e.linkLister.ListTaskBlockers(ctx, t.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]BlockerItem, 0, len(blockers))
+
+	if len(blockers) == 0 {
+		return items, nil
+	}
+
+	wf, err := e.workflowGetter.GetWorkflow(ctx, t.TeamId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range blockers {
+		visible, err := e.canView(ctx, userId, *b)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// Open показываем и для скрытого блокера: иначе непонятно, почему задачу нельзя завершить.
+		item := BlockerItem{Id: b.Id, Open: !wf.IsTerminal(b.Status), Hidden: !visible}
+
+		if visible {
+			item.Title = b.Title
+			item.Status = b.Status
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (e *executor) firstCommentsPage(ctx context.Context, taskId int) (*CommentsPage, error) {
+	comments, err := e.commentLister.ListTaskComments(ctx, taskId, 0, commentsLimit+1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	page := &CommentsPage{Comments: make([]comment.View, 0, len(comments))}
+
+	if len(comments) > commentsLimit {
+		page.HasMore = true
+		page.NextStartFromId = comments[commentsLimit-1].Id
+		comments = comments[:commentsLimit]
+	}
+
+	for _, c := range comments {
+		page.Comments = append(page.Comments, comment.NewView(c))
+	}
+
+	return page, nil
+}
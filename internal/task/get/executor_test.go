@@ -0,0 +1,385 @@
+package get
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/attachment"
+	"mkk-luna-test-task/internal/task/comment"
+	"mkk-luna-test-task/internal/task/history"
+	"mkk-luna-test-task/internal/team/label"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+	"mkk-luna-test-task/internal/team/workflow"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubTaskGetter struct {
+	err error
+}
+
+func (s *stubTaskGetter) GetTaskById(ctx context.Context, id int) (*task.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &task.Model{Id: id, Title: "title", Description: "**desc**", CreatorId: 1, AssigneeIds: []int{2, 3}, TeamId: 10, Version: 3}, nil
+}
+
+// stubAuthorizer при ownOnly пускает только к задачам, где читатель — создатель или исполнитель.
+type stubAuthorizer struct {
+	err     error
+	ownOnly bool
+}
+
+func (s *stubAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	if s.ownOnly && !slices.Contains(res.OwnerIds, userId) {
+		return nil, permission.ErrForbidden
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
+}
+
+// stubRepo отдаёт встраиваемые части и запоминает, какие из них запрашивали.
+type stubRepo struct {
+	comments []comment.Model
+	parent   *task.Model
+	err      error
+	calls    []string
+}
+
+func (s *stubRepo) GetUsernames(ctx context.Context, ids []int) (map[int]string, error) {
+	s.calls = append(s.calls, "users")
+
+	return map[int]string{1: "alice", 2: "bob", 3: "carol"}, s.err
+}
+
+func (s *stubRepo) CountTaskComments(ctx context.Context, taskId int) (int, error) {
+	s.calls = append(s.calls, "comments_count")
+
+	return len(s.comments), s.err
+}
+
+func (s *stubRepo) ListLatestHistory(ctx context.Context, taskId int, limit int) ([]*history.Model, error) {
+	s.calls = append(s.calls, "history")
+
+	return nil, s.err
+}
+
+func (s *stubRepo) ListTaskComments(ctx context.Context, taskId int, startFromId int, limit int) ([]comment.Model, error) {
+	s.calls = append(s.calls, "comments")
+
+	if len(s.comments) > limit {
+		return s.comments[:limit], s.err
+	}
+
+	return s.comments, s.err
+}
+
+func (s *stubRepo) ListTaskLabels(ctx context.Context, taskId int) ([]label.Model, error) {
+	s.calls = append(s.calls, "labels")
+
+	return []label.Model{{Id: 1, TeamId: 10, Name: "bug", Color: "#d73a4a"}}, s.err
+}
+
+func (s *stubRepo) GetTaskParent(ctx context.Context, taskId int) (*task.Model, error) {
+	s.calls = append(s.calls, "parent")
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	if s.parent == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	return s.parent, nil
+}
+
+func (s *stubRepo) ListSubtasks(ctx context.Context, taskId int, limit int) ([]task.Subtask, error) {
+	s.calls = append(s.calls, "subtasks")
+
+	return []task.Subtask{
+		{ParentId: 7, Task: task.Model{Id: 8, Title: "backend", Status: "todo"}},
+		{ParentId: 7, Task: task.Model{Id: 10, Title: "frontend", Status: "todo"}},
+		{ParentId: 8, Task: task.Model{Id: 9, Title: "api", Status: "done", AssigneeIds: []int{1}}},
+		// Родитель не попал в выборку — узел отбрасывается.
+		{ParentId: 99, Task: task.Model{Id: 11, Title: "lost", Status: "todo"}},
+	}, s.err
+}
+
+func (s *stubRepo) ListTaskBlockers(ctx context.Context, taskId int) ([]*task.Model, error) {
+	s.calls = append(s.calls, "blockers")
+
+	return []*task.Model{{Id: 3, Title: "design", Status: "done", CreatorId: 1}, {Id: 4, Title: "review", Status: "in_progress"}}, s.err
+}
+
+func (s *stubRepo) ListTaskWatchers(ctx context.Context, taskId int) ([]int, error) {
+	s.calls = append(s.calls, "watchers")
+
+	return []int{1, 3}, s.err
+}
+
+func (s *stubRepo) ListTaskAttachments(ctx context.Context, taskId int) ([]attachment.Model, error) {
+	s.calls = append(s.calls, "attachments")
+
+	return []attachment.Model{{Id: 1, TaskId: taskId, UploaderId: 2, FileName: "screenshot.png", ContentType: "image/png", Size: 1024}}, s.err
+}
+
+func (s *stubRepo) GetWorkflow(ctx context.Context, teamId int) (*workflow.Model, error) {
+	s.calls = append(s.calls, "workflow")
+
+	return workflow.Default(teamId), s.err
+}
+
+func (s *stubRepo) HTML(ctx context.Context, userId int, sources ...string) ([]string, error) {
+	s.calls = append(s.calls, "render")
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	rendered := make([]string, 0, len(sources))
+
+	for _, src := range sources {
+		rendered = append(rendered, "<p>"+src+"</p>")
+	}
+
+	return rendered, nil
+}
+
+func makeComments(n int) []comment.Model {
+	comments := make([]comment.Model, 0, n)
+
+	for i := 1; i <= n; i++ {
+		comments = append(comments, comment.Model{Id: i, CommenterId: 1, TaskId: 7, Text: "hi", CreatedAt: time.Now()})
+	}
+
+	return comments
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		taskGetter  *stubTaskGetter
+		authorizer  *stubAuthorizer
+		repo        *stubRepo
+		include     Include
+		renderHTML  bool
+		wantCalls   []string
+		check       func(t *testing.T, got *GetResult)
+		expectedErr string
+	}{
+		{
+			name:       "everything embedded",
+			taskGetter: &stubTaskGetter{},
+			authorizer: &stubAuthorizer{},
+			repo:       &stubRepo{comments: makeComments(3)},
+			include:    AllIncludes(),
+			wantCalls:  []string{"users", "comments_count", "history", "comments", "labels", "parent", "subtasks", "blockers", "workflow", "watchers", "users", "attachments"},
+			check: func(t *testing.T, got *GetResult) {
+				assert.Equal(t, &UserRef{Id: 1, Username: "alice"}, got.Creator)
+				assert.Equal(t, []UserRef{{Id: 2, Username: "bob"}, {Id: 3, Username: "carol"}}, got.Assignees)
+				assert.Equal(t, []UserRef{{Id: 1, Username: "alice"}, {Id: 3, Username: "carol"}}, got.Watchers)
+				assert.Equal(t, 3, *got.CommentsCount)
+				assert.NotNil(t, got.History)
+				assert.Empty(t, got.History)
+				assert.Len(t, got.Comments.Comments, 3)
+				assert.False(t, got.Comments.HasMore)
+				assert.Equal(t, []label.Model{{Id: 1, TeamId: 10, Name: "bug", Color: "#d73a4a"}}, got.Labels)
+				assert.Nil(t, got.Parent)
+				assert.Equal(t, []BlockerItem{
+					{Id: 3, Title: "design", Status: "done", Open: false},
+					{Id: 4, Title: "review", Status: "in_progress", Open: true},
+				}, got.Blockers)
+				assert.Equal(t, []attachment.Model{
+					{Id: 1, TaskId: 7, UploaderId: 2, FileName: "screenshot.png", ContentType: "image/png", Size: 1024},
+				}, got.Attachments)
+			},
+		},
+		{
+			name:       "subtask tree",
+			taskGetter: &stubTaskGetter{},
+			authorizer: &stubAuthorizer{},
+			repo:       &stubRepo{},
+			include:    Include{Subtasks: true},
+			wantCalls:  []string{"parent", "subtasks"},
+			check: func(t *testing.T, got *GetResult) {
+				assert.Equal(t, []*SubtaskNode{
+					{Id: 8, Title: "backend", Status: "todo", Subtasks: []*SubtaskNode{
+						{Id: 9, Title: "api", Status: "done", AssigneeIds: []int{1}, Subtasks: []*SubtaskNode{}},
+					}},
+					{Id: 10, Title: "frontend", Status: "todo", Subtasks: []*SubtaskNode{}},
+				}, got.Subtasks)
+				assert.Nil(t, got.Blockers)
+			},
+		},
+		{
+			name:       "linked tasks hidden from own-scope viewer",
+			taskGetter: &stubTaskGetter{},
+			authorizer: &stubAuthorizer{ownOnly: true},
+			repo:       &stubRepo{parent: &task.Model{Id: 5, Title: "epic", Status: "todo", CreatorId: 2}},
+			include:    Include{Subtasks: true, Blockers: true},
+			wantCalls:  []string{"parent", "subtasks", "blockers", "workflow"},
+			check: func(t *testing.T, got *GetResult) {
+				assert.Equal(t, &TaskRef{Id: 5, Hidden: true}, got.Parent)
+				// Скрытый узел остаётся в дереве, чтобы не потерять доступную подзадачу под ним.
+				assert.Equal(t, []*SubtaskNode{
+					{Id: 8, Hidden: true, Subtasks: []*SubtaskNode{
+						{Id: 9, Title: "api", Status: "done", AssigneeIds: []int{1}, Subtasks: []*SubtaskNode{}},
+					}},
+					{Id: 10, Hidden: true, Subtasks: []*SubtaskNode{}},
+				}, got.Subtasks)
+				assert.Equal(t, []BlockerItem{
+					{Id: 3, Title: "design", Status: "done", Open: false},
+					{Id: 4, Open: true, Hidden: true},
+				}, got.Blockers)
+			},
+		},
+		{
+			name:       "only the task",
+			taskGetter: &stubTaskGetter{},
+			authorizer: &stubAuthorizer{},
+			repo:       &stubRepo{},
+			include:    Include{},
+			wantCalls:  nil,
+			check: func(t *testing.T, got *GetResult) {
+				assert.Equal(t, 7, got.Task.Id)
+				assert.Nil(t, got.Creator)
+				assert.Nil(t, got.CommentsCount)
+				assert.Nil(t, got.History)
+				assert.Nil(t, got.Comments)
+				assert.Nil(t, got.Labels)
+				assert.Nil(t, got.Subtasks)
+				assert.Nil(t, got.Blockers)
+				assert.Nil(t, got.Watchers)
+				assert.Nil(t, got.Attachments)
+				assert.Nil(t, got.DescriptionHTML)
+			},
+		},
+		{
+			name:       "html rendering of description and comments",
+			taskGetter: &stubTaskGetter{},
+			authorizer: &stubAuthorizer{},
+			repo:       &stubRepo{comments: makeComments(2)},
+			include:    Include{Comments: true},
+			renderHTML: true,
+			wantCalls:  []string{"comments", "render"},
+			check: func(t *testing.T, got *GetResult) {
+				assert.Equal(t, "<p>**desc**</p>", *got.DescriptionHTML)
+				assert.Len(t, got.Comments.Comments, 2)
+
+				for _, c := range got.Comments.Comments {
+					assert.Equal(t, "<p>hi</p>", *c.TextHTML)
+				}
+			},
+		},
+		{
+			name:       "first comments page has more",
+			taskGetter: &stubTaskGetter{},
+			authorizer: &stubAuthorizer{},
+			repo:       &stubRepo{comments: makeComments(commentsLimit + 5)},
+			include:    Include{Comments: true},
+			wantCalls:  []string{"comments"},
+			check: func(t *testing.T, got *GetResult) {
+				assert.Len(t, got.Comments.Comments, commentsLimit)
+				assert.True(t, got.Comments.HasMore)
+				assert.Equal(t, commentsLimit, got.Comments.NextStartFromId)
+			},
+		},
+		{
+			name:        "task not found",
+			taskGetter:  &stubTaskGetter{err: sql.ErrNoRows},
+			authorizer:  &stubAuthorizer{},
+			repo:        &stubRepo{},
+			include:     AllIncludes(),
+			expectedErr: "task not found",
+		},
+		{
+			name:        "forbidden",
+			taskGetter:  &stubTaskGetter{},
+			authorizer:  &stubAuthorizer{err: permission.ErrForbidden},
+			repo:        &stubRepo{},
+			include:     AllIncludes(),
+			expectedErr: "forbidden",
+		},
+		{
+			name:        "render error",
+			taskGetter:  &stubTaskGetter{},
+			authorizer:  &stubAuthorizer{},
+			repo:        &stubRepo{err: errors.New("db error")},
+			include:     Include{},
+			renderHTML:  true,
+			wantCalls:   []string{"render"},
+			expectedErr: "db error",
+		},
+		{
+			name:        "embedded part error",
+			taskGetter:  &stubTaskGetter{},
+			authorizer:  &stubAuthorizer{},
+			repo:        &stubRepo{err: errors.New("db error")},
+			include:     Include{CommentsCount: true},
+			wantCalls:   []string{"comments_count"},
+			expectedErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(tt.taskGetter, tt.authorizer, tt.repo, tt.repo, tt.repo, tt.repo, tt.repo, tt.repo, tt.repo, tt.repo, tt.repo, tt.repo)
+
+			got, err := e.Execute(context.Background(), GetInput{UserId: 1, TaskId: 7, Include: tt.include, RenderHTML: tt.renderHTML})
+
+			assert.Equal(t, tt.wantCalls, tt.repo.calls)
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Nil(t, got)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			tt.check(t, got)
+		})
+	}
+}
+
+func TestParseInclude(t *testing.T) {
+	tests := []struct {
+		raw         string
+		want        Include
+		expectedErr string
+	}{
+		{raw: "", want: Include{}},
+		{raw: "users", want: Include{Users: true}},
+		{raw: "comments, history", want: Include{History: true, Comments: true}},
+		{raw: "users,comments_count,history,comments,labels,subtasks,blockers,watchers,attachments", want: AllIncludes()},
+		{raw: "reactions", expectedErr: "validation failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ParseInclude(tt.raw)
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
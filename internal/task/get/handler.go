@@ -0,0 +1,172 @@
+package get
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/attachment"
+	"mkk-luna-test-task/internal/task/history"
+	"mkk-luna-test-task/internal/task/render"
+	"mkk-luna-test-task/internal/team/label"
+)
+
+type response struct {
+	Id              int                 `json:"id"`
+	Status          string              `json:"status"`
+	Title           string              `json:"title"`
+	Description     string              `json:"description"`
+	DescriptionHTML *string             `json:"description_html,omitempty"`
+	CreatorId       int                 `json:"creator_id"`
+	AssigneeIds     []int               `json:"assignee_ids"`
+	TeamId          int                 `json:"team_id"`
+	Version         int                 `json:"version"`
+	CreatedAt       time.Time           `json:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at"`
+	DueAt           *time.Time          `json:"due_at"`
+	Priority        task.Priority       `json:"priority"`
+	ArchivedAt      *time.Time          `json:"archived_at,omitempty"`
+	Creator         *UserRef            `json:"creator,omitempty"`
+	Assignees       *[]UserRef          `json:"assignees,omitempty"`
+	CommentsCount   *int                `json:"comments_count,omitempty"`
+	History         *[]*history.Model   `json:"history,omitempty"`
+	Comments        *CommentsPage       `json:"comments,omitempty"`
+	Labels          *[]label.Model      `json:"labels,omitempty"`
+	Parent          *TaskRef            `json:"parent,omitempty"`
+	Subtasks        *[]*SubtaskNode     `json:"subtasks,omitempty"`
+	Blockers        *[]BlockerItem      `json:"blockers,omitempty"`
+	Watchers        *[]UserRef          `json:"watchers,omitempty"`
+	Attachments     *[]attachment.Model `json:"attachments,omitempty"`
+}
+
+type Executor interface {
+	Execute(ctx context.Context, in GetInput) (*GetResult, error)
+}
+
+type handler struct {
+	exec Executor
+}
+
+func NewHandler(exec Executor) *handler {
+	return &handler{
+		exec: exec,
+	}
+}
+
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	userIdAny := r.Context().Value("userId")
+
+	userId, ok := userIdAny.(int)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
+	taskId, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("invalid task id"))
+
+		return
+	}
+
+	include := AllIncludes()
+
+	if raw, ok := r.URL.Query()["include"]; ok {
+		include, err = ParseInclude(raw[0])
+
+		if err != nil {
+			apperror.Write(w, err)
+
+			return
+		}
+	}
+
+	renderHTML, err := render.WantHTML(r.URL.Query())
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	result, err := h.exec.Execute(r.Context(), GetInput{UserId: userId, TaskId: taskId, Include: include, RenderHTML: renderHTML})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	t := result.Task
+
+	resp := response{
+		Id:              t.Id,
+		Status:          t.Status,
+		Title:           t.Title,
+		Description:     t.Description,
+		DescriptionHTML: result.DescriptionHTML,
+		CreatorId:       t.CreatorId,
+		AssigneeIds:     t.AssigneeIds,
+		TeamId:          t.TeamId,
+		Version:         t.Version,
+		CreatedAt:       t.CreatedAt,
+		UpdatedAt:       t.UpdatedAt,
+		DueAt:           t.DueAt,
+		Priority:        t.Priority,
+		ArchivedAt:      t.ArchivedAt,
+		Creator:         result.Creator,
+		CommentsCount:   result.CommentsCount,
+		Comments:        result.Comments,
+		Parent:          result.Parent,
+	}
+
+	// Запрошенная, но пустая история отдаётся как [], а не пропадает из ответа.
+	if result.History != nil {
+		resp.History = &result.History
+	}
+
+	if result.Labels != nil {
+		resp.Labels = &result.Labels
+	}
+
+	if result.Subtasks != nil {
+		resp.Subtasks = &result.Subtasks
+	}
+
+	if result.Blockers != nil {
+		resp.Blockers = &result.Blockers
+	}
+
+	if result.Assignees != nil {
+		resp.Assignees = &result.Assignees
+	}
+
+	if result.Watchers != nil {
+		resp.Watchers = &result.Watchers
+	}
+
+	if result.Attachments != nil {
+		resp.Attachments = &result.Attachments
+	}
+
+	body, err := json.Marshal(resp)
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	w.Header().Set("ETag", task.ETag(t.Version))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
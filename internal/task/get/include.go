@@ -0,0 +1,59 @@
+package get
+
+import (
+	"strings"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+// Include описывает, что встроить в ответ вместе с самой задачей.
+type Include struct {
+	Users         bool
+	CommentsCount bool
+	History       bool
+	Comments      bool
+	Labels        bool
+	Subtasks      bool
+	Blockers      bool
+	Watchers      bool
+	Attachments   bool
+}
+
+func AllIncludes() Include {
+	return Include{Users: true, CommentsCount: true, History: true, Comments: true, Labels: true, Subtasks: true, Blockers: true, Watchers: true, Attachments: true}
+}
+
+// ParseInclude разбирает параметр include: список через запятую
+// из users, comments_count, history, comments, labels, subtasks, blockers, watchers и attachments.
+// Пустая строка означает «только задача».
+func ParseInclude(raw string) (Include, error) {
+	var inc Include
+
+	for _, part := range strings.Split(raw, ",") {
+		switch strings.TrimSpace(part) {
+		case "":
+		case "users":
+			inc.Users = true
+		case "comments_count":
+			inc.CommentsCount = true
+		case "history":
+			inc.History = true
+		case "comments":
+			inc.Comments = true
+		case "labels":
+			inc.Labels = true
+		case "subtasks":
+			inc.Subtasks = true
+		case "blockers":
+			inc.Blockers = true
+		case "watchers":
+			inc.Watchers = true
+		case "attachments":
+			inc.Attachments = true
+		default:
+			return Include{}, apperror.InvalidField("include", "unknown value "+strings.TrimSpace(part))
+		}
+	}
+
+	return inc, nil
+}
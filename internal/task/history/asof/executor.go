@@ -0,0 +1,98 @@
+package asof
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/history"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type AsOfResult struct {
+	TaskId    int
+	TeamId    int
+	CreatorId int
+	CreatedAt time.Time
+	AsOf      time.Time
+	State     history.Snapshot
+}
+
+type taskGetter interface {
+	GetTaskById(ctx context.Context, id int) (*task.Model, error)
+}
+
+type historyGetter interface {
+	GetHistoryAfter(ctx context.Context, taskId int, at time.Time) (*history.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type executor struct {
+	taskGetter    taskGetter
+	historyGetter historyGetter
+	authorizer    authorizer
+}
+
+func NewExecutor(taskGetter taskGetter, historyGetter historyGetter, authorizer authorizer) *executor {
+	return &executor{
+		taskGetter:    taskGetter,
+		historyGetter: historyGetter,
+		authorizer:    authorizer,
+	}
+}
+
+type AsOfInput struct {
+	UserId int
+	TaskId int
+	AsOf   time.Time
+}
+
+// Execute восстанавливает задачу на момент AsOf: это состояние до первого изменения после AsOf
+// или текущее, если позже задачу не меняли. Метки и архивация в состояние не входят.
+func (e *executor) Execute(ctx context.Context, in AsOfInput) (*AsOfResult, error) {
+	t, err := e.taskGetter.GetTaskById(ctx, in.TaskId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("task not found")
+		}
+
+		return nil, err
+	}
+
+	_, err = e.authorizer.Authorize(ctx, in.UserId, permission.TaskView, permission.TaskResource(*t))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if in.AsOf.Before(t.CreatedAt) {
+		return nil, apperror.NotFound("task did not exist at as_of")
+	}
+
+	state := history.SnapshotOf(*t)
+
+	h, err := e.historyGetter.GetHistoryAfter(ctx, t.Id, in.AsOf)
+
+	if err == nil {
+		state = h.Snapshot()
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	return &AsOfResult{
+		TaskId:    t.Id,
+		TeamId:    t.TeamId,
+		CreatorId: t.CreatorId,
+		CreatedAt: t.CreatedAt,
+		AsOf:      in.AsOf,
+		State:     state,
+	}, nil
+}
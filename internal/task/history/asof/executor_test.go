@@ -0,0 +1,174 @@
+package asof
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/history"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubTaskGetter struct {
+	task *task.Model
+}
+
+func (s *stubTaskGetter) GetTaskById(ctx context.Context, id int) (*task.Model, error) {
+	if s.task == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	t := *s.task
+
+	return &t, nil
+}
+
+type stubHistoryGetter struct {
+	history *history.Model
+	err     error
+}
+
+func (s *stubHistoryGetter) GetHistoryAfter(ctx context.Context, taskId int, at time.Time) (*history.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	if s.history == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	return s.history, nil
+}
+
+type stubAuthorizer struct {
+	err error
+}
+
+func (s *stubAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	asOf := time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	current := &task.Model{
+		Id:          1,
+		Status:      "done",
+		Title:       "Отчёт",
+		CreatorId:   5,
+		CreatedAt:   createdAt,
+		AssigneeIds: []int{6, 7},
+		TeamId:      10,
+		Priority:    task.HighPriority,
+	}
+
+	tests := []struct {
+		name          string
+		taskGetter    *stubTaskGetter
+		historyGetter *stubHistoryGetter
+		authorizer    *stubAuthorizer
+		asOf          time.Time
+		wantState     history.Snapshot
+		expectedErr   string
+	}{
+		{
+			name:       "state before the next change",
+			taskGetter: &stubTaskGetter{task: current},
+			historyGetter: &stubHistoryGetter{history: &history.Model{
+				Status:      "todo",
+				Title:       "Черновик отчёта",
+				AssigneeIds: []int{6},
+				Priority:    task.NormalPriority,
+			}},
+			authorizer: &stubAuthorizer{},
+			asOf:       asOf,
+			wantState: history.Snapshot{
+				Status:      "todo",
+				Title:       "Черновик отчёта",
+				AssigneeIds: []int{6},
+				Priority:    task.NormalPriority,
+			},
+		},
+		{
+			name:          "no changes after as_of",
+			taskGetter:    &stubTaskGetter{task: current},
+			historyGetter: &stubHistoryGetter{},
+			authorizer:    &stubAuthorizer{},
+			asOf:          asOf,
+			wantState: history.Snapshot{
+				Status:      "done",
+				Title:       "Отчёт",
+				AssigneeIds: []int{6, 7},
+				Priority:    task.HighPriority,
+			},
+		},
+		{
+			name:          "task not found",
+			taskGetter:    &stubTaskGetter{},
+			historyGetter: &stubHistoryGetter{},
+			authorizer:    &stubAuthorizer{},
+			asOf:          asOf,
+			expectedErr:   "task not found",
+		},
+		{
+			name:          "cannot view task",
+			taskGetter:    &stubTaskGetter{task: current},
+			historyGetter: &stubHistoryGetter{},
+			authorizer:    &stubAuthorizer{err: permission.ErrForbidden},
+			asOf:          asOf,
+			expectedErr:   "forbidden",
+		},
+		{
+			name:          "before creation",
+			taskGetter:    &stubTaskGetter{task: current},
+			historyGetter: &stubHistoryGetter{},
+			authorizer:    &stubAuthorizer{},
+			asOf:          createdAt.Add(-time.Second),
+			expectedErr:   "task did not exist at as_of",
+		},
+		{
+			name:          "db error",
+			taskGetter:    &stubTaskGetter{task: current},
+			historyGetter: &stubHistoryGetter{err: errors.New("db error")},
+			authorizer:    &stubAuthorizer{},
+			asOf:          asOf,
+			expectedErr:   "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(tt.taskGetter, tt.historyGetter, tt.authorizer)
+
+			got, err := e.Execute(context.Background(), AsOfInput{UserId: 5, TaskId: 1, AsOf: tt.asOf})
+
+			if tt.expectedErr != "" {
+				assert.Nil(t, got)
+				assert.EqualError(t, err, tt.expectedErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, &AsOfResult{
+				TaskId:    1,
+				TeamId:    10,
+				CreatorId: 5,
+				CreatedAt: createdAt,
+				AsOf:      tt.asOf,
+				State:     tt.wantState,
+			}, got)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package asof
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+)
+
+type response struct {
+	TaskId      int           `json:"task_id"`
+	TeamId      int           `json:"team_id"`
+	CreatorId   int           `json:"creator_id"`
+	CreatedAt   time.Time     `json:"created_at"`
+	AsOf        time.Time     `json:"as_of"`
+	Status      string        `json:"status"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	AssigneeIds []int         `json:"assignee_ids"`
+	DueAt       *time.Time    `json:"due_at"`
+	Priority    task.Priority `json:"priority"`
+}
+
+type Executor interface {
+	Execute(ctx context.Context, in AsOfInput) (*AsOfResult, error)
+}
+
+type handler struct {
+	exec Executor
+}
+
+func NewHandler(exec Executor) *handler {
+	return &handler{
+		exec: exec,
+	}
+}
+
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	userIdAny := r.Context().Value("userId")
+
+	userId, ok := userIdAny.(int)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
+	taskIdStr := chi.URLParam(r, "id")
+
+	taskId, err := strconv.Atoi(taskIdStr)
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("invalid task id"))
+
+		return
+	}
+
+	asOf, err := time.Parse(time.RFC3339, r.URL.Query().Get("as_of"))
+
+	if err != nil {
+		apperror.Write(w, apperror.InvalidField("as_of", "must be RFC 3339 time"))
+
+		return
+	}
+
+	result, err := h.exec.Execute(r.Context(), AsOfInput{
+		UserId: userId,
+		TaskId: taskId,
+		AsOf:   asOf,
+	})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	resp := response{
+		TaskId:      result.TaskId,
+		TeamId:      result.TeamId,
+		CreatorId:   result.CreatorId,
+		CreatedAt:   result.CreatedAt,
+		AsOf:        result.AsOf,
+		Status:      result.State.Status,
+		Title:       result.State.Title,
+		Description: result.State.Description,
+		AssigneeIds: result.State.AssigneeIds,
+		DueAt:       result.State.DueAt,
+		Priority:    result.State.Priority,
+	}
+
+	respBody, err := json.Marshal(resp)
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
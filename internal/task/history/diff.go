@@ -0,0 +1,121 @@
+package history
+
+import (
+	"slices"
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+)
+
+// CreateEvent в таблице не хранится: запись о создании собирается из первого состояния задачи.
+const CreateEvent Event = "create"
+
+// Snapshot — поля задачи, изменения которых показывает история.
+type Snapshot struct {
+	Status      string        `json:"status"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	AssigneeIds []int         `json:"assignee_ids"`
+	DueAt       *time.Time    `json:"due_at"`
+	Priority    task.Priority `json:"priority"`
+}
+
+// Change — изменение одного поля; Old равно nil для записи о создании.
+type Change struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+func SnapshotOf(t task.Model) Snapshot {
+	return Snapshot{
+		Status:      t.Status,
+		Title:       t.Title,
+		Description: t.Description,
+		AssigneeIds: t.AssigneeIds,
+		DueAt:       t.DueAt,
+		Priority:    t.Priority,
+	}
+}
+
+// Snapshot — состояние задачи до изменения, сохранённое в записи.
+func (m Model) Snapshot() Snapshot {
+	return Snapshot{
+		Status:      m.Status,
+		Title:       m.Title,
+		Description: m.Description,
+		AssigneeIds: m.AssigneeIds,
+		DueAt:       m.DueAt,
+		Priority:    m.Priority,
+	}
+}
+
+// Diff возвращает изменённые поля между before и after. Если before равен nil, в ответе все поля after.
+func Diff(before *Snapshot, after Snapshot) []Change {
+	changes := []Change{}
+
+	if before == nil {
+		return append(changes,
+			Change{Field: "status", New: after.Status},
+			Change{Field: "title", New: after.Title},
+			Change{Field: "description", New: after.Description},
+			Change{Field: "assignee_ids", New: after.AssigneeIds},
+			Change{Field: "due_at", New: after.DueAt},
+			Change{Field: "priority", New: after.Priority},
+		)
+	}
+
+	if before.Status != after.Status {
+		changes = append(changes, Change{Field: "status", Old: before.Status, New: after.Status})
+	}
+
+	if before.Title != after.Title {
+		changes = append(changes, Change{Field: "title", Old: before.Title, New: after.Title})
+	}
+
+	if before.Description != after.Description {
+		changes = append(changes, Change{Field: "description", Old: before.Description, New: after.Description})
+	}
+
+	if !slices.Equal(before.AssigneeIds, after.AssigneeIds) {
+		changes = append(changes, Change{Field: "assignee_ids", Old: before.AssigneeIds, New: after.AssigneeIds})
+	}
+
+	if !equalTime(before.DueAt, after.DueAt) {
+		changes = append(changes, Change{Field: "due_at", Old: before.DueAt, New: after.DueAt})
+	}
+
+	if before.Priority != after.Priority {
+		changes = append(changes, Change{Field: "priority", Old: before.Priority, New: after.Priority})
+	}
+
+	return changes
+}
+
+// EventChanges — изменения, которые видны только по событию записи: архивация и метки.
+func (m Model) EventChanges() []Change {
+	switch m.Event {
+	case ArchiveEvent:
+		return []Change{{Field: "archived", Old: false, New: true}}
+	case RestoreEvent:
+		return []Change{{Field: "archived", Old: true, New: false}}
+	case LabelEvent:
+		if m.Label != nil {
+			return []Change{{Field: "label", New: *m.Label}}
+		}
+	case UnlabelEvent:
+		if m.Label != nil {
+			return []Change{{Field: "label", Old: *m.Label}}
+		}
+	}
+
+	return nil
+}
+
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(*b)
+}
@@ -2,10 +2,15 @@ package list
 
 import (
 	"context"
-	"fmt"
+	"database/sql"
+	"errors"
 	"time"
 
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
 	"mkk-luna-test-task/internal/task/history"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
 )
 
 const defaultLimit = 100
@@ -16,37 +21,39 @@ type ListResult struct {
 	HasMore         bool
 }
 
+// HistoryItem — одно изменение задачи. У записи о создании нет id: она собирается из первого состояния задачи.
 type HistoryItem struct {
-	Id          int       `json:"id"`
-	TaskId      int       `json:"task_id"`
-	Status      string    `json:"status"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	CreatorId   int       `json:"creator_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	AssigneeId  int       `json:"assignee_id"`
-	TeamId      int       `json:"team_id"`
-	ChangedBy   int       `json:"changed_by"`
-	ChangedAt   time.Time `json:"changed_at"`
+	Id        int              `json:"id,omitempty"`
+	TaskId    int              `json:"task_id"`
+	Event     string           `json:"event"`
+	ChangedBy int              `json:"changed_by"`
+	ChangedAt time.Time        `json:"changed_at"`
+	Changes   []history.Change `json:"changes"`
 }
 
 type historyLister interface {
 	ListHistory(ctx context.Context, taskId int, startFromId int, limit int) ([]*history.Model, error)
 }
 
-type taskMembershipChecker interface {
-	IsPartOfTeamByTaskId(userId, taskId int) (bool, error)
+type taskGetter interface {
+	GetTaskById(ctx context.Context, id int) (*task.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
 }
 
 type executor struct {
-	historyLister         historyLister
-	taskMembershipChecker taskMembershipChecker
+	historyLister historyLister
+	taskGetter    taskGetter
+	authorizer    authorizer
 }
 
-func NewExecutor(historyLister historyLister, taskMembershipChecker taskMembershipChecker) *executor {
+func NewExecutor(historyLister historyLister, taskGetter taskGetter, authorizer authorizer) *executor {
 	return &executor{
-		historyLister:         historyLister,
-		taskMembershipChecker: taskMembershipChecker,
+		historyLister: historyLister,
+		taskGetter:    taskGetter,
+		authorizer:    authorizer,
 	}
 }
 
@@ -57,14 +64,20 @@ type ListInput struct {
 }
 
 func (e *executor) Execute(ctx context.Context, in ListInput) (*ListResult, error) {
-	isMember, err := e.taskMembershipChecker.IsPartOfTeamByTaskId(in.UserId, in.TaskId)
+	t, err := e.taskGetter.GetTaskById(ctx, in.TaskId)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to check team membership: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("task not found")
+		}
+
+		return nil, err
 	}
 
-	if !isMember {
-		return nil, fmt.Errorf("forbidden")
+	_, err = e.authorizer.Authorize(ctx, in.UserId, permission.TaskView, permission.TaskResource(*t))
+
+	if err != nil {
+		return nil, err
 	}
 
 	histories, err := e.historyLister.ListHistory(ctx, in.TaskId, in.StartFromId, defaultLimit+1)
@@ -79,25 +92,43 @@ func (e *executor) Execute(ctx context.Context, in ListInput) (*ListResult, erro
 
 	if len(histories) > defaultLimit {
 		hasMore = true
-		nextStartFromId = histories[defaultLimit].Id
+		nextStartFromId = histories[defaultLimit-1].Id
 		results = histories[:defaultLimit]
 	}
 
-	items := make([]HistoryItem, 0, len(results))
+	// Запись хранит состояние до изменения, а состояние после — это следующая запись
+	// или, для последней, текущая задача.
+	stateAfter := func(i int) history.Snapshot {
+		if i+1 < len(histories) {
+			return histories[i+1].Snapshot()
+		}
+
+		return history.SnapshotOf(*t)
+	}
+
+	items := make([]HistoryItem, 0, len(results)+1)
+
+	// Задача создана в том состоянии, которое сохранила первая запись.
+	if in.StartFromId == 0 {
+		items = append(items, HistoryItem{
+			TaskId:    t.Id,
+			Event:     string(history.CreateEvent),
+			ChangedBy: t.CreatorId,
+			ChangedAt: t.CreatedAt,
+			Changes:   history.Diff(nil, stateAfter(-1)),
+		})
+	}
+
+	for i, h := range results {
+		before := h.Snapshot()
 
-	for _, h := range results {
 		items = append(items, HistoryItem{
-			Id:          h.Id,
-			TaskId:      h.TaskId,
-			Status:      h.Status,
-			Title:       h.Title,
-			Description: h.Description,
-			CreatorId:   h.CreatorId,
-			CreatedAt:   h.CreatedAt,
-			AssigneeId:  h.AssigneeId,
-			TeamId:      h.TeamId,
-			ChangedBy:   h.ChangedBy,
-			ChangedAt:   h.ChangedAt,
+			Id:        h.Id,
+			TaskId:    h.TaskId,
+			Event:     string(h.Event),
+			ChangedBy: h.ChangedBy,
+			ChangedAt: h.ChangedAt,
+			Changes:   append(history.Diff(&before, stateAfter(i)), h.EventChanges()...),
 		})
 	}
 
@@ -2,11 +2,15 @@ package list
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
 	"time"
 
+	"mkk-luna-test-task/internal/task"
 	"mkk-luna-test-task/internal/task/history"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -31,24 +35,45 @@ func (s *stubHistoryListerError) ListHistory(ctx context.Context, taskId int, st
 	return nil, errors.New("db error")
 }
 
-type stubTaskMembershipCheckerAllowed struct{}
+type stubTaskGetter struct{}
 
-func (s *stubTaskMembershipCheckerAllowed) IsPartOfTeamByTaskId(userId, taskId int) (bool, error) {
-	return true, nil
+func (s *stubTaskGetter) GetTaskById(ctx context.Context, id int) (*task.Model, error) {
+	if id == 404 {
+		return nil, sql.ErrNoRows
+	}
+
+	return &task.Model{
+		Id:          id,
+		Status:      "done",
+		Title:       "History Title",
+		Description: "History Desc",
+		TeamId:      42,
+		CreatorId:   1,
+		CreatedAt:   time.Unix(0, 0),
+		AssigneeIds: []int{2},
+		Priority:    task.NormalPriority,
+	}, nil
+}
+
+type stubAuthorizerAllowed struct{}
+
+func (s *stubAuthorizerAllowed) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
 }
 
-type stubTaskMembershipCheckerDenied struct{}
+type stubAuthorizerDenied struct{}
 
-func (s *stubTaskMembershipCheckerDenied) IsPartOfTeamByTaskId(userId, taskId int) (bool, error) {
-	return false, nil
+func (s *stubAuthorizerDenied) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	return nil, permission.ErrForbidden
 }
 
-type stubTaskMembershipCheckerError struct{}
+type stubAuthorizerError struct{}
 
-func (s *stubTaskMembershipCheckerError) IsPartOfTeamByTaskId(userId, taskId int) (bool, error) {
-	return false, errors.New("membership check error")
+func (s *stubAuthorizerError) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	return nil, errors.New("membership check error")
 }
 
+// makeHistory — запись, состояние в которой совпадает с текущей задачей из stubTaskGetter.
 func makeHistory(id int) *history.Model {
 	return &history.Model{
 		Id:          id,
@@ -57,18 +82,39 @@ func makeHistory(id int) *history.Model {
 		Title:       "History Title",
 		Description: "History Desc",
 		CreatorId:   1,
-		CreatedAt:   time.Unix(int64(id), 0),
-		AssigneeId:  2,
+		CreatedAt:   time.Unix(0, 0),
+		AssigneeIds: []int{2},
 		TeamId:      42,
 		ChangedBy:   99,
 		ChangedAt:   time.Unix(int64(id+1), 0),
+		Event:       history.EditEvent,
+		Priority:    task.NormalPriority,
+	}
+}
+
+func createItem(status, title string, dueAt *time.Time) HistoryItem {
+	return HistoryItem{
+		TaskId:    100,
+		Event:     "create",
+		ChangedBy: 1,
+		ChangedAt: time.Unix(0, 0),
+		Changes: []history.Change{
+			{Field: "status", New: status},
+			{Field: "title", New: title},
+			{Field: "description", New: "History Desc"},
+			{Field: "assignee_ids", New: []int{2}},
+			{Field: "due_at", New: dueAt},
+			{Field: "priority", New: task.NormalPriority},
+		},
 	}
 }
 
 func TestExecutor_Execute(t *testing.T) {
+	dueAt := time.Date(2024, 3, 15, 18, 0, 0, 0, time.UTC)
+
 	type fields struct {
-		historyLister         historyLister
-		taskMembershipChecker taskMembershipChecker
+		historyLister historyLister
+		authorizer    authorizer
 	}
 	type args struct {
 		ctx context.Context
@@ -83,10 +129,17 @@ func TestExecutor_Execute(t *testing.T) {
 		expectedErr error
 	}{
 		{
-			name: "success - single history",
+			name: "success - edit shows changed fields up to current task",
 			fields: fields{
-				historyLister:         &stubHistoryListerSuccess{histories: []*history.Model{makeHistory(1)}},
-				taskMembershipChecker: &stubTaskMembershipCheckerAllowed{},
+				historyLister: &stubHistoryListerSuccess{histories: func() []*history.Model {
+					h := makeHistory(1)
+					h.Status = "todo"
+					h.Title = "Old Title"
+					h.DueAt = &dueAt
+
+					return []*history.Model{h}
+				}()},
+				authorizer: &stubAuthorizerAllowed{},
 			},
 			args: args{
 				ctx: context.Background(),
@@ -94,18 +147,18 @@ func TestExecutor_Execute(t *testing.T) {
 			},
 			want: &ListResult{
 				History: []HistoryItem{
+					createItem("todo", "Old Title", &dueAt),
 					{
-						Id:          1,
-						TaskId:      100,
-						Status:      "done",
-						Title:       "History Title",
-						Description: "History Desc",
-						CreatorId:   1,
-						CreatedAt:   time.Unix(1, 0),
-						AssigneeId:  2,
-						TeamId:      42,
-						ChangedBy:   99,
-						ChangedAt:   time.Unix(2, 0),
+						Id:        1,
+						TaskId:    100,
+						Event:     "edit",
+						ChangedBy: 99,
+						ChangedAt: time.Unix(2, 0),
+						Changes: []history.Change{
+							{Field: "status", Old: "todo", New: "done"},
+							{Field: "title", Old: "Old Title", New: "History Title"},
+							{Field: "due_at", Old: &dueAt, New: (*time.Time)(nil)},
+						},
 					},
 				},
 				NextStartFromId: 0,
@@ -116,15 +169,58 @@ func TestExecutor_Execute(t *testing.T) {
 		{
 			name: "success - no histories",
 			fields: fields{
-				historyLister:         &stubHistoryListerEmpty{},
-				taskMembershipChecker: &stubTaskMembershipCheckerAllowed{},
+				historyLister: &stubHistoryListerEmpty{},
+				authorizer:    &stubAuthorizerAllowed{},
 			},
 			args: args{
 				ctx: context.Background(),
 				in:  ListInput{UserId: 1, TaskId: 100, StartFromId: 0},
 			},
 			want: &ListResult{
-				History:         []HistoryItem{},
+				History:         []HistoryItem{createItem("done", "History Title", nil)},
+				NextStartFromId: 0,
+				HasMore:         false,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success - label and archive events",
+			fields: fields{
+				historyLister: &stubHistoryListerSuccess{histories: func() []*history.Model {
+					label := "bug"
+					labeled := makeHistory(6)
+					labeled.Event = history.LabelEvent
+					labeled.Label = &label
+					archived := makeHistory(7)
+					archived.Event = history.ArchiveEvent
+
+					return []*history.Model{labeled, archived}
+				}()},
+				authorizer: &stubAuthorizerAllowed{},
+			},
+			args: args{
+				ctx: context.Background(),
+				in:  ListInput{UserId: 1, TaskId: 100, StartFromId: 5},
+			},
+			want: &ListResult{
+				History: []HistoryItem{
+					{
+						Id:        6,
+						TaskId:    100,
+						Event:     "label",
+						ChangedBy: 99,
+						ChangedAt: time.Unix(7, 0),
+						Changes:   []history.Change{{Field: "label", New: "bug"}},
+					},
+					{
+						Id:        7,
+						TaskId:    100,
+						Event:     "archive",
+						ChangedBy: 99,
+						ChangedAt: time.Unix(8, 0),
+						Changes:   []history.Change{{Field: "archived", Old: false, New: true}},
+					},
+				},
 				NextStartFromId: 0,
 				HasMore:         false,
 			},
@@ -140,43 +236,53 @@ func TestExecutor_Execute(t *testing.T) {
 					}
 					return &stubHistoryListerSuccess{histories: histories}
 				}(),
-				taskMembershipChecker: &stubTaskMembershipCheckerAllowed{},
+				authorizer: &stubAuthorizerAllowed{},
 			},
 			args: args{
 				ctx: context.Background(),
 				in:  ListInput{UserId: 1, TaskId: 100, StartFromId: 0},
 			},
 			want: func() *ListResult {
-				historyItems := make([]HistoryItem, 0, defaultLimit)
+				historyItems := make([]HistoryItem, 0, defaultLimit+1)
+				historyItems = append(historyItems, createItem("done", "History Title", nil))
 				for i := 1; i <= defaultLimit; i++ {
 					model := makeHistory(i)
 					historyItems = append(historyItems, HistoryItem{
-						Id:          model.Id,
-						TaskId:      model.TaskId,
-						Status:      model.Status,
-						Title:       model.Title,
-						Description: model.Description,
-						CreatorId:   model.CreatorId,
-						CreatedAt:   model.CreatedAt,
-						AssigneeId:  model.AssigneeId,
-						TeamId:      model.TeamId,
-						ChangedBy:   model.ChangedBy,
-						ChangedAt:   model.ChangedAt,
+						Id:        model.Id,
+						TaskId:    model.TaskId,
+						Event:     "edit",
+						ChangedBy: model.ChangedBy,
+						ChangedAt: model.ChangedAt,
+						Changes:   []history.Change{},
 					})
 				}
 				return &ListResult{
 					History:         historyItems,
-					NextStartFromId: defaultLimit + 1,
+					NextStartFromId: defaultLimit,
 					HasMore:         true,
 				}
 			}(),
 			wantErr: false,
 		},
+		{
+			name: "fail - task not found",
+			fields: fields{
+				historyLister: &stubHistoryListerEmpty{},
+				authorizer:    &stubAuthorizerAllowed{},
+			},
+			args: args{
+				ctx: context.Background(),
+				in:  ListInput{UserId: 1, TaskId: 404},
+			},
+			want:        nil,
+			wantErr:     true,
+			expectedErr: errors.New("task not found"),
+		},
 		{
 			name: "fail - forbidden by team membership",
 			fields: fields{
-				historyLister:         &stubHistoryListerSuccess{histories: []*history.Model{makeHistory(1)}},
-				taskMembershipChecker: &stubTaskMembershipCheckerDenied{},
+				historyLister: &stubHistoryListerSuccess{histories: []*history.Model{makeHistory(1)}},
+				authorizer:    &stubAuthorizerDenied{},
 			},
 			args: args{
 				ctx: context.Background(),
@@ -189,8 +295,8 @@ func TestExecutor_Execute(t *testing.T) {
 		{
 			name: "fail - error checking team membership",
 			fields: fields{
-				historyLister:         &stubHistoryListerSuccess{histories: []*history.Model{makeHistory(1)}},
-				taskMembershipChecker: &stubTaskMembershipCheckerError{},
+				historyLister: &stubHistoryListerSuccess{histories: []*history.Model{makeHistory(1)}},
+				authorizer:    &stubAuthorizerError{},
 			},
 			args: args{
 				ctx: context.Background(),
@@ -198,13 +304,13 @@ func TestExecutor_Execute(t *testing.T) {
 			},
 			want:        nil,
 			wantErr:     true,
-			expectedErr: errors.New("failed to check team membership: membership check error"),
+			expectedErr: errors.New("membership check error"),
 		},
 		{
 			name: "fail - lister returns error",
 			fields: fields{
-				historyLister:         &stubHistoryListerError{},
-				taskMembershipChecker: &stubTaskMembershipCheckerAllowed{},
+				historyLister: &stubHistoryListerError{},
+				authorizer:    &stubAuthorizerAllowed{},
 			},
 			args: args{
 				ctx: context.Background(),
@@ -219,8 +325,9 @@ func TestExecutor_Execute(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			e := &executor{
-				historyLister:         tt.fields.historyLister,
-				taskMembershipChecker: tt.fields.taskMembershipChecker,
+				historyLister: tt.fields.historyLister,
+				taskGetter:    &stubTaskGetter{},
+				authorizer:    tt.fields.authorizer,
 			}
 
 			got, err := e.Execute(tt.args.ctx, tt.args.in)
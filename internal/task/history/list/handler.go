@@ -7,6 +7,8 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi"
+
+	"mkk-luna-test-task/internal/apperror"
 )
 
 type response struct {
@@ -35,7 +37,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	userId, ok := userIdAny.(int)
 
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
 
 		return
 	}
@@ -45,7 +47,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	taskId, err := strconv.Atoi(taskIdStr)
 
 	if err != nil {
-		http.Error(w, "invalid task id", http.StatusBadRequest)
+		apperror.Write(w, apperror.Validation("invalid task id"))
 
 		return
 	}
@@ -58,7 +60,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 		startFromId, err = strconv.Atoi(s)
 
 		if err != nil {
-			http.Error(w, "invalid start_from_id", http.StatusBadRequest)
+			apperror.Write(w, apperror.Validation("invalid start_from_id"))
 
 			return
 		}
@@ -71,7 +73,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	})
 
 	if err != nil {
-		http.Error(w, "error", http.StatusBadRequest)
+		apperror.Write(w, err)
 
 		return
 	}
@@ -85,7 +87,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	respBody, err := json.Marshal(resp)
 
 	if err != nil {
-		http.Error(w, "json error", http.StatusInternalServerError)
+		apperror.Write(w, err)
 
 		return
 	}
@@ -1,17 +1,36 @@
 package history
 
-import "time"
+import (
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+)
+
+type Event string
+
+const (
+	EditEvent    Event = "edit"
+	ArchiveEvent Event = "archive"
+	RestoreEvent Event = "restore"
+	LabelEvent   Event = "label"
+	UnlabelEvent Event = "unlabel"
+)
 
 type Model struct {
-	Id          int       `json:"id"`
-	TaskId      int       `json:"task_id"`
-	Status      string    `json:"status"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	CreatorId   int       `json:"creator_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	AssigneeId  int       `json:"assignee_id"`
-	TeamId      int       `json:"team_id"`
-	ChangedBy   int       `json:"changed_by"`
-	ChangedAt   time.Time `json:"changed_at"`
+	Id          int           `json:"id"`
+	TaskId      int           `json:"task_id"`
+	Status      string        `json:"status"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	CreatorId   int           `json:"creator_id"`
+	CreatedAt   time.Time     `json:"created_at"`
+	AssigneeIds []int         `json:"assignee_ids"`
+	TeamId      int           `json:"team_id"`
+	ChangedBy   int           `json:"changed_by"`
+	ChangedAt   time.Time     `json:"changed_at"`
+	Event       Event         `json:"event"`
+	DueAt       *time.Time    `json:"due_at"`
+	Priority    task.Priority `json:"priority"`
+	// Label — имя метки для событий label и unlabel.
+	Label *string `json:"label,omitempty"`
 }
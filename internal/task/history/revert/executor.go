@@ -0,0 +1,67 @@
+package revert
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task/edit"
+	"mkk-luna-test-task/internal/task/history"
+)
+
+type historyGetter interface {
+	GetHistoryById(ctx context.Context, id int) (*history.Model, error)
+}
+
+type editor interface {
+	Execute(ctx context.Context, in edit.EditInput) (*edit.EditResult, error)
+}
+
+type executor struct {
+	historyGetter historyGetter
+	editor        editor
+}
+
+func NewExecutor(historyGetter historyGetter, editor editor) *executor {
+	return &executor{
+		historyGetter: historyGetter,
+		editor:        editor,
+	}
+}
+
+type RevertInput struct {
+	UserId    int
+	TaskId    int
+	HistoryId int
+	// IfMatch — версия задачи, которую видел клиент; 0 — клиент версию не проверяет.
+	IfMatch int
+}
+
+// Execute возвращает задаче статус, название, описание и исполнителей из записи истории.
+// Правка идёт обычным путём: с проверкой прав и перехода статуса, записью в историю и обновлением кэша.
+func (e *executor) Execute(ctx context.Context, in RevertInput) (*edit.EditResult, error) {
+	h, err := e.historyGetter.GetHistoryById(ctx, in.HistoryId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("history entry not found")
+		}
+
+		return nil, err
+	}
+
+	if h.TaskId != in.TaskId {
+		return nil, apperror.NotFound("history entry not found")
+	}
+
+	return e.editor.Execute(ctx, edit.EditInput{
+		UserId:      in.UserId,
+		TaskId:      in.TaskId,
+		Status:      &h.Status,
+		Title:       &h.Title,
+		Description: &h.Description,
+		AssigneeIds: &h.AssigneeIds,
+		IfMatch:     in.IfMatch,
+	})
+}
@@ -0,0 +1,148 @@
+package revert
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"mkk-luna-test-task/internal/task/edit"
+	"mkk-luna-test-task/internal/task/history"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubHistoryGetter struct {
+	history *history.Model
+	err     error
+}
+
+func (s *stubHistoryGetter) GetHistoryById(ctx context.Context, id int) (*history.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	if s.history == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	return s.history, nil
+}
+
+type mockEditor struct {
+	err    error
+	called bool
+	in     edit.EditInput
+}
+
+func (m *mockEditor) Execute(ctx context.Context, in edit.EditInput) (*edit.EditResult, error) {
+	m.called = true
+	m.in = in
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &edit.EditResult{
+		Id:          in.TaskId,
+		Status:      *in.Status,
+		Title:       *in.Title,
+		Description: *in.Description,
+		AssigneeIds: *in.AssigneeIds,
+		Version:     4,
+	}, nil
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	snapshot := &history.Model{
+		Id:          7,
+		TaskId:      1,
+		Status:      "todo",
+		Title:       "Прежнее название",
+		Description: "Прежнее описание",
+		AssigneeIds: []int{3, 4},
+	}
+
+	tests := []struct {
+		name          string
+		historyGetter *stubHistoryGetter
+		editor        *mockEditor
+		in            RevertInput
+		want          *edit.EditResult
+		wantEditInput *edit.EditInput
+		expectedErr   string
+	}{
+		{
+			name:          "reverts tracked fields through edit",
+			historyGetter: &stubHistoryGetter{history: snapshot},
+			editor:        &mockEditor{},
+			in:            RevertInput{UserId: 5, TaskId: 1, HistoryId: 7, IfMatch: 3},
+			want: &edit.EditResult{
+				Id:          1,
+				Status:      "todo",
+				Title:       "Прежнее название",
+				Description: "Прежнее описание",
+				AssigneeIds: []int{3, 4},
+				Version:     4,
+			},
+			wantEditInput: &edit.EditInput{
+				UserId:      5,
+				TaskId:      1,
+				Status:      &snapshot.Status,
+				Title:       &snapshot.Title,
+				Description: &snapshot.Description,
+				AssigneeIds: &snapshot.AssigneeIds,
+				IfMatch:     3,
+			},
+		},
+		{
+			name:          "history entry not found",
+			historyGetter: &stubHistoryGetter{},
+			editor:        &mockEditor{},
+			in:            RevertInput{UserId: 5, TaskId: 1, HistoryId: 8},
+			expectedErr:   "history entry not found",
+		},
+		{
+			name:          "history entry of another task",
+			historyGetter: &stubHistoryGetter{history: snapshot},
+			editor:        &mockEditor{},
+			in:            RevertInput{UserId: 5, TaskId: 2, HistoryId: 7},
+			expectedErr:   "history entry not found",
+		},
+		{
+			name:          "edit is forbidden",
+			historyGetter: &stubHistoryGetter{history: snapshot},
+			editor:        &mockEditor{err: permission.ErrForbidden},
+			in:            RevertInput{UserId: 5, TaskId: 1, HistoryId: 7},
+			expectedErr:   "forbidden",
+		},
+		{
+			name:          "db error",
+			historyGetter: &stubHistoryGetter{err: errors.New("db error")},
+			editor:        &mockEditor{},
+			in:            RevertInput{UserId: 5, TaskId: 1, HistoryId: 7},
+			expectedErr:   "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(tt.historyGetter, tt.editor)
+
+			got, err := e.Execute(context.Background(), tt.in)
+
+			if tt.expectedErr != "" {
+				assert.Nil(t, got)
+				assert.EqualError(t, err, tt.expectedErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.True(t, tt.editor.called)
+			assert.Equal(t, *tt.wantEditInput, tt.editor.in)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package revert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/edit"
+)
+
+type response struct {
+	Id          int           `json:"id"`
+	Status      string        `json:"status"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	CreatorId   int           `json:"creator_id"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	AssigneeIds []int         `json:"assignee_ids"`
+	TeamId      int           `json:"team_id"`
+	Version     int           `json:"version"`
+	DueAt       *time.Time    `json:"due_at"`
+	Priority    task.Priority `json:"priority"`
+}
+
+type Executor interface {
+	Execute(ctx context.Context, in RevertInput) (*edit.EditResult, error)
+}
+
+type handler struct {
+	exec Executor
+}
+
+func NewHandler(exec Executor) *handler {
+	return &handler{
+		exec: exec,
+	}
+}
+
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	userIdAny := r.Context().Value("userId")
+
+	userId, ok := userIdAny.(int)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
+	taskId, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("invalid task id"))
+
+		return
+	}
+
+	historyId, err := strconv.Atoi(chi.URLParam(r, "historyId"))
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("invalid history id"))
+
+		return
+	}
+
+	ifMatch, err := task.ParseIfMatch(r.Header.Get("If-Match"))
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	result, err := h.exec.Execute(r.Context(), RevertInput{
+		UserId:    userId,
+		TaskId:    taskId,
+		HistoryId: historyId,
+		IfMatch:   ifMatch,
+	})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	respBody, err := json.Marshal(response{
+		Id:          result.Id,
+		Status:      result.Status,
+		Title:       result.Title,
+		Description: result.Description,
+		CreatorId:   result.CreatorId,
+		CreatedAt:   result.CreatedAt,
+		UpdatedAt:   result.UpdatedAt,
+		AssigneeIds: result.AssigneeIds,
+		TeamId:      result.TeamId,
+		Version:     result.Version,
+		DueAt:       result.DueAt,
+		Priority:    result.Priority,
+	})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", task.ETag(result.Version))
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
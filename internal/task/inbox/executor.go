@@ -0,0 +1,101 @@
+package inbox
+
+import (
+	"context"
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+)
+
+const defaultLimit = 100
+
+type userTaskLister interface {
+	ListUserTasks(ctx context.Context, userId int, relation task.Relation, f task.Filter, limit int) ([]*task.Model, error)
+}
+
+type executor struct {
+	userTaskLister userTaskLister
+}
+
+func NewExecutor(userTaskLister userTaskLister) *executor {
+	return &executor{
+		userTaskLister: userTaskLister,
+	}
+}
+
+type InboxInput struct {
+	UserId   int
+	Relation task.Relation
+	// Filter.TeamId необязателен: 0 — задачи из всех команд пользователя.
+	Filter task.Filter
+}
+
+type InboxResult struct {
+	Tasks      []TaskItem
+	NextCursor string
+	HasMore    bool
+}
+
+type TaskItem struct {
+	Id          int           `json:"id"`
+	Status      string        `json:"status"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	CreatorId   int           `json:"creator_id"`
+	AssigneeIds []int         `json:"assignee_ids"`
+	TeamId      int           `json:"team_id"`
+	Version     int           `json:"version"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	DueAt       *time.Time    `json:"due_at"`
+	Priority    task.Priority `json:"priority"`
+}
+
+// Execute не проверяет права по каждой команде отдельно: членство и task.view учитываются в самом запросе.
+func (e *executor) Execute(ctx context.Context, in InboxInput) (*InboxResult, error) {
+	f := in.Filter
+
+	if f.Sort == "" {
+		f.Sort = task.SortById
+	}
+
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	tasks, err := e.userTaskLister.ListUserTasks(ctx, in.UserId, in.Relation, f, defaultLimit+1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &InboxResult{}
+
+	if len(tasks) > defaultLimit {
+		tasks = tasks[:defaultLimit]
+
+		result.HasMore = true
+		result.NextCursor = task.NewCursor(*tasks[len(tasks)-1], f.Sort, f.Desc).Encode()
+	}
+
+	result.Tasks = make([]TaskItem, 0, len(tasks))
+
+	for _, t := range tasks {
+		result.Tasks = append(result.Tasks, TaskItem{
+			Id:          t.Id,
+			Status:      t.Status,
+			Title:       t.Title,
+			Description: t.Description,
+			CreatorId:   t.CreatorId,
+			AssigneeIds: t.AssigneeIds,
+			TeamId:      t.TeamId,
+			Version:     t.Version,
+			CreatedAt:   t.CreatedAt,
+			UpdatedAt:   t.UpdatedAt,
+			DueAt:       t.DueAt,
+			Priority:    t.Priority,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,109 @@
+package inbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"mkk-luna-test-task/internal/task"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockUserTaskLister struct {
+	total    int
+	err      error
+	userId   int
+	relation task.Relation
+	filter   task.Filter
+}
+
+func (m *mockUserTaskLister) ListUserTasks(ctx context.Context, userId int, relation task.Relation, f task.Filter, limit int) ([]*task.Model, error) {
+	m.userId = userId
+	m.relation = relation
+	m.filter = f
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	tasks := make([]*task.Model, 0, limit)
+
+	for id := 1; id <= m.total && len(tasks) < limit; id++ {
+		tasks = append(tasks, &task.Model{Id: id, TeamId: id%3 + 1, AssigneeIds: []int{userId}})
+	}
+
+	return tasks, nil
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		lister      *mockUserTaskLister
+		in          InboxInput
+		wantLen     int
+		wantHasMore bool
+		wantCursor  *task.Cursor
+		expectedErr string
+	}{
+		{
+			name:    "tasks from all teams",
+			lister:  &mockUserTaskLister{total: 3},
+			in:      InboxInput{UserId: 7},
+			wantLen: 3,
+		},
+		{
+			name:        "first page of many",
+			lister:      &mockUserTaskLister{total: defaultLimit + 5},
+			in:          InboxInput{UserId: 7, Relation: task.AssigneeRelation},
+			wantLen:     defaultLimit,
+			wantHasMore: true,
+			wantCursor:  &task.Cursor{Sort: task.SortById, Id: defaultLimit},
+		},
+		{
+			name:        "cursor from another sort order",
+			lister:      &mockUserTaskLister{},
+			in:          InboxInput{UserId: 7, Filter: task.Filter{Sort: task.SortByUpdatedAt, After: task.IdCursor(3)}},
+			expectedErr: "validation failed",
+		},
+		{
+			name:        "lister error",
+			lister:      &mockUserTaskLister{err: errors.New("db error")},
+			in:          InboxInput{UserId: 7},
+			expectedErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(tt.lister)
+
+			got, err := e.Execute(context.Background(), tt.in)
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Nil(t, got)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.in.UserId, tt.lister.userId)
+			assert.Equal(t, tt.in.Relation, tt.lister.relation)
+			assert.Equal(t, task.SortById, tt.lister.filter.Sort)
+			assert.Len(t, got.Tasks, tt.wantLen)
+			assert.Equal(t, tt.wantHasMore, got.HasMore)
+
+			if tt.wantCursor == nil {
+				assert.Empty(t, got.NextCursor)
+
+				return
+			}
+
+			cursor, err := task.DecodeCursor(got.NextCursor)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCursor, cursor)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package inbox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+)
+
+type response struct {
+	Tasks      []TaskItem `json:"tasks"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+	HasMore    bool       `json:"has_more"`
+}
+
+type Executor interface {
+	Execute(ctx context.Context, in InboxInput) (*InboxResult, error)
+}
+
+type handler struct {
+	exec Executor
+}
+
+func NewHandler(exec Executor) *handler {
+	return &handler{
+		exec: exec,
+	}
+}
+
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	userIdAny := r.Context().Value("userId")
+
+	userId, ok := userIdAny.(int)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
+	query := r.URL.Query()
+
+	relation := task.Relation(query.Get("relation"))
+
+	if relation != task.AnyRelation && relation != task.AssigneeRelation && relation != task.CreatorRelation {
+		apperror.Write(w, apperror.InvalidField("relation", "must be one of: assignee creator"))
+
+		return
+	}
+
+	filter, err := task.ParseFilterQuery(query)
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	if s := query.Get("team_id"); s != "" {
+		filter.TeamId, err = strconv.Atoi(s)
+
+		if err != nil {
+			apperror.Write(w, apperror.Validation("invalid team_id"))
+
+			return
+		}
+	}
+
+	result, err := h.exec.Execute(r.Context(), InboxInput{
+		UserId:   userId,
+		Relation: relation,
+		Filter:   filter,
+	})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	resp := response{
+		Tasks:      result.Tasks,
+		NextCursor: result.NextCursor,
+		HasMore:    result.HasMore,
+	}
+
+	respBody, err := json.Marshal(resp)
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
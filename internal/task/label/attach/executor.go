@@ -0,0 +1,91 @@
+package attach
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/label"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type taskGetter interface {
+	GetTaskById(ctx context.Context, id int) (*task.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type labelGetter interface {
+	GetLabel(ctx context.Context, id int) (*label.Model, error)
+}
+
+type labelAttacher interface {
+	AttachTaskLabel(ctx context.Context, taskId int, l label.Model, changedBy int, changedAt time.Time) error
+}
+
+type executor struct {
+	taskGetter    taskGetter
+	authorizer    authorizer
+	labelGetter   labelGetter
+	labelAttacher labelAttacher
+}
+
+func NewExecutor(taskGetter taskGetter, authorizer authorizer, labelGetter labelGetter, labelAttacher labelAttacher) *executor {
+	return &executor{
+		taskGetter:    taskGetter,
+		authorizer:    authorizer,
+		labelGetter:   labelGetter,
+		labelAttacher: labelAttacher,
+	}
+}
+
+type AttachInput struct {
+	UserId  int
+	TaskId  int
+	LabelId int
+}
+
+// Execute вешает на задачу метку из каталога её команды. Повесить уже висящую метку — не ошибка.
+func (e *executor) Execute(ctx context.Context, in AttachInput) error {
+	t, err := e.taskGetter.GetTaskById(ctx, in.TaskId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.NotFound("task not found")
+		}
+
+		return err
+	}
+
+	_, err = e.authorizer.Authorize(ctx, in.UserId, permission.TaskEdit, permission.TaskResource(*t))
+
+	if err != nil {
+		return err
+	}
+
+	if t.IsArchived() {
+		return task.ErrArchived
+	}
+
+	l, err := e.labelGetter.GetLabel(ctx, in.LabelId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return label.ErrNotFound
+		}
+
+		return err
+	}
+
+	if l.TeamId != t.TeamId {
+		return label.ErrNotFound
+	}
+
+	return e.labelAttacher.AttachTaskLabel(ctx, t.Id, *l, in.UserId, time.Now())
+}
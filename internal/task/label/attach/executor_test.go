@@ -0,0 +1,161 @@
+package attach
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/label"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubTaskGetter struct {
+	task *task.Model
+}
+
+func (s *stubTaskGetter) GetTaskById(ctx context.Context, id int) (*task.Model, error) {
+	if s.task == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	t := *s.task
+
+	return &t, nil
+}
+
+type stubAuthorizer struct {
+	err error
+}
+
+func (s *stubAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
+}
+
+type stubLabelGetter struct {
+	label *label.Model
+}
+
+func (s *stubLabelGetter) GetLabel(ctx context.Context, id int) (*label.Model, error) {
+	if s.label == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	l := *s.label
+
+	return &l, nil
+}
+
+type mockLabelAttacher struct {
+	err      error
+	attached *label.Model
+}
+
+func (m *mockLabelAttacher) AttachTaskLabel(ctx context.Context, taskId int, l label.Model, changedBy int, changedAt time.Time) error {
+	m.attached = &l
+
+	return m.err
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	archivedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	activeTask := &task.Model{Id: 1, TeamId: 10, CreatorId: 5}
+	archivedTask := &task.Model{Id: 1, TeamId: 10, CreatorId: 5, ArchivedAt: &archivedAt}
+
+	bug := &label.Model{Id: 7, TeamId: 10, Name: "bug", Color: "#d73a4a"}
+	foreign := &label.Model{Id: 8, TeamId: 11, Name: "bug", Color: "#d73a4a"}
+
+	tests := []struct {
+		name         string
+		taskGetter   *stubTaskGetter
+		authorizer   *stubAuthorizer
+		labelGetter  *stubLabelGetter
+		attacher     *mockLabelAttacher
+		wantAttached bool
+		expectedErr  string
+	}{
+		{
+			name:         "attached",
+			taskGetter:   &stubTaskGetter{task: activeTask},
+			authorizer:   &stubAuthorizer{},
+			labelGetter:  &stubLabelGetter{label: bug},
+			attacher:     &mockLabelAttacher{},
+			wantAttached: true,
+		},
+		{
+			name:        "task not found",
+			taskGetter:  &stubTaskGetter{},
+			authorizer:  &stubAuthorizer{},
+			labelGetter: &stubLabelGetter{label: bug},
+			attacher:    &mockLabelAttacher{},
+			expectedErr: "task not found",
+		},
+		{
+			name:        "cannot edit task",
+			taskGetter:  &stubTaskGetter{task: activeTask},
+			authorizer:  &stubAuthorizer{err: permission.ErrForbidden},
+			labelGetter: &stubLabelGetter{label: bug},
+			attacher:    &mockLabelAttacher{},
+			expectedErr: "forbidden",
+		},
+		{
+			name:        "archived task",
+			taskGetter:  &stubTaskGetter{task: archivedTask},
+			authorizer:  &stubAuthorizer{},
+			labelGetter: &stubLabelGetter{label: bug},
+			attacher:    &mockLabelAttacher{},
+			expectedErr: "task is archived",
+		},
+		{
+			name:        "label of another team",
+			taskGetter:  &stubTaskGetter{task: activeTask},
+			authorizer:  &stubAuthorizer{},
+			labelGetter: &stubLabelGetter{label: foreign},
+			attacher:    &mockLabelAttacher{},
+			expectedErr: "label not found",
+		},
+		{
+			name:        "unknown label",
+			taskGetter:  &stubTaskGetter{task: activeTask},
+			authorizer:  &stubAuthorizer{},
+			labelGetter: &stubLabelGetter{},
+			attacher:    &mockLabelAttacher{},
+			expectedErr: "label not found",
+		},
+		{
+			name:         "db error",
+			taskGetter:   &stubTaskGetter{task: activeTask},
+			authorizer:   &stubAuthorizer{},
+			labelGetter:  &stubLabelGetter{label: bug},
+			attacher:     &mockLabelAttacher{err: errors.New("db error")},
+			wantAttached: true,
+			expectedErr:  "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(tt.taskGetter, tt.authorizer, tt.labelGetter, tt.attacher)
+
+			err := e.Execute(context.Background(), AttachInput{UserId: 5, TaskId: 1, LabelId: 7})
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantAttached, tt.attacher.attached != nil)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package task
+
+import "mkk-luna-test-task/internal/apperror"
+
+var (
+	// ErrLinkCycle — новая связь замкнула бы подзадачи или блокировки в цикл.
+	ErrLinkCycle = apperror.Conflict("link would create a cycle")
+	// ErrOpenBlockers — задачу нельзя завершить, пока не завершены задачи, которые её блокируют.
+	ErrOpenBlockers = apperror.Conflict("task has open blockers")
+)
+
+// Subtask — задача из дерева подзадач вместе с id её родителя.
+type Subtask struct {
+	ParentId int
+	Task     Model
+}
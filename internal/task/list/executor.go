@@ -2,35 +2,44 @@ package list
 
 import (
 	"context"
-	"fmt"
+	"time"
 
 	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
 )
 
 const defaultLimit = 100
 
 type ListResult struct {
-	Tasks           []TaskItem
+	Tasks []TaskItem
+	// NextStartFromId заполняется только для сортировки по id по возрастанию, ради старых клиентов.
 	NextStartFromId int
+	NextCursor      string
 	HasMore         bool
 }
 
 type TaskItem struct {
-	Id          int    `json:"id"`
-	Status      string `json:"status"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	CreatorId   int    `json:"creator_id"`
-	AssigneeId  int    `json:"assignee_id"`
-	TeamId      int    `json:"team_id"`
+	Id          int           `json:"id"`
+	Status      string        `json:"status"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	CreatorId   int           `json:"creator_id"`
+	AssigneeIds []int         `json:"assignee_ids"`
+	TeamId      int           `json:"team_id"`
+	Version     int           `json:"version"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	DueAt       *time.Time    `json:"due_at"`
+	Priority    task.Priority `json:"priority"`
 }
 
 type taskLister interface {
-	ListTasks(ctx context.Context, teamId int, status string, assigneeId int, startFromId int, limit int) ([]*task.Model, error)
+	ListTasks(ctx context.Context, f task.Filter, limit int) ([]*task.Model, error)
 }
 
-type teamMembershipChecker interface {
-	CheckUserIsInTeam(userId, teamId int) (bool, error)
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
 }
 
 type taskCacheLister interface {
@@ -42,104 +51,81 @@ type taskCacheWriter interface {
 }
 
 type executor struct {
-	taskLister            taskLister
-	teamMembershipChecker teamMembershipChecker
-	cacheLister           taskCacheLister
-	cacheWriter           taskCacheWriter
+	taskLister  taskLister
+	authorizer  authorizer
+	cacheLister taskCacheLister
+	cacheWriter taskCacheWriter
 }
 
 func NewExecutor(
 	taskLister taskLister,
-	teamMembershipChecker teamMembershipChecker,
+	authorizer authorizer,
 	cacheLister taskCacheLister,
 	cacheWriter taskCacheWriter,
 ) *executor {
 	return &executor{
-		taskLister:            taskLister,
-		teamMembershipChecker: teamMembershipChecker,
-		cacheLister:           cacheLister,
-		cacheWriter:           cacheWriter,
+		taskLister:  taskLister,
+		authorizer:  authorizer,
+		cacheLister: cacheLister,
+		cacheWriter: cacheWriter,
 	}
 }
 
 type ListInput struct {
-	UserId      int
-	TeamId      int
-	Status      string
-	AssigneeId  int
-	StartFromId int
+	UserId int
+	Filter task.Filter
 }
 
 func (e *executor) Execute(ctx context.Context, in ListInput) (*ListResult, error) {
-	isMember, err := e.teamMembershipChecker.CheckUserIsInTeam(in.UserId, in.TeamId)
+	f := in.Filter
 
-	if err != nil {
-		return nil, err
+	if f.Sort == "" {
+		f.Sort = task.SortById
 	}
 
-	if !isMember {
-		return nil, fmt.Errorf("forbidden")
+	if err := f.Validate(); err != nil {
+		return nil, err
 	}
 
-	listFromCache := (in.Status == "" && in.AssigneeId == 0)
+	_, err := e.authorizer.Authorize(ctx, in.UserId, permission.TaskView, permission.TeamResource(f.TeamId))
 
-	var tasks []*task.Model
-	hasMore := false
-	nextStartFromId := 0
+	if err != nil {
+		return nil, err
+	}
 
 	// Предполагаю, что самое нагруженное это не поиск, поэтому кэш только простого отображения.
+	listFromCache := f.IsDefault()
+
 	if listFromCache && e.cacheLister != nil {
 		var startFromID *int = nil
 
-		if in.StartFromId > 0 {
-			startFromID = &in.StartFromId
+		if f.After != nil {
+			startFromID = &f.After.Id
 		}
 
-		cachedTasks, hit, err := e.cacheLister.ListTasksFromCache(ctx, in.TeamId, defaultLimit+1, startFromID)
+		cachedTasks, hit, err := e.cacheLister.ListTasksFromCache(ctx, f.TeamId, defaultLimit+1, startFromID)
 
 		if err != nil {
 			return nil, err
 		}
 
 		if hit && cachedTasks != nil {
-			results := cachedTasks
+			tasks := make([]*task.Model, 0, len(cachedTasks))
 
-			if len(cachedTasks) > defaultLimit {
-				hasMore = true
-				nextStartFromId = cachedTasks[defaultLimit].Id
-				results = cachedTasks[:defaultLimit]
+			for i := range cachedTasks {
+				tasks = append(tasks, &cachedTasks[i])
 			}
 
-			items := make([]TaskItem, 0, len(results))
-
-			for _, t := range results {
-				items = append(items, TaskItem{
-					Id:          t.Id,
-					Status:      t.Status,
-					Title:       t.Title,
-					Description: t.Description,
-					CreatorId:   t.CreatorId,
-					AssigneeId:  t.AssigneeId,
-					TeamId:      t.TeamId,
-				})
-			}
-
-			return &ListResult{
-				Tasks:           items,
-				NextStartFromId: nextStartFromId,
-				HasMore:         hasMore,
-			}, nil
+			return newListResult(tasks, f), nil
 		}
 	}
 
-	dbTasks, err := e.taskLister.ListTasks(ctx, in.TeamId, in.Status, in.AssigneeId, in.StartFromId, defaultLimit+1)
+	tasks, err := e.taskLister.ListTasks(ctx, f, defaultLimit+1)
 
 	if err != nil {
 		return nil, err
 	}
 
-	tasks = dbTasks
-
 	if listFromCache {
 		modelTasks := make([]task.Model, 0, len(tasks))
 
@@ -155,34 +141,46 @@ func (e *executor) Execute(ctx context.Context, in ListInput) (*ListResult, erro
 			}
 		}
 
-		_ = e.cacheWriter.CacheTasks(ctx, in.TeamId, modelTasks)
+		_ = e.cacheWriter.CacheTasks(ctx, f.TeamId, modelTasks)
 	}
 
-	results := tasks
+	return newListResult(tasks, f), nil
+}
+
+// newListResult отрезает лишнюю задачу, запрошенную для has_more, и строит курсор на последнюю выданную.
+func newListResult(tasks []*task.Model, f task.Filter) *ListResult {
+	result := &ListResult{}
 
 	if len(tasks) > defaultLimit {
-		hasMore = true
-		nextStartFromId = tasks[defaultLimit].Id
-		results = tasks[:defaultLimit]
+		tasks = tasks[:defaultLimit]
+		last := tasks[len(tasks)-1]
+
+		result.HasMore = true
+		result.NextCursor = task.NewCursor(*last, f.Sort, f.Desc).Encode()
+
+		if f.Sort == task.SortById && !f.Desc {
+			result.NextStartFromId = last.Id
+		}
 	}
 
-	items := make([]TaskItem, 0, len(results))
+	result.Tasks = make([]TaskItem, 0, len(tasks))
 
-	for _, t := range results {
-		items = append(items, TaskItem{
+	for _, t := range tasks {
+		result.Tasks = append(result.Tasks, TaskItem{
 			Id:          t.Id,
 			Status:      t.Status,
 			Title:       t.Title,
 			Description: t.Description,
 			CreatorId:   t.CreatorId,
-			AssigneeId:  t.AssigneeId,
+			AssigneeIds: t.AssigneeIds,
 			TeamId:      t.TeamId,
+			Version:     t.Version,
+			CreatedAt:   t.CreatedAt,
+			UpdatedAt:   t.UpdatedAt,
+			DueAt:       t.DueAt,
+			Priority:    t.Priority,
 		})
 	}
 
-	return &ListResult{
-		Tasks:           items,
-		NextStartFromId: nextStartFromId,
-		HasMore:         hasMore,
-	}, nil
+	return result
 }
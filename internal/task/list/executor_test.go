@@ -4,15 +4,18 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
 
 	"github.com/stretchr/testify/assert"
 )
 
 type stubTaskListerSuccess struct{}
 
-func (s *stubTaskListerSuccess) ListTasks(ctx context.Context, teamId int, status string, assigneeId int, startFromId int, limit int) ([]*task.Model, error) {
+func (s *stubTaskListerSuccess) ListTasks(ctx context.Context, f task.Filter, limit int) ([]*task.Model, error) {
 	return []*task.Model{
 		{
 			Id:          1,
@@ -20,8 +23,8 @@ func (s *stubTaskListerSuccess) ListTasks(ctx context.Context, teamId int, statu
 			Title:       "Test Task 1",
 			Description: "Description 1",
 			CreatorId:   10,
-			AssigneeId:  20,
-			TeamId:      teamId,
+			AssigneeIds: []int{20},
+			TeamId:      f.TeamId,
 		},
 		{
 			Id:          2,
@@ -29,40 +32,40 @@ func (s *stubTaskListerSuccess) ListTasks(ctx context.Context, teamId int, statu
 			Title:       "Test Task 2",
 			Description: "Description 2",
 			CreatorId:   11,
-			AssigneeId:  21,
-			TeamId:      teamId,
+			AssigneeIds: []int{21},
+			TeamId:      f.TeamId,
 		},
 	}, nil
 }
 
 type stubTaskListerEmpty struct{}
 
-func (s *stubTaskListerEmpty) ListTasks(ctx context.Context, teamId int, status string, assigneeId int, startFromId int, limit int) ([]*task.Model, error) {
+func (s *stubTaskListerEmpty) ListTasks(ctx context.Context, f task.Filter, limit int) ([]*task.Model, error) {
 	return []*task.Model{}, nil
 }
 
 type stubTaskListerError struct{}
 
-func (s *stubTaskListerError) ListTasks(ctx context.Context, teamId int, status string, assigneeId int, startFromId int, limit int) ([]*task.Model, error) {
+func (s *stubTaskListerError) ListTasks(ctx context.Context, f task.Filter, limit int) ([]*task.Model, error) {
 	return nil, errors.New("db error")
 }
 
-type stubTeamMembershipCheckerAllow struct{}
+type stubAuthorizerAllow struct{}
 
-func (s *stubTeamMembershipCheckerAllow) CheckUserIsInTeam(userId, teamId int) (bool, error) {
-	return true, nil
+func (s *stubAuthorizerAllow) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
 }
 
-type stubTeamMembershipCheckerDeny struct{}
+type stubAuthorizerDeny struct{}
 
-func (s *stubTeamMembershipCheckerDeny) CheckUserIsInTeam(userId, teamId int) (bool, error) {
-	return false, nil
+func (s *stubAuthorizerDeny) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	return nil, permission.ErrForbidden
 }
 
-type stubTeamMembershipCheckerError struct{}
+type stubAuthorizerError struct{}
 
-func (s *stubTeamMembershipCheckerError) CheckUserIsInTeam(userId, teamId int) (bool, error) {
-	return false, errors.New("membership check error")
+func (s *stubAuthorizerError) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	return nil, errors.New("membership check error")
 }
 
 type mockTaskCacheLister struct {
@@ -101,10 +104,10 @@ func (c *mockTaskCacheWriter) CacheTasks(ctx context.Context, teamId int, tasks
 
 func TestExecutor_Execute(t *testing.T) {
 	type fields struct {
-		taskLister            taskLister
-		teamMembershipChecker teamMembershipChecker
-		cacheLister           taskCacheLister
-		cacheWriter           taskCacheWriter
+		taskLister  taskLister
+		authorizer  authorizer
+		cacheLister taskCacheLister
+		cacheWriter taskCacheWriter
 	}
 	type args struct {
 		ctx context.Context
@@ -122,19 +125,16 @@ func TestExecutor_Execute(t *testing.T) {
 		{
 			name: "success - multiple tasks",
 			fields: fields{
-				taskLister:            &stubTaskListerSuccess{},
-				teamMembershipChecker: &stubTeamMembershipCheckerAllow{},
-				cacheLister:           &mockTaskCacheLister{},
-				cacheWriter:           &mockTaskCacheWriter{},
+				taskLister:  &stubTaskListerSuccess{},
+				authorizer:  &stubAuthorizerAllow{},
+				cacheLister: &mockTaskCacheLister{},
+				cacheWriter: &mockTaskCacheWriter{},
 			},
 			args: args{
 				ctx: context.Background(),
 				in: ListInput{
-					UserId:      100,
-					TeamId:      1,
-					Status:      "",
-					AssigneeId:  0,
-					StartFromId: 0,
+					UserId: 100,
+					Filter: task.Filter{TeamId: 1},
 				},
 			},
 			want: &ListResult{
@@ -145,7 +145,7 @@ func TestExecutor_Execute(t *testing.T) {
 						Title:       "Test Task 1",
 						Description: "Description 1",
 						CreatorId:   10,
-						AssigneeId:  20,
+						AssigneeIds: []int{20},
 						TeamId:      1,
 					},
 					{
@@ -154,7 +154,7 @@ func TestExecutor_Execute(t *testing.T) {
 						Title:       "Test Task 2",
 						Description: "Description 2",
 						CreatorId:   11,
-						AssigneeId:  21,
+						AssigneeIds: []int{21},
 						TeamId:      1,
 					},
 				},
@@ -166,19 +166,16 @@ func TestExecutor_Execute(t *testing.T) {
 		{
 			name: "success - no tasks",
 			fields: fields{
-				taskLister:            &stubTaskListerEmpty{},
-				teamMembershipChecker: &stubTeamMembershipCheckerAllow{},
-				cacheLister:           &mockTaskCacheLister{},
-				cacheWriter:           &mockTaskCacheWriter{},
+				taskLister:  &stubTaskListerEmpty{},
+				authorizer:  &stubAuthorizerAllow{},
+				cacheLister: &mockTaskCacheLister{},
+				cacheWriter: &mockTaskCacheWriter{},
 			},
 			args: args{
 				ctx: context.Background(),
 				in: ListInput{
-					UserId:      101,
-					TeamId:      1,
-					Status:      "",
-					AssigneeId:  0,
-					StartFromId: 0,
+					UserId: 101,
+					Filter: task.Filter{TeamId: 1},
 				},
 			},
 			want: &ListResult{
@@ -191,19 +188,16 @@ func TestExecutor_Execute(t *testing.T) {
 		{
 			name: "fail - forbidden by team membership",
 			fields: fields{
-				taskLister:            &stubTaskListerSuccess{},
-				teamMembershipChecker: &stubTeamMembershipCheckerDeny{},
-				cacheLister:           &mockTaskCacheLister{},
-				cacheWriter:           &mockTaskCacheWriter{},
+				taskLister:  &stubTaskListerSuccess{},
+				authorizer:  &stubAuthorizerDeny{},
+				cacheLister: &mockTaskCacheLister{},
+				cacheWriter: &mockTaskCacheWriter{},
 			},
 			args: args{
 				ctx: context.Background(),
 				in: ListInput{
-					UserId:      999,
-					TeamId:      8,
-					Status:      "",
-					AssigneeId:  0,
-					StartFromId: 0,
+					UserId: 999,
+					Filter: task.Filter{TeamId: 8},
 				},
 			},
 			want:        nil,
@@ -213,19 +207,16 @@ func TestExecutor_Execute(t *testing.T) {
 		{
 			name: "fail - error checking team membership",
 			fields: fields{
-				taskLister:            &stubTaskListerSuccess{},
-				teamMembershipChecker: &stubTeamMembershipCheckerError{},
-				cacheLister:           &mockTaskCacheLister{},
-				cacheWriter:           &mockTaskCacheWriter{},
+				taskLister:  &stubTaskListerSuccess{},
+				authorizer:  &stubAuthorizerError{},
+				cacheLister: &mockTaskCacheLister{},
+				cacheWriter: &mockTaskCacheWriter{},
 			},
 			args: args{
 				ctx: context.Background(),
 				in: ListInput{
-					UserId:      888,
-					TeamId:      7,
-					Status:      "",
-					AssigneeId:  0,
-					StartFromId: 0,
+					UserId: 888,
+					Filter: task.Filter{TeamId: 7},
 				},
 			},
 			want:        nil,
@@ -235,19 +226,16 @@ func TestExecutor_Execute(t *testing.T) {
 		{
 			name: "fail - lister returns error",
 			fields: fields{
-				taskLister:            &stubTaskListerError{},
-				teamMembershipChecker: &stubTeamMembershipCheckerAllow{},
-				cacheLister:           &mockTaskCacheLister{},
-				cacheWriter:           &mockTaskCacheWriter{},
+				taskLister:  &stubTaskListerError{},
+				authorizer:  &stubAuthorizerAllow{},
+				cacheLister: &mockTaskCacheLister{},
+				cacheWriter: &mockTaskCacheWriter{},
 			},
 			args: args{
 				ctx: context.Background(),
 				in: ListInput{
-					UserId:      123,
-					TeamId:      3,
-					Status:      "",
-					AssigneeId:  0,
-					StartFromId: 0,
+					UserId: 123,
+					Filter: task.Filter{TeamId: 3},
 				},
 			},
 			want:        nil,
@@ -257,15 +245,15 @@ func TestExecutor_Execute(t *testing.T) {
 		{
 			name: "success - cache hit returns tasks",
 			fields: fields{
-				taskLister:            &stubTaskListerSuccess{},
-				teamMembershipChecker: &stubTeamMembershipCheckerAllow{},
+				taskLister: &stubTaskListerSuccess{},
+				authorizer: &stubAuthorizerAllow{},
 				cacheLister: &mockTaskCacheLister{
 					tasks: []task.Model{
 						{
-							Id: 101, Status: "open", Title: "Cached Task 1", Description: "DescA", CreatorId: 1, AssigneeId: 2, TeamId: 5,
+							Id: 101, Status: "open", Title: "Cached Task 1", Description: "DescA", CreatorId: 1, AssigneeIds: []int{2}, TeamId: 5,
 						},
 						{
-							Id: 102, Status: "closed", Title: "Cached Task 2", Description: "DescB", CreatorId: 3, AssigneeId: 4, TeamId: 5,
+							Id: 102, Status: "closed", Title: "Cached Task 2", Description: "DescB", CreatorId: 3, AssigneeIds: []int{4}, TeamId: 5,
 						},
 					},
 					hit: true,
@@ -275,20 +263,17 @@ func TestExecutor_Execute(t *testing.T) {
 			args: args{
 				ctx: context.Background(),
 				in: ListInput{
-					UserId:      1,
-					TeamId:      5,
-					Status:      "",
-					AssigneeId:  0,
-					StartFromId: 0,
+					UserId: 1,
+					Filter: task.Filter{TeamId: 5},
 				},
 			},
 			want: &ListResult{
 				Tasks: []TaskItem{
 					{
-						Id: 101, Status: "open", Title: "Cached Task 1", Description: "DescA", CreatorId: 1, AssigneeId: 2, TeamId: 5,
+						Id: 101, Status: "open", Title: "Cached Task 1", Description: "DescA", CreatorId: 1, AssigneeIds: []int{2}, TeamId: 5,
 					},
 					{
-						Id: 102, Status: "closed", Title: "Cached Task 2", Description: "DescB", CreatorId: 3, AssigneeId: 4, TeamId: 5,
+						Id: 102, Status: "closed", Title: "Cached Task 2", Description: "DescB", CreatorId: 3, AssigneeIds: []int{4}, TeamId: 5,
 					},
 				},
 				NextStartFromId: 0,
@@ -303,8 +288,8 @@ func TestExecutor_Execute(t *testing.T) {
 			fields: func() fields {
 				writer := &mockTaskCacheWriter{}
 				return fields{
-					taskLister:            &stubTaskListerSuccess{},
-					teamMembershipChecker: &stubTeamMembershipCheckerAllow{},
+					taskLister: &stubTaskListerSuccess{},
+					authorizer: &stubAuthorizerAllow{},
 					cacheLister: &mockTaskCacheLister{
 						tasks: nil, hit: false,
 					},
@@ -314,11 +299,8 @@ func TestExecutor_Execute(t *testing.T) {
 			args: args{
 				ctx: context.Background(),
 				in: ListInput{
-					UserId:      222,
-					TeamId:      77,
-					Status:      "",
-					AssigneeId:  0,
-					StartFromId: 0,
+					UserId: 222,
+					Filter: task.Filter{TeamId: 77},
 				},
 			},
 			want: &ListResult{
@@ -329,7 +311,7 @@ func TestExecutor_Execute(t *testing.T) {
 						Title:       "Test Task 1",
 						Description: "Description 1",
 						CreatorId:   10,
-						AssigneeId:  20,
+						AssigneeIds: []int{20},
 						TeamId:      77,
 					},
 					{
@@ -338,7 +320,7 @@ func TestExecutor_Execute(t *testing.T) {
 						Title:       "Test Task 2",
 						Description: "Description 2",
 						CreatorId:   11,
-						AssigneeId:  21,
+						AssigneeIds: []int{21},
 						TeamId:      77,
 					},
 				},
@@ -351,8 +333,8 @@ func TestExecutor_Execute(t *testing.T) {
 		{
 			name: "success - does not use cache if Status specified (search)",
 			fields: fields{
-				taskLister:            &stubTaskListerSuccess{},
-				teamMembershipChecker: &stubTeamMembershipCheckerAllow{},
+				taskLister: &stubTaskListerSuccess{},
+				authorizer: &stubAuthorizerAllow{},
 				cacheLister: &mockTaskCacheLister{
 					tasks: nil, hit: false,
 				},
@@ -361,11 +343,8 @@ func TestExecutor_Execute(t *testing.T) {
 			args: args{
 				ctx: context.Background(),
 				in: ListInput{
-					UserId:      333,
-					TeamId:      42,
-					Status:      "open",
-					AssigneeId:  0,
-					StartFromId: 0,
+					UserId: 333,
+					Filter: task.Filter{TeamId: 42, Statuses: []string{"open"}},
 				},
 			},
 			want: &ListResult{
@@ -376,7 +355,7 @@ func TestExecutor_Execute(t *testing.T) {
 						Title:       "Test Task 1",
 						Description: "Description 1",
 						CreatorId:   10,
-						AssigneeId:  20,
+						AssigneeIds: []int{20},
 						TeamId:      42,
 					},
 					{
@@ -385,7 +364,7 @@ func TestExecutor_Execute(t *testing.T) {
 						Title:       "Test Task 2",
 						Description: "Description 2",
 						CreatorId:   11,
-						AssigneeId:  21,
+						AssigneeIds: []int{21},
 						TeamId:      42,
 					},
 				},
@@ -399,10 +378,10 @@ func TestExecutor_Execute(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			e := &executor{
-				taskLister:            tt.fields.taskLister,
-				teamMembershipChecker: tt.fields.teamMembershipChecker,
-				cacheLister:           tt.fields.cacheLister,
-				cacheWriter:           tt.fields.cacheWriter,
+				taskLister:  tt.fields.taskLister,
+				authorizer:  tt.fields.authorizer,
+				cacheLister: tt.fields.cacheLister,
+				cacheWriter: tt.fields.cacheWriter,
 			}
 
 			got, err := e.Execute(tt.args.ctx, tt.args.in)
@@ -428,9 +407,9 @@ func TestExecutor_Execute(t *testing.T) {
 			} else {
 				cacheLister, _ := tt.fields.cacheLister.(*mockTaskCacheLister)
 
-				if !cacheLister.hit && tt.args.in.Status == "" {
+				if !cacheLister.hit && len(tt.args.in.Filter.Statuses) == 0 {
 					assert.True(t, writer.called)
-					assert.Equal(t, tt.args.in.TeamId, writer.teamId)
+					assert.Equal(t, tt.args.in.Filter.TeamId, writer.teamId)
 
 					if len(tt.want.Tasks) > 0 {
 						assert.NotEmpty(t, writer.tasksWritten)
@@ -442,3 +421,102 @@ func TestExecutor_Execute(t *testing.T) {
 		})
 	}
 }
+
+type mockTaskListerPages struct {
+	total  int
+	filter task.Filter
+}
+
+func (m *mockTaskListerPages) ListTasks(ctx context.Context, f task.Filter, limit int) ([]*task.Model, error) {
+	m.filter = f
+
+	tasks := make([]*task.Model, 0, limit)
+
+	for id := 1; id <= m.total && len(tasks) < limit; id++ {
+		tasks = append(tasks, &task.Model{Id: id, TeamId: f.TeamId, UpdatedAt: time.Unix(int64(1000-id), 0)})
+	}
+
+	return tasks, nil
+}
+
+func TestExecutor_Execute_Pagination(t *testing.T) {
+	tests := []struct {
+		name            string
+		filter          task.Filter
+		total           int
+		wantHasMore     bool
+		wantStartFromId int
+		wantCursor      *task.Cursor
+		expectedErr     string
+	}{
+		{
+			name:            "next page starts after the last returned task",
+			filter:          task.Filter{TeamId: 1, Statuses: []string{"todo"}},
+			total:           defaultLimit + 1,
+			wantHasMore:     true,
+			wantStartFromId: defaultLimit,
+			wantCursor:      &task.Cursor{Sort: task.SortById, Id: defaultLimit},
+		},
+		{
+			name:        "cursor keeps sort value for time sorting",
+			filter:      task.Filter{TeamId: 1, Sort: task.SortByUpdatedAt, Desc: true},
+			total:       defaultLimit + 1,
+			wantHasMore: true,
+			wantCursor: &task.Cursor{
+				Sort: task.SortByUpdatedAt,
+				Desc: true,
+				Key:  time.Unix(int64(1000-defaultLimit), 0).UTC().Format(time.RFC3339Nano),
+				Id:   defaultLimit,
+			},
+		},
+		{
+			name:   "last page has no cursor",
+			filter: task.Filter{TeamId: 1, Sort: task.SortByCreatedAt},
+			total:  3,
+		},
+		{
+			name:        "unknown sort field",
+			filter:      task.Filter{TeamId: 1, Sort: "title"},
+			expectedErr: "validation failed",
+		},
+		{
+			name:        "cursor from another sort order",
+			filter:      task.Filter{TeamId: 1, Sort: task.SortByCreatedAt, After: task.IdCursor(5)},
+			expectedErr: "validation failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lister := &mockTaskListerPages{total: tt.total}
+			writer := &mockTaskCacheWriter{}
+
+			e := NewExecutor(lister, &stubAuthorizerAllow{}, &mockTaskCacheLister{}, writer)
+
+			got, err := e.Execute(context.Background(), ListInput{UserId: 1, Filter: tt.filter})
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Nil(t, got)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHasMore, got.HasMore)
+			assert.Equal(t, tt.wantStartFromId, got.NextStartFromId)
+			assert.False(t, writer.called, "filtered and sorted lists must not be cached")
+
+			if tt.wantCursor == nil {
+				assert.Empty(t, got.NextCursor)
+
+				return
+			}
+
+			cursor, err := task.DecodeCursor(got.NextCursor)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCursor, cursor)
+		})
+	}
+}
@@ -5,11 +5,15 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
 )
 
 type response struct {
 	Tasks           []TaskItem `json:"tasks"`
 	NextStartFromId int        `json:"next_start_from_id,omitempty"`
+	NextCursor      string     `json:"next_cursor,omitempty"`
 	HasMore         bool       `json:"has_more"`
 }
 
@@ -33,7 +37,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	userId, ok := userIdAny.(int)
 
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
 
 		return
 	}
@@ -43,7 +47,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	teamIdStr := query.Get("team_id")
 
 	if teamIdStr == "" {
-		http.Error(w, "team_id is required", http.StatusBadRequest)
+		apperror.Write(w, apperror.Validation("team_id is required"))
 
 		return
 	}
@@ -51,47 +55,28 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	teamId, err := strconv.Atoi(teamIdStr)
 
 	if err != nil {
-		http.Error(w, "invalid team_id", http.StatusBadRequest)
+		apperror.Write(w, apperror.Validation("invalid team_id"))
 
 		return
 	}
 
-	status := query.Get("status")
-
-	assigneeId := 0
-
-	if s := query.Get("assignee_id"); s != "" {
-		assigneeId, err = strconv.Atoi(s)
+	filter, err := task.ParseFilterQuery(query)
 
-		if err != nil {
-			http.Error(w, "invalid assignee_id", http.StatusBadRequest)
+	if err != nil {
+		apperror.Write(w, err)
 
-			return
-		}
+		return
 	}
 
-	startFromId := 0
-
-	if s := query.Get("start_from_id"); s != "" {
-		startFromId, err = strconv.Atoi(s)
-
-		if err != nil {
-			http.Error(w, "invalid start_from_id", http.StatusBadRequest)
-
-			return
-		}
-	}
+	filter.TeamId = teamId
 
 	result, err := h.exec.Execute(r.Context(), ListInput{
-		UserId:      userId,
-		TeamId:      teamId,
-		Status:      status,
-		AssigneeId:  assigneeId,
-		StartFromId: startFromId,
+		UserId: userId,
+		Filter: filter,
 	})
 
 	if err != nil {
-		http.Error(w, "error", http.StatusBadRequest)
+		apperror.Write(w, err)
 
 		return
 	}
@@ -99,13 +84,14 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	resp := response{
 		Tasks:           result.Tasks,
 		NextStartFromId: result.NextStartFromId,
+		NextCursor:      result.NextCursor,
 		HasMore:         result.HasMore,
 	}
 
 	respBody, err := json.Marshal(resp)
 
 	if err != nil {
-		http.Error(w, "error", http.StatusInternalServerError)
+		apperror.Write(w, err)
 
 		return
 	}
@@ -1,6 +1,19 @@
 package task
 
-import "time"
+import (
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+// ErrVersionMismatch возвращается, если задачу изменили после того, как клиент прочитал её версию.
+var ErrVersionMismatch = apperror.PreconditionFailed("task has been modified")
+
+// Архивную задачу нельзя менять и комментировать, пока её не восстановят.
+var (
+	ErrArchived    = apperror.Conflict("task is archived")
+	ErrNotArchived = apperror.Conflict("task is not archived")
+)
 
 type Model struct {
 	Id          int
@@ -9,6 +22,21 @@ type Model struct {
 	Description string
 	CreatorId   int
 	CreatedAt   time.Time
-	AssigneeId  int
+	UpdatedAt   time.Time
+	// AssigneeIds — исполнители по возрастанию id; пустой список — задача ни на кого не назначена.
+	AssigneeIds []int
 	TeamId      int
+	Version     int
+	ArchivedAt  *time.Time
+	DueAt       *time.Time
+	Priority    Priority
+}
+
+func (m Model) IsArchived() bool {
+	return m.ArchivedAt != nil
+}
+
+// IsPastDue — срок задачи прошёл; завершена ли она, решает процесс команды.
+func (m Model) IsPastDue(now time.Time) bool {
+	return m.DueAt != nil && m.DueAt.Before(now)
 }
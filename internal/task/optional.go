@@ -0,0 +1,20 @@
+package task
+
+import "encoding/json"
+
+// Optional отличает поле, которого нет в JSON-теле, от поля с явным null:
+// для PATCH отсутствие значит «не менять», а null — «очистить».
+type Optional[T any] struct {
+	Set   bool
+	Value T
+}
+
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{Set: true, Value: v}
+}
+
+func (o *Optional[T]) UnmarshalJSON(b []byte) error {
+	o.Set = true
+
+	return json.Unmarshal(b, &o.Value)
+}
@@ -0,0 +1,80 @@
+package overdue
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+)
+
+// Сколько задач отмечаем за один запрос; остальные доберём следующими пачками того же прохода.
+const batchSize = 100
+
+type overdueMarker interface {
+	MarkOverdueTasks(ctx context.Context, now time.Time, limit int) ([]*task.Model, error)
+}
+
+type notifier interface {
+	NotifyOverdue(ctx context.Context, t task.Model) error
+}
+
+// Job периодически находит задачи с прошедшим сроком и уведомляет о каждой один раз.
+type Job struct {
+	marker   overdueMarker
+	notifier notifier
+	interval time.Duration
+}
+
+func NewJob(marker overdueMarker, notifier notifier, interval time.Duration) *Job {
+	return &Job{
+		marker:   marker,
+		notifier: notifier,
+		interval: interval,
+	}
+}
+
+// Run работает до отмены контекста; ошибка прохода не останавливает задачу, следующий проход повторит попытку.
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+
+	defer ticker.Stop()
+
+	for {
+		if _, err := j.RunOnce(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("overdue job error: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce отмечает все просроченные на момент now задачи и возвращает, сколько из них отмечено.
+// Отметка ставится до уведомления: неудачное уведомление не повторяется, зато и не приходит дважды.
+func (j *Job) RunOnce(ctx context.Context, now time.Time) (int, error) {
+	total := 0
+
+	for {
+		tasks, err := j.marker.MarkOverdueTasks(ctx, now, batchSize)
+
+		total += len(tasks)
+
+		for _, t := range tasks {
+			if err := j.notifier.NotifyOverdue(ctx, *t); err != nil {
+				log.Printf("overdue notification for task %d failed: %v", t.Id, err)
+			}
+		}
+
+		if err != nil {
+			return total, err
+		}
+
+		if len(tasks) < batchSize {
+			return total, nil
+		}
+	}
+}
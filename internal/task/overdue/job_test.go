@@ -0,0 +1,142 @@
+package overdue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubMarker отдаёт заранее заготовленные пачки по одной на вызов.
+type stubMarker struct {
+	batches [][]*task.Model
+	err     error
+	calls   int
+}
+
+func (s *stubMarker) MarkOverdueTasks(ctx context.Context, now time.Time, limit int) ([]*task.Model, error) {
+	s.calls++
+
+	if len(s.batches) == 0 {
+		return nil, s.err
+	}
+
+	batch := s.batches[0]
+	s.batches = s.batches[1:]
+
+	return batch, nil
+}
+
+type mockNotifier struct {
+	err      error
+	notified []int
+}
+
+func (m *mockNotifier) NotifyOverdue(ctx context.Context, t task.Model) error {
+	m.notified = append(m.notified, t.Id)
+
+	return m.err
+}
+
+func tasks(from, n int) []*task.Model {
+	result := make([]*task.Model, 0, n)
+
+	for i := 0; i < n; i++ {
+		result = append(result, &task.Model{Id: from + i})
+	}
+
+	return result
+}
+
+func TestJob_RunOnce(t *testing.T) {
+	tests := []struct {
+		name         string
+		marker       *stubMarker
+		notifier     *mockNotifier
+		wantCount    int
+		wantCalls    int
+		wantNotified int
+		expectedErr  string
+	}{
+		{
+			name:      "nothing overdue",
+			marker:    &stubMarker{},
+			notifier:  &mockNotifier{},
+			wantCalls: 1,
+		},
+		{
+			name:         "partial batch ends the pass",
+			marker:       &stubMarker{batches: [][]*task.Model{tasks(1, 3)}},
+			notifier:     &mockNotifier{},
+			wantCount:    3,
+			wantCalls:    1,
+			wantNotified: 3,
+		},
+		{
+			name:         "full batch asks for the next one",
+			marker:       &stubMarker{batches: [][]*task.Model{tasks(1, batchSize), tasks(batchSize+1, 2)}},
+			notifier:     &mockNotifier{},
+			wantCount:    batchSize + 2,
+			wantCalls:    2,
+			wantNotified: batchSize + 2,
+		},
+		{
+			name:         "failed notification does not stop the pass",
+			marker:       &stubMarker{batches: [][]*task.Model{tasks(1, 2)}},
+			notifier:     &mockNotifier{err: errors.New("smtp down")},
+			wantCount:    2,
+			wantCalls:    1,
+			wantNotified: 2,
+		},
+		{
+			name:        "marker error",
+			marker:      &stubMarker{err: errors.New("db error")},
+			notifier:    &mockNotifier{},
+			wantCalls:   1,
+			expectedErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := NewJob(tt.marker, tt.notifier, time.Minute)
+
+			got, err := j.RunOnce(context.Background(), time.Now())
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantCount, got)
+			assert.Equal(t, tt.wantCalls, tt.marker.calls)
+			assert.Len(t, tt.notifier.notified, tt.wantNotified)
+		})
+	}
+}
+
+func TestJob_RunStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	marker := &stubMarker{}
+
+	done := make(chan struct{})
+
+	go func() {
+		NewJob(marker, &mockNotifier{}, time.Hour).Run(ctx)
+		close(done)
+	}()
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("job did not stop after cancel")
+	}
+}
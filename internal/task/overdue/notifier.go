@@ -0,0 +1,40 @@
+package overdue
+
+import (
+	"context"
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/user/notification"
+)
+
+type watcherLister interface {
+	ListTaskWatchers(ctx context.Context, taskId int) ([]int, error)
+}
+
+type notificationCreator interface {
+	CreateNotifications(ctx context.Context, notifications []notification.Model) error
+}
+
+// inAppNotifier сообщает о просрочке исполнителям и наблюдателям задачи в центре уведомлений.
+type inAppNotifier struct {
+	watcherLister       watcherLister
+	notificationCreator notificationCreator
+}
+
+func NewNotifier(watcherLister watcherLister, notificationCreator notificationCreator) *inAppNotifier {
+	return &inAppNotifier{
+		watcherLister:       watcherLister,
+		notificationCreator: notificationCreator,
+	}
+}
+
+func (n *inAppNotifier) NotifyOverdue(ctx context.Context, t task.Model) error {
+	watcherIds, err := n.watcherLister.ListTaskWatchers(ctx, t.Id)
+
+	if err != nil {
+		return err
+	}
+
+	return n.notificationCreator.CreateNotifications(ctx, notification.Overdue(t, watcherIds, time.Now()))
+}
@@ -0,0 +1,87 @@
+package overdue
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/user/notification"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubWatcherLister struct {
+	watcherIds []int
+	err        error
+}
+
+func (s *stubWatcherLister) ListTaskWatchers(ctx context.Context, taskId int) ([]int, error) {
+	return s.watcherIds, s.err
+}
+
+type mockNotificationCreator struct {
+	err           error
+	notifications []notification.Model
+}
+
+func (m *mockNotificationCreator) CreateNotifications(ctx context.Context, notifications []notification.Model) error {
+	m.notifications = append(m.notifications, notifications...)
+
+	return m.err
+}
+
+func TestInAppNotifier_NotifyOverdue(t *testing.T) {
+	tests := []struct {
+		name         string
+		watchers     *stubWatcherLister
+		creator      *mockNotificationCreator
+		wantNotified []int
+		expectedErr  string
+	}{
+		{
+			name:         "assignees and watchers",
+			watchers:     &stubWatcherLister{watcherIds: []int{3, 4}},
+			creator:      &mockNotificationCreator{},
+			wantNotified: []int{2, 3, 4},
+		},
+		{
+			name:        "watcher lister error",
+			watchers:    &stubWatcherLister{err: errors.New("db error")},
+			creator:     &mockNotificationCreator{},
+			expectedErr: "db error",
+		},
+		{
+			name:         "creator error",
+			watchers:     &stubWatcherLister{},
+			creator:      &mockNotificationCreator{err: errors.New("db error")},
+			wantNotified: []int{2, 3},
+			expectedErr:  "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := NewNotifier(tt.watchers, tt.creator)
+
+			err := n.NotifyOverdue(context.Background(), task.Model{Id: 7, TeamId: 10, AssigneeIds: []int{2, 3}})
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			var notified []int
+
+			for _, n := range tt.creator.notifications {
+				assert.Equal(t, notification.TaskOverdueKind, n.Kind)
+				assert.Equal(t, 7, *n.TaskId)
+
+				notified = append(notified, n.UserId)
+			}
+
+			assert.Equal(t, tt.wantNotified, notified)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package task
+
+import "mkk-luna-test-task/internal/apperror"
+
+// Priority хранится числом, чтобы по нему можно было сортировать, а наружу отдаётся строкой.
+// Нулевое значение — обычный приоритет.
+type Priority int
+
+const (
+	LowPriority    Priority = -1
+	NormalPriority Priority = 0
+	HighPriority   Priority = 1
+	UrgentPriority Priority = 2
+)
+
+var priorityNames = map[Priority]string{
+	LowPriority:    "low",
+	NormalPriority: "normal",
+	HighPriority:   "high",
+	UrgentPriority: "urgent",
+}
+
+func ParsePriority(s string) (Priority, error) {
+	for p, name := range priorityNames {
+		if name == s {
+			return p, nil
+		}
+	}
+
+	return 0, apperror.InvalidField("priority", "must be one of: low, normal, high, urgent")
+}
+
+func (p Priority) String() string {
+	return priorityNames[p]
+}
+
+func (p Priority) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *Priority) UnmarshalText(b []byte) error {
+	parsed, err := ParsePriority(string(b))
+
+	if err != nil {
+		return err
+	}
+
+	*p = parsed
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package task
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePriority(t *testing.T) {
+	for _, name := range []string{"low", "normal", "high", "urgent"} {
+		p, err := ParsePriority(name)
+
+		assert.NoError(t, err)
+		assert.Equal(t, name, p.String())
+	}
+
+	_, err := ParsePriority("critical")
+
+	assert.EqualError(t, err, "validation failed")
+}
+
+func TestPriority_JSON(t *testing.T) {
+	b, err := json.Marshal(struct {
+		Priority Priority `json:"priority"`
+	}{UrgentPriority})
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"priority":"urgent"}`, string(b))
+
+	var got struct {
+		Priority Priority `json:"priority"`
+	}
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"priority":"low"}`), &got))
+	assert.Equal(t, LowPriority, got.Priority)
+	assert.Less(t, int(LowPriority), int(NormalPriority))
+}
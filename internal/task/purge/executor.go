@@ -0,0 +1,106 @@
+package purge
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type taskGetter interface {
+	GetTaskById(ctx context.Context, id int) (*task.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type taskPurger interface {
+	PurgeTask(ctx context.Context, id int) error
+}
+
+type attachmentKeyLister interface {
+	ListTaskAttachmentKeys(ctx context.Context, taskId int) ([]string, error)
+}
+
+type blobDeleter interface {
+	Delete(ctx context.Context, key string) error
+}
+
+type executor struct {
+	taskGetter          taskGetter
+	authorizer          authorizer
+	taskPurger          taskPurger
+	attachmentKeyLister attachmentKeyLister
+	blobs               blobDeleter
+}
+
+func NewExecutor(
+	taskGetter taskGetter,
+	authorizer authorizer,
+	taskPurger taskPurger,
+	attachmentKeyLister attachmentKeyLister,
+	blobs blobDeleter,
+) *executor {
+	return &executor{
+		taskGetter:          taskGetter,
+		authorizer:          authorizer,
+		taskPurger:          taskPurger,
+		attachmentKeyLister: attachmentKeyLister,
+		blobs:               blobs,
+	}
+}
+
+type PurgeInput struct {
+	UserId int
+	TaskId int
+}
+
+func (e *executor) Execute(ctx context.Context, in PurgeInput) error {
+	t, err := e.taskGetter.GetTaskById(ctx, in.TaskId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.NotFound("task not found")
+		}
+
+		return err
+	}
+
+	_, err = e.authorizer.Authorize(ctx, in.UserId, permission.TaskPurge, permission.TaskResource(*t))
+
+	if err != nil {
+		return err
+	}
+
+	// Удалять навсегда можно только то, что уже убрано в архив.
+	if !t.IsArchived() {
+		return task.ErrNotArchived
+	}
+
+	// Строки вложений удаляются каскадом вместе с задачей, поэтому ключи файлов нужно взять заранее.
+	keys, err := e.attachmentKeyLister.ListTaskAttachmentKeys(ctx, in.TaskId)
+
+	if err != nil {
+		return err
+	}
+
+	err = e.taskPurger.PurgeTask(ctx, in.TaskId)
+
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := e.blobs.Delete(ctx, key); err != nil {
+			log.Printf("attachment blob %s cleanup error: %v", key, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package purge
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubTaskGetter struct {
+	task *task.Model
+	err  error
+}
+
+func (s *stubTaskGetter) GetTaskById(ctx context.Context, id int) (*task.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	t := *s.task
+
+	return &t, nil
+}
+
+type mockAuthorizer struct {
+	err    error
+	action permission.Action
+}
+
+func (m *mockAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	m.action = action
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.AdminRole}, nil
+}
+
+type mockTaskPurger struct {
+	err    error
+	called bool
+}
+
+func (m *mockTaskPurger) PurgeTask(ctx context.Context, id int) error {
+	m.called = true
+
+	return m.err
+}
+
+type stubAttachmentKeyLister struct {
+	keys []string
+	err  error
+}
+
+func (s *stubAttachmentKeyLister) ListTaskAttachmentKeys(ctx context.Context, taskId int) ([]string, error) {
+	return s.keys, s.err
+}
+
+type mockBlobs struct {
+	err     error
+	deleted []string
+}
+
+func (m *mockBlobs) Delete(ctx context.Context, key string) error {
+	m.deleted = append(m.deleted, key)
+
+	return m.err
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	archivedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	activeTask := &task.Model{Id: 1, TeamId: 10}
+	archivedTask := &task.Model{Id: 1, TeamId: 10, ArchivedAt: &archivedAt}
+
+	tests := []struct {
+		name        string
+		taskGetter  *stubTaskGetter
+		authorizer  *mockAuthorizer
+		purger      *mockTaskPurger
+		keyLister   *stubAttachmentKeyLister
+		blobs       *mockBlobs
+		wantPurge   bool
+		wantDeleted []string
+		expectedErr string
+	}{
+		{
+			name:       "success",
+			taskGetter: &stubTaskGetter{task: archivedTask},
+			authorizer: &mockAuthorizer{},
+			purger:     &mockTaskPurger{},
+			keyLister:  &stubAttachmentKeyLister{},
+			blobs:      &mockBlobs{},
+			wantPurge:  true,
+		},
+		{
+			name:        "attachment files are deleted",
+			taskGetter:  &stubTaskGetter{task: archivedTask},
+			authorizer:  &mockAuthorizer{},
+			purger:      &mockTaskPurger{},
+			keyLister:   &stubAttachmentKeyLister{keys: []string{"tasks/1/a", "tasks/1/b"}},
+			blobs:       &mockBlobs{},
+			wantPurge:   true,
+			wantDeleted: []string{"tasks/1/a", "tasks/1/b"},
+		},
+		{
+			name:        "storage error is not returned",
+			taskGetter:  &stubTaskGetter{task: archivedTask},
+			authorizer:  &mockAuthorizer{},
+			purger:      &mockTaskPurger{},
+			keyLister:   &stubAttachmentKeyLister{keys: []string{"tasks/1/a", "tasks/1/b"}},
+			blobs:       &mockBlobs{err: errors.New("storage error")},
+			wantPurge:   true,
+			wantDeleted: []string{"tasks/1/a", "tasks/1/b"},
+		},
+		{
+			name:        "key listing error",
+			taskGetter:  &stubTaskGetter{task: archivedTask},
+			authorizer:  &mockAuthorizer{},
+			purger:      &mockTaskPurger{},
+			keyLister:   &stubAttachmentKeyLister{err: errors.New("db error")},
+			blobs:       &mockBlobs{},
+			expectedErr: "db error",
+		},
+		{
+			name:        "task not found",
+			taskGetter:  &stubTaskGetter{err: sql.ErrNoRows},
+			authorizer:  &mockAuthorizer{},
+			purger:      &mockTaskPurger{},
+			keyLister:   &stubAttachmentKeyLister{},
+			blobs:       &mockBlobs{},
+			expectedErr: "task not found",
+		},
+		{
+			name:        "not an admin",
+			taskGetter:  &stubTaskGetter{task: archivedTask},
+			authorizer:  &mockAuthorizer{err: permission.ErrForbidden},
+			purger:      &mockTaskPurger{},
+			keyLister:   &stubAttachmentKeyLister{},
+			blobs:       &mockBlobs{},
+			expectedErr: "forbidden",
+		},
+		{
+			name:        "task is not archived",
+			taskGetter:  &stubTaskGetter{task: activeTask},
+			authorizer:  &mockAuthorizer{},
+			purger:      &mockTaskPurger{},
+			keyLister:   &stubAttachmentKeyLister{},
+			blobs:       &mockBlobs{},
+			expectedErr: "task is not archived",
+		},
+		{
+			name:        "purger error",
+			taskGetter:  &stubTaskGetter{task: archivedTask},
+			authorizer:  &mockAuthorizer{},
+			purger:      &mockTaskPurger{err: errors.New("db error")},
+			keyLister:   &stubAttachmentKeyLister{keys: []string{"tasks/1/a"}},
+			blobs:       &mockBlobs{},
+			wantPurge:   true,
+			expectedErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(tt.taskGetter, tt.authorizer, tt.purger, tt.keyLister, tt.blobs)
+
+			err := e.Execute(context.Background(), PurgeInput{UserId: 5, TaskId: 1})
+
+			assert.Equal(t, tt.wantPurge, tt.purger.called)
+			assert.Equal(t, tt.wantDeleted, tt.blobs.deleted)
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, permission.TaskPurge, tt.authorizer.action)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/markdown"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type tasksGetter interface {
+	GetTasksByIds(ctx context.Context, ids []int) ([]*task.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+// Renderer превращает Markdown описаний и комментариев в HTML для конкретного читателя:
+// #N становится ссылкой, только если задача существует и читателю можно её смотреть.
+type Renderer struct {
+	tasksGetter tasksGetter
+	authorizer  authorizer
+}
+
+func NewRenderer(tasksGetter tasksGetter, authorizer authorizer) *Renderer {
+	return &Renderer{
+		tasksGetter: tasksGetter,
+		authorizer:  authorizer,
+	}
+}
+
+// HTML возвращает HTML для каждого из sources в том же порядке.
+// Задачи по ссылкам из всех текстов загружаются и проверяются одним проходом.
+func (r *Renderer) HTML(ctx context.Context, userId int, sources ...string) ([]string, error) {
+	ids := []int{}
+	seen := map[int]bool{}
+
+	for _, src := range sources {
+		for _, id := range markdown.TaskRefIds(src) {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	refs, err := r.visibleRefs(ctx, userId, ids)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(sources))
+
+	for _, src := range sources {
+		result = append(result, markdown.Render(src, refs))
+	}
+
+	return result, nil
+}
+
+func (r *Renderer) visibleRefs(ctx context.Context, userId int, ids []int) (map[int]markdown.TaskRef, error) {
+	refs := map[int]markdown.TaskRef{}
+
+	if len(ids) == 0 {
+		return refs, nil
+	}
+
+	tasks, err := r.tasksGetter.GetTasksByIds(ctx, ids)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range tasks {
+		_, err := r.authorizer.Authorize(ctx, userId, permission.TaskView, permission.TaskResource(*t))
+
+		// Недоступная задача выглядит так же, как несуществующая: #N остаётся текстом.
+		if errors.Is(err, permission.ErrForbidden) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		refs[t.Id] = markdown.TaskRef{Id: t.Id, Title: t.Title}
+	}
+
+	return refs, nil
+}
+
+// WantHTML разбирает параметр render: пусто — только исходный текст, html — ещё и HTML рядом с ним.
+func WantHTML(query url.Values) (bool, error) {
+	switch mode := query.Get("render"); mode {
+	case "":
+		return false, nil
+	case "html":
+		return true, nil
+	default:
+		return false, apperror.InvalidField("render", "unknown value "+mode)
+	}
+}
@@ -0,0 +1,149 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockTasksGetter struct {
+	tasks  []*task.Model
+	err    error
+	called bool
+	ids    []int
+}
+
+func (m *mockTasksGetter) GetTasksByIds(ctx context.Context, ids []int) ([]*task.Model, error) {
+	m.called = true
+	m.ids = ids
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return m.tasks, nil
+}
+
+// stubAuthorizer пускает только в команду 10.
+type stubAuthorizer struct {
+	err error
+}
+
+func (s *stubAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	if res.TeamId != 10 {
+		return nil, permission.ErrForbidden
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
+}
+
+func TestRenderer_HTML(t *testing.T) {
+	tasks := []*task.Model{
+		{Id: 3, Title: "Своя", TeamId: 10},
+		{Id: 4, Title: "Чужая", TeamId: 20},
+	}
+
+	tests := []struct {
+		name        string
+		tasksGetter *mockTasksGetter
+		authorizer  *stubAuthorizer
+		sources     []string
+		want        []string
+		wantIds     []int
+		expectedErr string
+	}{
+		{
+			name:        "links only visible tasks",
+			tasksGetter: &mockTasksGetter{tasks: tasks},
+			authorizer:  &stubAuthorizer{},
+			sources:     []string{"см. #3 и #4", "**#5**, снова #3"},
+			want: []string{
+				`<p>см. <a href="/api/v1/tasks/3" class="task-ref" data-task-id="3" title="Своя">#3</a> и #4</p>` + "\n",
+				`<p><strong>#5</strong>, снова <a href="/api/v1/tasks/3" class="task-ref" data-task-id="3" title="Своя">#3</a></p>` + "\n",
+			},
+			wantIds: []int{3, 4, 5},
+		},
+		{
+			name:        "no refs",
+			tasksGetter: &mockTasksGetter{},
+			authorizer:  &stubAuthorizer{},
+			sources:     []string{"<b>текст</b>", ""},
+			want:        []string{"<p>&lt;b&gt;текст&lt;/b&gt;</p>\n", ""},
+		},
+		{
+			name:        "db error",
+			tasksGetter: &mockTasksGetter{err: errors.New("db error")},
+			authorizer:  &stubAuthorizer{},
+			sources:     []string{"#3"},
+			expectedErr: "db error",
+		},
+		{
+			name:        "authorizer error",
+			tasksGetter: &mockTasksGetter{tasks: tasks},
+			authorizer:  &stubAuthorizer{err: errors.New("db error")},
+			sources:     []string{"#3"},
+			expectedErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRenderer(tt.tasksGetter, tt.authorizer)
+
+			got, err := r.HTML(context.Background(), 5, tt.sources...)
+
+			if tt.expectedErr != "" {
+				assert.Nil(t, got)
+				assert.EqualError(t, err, tt.expectedErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantIds != nil, tt.tasksGetter.called)
+			assert.Equal(t, tt.wantIds, tt.tasksGetter.ids)
+		})
+	}
+}
+
+func TestWantHTML(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		want        bool
+		expectedErr string
+	}{
+		{name: "not set", query: ""},
+		{name: "html", query: "render=html", want: true},
+		{name: "unknown", query: "render=pdf", expectedErr: "validation failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _ := url.ParseQuery(tt.query)
+
+			got, err := WantHTML(query)
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
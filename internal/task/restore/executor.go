@@ -0,0 +1,88 @@
+package restore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type taskGetter interface {
+	GetTaskById(ctx context.Context, id int) (*task.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type taskRestorer interface {
+	RestoreTask(ctx context.Context, id int, expectedVersion int, restoredBy int, restoredAt time.Time) (*task.Model, error)
+}
+
+type cacheInvalidator interface {
+	InvalidateTeamTasksCache(ctx context.Context, teamId int) error
+}
+
+type executor struct {
+	taskGetter       taskGetter
+	authorizer       authorizer
+	taskRestorer     taskRestorer
+	cacheInvalidator cacheInvalidator
+}
+
+func NewExecutor(taskGetter taskGetter, authorizer authorizer, taskRestorer taskRestorer, cacheInvalidator cacheInvalidator) *executor {
+	return &executor{
+		taskGetter:       taskGetter,
+		authorizer:       authorizer,
+		taskRestorer:     taskRestorer,
+		cacheInvalidator: cacheInvalidator,
+	}
+}
+
+type RestoreInput struct {
+	UserId  int
+	TaskId  int
+	IfMatch int
+}
+
+type RestoreResult struct {
+	Version int
+}
+
+func (e *executor) Execute(ctx context.Context, in RestoreInput) (*RestoreResult, error) {
+	t, err := e.taskGetter.GetTaskById(ctx, in.TaskId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("task not found")
+		}
+
+		return nil, err
+	}
+
+	_, err = e.authorizer.Authorize(ctx, in.UserId, permission.TaskDelete, permission.TaskResource(*t))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !t.IsArchived() {
+		return nil, task.ErrNotArchived
+	}
+
+	restored, err := e.taskRestorer.RestoreTask(ctx, in.TaskId, in.IfMatch, in.UserId, time.Now())
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Если не получилось, кэш доски устареет до истечения ttl.
+	_ = e.cacheInvalidator.InvalidateTeamTasksCache(ctx, restored.TeamId)
+
+	return &RestoreResult{Version: restored.Version}, nil
+}
@@ -0,0 +1,154 @@
+package restore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubTaskGetter struct {
+	task *task.Model
+	err  error
+}
+
+func (s *stubTaskGetter) GetTaskById(ctx context.Context, id int) (*task.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	t := *s.task
+
+	return &t, nil
+}
+
+type stubAuthorizer struct {
+	err error
+}
+
+func (s *stubAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
+}
+
+type mockTaskRestorer struct {
+	err    error
+	called bool
+}
+
+func (m *mockTaskRestorer) RestoreTask(ctx context.Context, id int, expectedVersion int, restoredBy int, restoredAt time.Time) (*task.Model, error) {
+	m.called = true
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &task.Model{Id: id, TeamId: 10, Version: 5}, nil
+}
+
+type mockCacheInvalidator struct {
+	teamId int
+}
+
+func (m *mockCacheInvalidator) InvalidateTeamTasksCache(ctx context.Context, teamId int) error {
+	m.teamId = teamId
+
+	return nil
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	archivedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	activeTask := &task.Model{Id: 1, TeamId: 10, Version: 3}
+	archivedTask := &task.Model{Id: 1, TeamId: 10, Version: 4, ArchivedAt: &archivedAt}
+
+	tests := []struct {
+		name        string
+		taskGetter  *stubTaskGetter
+		authorizer  *stubAuthorizer
+		restorer    *mockTaskRestorer
+		wantVersion int
+		wantRestore bool
+		expectedErr string
+	}{
+		{
+			name:        "success",
+			taskGetter:  &stubTaskGetter{task: archivedTask},
+			authorizer:  &stubAuthorizer{},
+			restorer:    &mockTaskRestorer{},
+			wantVersion: 5,
+			wantRestore: true,
+		},
+		{
+			name:        "task not found",
+			taskGetter:  &stubTaskGetter{err: sql.ErrNoRows},
+			authorizer:  &stubAuthorizer{},
+			restorer:    &mockTaskRestorer{},
+			expectedErr: "task not found",
+		},
+		{
+			name:        "forbidden",
+			taskGetter:  &stubTaskGetter{task: archivedTask},
+			authorizer:  &stubAuthorizer{err: permission.ErrForbidden},
+			restorer:    &mockTaskRestorer{},
+			expectedErr: "forbidden",
+		},
+		{
+			name:        "not archived",
+			taskGetter:  &stubTaskGetter{task: activeTask},
+			authorizer:  &stubAuthorizer{},
+			restorer:    &mockTaskRestorer{},
+			expectedErr: "task is not archived",
+		},
+		{
+			name:        "stale version",
+			taskGetter:  &stubTaskGetter{task: archivedTask},
+			authorizer:  &stubAuthorizer{},
+			restorer:    &mockTaskRestorer{err: task.ErrVersionMismatch},
+			wantRestore: true,
+			expectedErr: "task has been modified",
+		},
+		{
+			name:        "restorer error",
+			taskGetter:  &stubTaskGetter{task: archivedTask},
+			authorizer:  &stubAuthorizer{},
+			restorer:    &mockTaskRestorer{err: errors.New("db error")},
+			wantRestore: true,
+			expectedErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := &mockCacheInvalidator{}
+
+			e := NewExecutor(tt.taskGetter, tt.authorizer, tt.restorer, cache)
+
+			got, err := e.Execute(context.Background(), RestoreInput{UserId: 5, TaskId: 1})
+
+			assert.Equal(t, tt.wantRestore, tt.restorer.called)
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Nil(t, got)
+				assert.Zero(t, cache.teamId)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantVersion, got.Version)
+			assert.Equal(t, 10, cache.teamId)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package add
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+var errSubtaskNotFound = apperror.NotFound("subtask not found")
+
+type taskGetter interface {
+	GetTaskById(ctx context.Context, id int) (*task.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type parentSetter interface {
+	SetTaskParent(ctx context.Context, teamId, subtaskId, parentId int) error
+}
+
+type executor struct {
+	taskGetter   taskGetter
+	authorizer   authorizer
+	parentSetter parentSetter
+}
+
+func NewExecutor(taskGetter taskGetter, authorizer authorizer, parentSetter parentSetter) *executor {
+	return &executor{
+		taskGetter:   taskGetter,
+		authorizer:   authorizer,
+		parentSetter: parentSetter,
+	}
+}
+
+type AddInput struct {
+	UserId    int
+	TaskId    int
+	SubtaskId int
+}
+
+// Execute делает задачу из той же команды подзадачей TaskId; если у неё был другой родитель, она переезжает.
+// Права нужны только на родителя: у самой подзадачи поля не меняются.
+func (e *executor) Execute(ctx context.Context, in AddInput) error {
+	parent, err := e.taskGetter.GetTaskById(ctx, in.TaskId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.NotFound("task not found")
+		}
+
+		return err
+	}
+
+	_, err = e.authorizer.Authorize(ctx, in.UserId, permission.TaskEdit, permission.TaskResource(*parent))
+
+	if err != nil {
+		return err
+	}
+
+	if parent.IsArchived() {
+		return task.ErrArchived
+	}
+
+	subtask, err := e.taskGetter.GetTaskById(ctx, in.SubtaskId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errSubtaskNotFound
+		}
+
+		return err
+	}
+
+	if subtask.TeamId != parent.TeamId {
+		return errSubtaskNotFound
+	}
+
+	if subtask.IsArchived() {
+		return task.ErrArchived
+	}
+
+	return e.parentSetter.SetTaskParent(ctx, parent.TeamId, subtask.Id, parent.Id)
+}
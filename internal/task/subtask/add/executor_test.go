@@ -0,0 +1,152 @@
+package add
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubTaskGetter struct {
+	tasks map[int]*task.Model
+}
+
+func (s *stubTaskGetter) GetTaskById(ctx context.Context, id int) (*task.Model, error) {
+	t, ok := s.tasks[id]
+
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	copied := *t
+
+	return &copied, nil
+}
+
+type stubAuthorizer struct {
+	err error
+}
+
+func (s *stubAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
+}
+
+type mockParentSetter struct {
+	err    error
+	called bool
+}
+
+func (m *mockParentSetter) SetTaskParent(ctx context.Context, teamId, subtaskId, parentId int) error {
+	m.called = true
+
+	return m.err
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	archivedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	parent := &task.Model{Id: 1, TeamId: 10, CreatorId: 5}
+	subtask := &task.Model{Id: 2, TeamId: 10}
+
+	tests := []struct {
+		name        string
+		tasks       map[int]*task.Model
+		authorizer  *stubAuthorizer
+		setter      *mockParentSetter
+		wantCalled  bool
+		expectedErr string
+	}{
+		{
+			name:       "linked",
+			tasks:      map[int]*task.Model{1: parent, 2: subtask},
+			authorizer: &stubAuthorizer{},
+			setter:     &mockParentSetter{},
+			wantCalled: true,
+		},
+		{
+			name:        "parent not found",
+			tasks:       map[int]*task.Model{2: subtask},
+			authorizer:  &stubAuthorizer{},
+			setter:      &mockParentSetter{},
+			expectedErr: "task not found",
+		},
+		{
+			name:        "cannot edit parent",
+			tasks:       map[int]*task.Model{1: parent, 2: subtask},
+			authorizer:  &stubAuthorizer{err: permission.ErrForbidden},
+			setter:      &mockParentSetter{},
+			expectedErr: "forbidden",
+		},
+		{
+			name:        "archived parent",
+			tasks:       map[int]*task.Model{1: {Id: 1, TeamId: 10, ArchivedAt: &archivedAt}, 2: subtask},
+			authorizer:  &stubAuthorizer{},
+			setter:      &mockParentSetter{},
+			expectedErr: "task is archived",
+		},
+		{
+			name:        "subtask not found",
+			tasks:       map[int]*task.Model{1: parent},
+			authorizer:  &stubAuthorizer{},
+			setter:      &mockParentSetter{},
+			expectedErr: "subtask not found",
+		},
+		{
+			name:        "subtask of another team",
+			tasks:       map[int]*task.Model{1: parent, 2: {Id: 2, TeamId: 11}},
+			authorizer:  &stubAuthorizer{},
+			setter:      &mockParentSetter{},
+			expectedErr: "subtask not found",
+		},
+		{
+			name:        "archived subtask",
+			tasks:       map[int]*task.Model{1: parent, 2: {Id: 2, TeamId: 10, ArchivedAt: &archivedAt}},
+			authorizer:  &stubAuthorizer{},
+			setter:      &mockParentSetter{},
+			expectedErr: "task is archived",
+		},
+		{
+			name:        "cycle",
+			tasks:       map[int]*task.Model{1: parent, 2: subtask},
+			authorizer:  &stubAuthorizer{},
+			setter:      &mockParentSetter{err: task.ErrLinkCycle},
+			wantCalled:  true,
+			expectedErr: "link would create a cycle",
+		},
+		{
+			name:        "db error",
+			tasks:       map[int]*task.Model{1: parent, 2: subtask},
+			authorizer:  &stubAuthorizer{},
+			setter:      &mockParentSetter{err: errors.New("db error")},
+			wantCalled:  true,
+			expectedErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(&stubTaskGetter{tasks: tt.tasks}, tt.authorizer, tt.setter)
+
+			err := e.Execute(context.Background(), AddInput{UserId: 5, TaskId: 1, SubtaskId: 2})
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantCalled, tt.setter.called)
+		})
+	}
+}
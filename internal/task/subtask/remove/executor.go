@@ -0,0 +1,70 @@
+package remove
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type taskGetter interface {
+	GetTaskById(ctx context.Context, id int) (*task.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type parentRemover interface {
+	RemoveTaskParent(ctx context.Context, subtaskId, parentId int) error
+}
+
+type executor struct {
+	taskGetter    taskGetter
+	authorizer    authorizer
+	parentRemover parentRemover
+}
+
+func NewExecutor(taskGetter taskGetter, authorizer authorizer, parentRemover parentRemover) *executor {
+	return &executor{
+		taskGetter:    taskGetter,
+		authorizer:    authorizer,
+		parentRemover: parentRemover,
+	}
+}
+
+type RemoveInput struct {
+	UserId    int
+	TaskId    int
+	SubtaskId int
+}
+
+// Execute отвязывает подзадачу от TaskId, она становится самостоятельной задачей.
+// Если SubtaskId не подзадача TaskId, ничего не меняется.
+func (e *executor) Execute(ctx context.Context, in RemoveInput) error {
+	parent, err := e.taskGetter.GetTaskById(ctx, in.TaskId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.NotFound("task not found")
+		}
+
+		return err
+	}
+
+	_, err = e.authorizer.Authorize(ctx, in.UserId, permission.TaskEdit, permission.TaskResource(*parent))
+
+	if err != nil {
+		return err
+	}
+
+	if parent.IsArchived() {
+		return task.ErrArchived
+	}
+
+	return e.parentRemover.RemoveTaskParent(ctx, in.SubtaskId, parent.Id)
+}
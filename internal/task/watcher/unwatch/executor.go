@@ -0,0 +1,30 @@
+package unwatch
+
+import (
+	"context"
+)
+
+type watcherRemover interface {
+	RemoveTaskWatcher(ctx context.Context, taskId, userId int) error
+}
+
+type executor struct {
+	watcherRemover watcherRemover
+}
+
+func NewExecutor(watcherRemover watcherRemover) *executor {
+	return &executor{
+		watcherRemover: watcherRemover,
+	}
+}
+
+type UnwatchInput struct {
+	UserId int
+	TaskId int
+}
+
+// Execute отписывает пользователя от задачи. Права не проверяются: отписаться можно и после ухода из команды,
+// а отписка от чужой или несуществующей задачи ничего не меняет и ничего о ней не раскрывает.
+func (e *executor) Execute(ctx context.Context, in UnwatchInput) error {
+	return e.watcherRemover.RemoveTaskWatcher(ctx, in.TaskId, in.UserId)
+}
@@ -0,0 +1,52 @@
+package unwatch
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockWatcherRemover struct {
+	err     error
+	removed [2]int
+}
+
+func (m *mockWatcherRemover) RemoveTaskWatcher(ctx context.Context, taskId, userId int) error {
+	m.removed = [2]int{taskId, userId}
+
+	return m.err
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		remover     *mockWatcherRemover
+		expectedErr string
+	}{
+		{
+			name:    "unwatched",
+			remover: &mockWatcherRemover{},
+		},
+		{
+			name:        "db error",
+			remover:     &mockWatcherRemover{err: errors.New("db error")},
+			expectedErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewExecutor(tt.remover).Execute(context.Background(), UnwatchInput{UserId: 5, TaskId: 1})
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, [2]int{1, 5}, tt.remover.removed)
+		})
+	}
+}
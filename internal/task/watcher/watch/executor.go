@@ -0,0 +1,68 @@
+package watch
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type taskGetter interface {
+	GetTaskById(ctx context.Context, id int) (*task.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type watcherAdder interface {
+	AddTaskWatcher(ctx context.Context, taskId, userId int) error
+}
+
+type executor struct {
+	taskGetter   taskGetter
+	authorizer   authorizer
+	watcherAdder watcherAdder
+}
+
+func NewExecutor(taskGetter taskGetter, authorizer authorizer, watcherAdder watcherAdder) *executor {
+	return &executor{
+		taskGetter:   taskGetter,
+		authorizer:   authorizer,
+		watcherAdder: watcherAdder,
+	}
+}
+
+type WatchInput struct {
+	UserId int
+	TaskId int
+}
+
+// Execute подписывает пользователя на задачу, которую ему можно смотреть. Повторная подписка — не ошибка.
+func (e *executor) Execute(ctx context.Context, in WatchInput) error {
+	t, err := e.taskGetter.GetTaskById(ctx, in.TaskId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.NotFound("task not found")
+		}
+
+		return err
+	}
+
+	_, err = e.authorizer.Authorize(ctx, in.UserId, permission.TaskView, permission.TaskResource(*t))
+
+	if err != nil {
+		return err
+	}
+
+	if t.IsArchived() {
+		return task.ErrArchived
+	}
+
+	return e.watcherAdder.AddTaskWatcher(ctx, t.Id, in.UserId)
+}
@@ -5,10 +5,13 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/validation"
 )
 
 type request struct {
-	Name string `json:"name"`
+	Name string `json:"name" validate:"required,min=2,max=100"`
 }
 
 type response struct {
@@ -36,7 +39,7 @@ func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
 	userId, ok := userIdAny.(int)
 
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
 
 		return
 	}
@@ -45,7 +48,7 @@ func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
 	if err != nil {
-		http.Error(w, "error", http.StatusInternalServerError)
+		apperror.Write(w, err)
 
 		return
 	}
@@ -53,7 +56,13 @@ func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
 	var req request
 
 	if err := json.Unmarshal(body, &req); err != nil {
-		http.Error(w, "json error", http.StatusBadRequest)
+		apperror.Write(w, apperror.Validation("invalid json"))
+
+		return
+	}
+
+	if err := validation.Struct(req); err != nil {
+		apperror.Write(w, err)
 
 		return
 	}
@@ -61,7 +70,7 @@ func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
 	result, err := h.exec.Execute(r.Context(), CreateInput{UserId: userId, Name: req.Name})
 
 	if err != nil {
-		http.Error(w, "error", http.StatusBadRequest)
+		apperror.Write(w, err)
 
 		return
 	}
@@ -71,7 +80,7 @@ func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
 	respBody, err := json.Marshal(resp)
 
 	if err != nil {
-		http.Error(w, "json error", http.StatusInternalServerError)
+		apperror.Write(w, err)
 
 		return
 	}
@@ -0,0 +1,166 @@
+package accept
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/team/invitation"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/user"
+)
+
+type invitationTokenVerifier interface {
+	Verify(token string) (hash string, err error)
+}
+
+type invitationGetter interface {
+	GetInvitation(ctx context.Context, id int) (*invitation.Model, error)
+	GetInvitationByTokenHash(ctx context.Context, hash string) (*invitation.Model, error)
+}
+
+type invitationAccepter interface {
+	AcceptInvitation(ctx context.Context, inv invitation.Model, userId int, acceptedAt time.Time) error
+}
+
+type invitationStatusUpdater interface {
+	UpdateInvitationStatus(ctx context.Context, id int, status invitation.Status, respondedAt time.Time) error
+}
+
+type userGetter interface {
+	GetUserById(ctx context.Context, id int) (*user.Model, error)
+}
+
+type teamMembershipChecker interface {
+	CheckUserIsInTeam(userId, teamId int) (bool, error)
+}
+
+type executor struct {
+	tokenVerifier           invitationTokenVerifier
+	invitationGetter        invitationGetter
+	invitationAccepter      invitationAccepter
+	invitationStatusUpdater invitationStatusUpdater
+	userGetter              userGetter
+	teamMembershipChecker   teamMembershipChecker
+}
+
+func NewExecutor(
+	tokenVerifier invitationTokenVerifier,
+	invitationGetter invitationGetter,
+	invitationAccepter invitationAccepter,
+	invitationStatusUpdater invitationStatusUpdater,
+	userGetter userGetter,
+	teamMembershipChecker teamMembershipChecker,
+) *executor {
+	return &executor{
+		tokenVerifier:           tokenVerifier,
+		invitationGetter:        invitationGetter,
+		invitationAccepter:      invitationAccepter,
+		invitationStatusUpdater: invitationStatusUpdater,
+		userGetter:              userGetter,
+		teamMembershipChecker:   teamMembershipChecker,
+	}
+}
+
+// AcceptInput — приглашение задаётся номером (InvitationId) или токеном из письма.
+type AcceptInput struct {
+	UserId       int
+	InvitationId int
+	Token        string
+}
+
+type AcceptResult struct {
+	TeamId int
+	Role   member.Role
+}
+
+func (e *executor) Execute(ctx context.Context, in AcceptInput) (*AcceptResult, error) {
+	inv, err := e.find(ctx, in)
+
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := e.userGetter.GetUserById(ctx, in.UserId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !inv.CanBeAnsweredBy(*u, in.InvitationId == 0) {
+		// По номеру чужое приглашение неотличимо от несуществующего.
+		if in.InvitationId != 0 {
+			return nil, apperror.NotFound("invitation not found")
+		}
+
+		return nil, apperror.Forbidden("forbidden")
+	}
+
+	if inv.Status != invitation.PendingStatus {
+		return nil, apperror.Conflict("invitation already used")
+	}
+
+	now := time.Now()
+
+	if inv.IsExpired(now) {
+		if err := e.invitationStatusUpdater.UpdateInvitationStatus(ctx, inv.Id, invitation.ExpiredStatus, now); err != nil && !errors.Is(err, invitation.ErrNotPending) {
+			return nil, err
+		}
+
+		return nil, apperror.Conflict("invitation expired")
+	}
+
+	isMember, err := e.teamMembershipChecker.CheckUserIsInTeam(u.Id, inv.TeamId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if isMember {
+		return nil, apperror.Conflict("already a member")
+	}
+
+	err = e.invitationAccepter.AcceptInvitation(ctx, *inv, u.Id, now)
+
+	if err != nil {
+		if errors.Is(err, invitation.ErrNotPending) {
+			return nil, apperror.Conflict("invitation already used")
+		}
+
+		return nil, err
+	}
+
+	return &AcceptResult{
+		TeamId: inv.TeamId,
+		Role:   inv.Role,
+	}, nil
+}
+
+func (e *executor) find(ctx context.Context, in AcceptInput) (*invitation.Model, error) {
+	var inv *invitation.Model
+	var err error
+
+	if in.InvitationId != 0 {
+		inv, err = e.invitationGetter.GetInvitation(ctx, in.InvitationId)
+	} else {
+		hash, verifyErr := e.tokenVerifier.Verify(in.Token)
+
+		if verifyErr != nil {
+			return nil, apperror.Validation("invalid token")
+		}
+
+		inv, err = e.invitationGetter.GetInvitationByTokenHash(ctx, hash)
+	}
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("invitation not found")
+		}
+
+		return nil, err
+	}
+
+	return inv, nil
+}
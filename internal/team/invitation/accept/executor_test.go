@@ -0,0 +1,227 @@
+package accept
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/team/invitation"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubTokenVerifier struct{}
+
+func (s *stubTokenVerifier) Verify(token string) (string, error) {
+	if token == "forged" {
+		return "", invitation.ErrInvalidToken
+	}
+
+	return "hash-" + token, nil
+}
+
+type stubInvitationGetter struct {
+	invitations map[string]*invitation.Model
+}
+
+func (s *stubInvitationGetter) GetInvitation(ctx context.Context, id int) (*invitation.Model, error) {
+	for _, inv := range s.invitations {
+		if inv.Id == id {
+			return inv, nil
+		}
+	}
+
+	return nil, sql.ErrNoRows
+}
+
+func (s *stubInvitationGetter) GetInvitationByTokenHash(ctx context.Context, hash string) (*invitation.Model, error) {
+	inv, ok := s.invitations[hash]
+
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return inv, nil
+}
+
+type mockInvitationAccepter struct {
+	acceptedBy int
+	err        error
+}
+
+func (m *mockInvitationAccepter) AcceptInvitation(ctx context.Context, inv invitation.Model, userId int, acceptedAt time.Time) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.acceptedBy = userId
+
+	return nil
+}
+
+type mockInvitationStatusUpdater struct {
+	status invitation.Status
+}
+
+func (m *mockInvitationStatusUpdater) UpdateInvitationStatus(ctx context.Context, id int, status invitation.Status, respondedAt time.Time) error {
+	m.status = status
+
+	return nil
+}
+
+type stubUserGetter struct{}
+
+func (s *stubUserGetter) GetUserById(ctx context.Context, id int) (*user.Model, error) {
+	return &user.Model{Id: id, Username: map[int]string{1: "dev", 2: "stranger", 3: "teammate"}[id]}, nil
+}
+
+type stubTeamMembershipChecker struct{}
+
+func (s *stubTeamMembershipChecker) CheckUserIsInTeam(userId, teamId int) (bool, error) {
+	return userId == 3, nil
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	invitations := map[string]*invitation.Model{
+		"hash-by-id":       {Id: 1, TeamId: 10, InviteeUserId: 1, Role: member.AdminRole, Status: invitation.PendingStatus, ExpiresAt: future},
+		"hash-by-username": {Id: 2, TeamId: 10, InviteeUsername: "dev", Role: member.NormalRole, Status: invitation.PendingStatus, ExpiresAt: future},
+		"hash-by-email":    {Id: 3, TeamId: 10, InviteeEmail: "x@y.z", Role: member.NormalRole, Status: invitation.PendingStatus, ExpiresAt: future},
+		"hash-used":        {Id: 4, TeamId: 10, InviteeUserId: 1, Role: member.NormalRole, Status: invitation.AcceptedStatus, ExpiresAt: future},
+		"hash-expired":     {Id: 5, TeamId: 10, InviteeUserId: 1, Role: member.NormalRole, Status: invitation.PendingStatus, ExpiresAt: past},
+	}
+
+	tests := []struct {
+		name         string
+		in           AcceptInput
+		accepterErr  error
+		want         *AcceptResult
+		wantErr      bool
+		expectedErr  error
+		expectStatus invitation.Status
+	}{
+		{
+			name: "accept invitation by user id",
+			in:   AcceptInput{UserId: 1, Token: "by-id"},
+			want: &AcceptResult{TeamId: 10, Role: member.AdminRole},
+		},
+		{
+			name: "accept invitation by username",
+			in:   AcceptInput{UserId: 1, Token: "by-username"},
+			want: &AcceptResult{TeamId: 10, Role: member.NormalRole},
+		},
+		{
+			name: "email invitation accepted by token holder",
+			in:   AcceptInput{UserId: 2, Token: "by-email"},
+			want: &AcceptResult{TeamId: 10, Role: member.NormalRole},
+		},
+		{
+			name: "accept by invitation id",
+			in:   AcceptInput{UserId: 1, InvitationId: 1},
+			want: &AcceptResult{TeamId: 10, Role: member.AdminRole},
+		},
+		{
+			name: "accept username invitation by invitation id",
+			in:   AcceptInput{UserId: 1, InvitationId: 2},
+			want: &AcceptResult{TeamId: 10, Role: member.NormalRole},
+		},
+		{
+			name:        "email invitation needs the token",
+			in:          AcceptInput{UserId: 2, InvitationId: 3},
+			wantErr:     true,
+			expectedErr: errors.New("invitation not found"),
+		},
+		{
+			name:        "another user's invitation by invitation id",
+			in:          AcceptInput{UserId: 2, InvitationId: 1},
+			wantErr:     true,
+			expectedErr: errors.New("invitation not found"),
+		},
+		{
+			name:        "unknown invitation id",
+			in:          AcceptInput{UserId: 1, InvitationId: 99},
+			wantErr:     true,
+			expectedErr: errors.New("invitation not found"),
+		},
+		{
+			name:        "invitation for another user",
+			in:          AcceptInput{UserId: 2, Token: "by-id"},
+			wantErr:     true,
+			expectedErr: errors.New("forbidden"),
+		},
+		{
+			name:        "forged token",
+			in:          AcceptInput{UserId: 1, Token: "forged"},
+			wantErr:     true,
+			expectedErr: errors.New("invalid token"),
+		},
+		{
+			name:        "unknown token",
+			in:          AcceptInput{UserId: 1, Token: "unknown"},
+			wantErr:     true,
+			expectedErr: errors.New("invitation not found"),
+		},
+		{
+			name:        "already used",
+			in:          AcceptInput{UserId: 1, Token: "used"},
+			wantErr:     true,
+			expectedErr: errors.New("invitation already used"),
+		},
+		{
+			name:         "expired",
+			in:           AcceptInput{UserId: 1, Token: "expired"},
+			wantErr:      true,
+			expectedErr:  errors.New("invitation expired"),
+			expectStatus: invitation.ExpiredStatus,
+		},
+		{
+			name:        "already a member",
+			in:          AcceptInput{UserId: 3, Token: "by-email"},
+			wantErr:     true,
+			expectedErr: errors.New("already a member"),
+		},
+		{
+			name:        "concurrent accept",
+			in:          AcceptInput{UserId: 1, Token: "by-id"},
+			accepterErr: invitation.ErrNotPending,
+			wantErr:     true,
+			expectedErr: errors.New("invitation already used"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accepter := &mockInvitationAccepter{err: tt.accepterErr}
+			statusUpdater := &mockInvitationStatusUpdater{}
+
+			e := NewExecutor(
+				&stubTokenVerifier{},
+				&stubInvitationGetter{invitations: invitations},
+				accepter,
+				statusUpdater,
+				&stubUserGetter{},
+				&stubTeamMembershipChecker{},
+			)
+
+			got, err := e.Execute(context.Background(), tt.in)
+
+			if tt.wantErr {
+				assert.Nil(t, got)
+				assert.EqualError(t, err, tt.expectedErr.Error())
+				assert.Zero(t, accepter.acceptedBy)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+				assert.Equal(t, tt.in.UserId, accepter.acceptedBy)
+			}
+
+			assert.Equal(t, tt.expectStatus, statusUpdater.status)
+		})
+	}
+}
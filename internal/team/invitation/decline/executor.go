@@ -0,0 +1,136 @@
+package decline
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/team/invitation"
+	"mkk-luna-test-task/internal/user"
+)
+
+type invitationTokenVerifier interface {
+	Verify(token string) (hash string, err error)
+}
+
+type invitationGetter interface {
+	GetInvitation(ctx context.Context, id int) (*invitation.Model, error)
+	GetInvitationByTokenHash(ctx context.Context, hash string) (*invitation.Model, error)
+}
+
+type invitationStatusUpdater interface {
+	UpdateInvitationStatus(ctx context.Context, id int, status invitation.Status, respondedAt time.Time) error
+}
+
+type userGetter interface {
+	GetUserById(ctx context.Context, id int) (*user.Model, error)
+}
+
+type executor struct {
+	tokenVerifier           invitationTokenVerifier
+	invitationGetter        invitationGetter
+	invitationStatusUpdater invitationStatusUpdater
+	userGetter              userGetter
+}
+
+func NewExecutor(
+	tokenVerifier invitationTokenVerifier,
+	invitationGetter invitationGetter,
+	invitationStatusUpdater invitationStatusUpdater,
+	userGetter userGetter,
+) *executor {
+	return &executor{
+		tokenVerifier:           tokenVerifier,
+		invitationGetter:        invitationGetter,
+		invitationStatusUpdater: invitationStatusUpdater,
+		userGetter:              userGetter,
+	}
+}
+
+// DeclineInput — приглашение задаётся номером (InvitationId) или токеном из письма.
+type DeclineInput struct {
+	UserId       int
+	InvitationId int
+	Token        string
+}
+
+func (e *executor) Execute(ctx context.Context, in DeclineInput) error {
+	inv, err := e.find(ctx, in)
+
+	if err != nil {
+		return err
+	}
+
+	u, err := e.userGetter.GetUserById(ctx, in.UserId)
+
+	if err != nil {
+		return err
+	}
+
+	if !inv.CanBeAnsweredBy(*u, in.InvitationId == 0) {
+		// По номеру чужое приглашение неотличимо от несуществующего.
+		if in.InvitationId != 0 {
+			return apperror.NotFound("invitation not found")
+		}
+
+		return apperror.Forbidden("forbidden")
+	}
+
+	if inv.Status != invitation.PendingStatus {
+		return apperror.Conflict("invitation already used")
+	}
+
+	now := time.Now()
+
+	// Просроченное приглашение отклонять уже незачем, просто фиксируем его статус.
+	status := invitation.DeclinedStatus
+
+	if inv.IsExpired(now) {
+		status = invitation.ExpiredStatus
+	}
+
+	err = e.invitationStatusUpdater.UpdateInvitationStatus(ctx, inv.Id, status, now)
+
+	if err != nil {
+		if errors.Is(err, invitation.ErrNotPending) {
+			return apperror.Conflict("invitation already used")
+		}
+
+		return err
+	}
+
+	if status == invitation.ExpiredStatus {
+		return apperror.Conflict("invitation expired")
+	}
+
+	return nil
+}
+
+func (e *executor) find(ctx context.Context, in DeclineInput) (*invitation.Model, error) {
+	var inv *invitation.Model
+	var err error
+
+	if in.InvitationId != 0 {
+		inv, err = e.invitationGetter.GetInvitation(ctx, in.InvitationId)
+	} else {
+		hash, verifyErr := e.tokenVerifier.Verify(in.Token)
+
+		if verifyErr != nil {
+			return nil, apperror.Validation("invalid token")
+		}
+
+		inv, err = e.invitationGetter.GetInvitationByTokenHash(ctx, hash)
+	}
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("invitation not found")
+		}
+
+		return nil, err
+	}
+
+	return inv, nil
+}
@@ -0,0 +1,167 @@
+package decline
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/team/invitation"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubTokenVerifier struct{}
+
+func (s *stubTokenVerifier) Verify(token string) (string, error) {
+	if token == "forged" {
+		return "", invitation.ErrInvalidToken
+	}
+
+	return "hash-" + token, nil
+}
+
+type stubInvitationGetter struct {
+	invitations map[string]*invitation.Model
+}
+
+func (s *stubInvitationGetter) GetInvitation(ctx context.Context, id int) (*invitation.Model, error) {
+	for _, inv := range s.invitations {
+		if inv.Id == id {
+			return inv, nil
+		}
+	}
+
+	return nil, sql.ErrNoRows
+}
+
+func (s *stubInvitationGetter) GetInvitationByTokenHash(ctx context.Context, hash string) (*invitation.Model, error) {
+	inv, ok := s.invitations[hash]
+
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return inv, nil
+}
+
+type mockInvitationStatusUpdater struct {
+	status invitation.Status
+}
+
+func (m *mockInvitationStatusUpdater) UpdateInvitationStatus(ctx context.Context, id int, status invitation.Status, respondedAt time.Time) error {
+	m.status = status
+
+	return nil
+}
+
+type stubUserGetter struct{}
+
+func (s *stubUserGetter) GetUserById(ctx context.Context, id int) (*user.Model, error) {
+	return &user.Model{Id: id}, nil
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	invitations := map[string]*invitation.Model{
+		"hash-pending":  {Id: 1, TeamId: 10, InviteeUserId: 1, Role: member.NormalRole, Status: invitation.PendingStatus, ExpiresAt: future},
+		"hash-declined": {Id: 2, TeamId: 10, InviteeUserId: 1, Role: member.NormalRole, Status: invitation.DeclinedStatus, ExpiresAt: future},
+		"hash-expired":  {Id: 3, TeamId: 10, InviteeUserId: 1, Role: member.NormalRole, Status: invitation.PendingStatus, ExpiresAt: past},
+		"hash-by-email": {Id: 4, TeamId: 10, InviteeEmail: "x@y.z", Role: member.NormalRole, Status: invitation.PendingStatus, ExpiresAt: future},
+	}
+
+	tests := []struct {
+		name         string
+		in           DeclineInput
+		wantErr      bool
+		expectedErr  error
+		expectStatus invitation.Status
+	}{
+		{
+			name:         "decline",
+			in:           DeclineInput{UserId: 1, Token: "pending"},
+			expectStatus: invitation.DeclinedStatus,
+		},
+		{
+			name:         "decline by invitation id",
+			in:           DeclineInput{UserId: 1, InvitationId: 1},
+			expectStatus: invitation.DeclinedStatus,
+		},
+		{
+			name:         "email invitation declined by token holder",
+			in:           DeclineInput{UserId: 2, Token: "by-email"},
+			expectStatus: invitation.DeclinedStatus,
+		},
+		{
+			name:        "email invitation needs the token",
+			in:          DeclineInput{UserId: 2, InvitationId: 4},
+			wantErr:     true,
+			expectedErr: errors.New("invitation not found"),
+		},
+		{
+			name:        "another user's invitation by invitation id",
+			in:          DeclineInput{UserId: 2, InvitationId: 1},
+			wantErr:     true,
+			expectedErr: errors.New("invitation not found"),
+		},
+		{
+			name:        "invitation for another user",
+			in:          DeclineInput{UserId: 2, Token: "pending"},
+			wantErr:     true,
+			expectedErr: errors.New("forbidden"),
+		},
+		{
+			name:        "forged token",
+			in:          DeclineInput{UserId: 1, Token: "forged"},
+			wantErr:     true,
+			expectedErr: errors.New("invalid token"),
+		},
+		{
+			name:        "unknown token",
+			in:          DeclineInput{UserId: 1, Token: "unknown"},
+			wantErr:     true,
+			expectedErr: errors.New("invitation not found"),
+		},
+		{
+			name:        "already declined",
+			in:          DeclineInput{UserId: 1, Token: "declined"},
+			wantErr:     true,
+			expectedErr: errors.New("invitation already used"),
+		},
+		{
+			name:         "expired",
+			in:           DeclineInput{UserId: 1, Token: "expired"},
+			wantErr:      true,
+			expectedErr:  errors.New("invitation expired"),
+			expectStatus: invitation.ExpiredStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statusUpdater := &mockInvitationStatusUpdater{}
+
+			e := NewExecutor(
+				&stubTokenVerifier{},
+				&stubInvitationGetter{invitations: invitations},
+				statusUpdater,
+				&stubUserGetter{},
+			)
+
+			err := e.Execute(context.Background(), tt.in)
+
+			if tt.wantErr {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.expectStatus, statusUpdater.status)
+		})
+	}
+}
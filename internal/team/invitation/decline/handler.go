@@ -0,0 +1,87 @@
+package decline
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+type request struct {
+	Token string `json:"token"`
+}
+
+type Executor interface {
+	Execute(ctx context.Context, in DeclineInput) error
+}
+
+type handler struct {
+	exec Executor
+}
+
+func NewHandler(exec Executor) *handler {
+	return &handler{
+		exec: exec,
+	}
+}
+
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	userIdAny := r.Context().Value("userId")
+
+	userId, ok := userIdAny.(int)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
+	in, err := parseInput(r, userId)
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	if err := h.exec.Execute(r.Context(), *in); err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseInput берёт номер приглашения из пути, а если его там нет — токен из тела запроса.
+func parseInput(r *http.Request, userId int) (*DeclineInput, error) {
+	if invitationIdStr := chi.URLParam(r, "invitationId"); invitationIdStr != "" {
+		invitationId, err := strconv.Atoi(invitationIdStr)
+
+		if err != nil {
+			return nil, apperror.Validation("invalid invitation id")
+		}
+
+		return &DeclineInput{UserId: userId, InvitationId: invitationId}, nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	defer r.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var req request
+
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, apperror.Validation("invalid json")
+	}
+
+	return &DeclineInput{UserId: userId, Token: req.Token}, nil
+}
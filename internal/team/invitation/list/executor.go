@@ -0,0 +1,74 @@
+package list
+
+import (
+	"context"
+	"time"
+
+	"mkk-luna-test-task/internal/team/invitation"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/user"
+)
+
+type userGetter interface {
+	GetUserById(ctx context.Context, id int) (*user.Model, error)
+}
+
+type invitationLister interface {
+	ListUserInvitations(ctx context.Context, userId int, username string, now time.Time) ([]invitation.Model, error)
+}
+
+type executor struct {
+	userGetter       userGetter
+	invitationLister invitationLister
+}
+
+func NewExecutor(userGetter userGetter, invitationLister invitationLister) *executor {
+	return &executor{
+		userGetter:       userGetter,
+		invitationLister: invitationLister,
+	}
+}
+
+type ListInput struct {
+	UserId int
+}
+
+type InvitationItem struct {
+	Id        int         `json:"id"`
+	TeamId    int         `json:"team_id"`
+	InviterId int         `json:"inviter_id"`
+	Role      member.Role `json:"role"`
+	CreatedAt time.Time   `json:"created_at"`
+	ExpiresAt time.Time   `json:"expires_at"`
+}
+
+// Execute возвращает приглашения, на которые пользователь может ответить по номеру.
+// Приглашения по email сюда не попадают: их принимают по токену из письма.
+func (e *executor) Execute(ctx context.Context, in ListInput) ([]InvitationItem, error) {
+	u, err := e.userGetter.GetUserById(ctx, in.UserId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := e.invitationLister.ListUserInvitations(ctx, u.Id, u.Username, time.Now())
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]InvitationItem, 0, len(invitations))
+
+	for _, inv := range invitations {
+		items = append(items, InvitationItem{
+			Id:        inv.Id,
+			TeamId:    inv.TeamId,
+			InviterId: inv.InviterId,
+			Role:      inv.Role,
+			CreatedAt: inv.CreatedAt,
+			ExpiresAt: inv.ExpiresAt,
+		})
+	}
+
+	return items, nil
+}
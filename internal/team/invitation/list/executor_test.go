@@ -0,0 +1,108 @@
+package list
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/team/invitation"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubUserGetter struct {
+	err error
+}
+
+func (s *stubUserGetter) GetUserById(ctx context.Context, id int) (*user.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &user.Model{Id: id, Username: "dev"}, nil
+}
+
+type stubInvitationLister struct {
+	invitations []invitation.Model
+	err         error
+
+	gotUserId   int
+	gotUsername string
+}
+
+func (s *stubInvitationLister) ListUserInvitations(ctx context.Context, userId int, username string, now time.Time) ([]invitation.Model, error) {
+	s.gotUserId = userId
+	s.gotUsername = username
+
+	return s.invitations, s.err
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	createdAt := time.Unix(100, 0)
+	expiresAt := time.Unix(200, 0)
+
+	tests := []struct {
+		name        string
+		userGetter  *stubUserGetter
+		lister      *stubInvitationLister
+		want        []InvitationItem
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name:       "lists invitations by id and username",
+			userGetter: &stubUserGetter{},
+			lister: &stubInvitationLister{invitations: []invitation.Model{
+				{Id: 3, TeamId: 10, InviterId: 1, InviteeUsername: "dev", Role: member.NormalRole, TokenHash: "h3", CreatedAt: createdAt, ExpiresAt: expiresAt},
+				{Id: 2, TeamId: 11, InviterId: 4, InviteeUserId: 5, Role: member.AdminRole, TokenHash: "h2", CreatedAt: createdAt, ExpiresAt: expiresAt},
+			}},
+			want: []InvitationItem{
+				{Id: 3, TeamId: 10, InviterId: 1, Role: member.NormalRole, CreatedAt: createdAt, ExpiresAt: expiresAt},
+				{Id: 2, TeamId: 11, InviterId: 4, Role: member.AdminRole, CreatedAt: createdAt, ExpiresAt: expiresAt},
+			},
+		},
+		{
+			name:       "no invitations",
+			userGetter: &stubUserGetter{},
+			lister:     &stubInvitationLister{},
+			want:       []InvitationItem{},
+		},
+		{
+			name:        "user getter error",
+			userGetter:  &stubUserGetter{err: errors.New("db error")},
+			lister:      &stubInvitationLister{},
+			wantErr:     true,
+			expectedErr: errors.New("db error"),
+		},
+		{
+			name:        "lister error",
+			userGetter:  &stubUserGetter{},
+			lister:      &stubInvitationLister{err: errors.New("db error")},
+			wantErr:     true,
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(tt.userGetter, tt.lister)
+
+			got, err := e.Execute(context.Background(), ListInput{UserId: 5})
+
+			if tt.wantErr {
+				assert.Nil(t, got)
+				assert.EqualError(t, err, tt.expectedErr.Error())
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, 5, tt.lister.gotUserId)
+			assert.Equal(t, "dev", tt.lister.gotUsername)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package list
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+type response struct {
+	Invitations []InvitationItem `json:"invitations"`
+}
+
+type Executor interface {
+	Execute(ctx context.Context, in ListInput) ([]InvitationItem, error)
+}
+
+type handler struct {
+	exec Executor
+}
+
+func NewHandler(exec Executor) *handler {
+	return &handler{
+		exec: exec,
+	}
+}
+
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	userIdAny := r.Context().Value("userId")
+
+	userId, ok := userIdAny.(int)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
+	items, err := h.exec.Execute(r.Context(), ListInput{UserId: userId})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	respBody, err := json.Marshal(response{Invitations: items})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
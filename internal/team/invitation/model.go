@@ -0,0 +1,63 @@
+package invitation
+
+import (
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/user"
+)
+
+type Status string
+
+const PendingStatus Status = "pending"
+const AcceptedStatus Status = "accepted"
+const DeclinedStatus Status = "declined"
+const ExpiredStatus Status = "expired"
+
+var ErrNotPending = apperror.Conflict("invitation is not pending")
+
+type Model struct {
+	Id              int
+	TeamId          int
+	InviterId       int
+	InviteeUserId   int
+	InviteeUsername string
+	InviteeEmail    string
+	Role            member.Role
+	Status          Status
+	TokenHash       string
+	CreatedAt       time.Time
+	ExpiresAt       time.Time
+	RespondedAt     *time.Time
+}
+
+func (m *Model) IsExpired(now time.Time) bool {
+	return !now.Before(m.ExpiresAt)
+}
+
+// IsAddressedTo — приглашение выписано на этот аккаунт: по id или по имени.
+// У аккаунтов нет email, поэтому приглашение по email так не сопоставить — см. IsByEmail.
+func (m *Model) IsAddressedTo(u user.Model) bool {
+	if m.InviteeUserId != 0 {
+		return m.InviteeUserId == u.Id
+	}
+
+	if m.InviteeUsername != "" {
+		return m.InviteeUsername == u.Username
+	}
+
+	return false
+}
+
+// IsByEmail — приглашение только по email. Его принимает тот, кто предъявил токен из письма:
+// токен уходит лишь на этот адрес, так что владение им и есть подтверждение.
+func (m *Model) IsByEmail() bool {
+	return m.InviteeUserId == 0 && m.InviteeUsername == ""
+}
+
+// CanBeAnsweredBy — пользователь может принять или отклонить приглашение.
+// byToken — приглашение найдено по токену, а не по номеру.
+func (m *Model) CanBeAnsweredBy(u user.Model, byToken bool) bool {
+	return m.IsAddressedTo(u) || (byToken && m.IsByEmail())
+}
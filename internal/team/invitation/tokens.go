@@ -0,0 +1,67 @@
+package invitation
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+var ErrInvalidToken = apperror.Validation("invalid invitation token")
+
+type TokenSigner struct {
+	secret []byte
+}
+
+func NewTokenSigner(secret []byte) *TokenSigner {
+	return &TokenSigner{
+		secret: secret,
+	}
+}
+
+// Токен — случайная часть и её подпись, поддельный токен отсекается без похода в базу.
+// В базе храним только хэш токена целиком.
+func (s *TokenSigner) Issue() (token string, hash string, err error) {
+	b := make([]byte, 32)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(b)
+
+	token = payload + "." + s.sign(payload)
+
+	return token, hashToken(token), nil
+}
+
+func (s *TokenSigner) Verify(token string) (hash string, err error) {
+	payload, signature, ok := strings.Cut(token, ".")
+
+	if !ok || payload == "" {
+		return "", ErrInvalidToken
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.sign(payload))) {
+		return "", ErrInvalidToken
+	}
+
+	return hashToken(token), nil
+}
+
+func (s *TokenSigner) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,32 @@
+package invitation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenSigner(t *testing.T) {
+	signer := NewTokenSigner([]byte("secret"))
+
+	token, hash, err := signer.Issue()
+
+	assert.NoError(t, err)
+
+	verifiedHash, err := signer.Verify(token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, hash, verifiedHash)
+
+	_, err = NewTokenSigner([]byte("other secret")).Verify(token)
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = signer.Verify(token + "x")
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = signer.Verify("garbage")
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
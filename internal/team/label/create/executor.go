@@ -0,0 +1,52 @@
+package create
+
+import (
+	"context"
+
+	"mkk-luna-test-task/internal/team/label"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type labelCreator interface {
+	CreateLabel(ctx context.Context, l label.Model) (*label.Model, error)
+}
+
+type executor struct {
+	authorizer   authorizer
+	labelCreator labelCreator
+}
+
+func NewExecutor(authorizer authorizer, labelCreator labelCreator) *executor {
+	return &executor{
+		authorizer:   authorizer,
+		labelCreator: labelCreator,
+	}
+}
+
+type CreateInput struct {
+	UserId int
+	TeamId int
+	Name   string
+	Color  string
+}
+
+func (e *executor) Execute(ctx context.Context, in CreateInput) (*label.Model, error) {
+	l := label.Model{TeamId: in.TeamId, Name: in.Name, Color: in.Color}
+
+	if err := l.Normalize(); err != nil {
+		return nil, err
+	}
+
+	_, err := e.authorizer.Authorize(ctx, in.UserId, permission.TeamLabels, permission.TeamResource(in.TeamId))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return e.labelCreator.CreateLabel(ctx, l)
+}
@@ -0,0 +1,96 @@
+package create
+
+import (
+	"context"
+	"testing"
+
+	"mkk-luna-test-task/internal/team/label"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubAuthorizer struct {
+	err error
+}
+
+func (s *stubAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.AdminRole}, nil
+}
+
+type mockLabelCreator struct {
+	err     error
+	created *label.Model
+}
+
+func (m *mockLabelCreator) CreateLabel(ctx context.Context, l label.Model) (*label.Model, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	l.Id = 7
+	m.created = &l
+
+	return &l, nil
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		in          CreateInput
+		authorizer  *stubAuthorizer
+		creator     *mockLabelCreator
+		want        *label.Model
+		expectedErr string
+	}{
+		{
+			name:       "created normalized",
+			in:         CreateInput{UserId: 1, TeamId: 10, Name: " bug ", Color: "#D73A4A"},
+			authorizer: &stubAuthorizer{},
+			creator:    &mockLabelCreator{},
+			want:       &label.Model{Id: 7, TeamId: 10, Name: "bug", Color: "#d73a4a"},
+		},
+		{
+			name:        "invalid colour",
+			in:          CreateInput{UserId: 1, TeamId: 10, Name: "bug", Color: "red"},
+			authorizer:  &stubAuthorizer{},
+			creator:     &mockLabelCreator{},
+			expectedErr: "validation failed",
+		},
+		{
+			name:        "normal member cannot manage catalog",
+			in:          CreateInput{UserId: 3, TeamId: 10, Name: "bug", Color: "#d73a4a"},
+			authorizer:  &stubAuthorizer{err: permission.ErrForbidden},
+			creator:     &mockLabelCreator{},
+			expectedErr: "forbidden",
+		},
+		{
+			name:        "duplicate name",
+			in:          CreateInput{UserId: 1, TeamId: 10, Name: "bug", Color: "#d73a4a"},
+			authorizer:  &stubAuthorizer{},
+			creator:     &mockLabelCreator{err: label.ErrDuplicate},
+			expectedErr: "label with this name already exists",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewExecutor(tt.authorizer, tt.creator).Execute(context.Background(), tt.in)
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Nil(t, got)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
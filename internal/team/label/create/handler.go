@@ -0,0 +1,95 @@
+package create
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/team/label"
+)
+
+type request struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type Executor interface {
+	Execute(ctx context.Context, in CreateInput) (*label.Model, error)
+}
+
+type handler struct {
+	exec Executor
+}
+
+func NewHandler(exec Executor) *handler {
+	return &handler{
+		exec: exec,
+	}
+}
+
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	userIdAny := r.Context().Value("userId")
+
+	userId, ok := userIdAny.(int)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
+	teamId, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("invalid team id"))
+
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	defer r.Body.Close()
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	var req request
+
+	if err := json.Unmarshal(body, &req); err != nil {
+		apperror.Write(w, apperror.Validation("invalid json"))
+
+		return
+	}
+
+	l, err := h.exec.Execute(r.Context(), CreateInput{
+		UserId: userId,
+		TeamId: teamId,
+		Name:   req.Name,
+		Color:  req.Color,
+	})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	respBody, err := json.Marshal(l)
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(respBody)
+}
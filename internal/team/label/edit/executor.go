@@ -0,0 +1,85 @@
+package edit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"mkk-luna-test-task/internal/team/label"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type labelGetter interface {
+	GetLabel(ctx context.Context, id int) (*label.Model, error)
+}
+
+type labelUpdater interface {
+	UpdateLabel(ctx context.Context, l label.Model) error
+}
+
+type executor struct {
+	authorizer   authorizer
+	labelGetter  labelGetter
+	labelUpdater labelUpdater
+}
+
+func NewExecutor(authorizer authorizer, labelGetter labelGetter, labelUpdater labelUpdater) *executor {
+	return &executor{
+		authorizer:   authorizer,
+		labelGetter:  labelGetter,
+		labelUpdater: labelUpdater,
+	}
+}
+
+type EditInput struct {
+	UserId  int
+	TeamId  int
+	LabelId int
+	Name    string
+	Color   string
+}
+
+// Execute переименовывает или перекрашивает метку; на задачах она остаётся, история хранит прежнее имя.
+func (e *executor) Execute(ctx context.Context, in EditInput) (*label.Model, error) {
+	updated := label.Model{Id: in.LabelId, TeamId: in.TeamId, Name: in.Name, Color: in.Color}
+
+	if err := updated.Normalize(); err != nil {
+		return nil, err
+	}
+
+	_, err := e.authorizer.Authorize(ctx, in.UserId, permission.TeamLabels, permission.TeamResource(in.TeamId))
+
+	if err != nil {
+		return nil, err
+	}
+
+	old, err := e.labelGetter.GetLabel(ctx, in.LabelId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, label.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	// Метку другой команды не показываем даже по прямой ссылке.
+	if old.TeamId != in.TeamId {
+		return nil, label.ErrNotFound
+	}
+
+	if *old == updated {
+		return old, nil
+	}
+
+	if err := e.labelUpdater.UpdateLabel(ctx, updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
@@ -0,0 +1,134 @@
+package edit
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"mkk-luna-test-task/internal/team/label"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubAuthorizer struct {
+	err error
+}
+
+func (s *stubAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.AdminRole}, nil
+}
+
+type stubLabelGetter struct {
+	label *label.Model
+}
+
+func (s *stubLabelGetter) GetLabel(ctx context.Context, id int) (*label.Model, error) {
+	if s.label == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	l := *s.label
+
+	return &l, nil
+}
+
+type mockLabelUpdater struct {
+	err     error
+	updated *label.Model
+}
+
+func (m *mockLabelUpdater) UpdateLabel(ctx context.Context, l label.Model) error {
+	m.updated = &l
+
+	return m.err
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	bug := &label.Model{Id: 5, TeamId: 10, Name: "bug", Color: "#d73a4a"}
+
+	tests := []struct {
+		name        string
+		in          EditInput
+		authorizer  *stubAuthorizer
+		getter      *stubLabelGetter
+		updater     *mockLabelUpdater
+		want        *label.Model
+		wantUpdate  bool
+		expectedErr string
+	}{
+		{
+			name:       "renamed",
+			in:         EditInput{UserId: 1, TeamId: 10, LabelId: 5, Name: "defect", Color: "#d73a4a"},
+			authorizer: &stubAuthorizer{},
+			getter:     &stubLabelGetter{label: bug},
+			updater:    &mockLabelUpdater{},
+			want:       &label.Model{Id: 5, TeamId: 10, Name: "defect", Color: "#d73a4a"},
+			wantUpdate: true,
+		},
+		{
+			name:       "nothing changed",
+			in:         EditInput{UserId: 1, TeamId: 10, LabelId: 5, Name: "bug", Color: "#D73A4A"},
+			authorizer: &stubAuthorizer{},
+			getter:     &stubLabelGetter{label: bug},
+			updater:    &mockLabelUpdater{},
+			want:       bug,
+		},
+		{
+			name:        "label of another team",
+			in:          EditInput{UserId: 1, TeamId: 11, LabelId: 5, Name: "defect", Color: "#d73a4a"},
+			authorizer:  &stubAuthorizer{},
+			getter:      &stubLabelGetter{label: bug},
+			updater:     &mockLabelUpdater{},
+			expectedErr: "label not found",
+		},
+		{
+			name:        "unknown label",
+			in:          EditInput{UserId: 1, TeamId: 10, LabelId: 6, Name: "defect", Color: "#d73a4a"},
+			authorizer:  &stubAuthorizer{},
+			getter:      &stubLabelGetter{},
+			updater:     &mockLabelUpdater{},
+			expectedErr: "label not found",
+		},
+		{
+			name:        "forbidden",
+			in:          EditInput{UserId: 3, TeamId: 10, LabelId: 5, Name: "defect", Color: "#d73a4a"},
+			authorizer:  &stubAuthorizer{err: permission.ErrForbidden},
+			getter:      &stubLabelGetter{label: bug},
+			updater:     &mockLabelUpdater{},
+			expectedErr: "forbidden",
+		},
+		{
+			name:        "name taken",
+			in:          EditInput{UserId: 1, TeamId: 10, LabelId: 5, Name: "ui", Color: "#d73a4a"},
+			authorizer:  &stubAuthorizer{},
+			getter:      &stubLabelGetter{label: bug},
+			updater:     &mockLabelUpdater{err: label.ErrDuplicate},
+			wantUpdate:  true,
+			expectedErr: "label with this name already exists",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewExecutor(tt.authorizer, tt.getter, tt.updater).Execute(context.Background(), tt.in)
+
+			assert.Equal(t, tt.wantUpdate, tt.updater.updated != nil)
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Nil(t, got)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
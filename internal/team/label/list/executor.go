@@ -0,0 +1,45 @@
+package list
+
+import (
+	"context"
+
+	"mkk-luna-test-task/internal/team/label"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type labelLister interface {
+	ListLabels(ctx context.Context, teamId int) ([]label.Model, error)
+}
+
+type executor struct {
+	authorizer  authorizer
+	labelLister labelLister
+}
+
+func NewExecutor(authorizer authorizer, labelLister labelLister) *executor {
+	return &executor{
+		authorizer:  authorizer,
+		labelLister: labelLister,
+	}
+}
+
+type ListInput struct {
+	UserId int
+	TeamId int
+}
+
+func (e *executor) Execute(ctx context.Context, in ListInput) ([]label.Model, error) {
+	// Каталог нужен всем, кто работает с задачами команды, поэтому достаточно права на просмотр задач.
+	_, err := e.authorizer.Authorize(ctx, in.UserId, permission.TaskView, permission.TeamResource(in.TeamId))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return e.labelLister.ListLabels(ctx, in.TeamId)
+}
@@ -0,0 +1,81 @@
+package list
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"mkk-luna-test-task/internal/team/label"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubAuthorizer struct {
+	err error
+}
+
+func (s *stubAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
+}
+
+type stubLabelLister struct {
+	labels []label.Model
+	err    error
+}
+
+func (s *stubLabelLister) ListLabels(ctx context.Context, teamId int) ([]label.Model, error) {
+	return s.labels, s.err
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	labels := []label.Model{{Id: 1, TeamId: 10, Name: "bug", Color: "#d73a4a"}}
+
+	tests := []struct {
+		name        string
+		authorizer  *stubAuthorizer
+		lister      *stubLabelLister
+		want        []label.Model
+		expectedErr string
+	}{
+		{
+			name:       "member sees catalog",
+			authorizer: &stubAuthorizer{},
+			lister:     &stubLabelLister{labels: labels},
+			want:       labels,
+		},
+		{
+			name:        "not a member",
+			authorizer:  &stubAuthorizer{err: permission.ErrForbidden},
+			lister:      &stubLabelLister{labels: labels},
+			expectedErr: "forbidden",
+		},
+		{
+			name:        "db error",
+			authorizer:  &stubAuthorizer{},
+			lister:      &stubLabelLister{err: errors.New("db error")},
+			expectedErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewExecutor(tt.authorizer, tt.lister).Execute(context.Background(), ListInput{UserId: 1, TeamId: 10})
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Nil(t, got)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package label
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+const maxNameLength = 64
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+var (
+	ErrNotFound  = apperror.NotFound("label not found")
+	ErrDuplicate = apperror.Conflict("label with this name already exists")
+)
+
+// Model — метка из каталога команды. Метки одной команды различаются по имени.
+type Model struct {
+	Id     int    `json:"id"`
+	TeamId int    `json:"team_id"`
+	Name   string `json:"name"`
+	Color  string `json:"color"`
+}
+
+// Normalize приводит имя и цвет к виду, в котором они хранятся, и проверяет их.
+// В имени нельзя запятую: в фильтре списка задач метки перечисляются через неё.
+func (m *Model) Normalize() error {
+	m.Name = strings.TrimSpace(m.Name)
+	m.Color = strings.ToLower(strings.TrimSpace(m.Color))
+
+	var fields []apperror.FieldError
+
+	switch {
+	case m.Name == "":
+		fields = append(fields, apperror.FieldError{Field: "name", Message: "is required"})
+	case utf8.RuneCountInString(m.Name) > maxNameLength:
+		fields = append(fields, apperror.FieldError{Field: "name", Message: "must be at most 64 characters"})
+	case strings.Contains(m.Name, ","):
+		fields = append(fields, apperror.FieldError{Field: "name", Message: "must not contain commas"})
+	}
+
+	if !colorPattern.MatchString(m.Color) {
+		fields = append(fields, apperror.FieldError{Field: "color", Message: "must be a hex colour like #d73a4a"})
+	}
+
+	if len(fields) > 0 {
+		return apperror.InvalidFields(fields...)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package label
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel_Normalize(t *testing.T) {
+	tests := []struct {
+		name        string
+		label       Model
+		want        Model
+		expectedErr string
+	}{
+		{
+			name:  "trimmed and lowercased",
+			label: Model{Name: "  bug ", Color: "#D73A4A"},
+			want:  Model{Name: "bug", Color: "#d73a4a"},
+		},
+		{
+			name:  "unicode name",
+			label: Model{Name: "срочно", Color: "#ffffff"},
+			want:  Model{Name: "срочно", Color: "#ffffff"},
+		},
+		{
+			name:        "empty name",
+			label:       Model{Name: "  ", Color: "#ffffff"},
+			expectedErr: "validation failed",
+		},
+		{
+			name:        "comma in name",
+			label:       Model{Name: "bug,ui", Color: "#ffffff"},
+			expectedErr: "validation failed",
+		},
+		{
+			name:        "short colour",
+			label:       Model{Name: "bug", Color: "#fff"},
+			expectedErr: "validation failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.label.Normalize()
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, tt.label)
+		})
+	}
+}
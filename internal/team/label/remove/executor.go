@@ -0,0 +1,69 @@
+package remove
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"mkk-luna-test-task/internal/team/label"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type labelGetter interface {
+	GetLabel(ctx context.Context, id int) (*label.Model, error)
+}
+
+type labelDeleter interface {
+	DeleteLabel(ctx context.Context, l label.Model, deletedBy int, deletedAt time.Time) error
+}
+
+type executor struct {
+	authorizer   authorizer
+	labelGetter  labelGetter
+	labelDeleter labelDeleter
+}
+
+func NewExecutor(authorizer authorizer, labelGetter labelGetter, labelDeleter labelDeleter) *executor {
+	return &executor{
+		authorizer:   authorizer,
+		labelGetter:  labelGetter,
+		labelDeleter: labelDeleter,
+	}
+}
+
+type RemoveInput struct {
+	UserId  int
+	TeamId  int
+	LabelId int
+}
+
+// Execute удаляет метку из каталога; с задач она снимается с записью в их историю.
+func (e *executor) Execute(ctx context.Context, in RemoveInput) error {
+	_, err := e.authorizer.Authorize(ctx, in.UserId, permission.TeamLabels, permission.TeamResource(in.TeamId))
+
+	if err != nil {
+		return err
+	}
+
+	l, err := e.labelGetter.GetLabel(ctx, in.LabelId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return label.ErrNotFound
+		}
+
+		return err
+	}
+
+	if l.TeamId != in.TeamId {
+		return label.ErrNotFound
+	}
+
+	return e.labelDeleter.DeleteLabel(ctx, *l, in.UserId, time.Now())
+}
@@ -0,0 +1,115 @@
+package remove
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/team/label"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubAuthorizer struct {
+	err error
+}
+
+func (s *stubAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.AdminRole}, nil
+}
+
+type stubLabelGetter struct {
+	label *label.Model
+}
+
+func (s *stubLabelGetter) GetLabel(ctx context.Context, id int) (*label.Model, error) {
+	if s.label == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	l := *s.label
+
+	return &l, nil
+}
+
+type mockLabelDeleter struct {
+	deleted   *label.Model
+	deletedBy int
+}
+
+func (m *mockLabelDeleter) DeleteLabel(ctx context.Context, l label.Model, deletedBy int, deletedAt time.Time) error {
+	m.deleted = &l
+	m.deletedBy = deletedBy
+
+	return nil
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	bug := &label.Model{Id: 5, TeamId: 10, Name: "bug", Color: "#d73a4a"}
+
+	tests := []struct {
+		name        string
+		in          RemoveInput
+		authorizer  *stubAuthorizer
+		getter      *stubLabelGetter
+		wantDeleted bool
+		expectedErr string
+	}{
+		{
+			name:        "deleted",
+			in:          RemoveInput{UserId: 1, TeamId: 10, LabelId: 5},
+			authorizer:  &stubAuthorizer{},
+			getter:      &stubLabelGetter{label: bug},
+			wantDeleted: true,
+		},
+		{
+			name:        "label of another team",
+			in:          RemoveInput{UserId: 1, TeamId: 11, LabelId: 5},
+			authorizer:  &stubAuthorizer{},
+			getter:      &stubLabelGetter{label: bug},
+			expectedErr: "label not found",
+		},
+		{
+			name:        "unknown label",
+			in:          RemoveInput{UserId: 1, TeamId: 10, LabelId: 6},
+			authorizer:  &stubAuthorizer{},
+			getter:      &stubLabelGetter{},
+			expectedErr: "label not found",
+		},
+		{
+			name:        "forbidden",
+			in:          RemoveInput{UserId: 3, TeamId: 10, LabelId: 5},
+			authorizer:  &stubAuthorizer{err: permission.ErrForbidden},
+			getter:      &stubLabelGetter{label: bug},
+			expectedErr: "forbidden",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleter := &mockLabelDeleter{}
+
+			err := NewExecutor(tt.authorizer, tt.getter, deleter).Execute(context.Background(), tt.in)
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantDeleted, deleter.deleted != nil)
+
+			if tt.wantDeleted {
+				assert.Equal(t, bug.Name, deleter.deleted.Name)
+				assert.Equal(t, tt.in.UserId, deleter.deletedBy)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+
+	"mkk-luna-test-task/internal/apperror"
 )
 
 type response struct {
@@ -30,7 +32,7 @@ func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
 	userId, ok := userIdAny.(int)
 
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
 
 		return
 	}
@@ -38,7 +40,7 @@ func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
 	result, err := h.exec.Execute(r.Context(), userId)
 
 	if err != nil {
-		http.Error(w, "error", http.StatusBadRequest)
+		apperror.Write(w, err)
 
 		return
 	}
@@ -48,7 +50,7 @@ func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
 	respBody, err := json.Marshal(resp)
 
 	if err != nil {
-		http.Error(w, "json error", http.StatusInternalServerError)
+		apperror.Write(w, err)
 
 		return
 	}
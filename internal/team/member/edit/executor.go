@@ -0,0 +1,101 @@
+package edit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type memberGetter interface {
+	GetMember(ctx context.Context, teamId, userId int) (*member.Model, error)
+}
+
+type memberRoleUpdater interface {
+	UpdateMemberRole(ctx context.Context, teamId, userId int, role member.Role) error
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type executor struct {
+	authorizer        authorizer
+	memberGetter      memberGetter
+	memberRoleUpdater memberRoleUpdater
+}
+
+func NewExecutor(authorizer authorizer, memberGetter memberGetter, memberRoleUpdater memberRoleUpdater) *executor {
+	return &executor{
+		authorizer:        authorizer,
+		memberGetter:      memberGetter,
+		memberRoleUpdater: memberRoleUpdater,
+	}
+}
+
+type EditInput struct {
+	ActorUserId  int
+	TeamId       int
+	TargetUserId int
+	Role         member.Role
+}
+
+type EditResult struct {
+	UserId int
+	TeamId int
+	Role   member.Role
+}
+
+func (e *executor) Execute(ctx context.Context, in EditInput) (*EditResult, error) {
+	if !in.Role.IsValid() {
+		return nil, apperror.Validation("invalid role")
+	}
+
+	// Владелец появляется только через передачу владения, иначе легко остаться с двумя.
+	if in.Role == member.OwnerRole {
+		return nil, apperror.Validation("use ownership transfer")
+	}
+
+	if in.ActorUserId == in.TargetUserId {
+		return nil, apperror.Validation("cannot change own role")
+	}
+
+	actor, err := e.authorizer.Authorize(ctx, in.ActorUserId, permission.MemberManage, permission.TeamResource(in.TeamId))
+
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := e.memberGetter.GetMember(ctx, in.TeamId, in.TargetUserId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("member not found")
+		}
+
+		return nil, err
+	}
+
+	if !actor.Role.Outranks(target.Role) {
+		return nil, permission.ErrForbidden
+	}
+
+	if !actor.Role.CanGrant(in.Role) {
+		return nil, permission.ErrForbidden
+	}
+
+	if target.Role != in.Role {
+		if err := e.memberRoleUpdater.UpdateMemberRole(ctx, in.TeamId, in.TargetUserId, in.Role); err != nil {
+			return nil, err
+		}
+	}
+
+	return &EditResult{
+		UserId: in.TargetUserId,
+		TeamId: in.TeamId,
+		Role:   in.Role,
+	}, nil
+}
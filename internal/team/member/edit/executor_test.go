@@ -0,0 +1,164 @@
+package edit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubMemberGetter struct {
+	roles map[int]member.Role
+}
+
+func (s *stubMemberGetter) GetMember(ctx context.Context, teamId, userId int) (*member.Model, error) {
+	role, ok := s.roles[userId]
+
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return &member.Model{UserId: userId, TeamId: teamId, Role: role}, nil
+}
+
+type mockMemberRoleUpdater struct {
+	called bool
+	role   member.Role
+}
+
+func (m *mockMemberRoleUpdater) UpdateMemberRole(ctx context.Context, teamId, userId int, role member.Role) error {
+	m.called = true
+	m.role = role
+
+	return nil
+}
+
+const (
+	ownerId  = 1
+	adminId  = 2
+	normalId = 3
+	otherId  = 4
+	outsider = 99
+)
+
+func teamRoles() map[int]member.Role {
+	return map[int]member.Role{
+		ownerId:  member.OwnerRole,
+		adminId:  member.AdminRole,
+		normalId: member.NormalRole,
+		otherId:  member.NormalRole,
+	}
+}
+
+type stubOverridesLister struct{}
+
+func (s *stubOverridesLister) ListPermissionOverrides(ctx context.Context, teamId int) ([]permission.Override, error) {
+	return nil, nil
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	tests := []struct {
+		name         string
+		in           EditInput
+		wantErr      bool
+		expectedErr  error
+		expectUpdate bool
+	}{
+		{
+			name:         "owner promotes normal to admin",
+			in:           EditInput{ActorUserId: ownerId, TargetUserId: normalId, Role: member.AdminRole},
+			expectUpdate: true,
+		},
+		{
+			name:         "owner demotes admin",
+			in:           EditInput{ActorUserId: ownerId, TargetUserId: adminId, Role: member.NormalRole},
+			expectUpdate: true,
+		},
+		{
+			name:         "same role is a no-op",
+			in:           EditInput{ActorUserId: ownerId, TargetUserId: adminId, Role: member.AdminRole},
+			expectUpdate: false,
+		},
+		{
+			name:        "admin cannot create admins",
+			in:          EditInput{ActorUserId: adminId, TargetUserId: normalId, Role: member.AdminRole},
+			wantErr:     true,
+			expectedErr: errors.New("forbidden"),
+		},
+		{
+			name:        "admin cannot demote owner",
+			in:          EditInput{ActorUserId: adminId, TargetUserId: ownerId, Role: member.NormalRole},
+			wantErr:     true,
+			expectedErr: errors.New("forbidden"),
+		},
+		{
+			name:        "normal cannot change roles",
+			in:          EditInput{ActorUserId: normalId, TargetUserId: otherId, Role: member.NormalRole},
+			wantErr:     true,
+			expectedErr: errors.New("forbidden"),
+		},
+		{
+			name:        "owner role only via transfer",
+			in:          EditInput{ActorUserId: ownerId, TargetUserId: adminId, Role: member.OwnerRole},
+			wantErr:     true,
+			expectedErr: errors.New("use ownership transfer"),
+		},
+		{
+			name:        "invalid role",
+			in:          EditInput{ActorUserId: ownerId, TargetUserId: adminId, Role: "superuser"},
+			wantErr:     true,
+			expectedErr: errors.New("invalid role"),
+		},
+		{
+			name:        "own role",
+			in:          EditInput{ActorUserId: ownerId, TargetUserId: ownerId, Role: member.AdminRole},
+			wantErr:     true,
+			expectedErr: errors.New("cannot change own role"),
+		},
+		{
+			name:        "actor outside team",
+			in:          EditInput{ActorUserId: outsider, TargetUserId: normalId, Role: member.NormalRole},
+			wantErr:     true,
+			expectedErr: errors.New("forbidden"),
+		},
+		{
+			name:        "target outside team",
+			in:          EditInput{ActorUserId: ownerId, TargetUserId: outsider, Role: member.NormalRole},
+			wantErr:     true,
+			expectedErr: errors.New("member not found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updater := &mockMemberRoleUpdater{}
+
+			getter := &stubMemberGetter{roles: teamRoles()}
+
+			e := NewExecutor(permission.NewEngine(getter, &stubOverridesLister{}), getter, updater)
+
+			tt.in.TeamId = 10
+
+			got, err := e.Execute(context.Background(), tt.in)
+
+			if tt.wantErr {
+				assert.Nil(t, got)
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, &EditResult{UserId: tt.in.TargetUserId, TeamId: 10, Role: tt.in.Role}, got)
+			}
+
+			assert.Equal(t, tt.expectUpdate, updater.called)
+
+			if tt.expectUpdate {
+				assert.Equal(t, tt.in.Role, updater.role)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package edit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/team/member"
+)
+
+type request struct {
+	Role member.Role `json:"role"`
+}
+
+type response struct {
+	UserId int         `json:"user_id"`
+	TeamId int         `json:"team_id"`
+	Role   member.Role `json:"role"`
+}
+
+type Executor interface {
+	Execute(ctx context.Context, in EditInput) (*EditResult, error)
+}
+
+type handler struct {
+	exec Executor
+}
+
+func NewHandler(exec Executor) *handler {
+	return &handler{
+		exec: exec,
+	}
+}
+
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	userIdAny := r.Context().Value("userId")
+
+	userId, ok := userIdAny.(int)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
+	teamId, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("invalid team id"))
+
+		return
+	}
+
+	targetUserId, err := strconv.Atoi(chi.URLParam(r, "userId"))
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("invalid user id"))
+
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	defer r.Body.Close()
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	var req request
+
+	if err := json.Unmarshal(body, &req); err != nil {
+		apperror.Write(w, apperror.Validation("invalid json"))
+
+		return
+	}
+
+	result, err := h.exec.Execute(r.Context(), EditInput{
+		ActorUserId:  userId,
+		TeamId:       teamId,
+		TargetUserId: targetUserId,
+		Role:         req.Role,
+	})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	respBody, err := json.Marshal(response{UserId: result.UserId, TeamId: result.TeamId, Role: result.Role})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
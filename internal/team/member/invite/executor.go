@@ -2,38 +2,87 @@ package invite
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"log"
+	"time"
 
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/team/invitation"
 	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+	"mkk-luna-test-task/internal/user"
+	"mkk-luna-test-task/internal/user/notification"
 )
 
-type memberInviter interface {
-	InviteMember(ctx context.Context, userId, teamId int, role member.Role) (*member.Model, error)
+var (
+	ErrInvalidRole      = apperror.Validation("invalid role")
+	ErrInvalidInvitee   = apperror.Validation("specify exactly one of user_id, username or email")
+	ErrRoleNotGrantable = apperror.Forbidden("cannot grant this role")
+	ErrUserNotFound     = apperror.NotFound("user not found")
+	ErrAlreadyMember    = apperror.Conflict("already a member")
+)
+
+type invitationCreator interface {
+	CreateInvitation(ctx context.Context, inv invitation.Model) (*invitation.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type userGetter interface {
+	GetUser(ctx context.Context, username string) (*user.Model, error)
+	GetUserById(ctx context.Context, id int) (*user.Model, error)
+}
+
+type teamMembershipChecker interface {
+	CheckUserIsInTeam(userId, teamId int) (bool, error)
 }
 
-type memberInvitationChecker interface {
-	CanUserInvite(ctx context.Context, teamId int, userId int) (bool, error)
+type invitationTokenIssuer interface {
+	Issue() (token string, hash string, err error)
 }
 
 type emailSender interface {
 	SendEmail(address string, text string) error
 }
 
+type notificationCreator interface {
+	CreateNotifications(ctx context.Context, notifications []notification.Model) error
+}
+
 type executor struct {
-	memberInviter           memberInviter
-	memberInvitationChecker memberInvitationChecker
-	emailSender             emailSender
+	invitationCreator     invitationCreator
+	authorizer            authorizer
+	userGetter            userGetter
+	teamMembershipChecker teamMembershipChecker
+	tokenIssuer           invitationTokenIssuer
+	emailSender           emailSender
+	notificationCreator   notificationCreator
+	expiry                time.Duration
 }
 
 func NewExecutor(
-	memberInviter memberInviter,
-	memberInvitationChecker memberInvitationChecker,
+	invitationCreator invitationCreator,
+	authorizer authorizer,
+	userGetter userGetter,
+	teamMembershipChecker teamMembershipChecker,
+	tokenIssuer invitationTokenIssuer,
 	emailSender emailSender,
+	notificationCreator notificationCreator,
+	expiry time.Duration,
 ) *executor {
 	return &executor{
-		memberInviter:           memberInviter,
-		memberInvitationChecker: memberInvitationChecker,
-		emailSender:             emailSender,
+		invitationCreator:     invitationCreator,
+		authorizer:            authorizer,
+		userGetter:            userGetter,
+		teamMembershipChecker: teamMembershipChecker,
+		tokenIssuer:           tokenIssuer,
+		emailSender:           emailSender,
+		notificationCreator:   notificationCreator,
+		expiry:                expiry,
 	}
 }
 
@@ -41,42 +90,167 @@ type InviteInput struct {
 	InviterUserId int
 	TeamId        int
 	UserId        int
-	Role          member.Role
+	Username      string
 	Email         string
+	Role          member.Role
 }
 
 type InviteResult struct {
-	UserId int
-	TeamId int
-	Role   member.Role
+	InvitationId int
+	TeamId       int
+	Role         member.Role
+	Status       invitation.Status
+	ExpiresAt    time.Time
 }
 
-const simpleEmailText = "You have been invited to new team!"
+const emailTextTemplate = "You have been invited to team #%d as %s. Use this token to accept or decline the invitation: %s"
 
 func (e *executor) Execute(ctx context.Context, in InviteInput) (*InviteResult, error) {
-	canInvite, err := e.memberInvitationChecker.CanUserInvite(ctx, in.TeamId, in.InviterUserId)
+	if !in.Role.IsValid() {
+		return nil, ErrInvalidRole
+	}
+
+	if countInvitees(in) != 1 {
+		return nil, ErrInvalidInvitee
+	}
+
+	inviter, err := e.authorizer.Authorize(ctx, in.InviterUserId, permission.MemberInvite, permission.TeamResource(in.TeamId))
 
 	if err != nil {
 		return nil, err
 	}
 
-	if !canInvite {
-		return nil, fmt.Errorf("forbidden")
+	if !inviter.Role.CanGrant(in.Role) {
+		return nil, ErrRoleNotGrantable
+	}
+
+	inv := invitation.Model{
+		TeamId:       in.TeamId,
+		InviterId:    in.InviterUserId,
+		InviteeEmail: in.Email,
+		Role:         in.Role,
+		Status:       invitation.PendingStatus,
 	}
 
-	invited, err := e.memberInviter.InviteMember(ctx, in.UserId, in.TeamId, in.Role)
+	invitee, err := e.findInvitee(ctx, in)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if invitee != nil {
+		isMember, err := e.teamMembershipChecker.CheckUserIsInTeam(invitee.Id, in.TeamId)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if isMember {
+			return nil, ErrAlreadyMember
+		}
+
+		inv.InviteeUserId = invitee.Id
+	} else {
+		inv.InviteeUsername = in.Username
+	}
+
+	token, hash, err := e.tokenIssuer.Issue()
+
+	if err != nil {
+		return nil, fmt.Errorf("could not create invitation token: %w", err)
+	}
+
+	now := time.Now()
+
+	inv.TokenHash = hash
+	inv.CreatedAt = now
+	inv.ExpiresAt = now.Add(e.expiry)
+
+	created, err := e.invitationCreator.CreateInvitation(ctx, inv)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Токен получает только адресат письма, пригласившему он не возвращается:
+	// иначе тот мог бы принять приглашение сам. Остальные отвечают по номеру приглашения.
 	if in.Email != "" {
-		_ = e.emailSender.SendEmail(in.Email, simpleEmailText)
+		_ = e.emailSender.SendEmail(in.Email, fmt.Sprintf(emailTextTemplate, in.TeamId, in.Role, token))
+	}
+
+	// Зарегистрированный пользователь узнаёт о приглашении в приложении.
+	if created.InviteeUserId != 0 {
+		err := e.notificationCreator.CreateNotifications(ctx, []notification.Model{{
+			UserId:       created.InviteeUserId,
+			Kind:         notification.TeamInvitationKind,
+			ActorId:      created.InviterId,
+			TeamId:       created.TeamId,
+			InvitationId: &created.Id,
+			Data:         map[string]string{"role": string(created.Role)},
+			CreatedAt:    now,
+		}})
+
+		if err != nil {
+			log.Printf("invitation %d notification error: %v", created.Id, err)
+		}
 	}
 
 	return &InviteResult{
-		UserId: invited.UserId,
-		TeamId: invited.TeamId,
-		Role:   invited.Role,
+		InvitationId: created.Id,
+		TeamId:       created.TeamId,
+		Role:         created.Role,
+		Status:       created.Status,
+		ExpiresAt:    created.ExpiresAt,
 	}, nil
 }
+
+// Приглашение по имени допустимо и без аккаунта: пользователь примет его после регистрации.
+func (e *executor) findInvitee(ctx context.Context, in InviteInput) (*user.Model, error) {
+	if in.UserId != 0 {
+		u, err := e.userGetter.GetUserById(ctx, in.UserId)
+
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, ErrUserNotFound
+			}
+
+			return nil, err
+		}
+
+		return u, nil
+	}
+
+	if in.Username != "" {
+		u, err := e.userGetter.GetUser(ctx, in.Username)
+
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, nil
+			}
+
+			return nil, err
+		}
+
+		return u, nil
+	}
+
+	return nil, nil
+}
+
+func countInvitees(in InviteInput) int {
+	count := 0
+
+	if in.UserId != 0 {
+		count++
+	}
+
+	if in.Username != "" {
+		count++
+	}
+
+	if in.Email != "" {
+		count++
+	}
+
+	return count
+}
@@ -2,172 +2,314 @@ package invite
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
+	"mkk-luna-test-task/internal/team/invitation"
 	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+	"mkk-luna-test-task/internal/user"
+	"mkk-luna-test-task/internal/user/notification"
 
 	"github.com/stretchr/testify/assert"
 )
 
-type stubMemberInviterSuccess struct{}
+type mockInvitationCreator struct {
+	created *invitation.Model
+	err     error
+}
+
+func (m *mockInvitationCreator) CreateInvitation(ctx context.Context, inv invitation.Model) (*invitation.Model, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	inv.Id = 7
+	m.created = &inv
+
+	return &inv, nil
+}
+
+type stubAuthorizerAllow struct{}
+
+func (s *stubAuthorizerAllow) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	roles := map[int]member.Role{1: member.OwnerRole, 2: member.AdminRole}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: roles[userId]}, nil
+}
+
+type stubAuthorizerForbid struct{}
 
-func (s *stubMemberInviterSuccess) InviteMember(ctx context.Context, userId, teamId int, role member.Role) (*member.Model, error) {
-	return &member.Model{
-		UserId: userId,
-		TeamId: teamId,
-		Role:   role,
-	}, nil
+func (s *stubAuthorizerForbid) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	return nil, permission.ErrForbidden
 }
 
-type stubMemberInviterError struct{}
+type stubAuthorizerError struct{}
+
+func (s *stubAuthorizerError) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	return nil, errors.New("perms check error")
+}
+
+type stubUserGetter struct {
+	users map[int]*user.Model
+}
+
+func (s *stubUserGetter) GetUser(ctx context.Context, username string) (*user.Model, error) {
+	for _, u := range s.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+
+	return nil, sql.ErrNoRows
+}
+
+func (s *stubUserGetter) GetUserById(ctx context.Context, id int) (*user.Model, error) {
+	u, ok := s.users[id]
+
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
 
-func (s *stubMemberInviterError) InviteMember(ctx context.Context, userId, teamId int, role member.Role) (*member.Model, error) {
-	return nil, errors.New("db error")
+	return u, nil
 }
 
-type stubInvitationCheckerAllow struct{}
+type stubTeamMembershipChecker struct {
+	members map[int]bool
+}
 
-func (s *stubInvitationCheckerAllow) CanUserInvite(ctx context.Context, teamId int, userId int) (bool, error) {
-	return true, nil
+func (s *stubTeamMembershipChecker) CheckUserIsInTeam(userId, teamId int) (bool, error) {
+	return s.members[userId], nil
 }
 
-type stubInvitationCheckerForbid struct{}
+type stubTokenIssuer struct{}
+
+func (s *stubTokenIssuer) Issue() (string, string, error) {
+	return "token", "hash", nil
+}
 
-func (s *stubInvitationCheckerForbid) CanUserInvite(ctx context.Context, teamId int, userId int) (bool, error) {
-	return false, nil
+type mockEmailSender struct {
+	address string
+	text    string
 }
 
-type stubInvitationCheckerError struct{}
+func (m *mockEmailSender) SendEmail(address string, text string) error {
+	m.address = address
+	m.text = text
 
-func (s *stubInvitationCheckerError) CanUserInvite(ctx context.Context, teamId int, userId int) (bool, error) {
-	return false, errors.New("perms check error")
+	return nil
 }
 
-type stubEmailSender struct{}
+type mockNotificationCreator struct {
+	notifications []notification.Model
+}
+
+func (m *mockNotificationCreator) CreateNotifications(ctx context.Context, notifications []notification.Model) error {
+	m.notifications = append(m.notifications, notifications...)
 
-func (s *stubEmailSender) SendEmail(address string, text string) error {
 	return nil
 }
 
 func TestExecutor_Execute(t *testing.T) {
-	type fields struct {
-		memberInviter           memberInviter
-		memberInvitationChecker memberInvitationChecker
-	}
-	type args struct {
-		ctx context.Context
-		in  InviteInput
+	users := map[int]*user.Model{
+		20: {Id: 20, Username: "dev"},
+		30: {Id: 30, Username: "teammate"},
 	}
+
 	tests := []struct {
-		name        string
-		fields      fields
-		args        args
-		want        *InviteResult
-		wantErr     bool
-		expectedErr error
+		name             string
+		authorizer       authorizer
+		creatorErr       error
+		in               InviteInput
+		wantErr          bool
+		expectedErr      error
+		expectInvitation *invitation.Model
+		expectEmailTo    string
 	}{
 		{
-			name: "success",
-			fields: fields{
-				memberInviter:           &stubMemberInviterSuccess{},
-				memberInvitationChecker: &stubInvitationCheckerAllow{},
-			},
-			args: args{
-				ctx: context.Background(),
-				in: InviteInput{
-					InviterUserId: 1,
-					TeamId:        10,
-					UserId:        20,
-					Role:          member.NormalRole,
-				},
-			},
-			want: &InviteResult{
-				UserId: 20,
-				TeamId: 10,
-				Role:   member.NormalRole,
-			},
-			wantErr: false,
-		},
-		{
-			name: "invitation forbidden",
-			fields: fields{
-				memberInviter:           &stubMemberInviterSuccess{},
-				memberInvitationChecker: &stubInvitationCheckerForbid{},
-			},
-			args: args{
-				ctx: context.Background(),
-				in: InviteInput{
-					InviterUserId: 2,
-					TeamId:        20,
-					UserId:        30,
-					Role:          member.NormalRole,
-				},
-			},
-			want:        nil,
+			name:             "invite existing user by id",
+			authorizer:       &stubAuthorizerAllow{},
+			in:               InviteInput{InviterUserId: 1, TeamId: 10, UserId: 20, Role: member.NormalRole},
+			expectInvitation: &invitation.Model{TeamId: 10, InviterId: 1, InviteeUserId: 20, Role: member.NormalRole},
+		},
+		{
+			name:             "invite existing user by username",
+			authorizer:       &stubAuthorizerAllow{},
+			in:               InviteInput{InviterUserId: 1, TeamId: 10, Username: "dev", Role: member.NormalRole},
+			expectInvitation: &invitation.Model{TeamId: 10, InviterId: 1, InviteeUserId: 20, Role: member.NormalRole},
+		},
+		{
+			name:             "invite unregistered username",
+			authorizer:       &stubAuthorizerAllow{},
+			in:               InviteInput{InviterUserId: 1, TeamId: 10, Username: "newcomer", Role: member.NormalRole},
+			expectInvitation: &invitation.Model{TeamId: 10, InviterId: 1, InviteeUsername: "newcomer", Role: member.NormalRole},
+		},
+		{
+			name:             "invite by email",
+			authorizer:       &stubAuthorizerAllow{},
+			in:               InviteInput{InviterUserId: 1, TeamId: 10, Email: "new@example.com", Role: member.NormalRole},
+			expectInvitation: &invitation.Model{TeamId: 10, InviterId: 1, InviteeEmail: "new@example.com", Role: member.NormalRole},
+			expectEmailTo:    "new@example.com",
+		},
+		{
+			name:             "owner invites admin",
+			authorizer:       &stubAuthorizerAllow{},
+			in:               InviteInput{InviterUserId: 1, TeamId: 10, UserId: 20, Role: member.AdminRole},
+			expectInvitation: &invitation.Model{TeamId: 10, InviterId: 1, InviteeUserId: 20, Role: member.AdminRole},
+		},
+		{
+			name:             "admin invites normal",
+			authorizer:       &stubAuthorizerAllow{},
+			in:               InviteInput{InviterUserId: 2, TeamId: 10, UserId: 20, Role: member.NormalRole},
+			expectInvitation: &invitation.Model{TeamId: 10, InviterId: 2, InviteeUserId: 20, Role: member.NormalRole},
+		},
+		{
+			name:        "admin cannot invite admin",
+			authorizer:  &stubAuthorizerAllow{},
+			in:          InviteInput{InviterUserId: 2, TeamId: 10, UserId: 20, Role: member.AdminRole},
 			wantErr:     true,
-			expectedErr: errors.New("forbidden"),
-		},
-		{
-			name: "invitation checker error",
-			fields: fields{
-				memberInviter:           &stubMemberInviterSuccess{},
-				memberInvitationChecker: &stubInvitationCheckerError{},
-			},
-			args: args{
-				ctx: context.Background(),
-				in: InviteInput{
-					InviterUserId: 3,
-					TeamId:        30,
-					UserId:        40,
-					Role:          member.NormalRole,
-				},
-			},
-			want:        nil,
+			expectedErr: ErrRoleNotGrantable,
+		},
+		{
+			name:        "owner cannot invite another owner",
+			authorizer:  &stubAuthorizerAllow{},
+			in:          InviteInput{InviterUserId: 1, TeamId: 10, UserId: 20, Role: member.OwnerRole},
+			wantErr:     true,
+			expectedErr: ErrRoleNotGrantable,
+		},
+		{
+			name:        "admin cannot invite owner",
+			authorizer:  &stubAuthorizerAllow{},
+			in:          InviteInput{InviterUserId: 2, TeamId: 10, UserId: 20, Role: member.OwnerRole},
+			wantErr:     true,
+			expectedErr: ErrRoleNotGrantable,
+		},
+		{
+			name:        "unknown role",
+			authorizer:  &stubAuthorizerAllow{},
+			in:          InviteInput{InviterUserId: 1, TeamId: 10, UserId: 20, Role: "member"},
+			wantErr:     true,
+			expectedErr: ErrInvalidRole,
+		},
+		{
+			name:        "empty role",
+			authorizer:  &stubAuthorizerAllow{},
+			in:          InviteInput{InviterUserId: 1, TeamId: 10, UserId: 20},
+			wantErr:     true,
+			expectedErr: ErrInvalidRole,
+		},
+		{
+			name:        "no invitee",
+			authorizer:  &stubAuthorizerAllow{},
+			in:          InviteInput{InviterUserId: 1, TeamId: 10, Role: member.NormalRole},
+			wantErr:     true,
+			expectedErr: ErrInvalidInvitee,
+		},
+		{
+			name:        "several invitees",
+			authorizer:  &stubAuthorizerAllow{},
+			in:          InviteInput{InviterUserId: 1, TeamId: 10, UserId: 20, Email: "a@b.c", Role: member.NormalRole},
+			wantErr:     true,
+			expectedErr: ErrInvalidInvitee,
+		},
+		{
+			name:        "unknown user id",
+			authorizer:  &stubAuthorizerAllow{},
+			in:          InviteInput{InviterUserId: 1, TeamId: 10, UserId: 99, Role: member.NormalRole},
+			wantErr:     true,
+			expectedErr: ErrUserNotFound,
+		},
+		{
+			name:        "already a member",
+			authorizer:  &stubAuthorizerAllow{},
+			in:          InviteInput{InviterUserId: 1, TeamId: 10, UserId: 30, Role: member.NormalRole},
+			wantErr:     true,
+			expectedErr: ErrAlreadyMember,
+		},
+		{
+			name:        "invitation forbidden",
+			authorizer:  &stubAuthorizerForbid{},
+			in:          InviteInput{InviterUserId: 2, TeamId: 10, UserId: 20, Role: member.NormalRole},
+			wantErr:     true,
+			expectedErr: permission.ErrForbidden,
+		},
+		{
+			name:        "invitation checker error",
+			authorizer:  &stubAuthorizerError{},
+			in:          InviteInput{InviterUserId: 3, TeamId: 10, UserId: 20, Role: member.NormalRole},
 			wantErr:     true,
 			expectedErr: errors.New("perms check error"),
 		},
 		{
-			name: "member inviter error",
-			fields: fields{
-				memberInviter:           &stubMemberInviterError{},
-				memberInvitationChecker: &stubInvitationCheckerAllow{},
-			},
-			args: args{
-				ctx: context.Background(),
-				in: InviteInput{
-					InviterUserId: 4,
-					TeamId:        40,
-					UserId:        50,
-					Role:          member.NormalRole,
-				},
-			},
-			want:        nil,
+			name:        "creator error",
+			authorizer:  &stubAuthorizerAllow{},
+			creatorErr:  errors.New("db error"),
+			in:          InviteInput{InviterUserId: 1, TeamId: 10, UserId: 20, Role: member.NormalRole},
 			wantErr:     true,
 			expectedErr: errors.New("db error"),
 		},
 	}
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			e := &executor{
-				memberInviter:           tt.fields.memberInviter,
-				memberInvitationChecker: tt.fields.memberInvitationChecker,
-				emailSender:             &stubEmailSender{},
-			}
+			creator := &mockInvitationCreator{err: tt.creatorErr}
+			sender := &mockEmailSender{}
+			notifier := &mockNotificationCreator{}
+
+			e := NewExecutor(
+				creator,
+				tt.authorizer,
+				&stubUserGetter{users: users},
+				&stubTeamMembershipChecker{members: map[int]bool{30: true}},
+				&stubTokenIssuer{},
+				sender,
+				notifier,
+				time.Hour,
+			)
 
-			got, err := e.Execute(tt.args.ctx, tt.args.in)
+			got, err := e.Execute(context.Background(), tt.in)
 
 			if tt.wantErr {
 				assert.Nil(t, got)
-				assert.Error(t, err)
-
-				if tt.expectedErr != nil {
-					assert.EqualError(t, err, tt.expectedErr.Error())
-				}
-			} else {
-				assert.NoError(t, err)
-				assert.Equal(t, tt.want, got)
+				assert.EqualError(t, err, tt.expectedErr.Error())
+				assert.Nil(t, creator.created)
+				assert.Empty(t, notifier.notifications)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, 7, got.InvitationId)
+			assert.Equal(t, invitation.PendingStatus, got.Status)
+
+			assert.Equal(t, tt.expectInvitation.InviteeUserId, creator.created.InviteeUserId)
+			assert.Equal(t, tt.expectInvitation.InviteeUsername, creator.created.InviteeUsername)
+			assert.Equal(t, tt.expectInvitation.InviteeEmail, creator.created.InviteeEmail)
+			assert.Equal(t, tt.expectInvitation.Role, creator.created.Role)
+			assert.Equal(t, "hash", creator.created.TokenHash)
+			assert.Equal(t, time.Hour, creator.created.ExpiresAt.Sub(creator.created.CreatedAt))
+
+			assert.Equal(t, tt.expectEmailTo, sender.address)
+
+			if tt.expectEmailTo != "" {
+				assert.Contains(t, sender.text, "token")
 			}
+
+			// Без аккаунта уведомлять некого: приглашение по имени видно в списке после регистрации,
+			// по email — только из письма.
+			if tt.expectInvitation.InviteeUserId == 0 {
+				assert.Empty(t, notifier.notifications)
+
+				return
+			}
+
+			assert.Len(t, notifier.notifications, 1)
+			assert.Equal(t, tt.expectInvitation.InviteeUserId, notifier.notifications[0].UserId)
+			assert.Equal(t, notification.TeamInvitationKind, notifier.notifications[0].Kind)
+			assert.Equal(t, 7, *notifier.notifications[0].InvitationId)
+			assert.Equal(t, string(tt.expectInvitation.Role), notifier.notifications[0].Data["role"])
 		})
 	}
 }
@@ -5,21 +5,28 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi"
 
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/team/invitation"
 	"mkk-luna-test-task/internal/team/member"
 )
 
 type request struct {
-	UserId int         `json:"user_id"`
-	Role   member.Role `json:"role"`
+	UserId   int         `json:"user_id"`
+	Username string      `json:"username"`
+	Email    string      `json:"email"`
+	Role     member.Role `json:"role"`
 }
 
 type response struct {
-	UserId int         `json:"user_id"`
-	TeamId int         `json:"team_id"`
-	Role   member.Role `json:"role"`
+	InvitationId int               `json:"invitation_id"`
+	TeamId       int               `json:"team_id"`
+	Role         member.Role       `json:"role"`
+	Status       invitation.Status `json:"status"`
+	ExpiresAt    time.Time         `json:"expires_at"`
 }
 
 type handler struct {
@@ -38,7 +45,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	userId, ok := userIdAny.(int)
 
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
 
 		return
 	}
@@ -48,7 +55,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	teamId, err := strconv.Atoi(teamIdStr)
 
 	if err != nil {
-		http.Error(w, "invalid team id", http.StatusBadRequest)
+		apperror.Write(w, apperror.Validation("invalid team id"))
 
 		return
 	}
@@ -57,7 +64,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
 	if err != nil {
-		http.Error(w, "error", http.StatusInternalServerError)
+		apperror.Write(w, err)
 
 		return
 	}
@@ -65,7 +72,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	var req request
 
 	if err := json.Unmarshal(body, &req); err != nil {
-		http.Error(w, "json error", http.StatusBadRequest)
+		apperror.Write(w, apperror.Validation("invalid json"))
 
 		return
 	}
@@ -74,21 +81,29 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 		InviterUserId: userId,
 		TeamId:        teamId,
 		UserId:        req.UserId,
+		Username:      req.Username,
+		Email:         req.Email,
 		Role:          req.Role,
 	})
 
 	if err != nil {
-		http.Error(w, "error", http.StatusBadRequest)
+		apperror.Write(w, err)
 
 		return
 	}
 
-	resp := response{UserId: result.UserId, TeamId: result.TeamId, Role: result.Role}
+	resp := response{
+		InvitationId: result.InvitationId,
+		TeamId:       result.TeamId,
+		Role:         result.Role,
+		Status:       result.Status,
+		ExpiresAt:    result.ExpiresAt,
+	}
 
 	respBody, err := json.Marshal(resp)
 
 	if err != nil {
-		http.Error(w, "json error", http.StatusInternalServerError)
+		apperror.Write(w, err)
 
 		return
 	}
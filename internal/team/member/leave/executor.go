@@ -0,0 +1,67 @@
+package leave
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/team/member"
+)
+
+type memberGetter interface {
+	GetMember(ctx context.Context, teamId, userId int) (*member.Model, error)
+}
+
+type ownersCounter interface {
+	CountOwners(ctx context.Context, teamId int) (int, error)
+}
+
+type memberRemover interface {
+	RemoveMember(ctx context.Context, teamId, userId int) error
+}
+
+type executor struct {
+	memberGetter  memberGetter
+	ownersCounter ownersCounter
+	memberRemover memberRemover
+}
+
+func NewExecutor(memberGetter memberGetter, ownersCounter ownersCounter, memberRemover memberRemover) *executor {
+	return &executor{
+		memberGetter:  memberGetter,
+		ownersCounter: ownersCounter,
+		memberRemover: memberRemover,
+	}
+}
+
+type LeaveInput struct {
+	UserId int
+	TeamId int
+}
+
+func (e *executor) Execute(ctx context.Context, in LeaveInput) error {
+	m, err := e.memberGetter.GetMember(ctx, in.TeamId, in.UserId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.NotFound("member not found")
+		}
+
+		return err
+	}
+
+	if m.Role == member.OwnerRole {
+		owners, err := e.ownersCounter.CountOwners(ctx, in.TeamId)
+
+		if err != nil {
+			return err
+		}
+
+		if owners <= 1 {
+			return apperror.Conflict("last owner cannot leave")
+		}
+	}
+
+	return e.memberRemover.RemoveMember(ctx, in.TeamId, in.UserId)
+}
@@ -0,0 +1,101 @@
+package leave
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"mkk-luna-test-task/internal/team/member"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubMemberGetter struct {
+	model *member.Model
+	err   error
+}
+
+func (s *stubMemberGetter) GetMember(ctx context.Context, teamId, userId int) (*member.Model, error) {
+	return s.model, s.err
+}
+
+type stubOwnersCounter struct {
+	count int
+}
+
+func (s *stubOwnersCounter) CountOwners(ctx context.Context, teamId int) (int, error) {
+	return s.count, nil
+}
+
+type mockMemberRemover struct {
+	called bool
+}
+
+func (m *mockMemberRemover) RemoveMember(ctx context.Context, teamId, userId int) error {
+	m.called = true
+
+	return nil
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	tests := []struct {
+		name         string
+		memberGetter *stubMemberGetter
+		owners       int
+		wantErr      bool
+		expectedErr  error
+	}{
+		{
+			name:         "normal member leaves",
+			memberGetter: &stubMemberGetter{model: &member.Model{Role: member.NormalRole}},
+			owners:       1,
+		},
+		{
+			name:         "one of two owners leaves",
+			memberGetter: &stubMemberGetter{model: &member.Model{Role: member.OwnerRole}},
+			owners:       2,
+		},
+		{
+			name:         "last owner cannot leave",
+			memberGetter: &stubMemberGetter{model: &member.Model{Role: member.OwnerRole}},
+			owners:       1,
+			wantErr:      true,
+			expectedErr:  errors.New("last owner cannot leave"),
+		},
+		{
+			name:         "not a member",
+			memberGetter: &stubMemberGetter{err: sql.ErrNoRows},
+			wantErr:      true,
+			expectedErr:  errors.New("member not found"),
+		},
+		{
+			name:         "getter error",
+			memberGetter: &stubMemberGetter{err: errors.New("db error")},
+			wantErr:      true,
+			expectedErr:  errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remover := &mockMemberRemover{}
+
+			e := &executor{
+				memberGetter:  tt.memberGetter,
+				ownersCounter: &stubOwnersCounter{count: tt.owners},
+				memberRemover: remover,
+			}
+
+			err := e.Execute(context.Background(), LeaveInput{UserId: 1, TeamId: 10})
+
+			if tt.wantErr {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+				assert.False(t, remover.called)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, remover.called)
+			}
+		})
+	}
+}
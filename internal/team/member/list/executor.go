@@ -0,0 +1,65 @@
+package list
+
+import (
+	"context"
+
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type ListResult struct {
+	Members []MemberItem
+}
+
+type MemberItem struct {
+	UserId   int         `json:"user_id"`
+	Username string      `json:"username"`
+	Role     member.Role `json:"role"`
+}
+
+type memberLister interface {
+	ListMembers(ctx context.Context, teamId int) ([]*member.Model, error)
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type executor struct {
+	memberLister memberLister
+	authorizer   authorizer
+}
+
+func NewExecutor(memberLister memberLister, authorizer authorizer) *executor {
+	return &executor{
+		memberLister: memberLister,
+		authorizer:   authorizer,
+	}
+}
+
+type ListInput struct {
+	UserId int
+	TeamId int
+}
+
+func (e *executor) Execute(ctx context.Context, in ListInput) (*ListResult, error) {
+	_, err := e.authorizer.Authorize(ctx, in.UserId, permission.MemberList, permission.TeamResource(in.TeamId))
+
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := e.memberLister.ListMembers(ctx, in.TeamId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]MemberItem, 0, len(members))
+
+	for _, m := range members {
+		items = append(items, MemberItem{UserId: m.UserId, Username: m.Username, Role: m.Role})
+	}
+
+	return &ListResult{Members: items}, nil
+}
@@ -0,0 +1,102 @@
+package list
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubMemberListerSuccess struct{}
+
+func (s *stubMemberListerSuccess) ListMembers(ctx context.Context, teamId int) ([]*member.Model, error) {
+	return []*member.Model{
+		{UserId: 1, TeamId: teamId, Role: member.OwnerRole, Username: "lead"},
+		{UserId: 2, TeamId: teamId, Role: member.NormalRole, Username: "dev"},
+	}, nil
+}
+
+type stubMemberListerError struct{}
+
+func (s *stubMemberListerError) ListMembers(ctx context.Context, teamId int) ([]*member.Model, error) {
+	return nil, errors.New("db error")
+}
+
+type stubAuthorizer struct {
+	err error
+}
+
+func (s *stubAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	tests := []struct {
+		name         string
+		memberLister memberLister
+		authorizer   authorizer
+		want         *ListResult
+		wantErr      bool
+		expectedErr  error
+	}{
+		{
+			name:         "success",
+			memberLister: &stubMemberListerSuccess{},
+			authorizer:   &stubAuthorizer{},
+			want: &ListResult{
+				Members: []MemberItem{
+					{UserId: 1, Username: "lead", Role: member.OwnerRole},
+					{UserId: 2, Username: "dev", Role: member.NormalRole},
+				},
+			},
+		},
+		{
+			name:         "not a member",
+			memberLister: &stubMemberListerSuccess{},
+			authorizer:   &stubAuthorizer{err: permission.ErrForbidden},
+			wantErr:      true,
+			expectedErr:  errors.New("forbidden"),
+		},
+		{
+			name:         "membership check error",
+			memberLister: &stubMemberListerSuccess{},
+			authorizer:   &stubAuthorizer{err: errors.New("membership error")},
+			wantErr:      true,
+			expectedErr:  errors.New("membership error"),
+		},
+		{
+			name:         "lister error",
+			memberLister: &stubMemberListerError{},
+			authorizer:   &stubAuthorizer{},
+			wantErr:      true,
+			expectedErr:  errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &executor{
+				memberLister: tt.memberLister,
+				authorizer:   tt.authorizer,
+			}
+
+			got, err := e.Execute(context.Background(), ListInput{UserId: 1, TeamId: 10})
+
+			if tt.wantErr {
+				assert.Nil(t, got)
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -7,7 +7,43 @@ const AdminRole Role = "admin"
 const NormalRole Role = "normal"
 
 type Model struct {
-	UserId int
-	TeamId int
-	Role   Role
+	UserId   int
+	TeamId   int
+	Role     Role
+	Username string
+}
+
+func (r Role) IsValid() bool {
+	return r.rank() > 0
+}
+
+// Роль старше другой, если может ею управлять: owner > admin > normal.
+func (r Role) Outranks(other Role) bool {
+	return r.rank() > other.rank()
+}
+
+// Выдать можно роль не выше своей, но admin назначает только owner.
+// Роль owner не выдаётся никем: владелец в команде один и меняется только передачей владения.
+func (r Role) CanGrant(role Role) bool {
+	switch role {
+	case OwnerRole:
+		return false
+	case AdminRole:
+		return r == OwnerRole
+	}
+
+	return role.IsValid() && r.rank() >= role.rank()
+}
+
+func (r Role) rank() int {
+	switch r {
+	case OwnerRole:
+		return 3
+	case AdminRole:
+		return 2
+	case NormalRole:
+		return 1
+	default:
+		return 0
+	}
 }
@@ -0,0 +1,31 @@
+package member
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRole_CanGrant(t *testing.T) {
+	tests := []struct {
+		granter Role
+		role    Role
+		want    bool
+	}{
+		{OwnerRole, OwnerRole, false},
+		{OwnerRole, AdminRole, true},
+		{OwnerRole, NormalRole, true},
+		{AdminRole, OwnerRole, false},
+		{AdminRole, AdminRole, false},
+		{AdminRole, NormalRole, true},
+		{NormalRole, NormalRole, true},
+		{NormalRole, AdminRole, false},
+		{AdminRole, "superuser", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.granter)+"->"+string(tt.role), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.granter.CanGrant(tt.role))
+		})
+	}
+}
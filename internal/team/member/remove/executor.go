@@ -0,0 +1,72 @@
+package remove
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type memberGetter interface {
+	GetMember(ctx context.Context, teamId, userId int) (*member.Model, error)
+}
+
+type memberRemover interface {
+	RemoveMember(ctx context.Context, teamId, userId int) error
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type executor struct {
+	authorizer    authorizer
+	memberGetter  memberGetter
+	memberRemover memberRemover
+}
+
+func NewExecutor(authorizer authorizer, memberGetter memberGetter, memberRemover memberRemover) *executor {
+	return &executor{
+		authorizer:    authorizer,
+		memberGetter:  memberGetter,
+		memberRemover: memberRemover,
+	}
+}
+
+type RemoveInput struct {
+	ActorUserId  int
+	TeamId       int
+	TargetUserId int
+}
+
+func (e *executor) Execute(ctx context.Context, in RemoveInput) error {
+	if in.ActorUserId == in.TargetUserId {
+		return apperror.Validation("use leave to remove yourself")
+	}
+
+	actor, err := e.authorizer.Authorize(ctx, in.ActorUserId, permission.MemberManage, permission.TeamResource(in.TeamId))
+
+	if err != nil {
+		return err
+	}
+
+	target, err := e.memberGetter.GetMember(ctx, in.TeamId, in.TargetUserId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.NotFound("member not found")
+		}
+
+		return err
+	}
+
+	// Владельца никто не старше, поэтому удалить его нельзя — только передать владение.
+	if !actor.Role.Outranks(target.Role) {
+		return permission.ErrForbidden
+	}
+
+	return e.memberRemover.RemoveMember(ctx, in.TeamId, in.TargetUserId)
+}
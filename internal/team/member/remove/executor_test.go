@@ -0,0 +1,136 @@
+package remove
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubMemberGetter struct {
+	roles map[int]member.Role
+}
+
+func (s *stubMemberGetter) GetMember(ctx context.Context, teamId, userId int) (*member.Model, error) {
+	role, ok := s.roles[userId]
+
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return &member.Model{UserId: userId, TeamId: teamId, Role: role}, nil
+}
+
+type mockMemberRemover struct {
+	removed int
+	err     error
+}
+
+func (m *mockMemberRemover) RemoveMember(ctx context.Context, teamId, userId int) error {
+	m.removed = userId
+
+	return m.err
+}
+
+type stubOverridesLister struct{}
+
+func (s *stubOverridesLister) ListPermissionOverrides(ctx context.Context, teamId int) ([]permission.Override, error) {
+	return nil, nil
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	roles := map[int]member.Role{
+		1: member.OwnerRole,
+		2: member.AdminRole,
+		3: member.NormalRole,
+		4: member.AdminRole,
+	}
+
+	tests := []struct {
+		name          string
+		in            RemoveInput
+		remover       *mockMemberRemover
+		wantErr       bool
+		expectedErr   error
+		expectRemoved int
+	}{
+		{
+			name:          "owner removes admin",
+			in:            RemoveInput{ActorUserId: 1, TargetUserId: 2},
+			remover:       &mockMemberRemover{},
+			expectRemoved: 2,
+		},
+		{
+			name:          "admin removes normal",
+			in:            RemoveInput{ActorUserId: 2, TargetUserId: 3},
+			remover:       &mockMemberRemover{},
+			expectRemoved: 3,
+		},
+		{
+			name:        "admin cannot remove another admin",
+			in:          RemoveInput{ActorUserId: 2, TargetUserId: 4},
+			remover:     &mockMemberRemover{},
+			wantErr:     true,
+			expectedErr: errors.New("forbidden"),
+		},
+		{
+			name:        "admin cannot remove owner",
+			in:          RemoveInput{ActorUserId: 2, TargetUserId: 1},
+			remover:     &mockMemberRemover{},
+			wantErr:     true,
+			expectedErr: errors.New("forbidden"),
+		},
+		{
+			name:        "normal cannot remove",
+			in:          RemoveInput{ActorUserId: 3, TargetUserId: 2},
+			remover:     &mockMemberRemover{},
+			wantErr:     true,
+			expectedErr: errors.New("forbidden"),
+		},
+		{
+			name:        "self removal",
+			in:          RemoveInput{ActorUserId: 2, TargetUserId: 2},
+			remover:     &mockMemberRemover{},
+			wantErr:     true,
+			expectedErr: errors.New("use leave to remove yourself"),
+		},
+		{
+			name:        "target not in team",
+			in:          RemoveInput{ActorUserId: 1, TargetUserId: 50},
+			remover:     &mockMemberRemover{},
+			wantErr:     true,
+			expectedErr: errors.New("member not found"),
+		},
+		{
+			name:          "remover error",
+			in:            RemoveInput{ActorUserId: 1, TargetUserId: 3},
+			remover:       &mockMemberRemover{err: errors.New("db error")},
+			wantErr:       true,
+			expectedErr:   errors.New("db error"),
+			expectRemoved: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := &stubMemberGetter{roles: roles}
+
+			e := NewExecutor(permission.NewEngine(getter, &stubOverridesLister{}), getter, tt.remover)
+
+			err := e.Execute(context.Background(), tt.in)
+
+			if tt.wantErr {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.expectRemoved, tt.remover.removed)
+		})
+	}
+}
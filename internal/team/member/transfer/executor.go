@@ -0,0 +1,67 @@
+package transfer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type memberGetter interface {
+	GetMember(ctx context.Context, teamId, userId int) (*member.Model, error)
+}
+
+type ownershipTransferrer interface {
+	TransferOwnership(ctx context.Context, teamId, fromUserId, toUserId int) error
+}
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type executor struct {
+	authorizer           authorizer
+	memberGetter         memberGetter
+	ownershipTransferrer ownershipTransferrer
+}
+
+func NewExecutor(authorizer authorizer, memberGetter memberGetter, ownershipTransferrer ownershipTransferrer) *executor {
+	return &executor{
+		authorizer:           authorizer,
+		memberGetter:         memberGetter,
+		ownershipTransferrer: ownershipTransferrer,
+	}
+}
+
+type TransferInput struct {
+	OwnerUserId int
+	TeamId      int
+	NewOwnerId  int
+}
+
+func (e *executor) Execute(ctx context.Context, in TransferInput) error {
+	if in.OwnerUserId == in.NewOwnerId {
+		return apperror.Conflict("already an owner")
+	}
+
+	_, err := e.authorizer.Authorize(ctx, in.OwnerUserId, permission.TeamTransfer, permission.TeamResource(in.TeamId))
+
+	if err != nil {
+		return err
+	}
+
+	_, err = e.memberGetter.GetMember(ctx, in.TeamId, in.NewOwnerId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.NotFound("member not found")
+		}
+
+		return err
+	}
+
+	return e.ownershipTransferrer.TransferOwnership(ctx, in.TeamId, in.OwnerUserId, in.NewOwnerId)
+}
@@ -0,0 +1,109 @@
+package transfer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubMemberGetter struct {
+	roles map[int]member.Role
+}
+
+func (s *stubMemberGetter) GetMember(ctx context.Context, teamId, userId int) (*member.Model, error) {
+	role, ok := s.roles[userId]
+
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return &member.Model{UserId: userId, TeamId: teamId, Role: role}, nil
+}
+
+type mockOwnershipTransferrer struct {
+	from, to int
+}
+
+func (m *mockOwnershipTransferrer) TransferOwnership(ctx context.Context, teamId, fromUserId, toUserId int) error {
+	m.from = fromUserId
+	m.to = toUserId
+
+	return nil
+}
+
+type stubOverridesLister struct{}
+
+func (s *stubOverridesLister) ListPermissionOverrides(ctx context.Context, teamId int) ([]permission.Override, error) {
+	return nil, nil
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	roles := map[int]member.Role{
+		1: member.OwnerRole,
+		2: member.AdminRole,
+		3: member.NormalRole,
+	}
+
+	tests := []struct {
+		name        string
+		in          TransferInput
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name: "owner transfers to normal member",
+			in:   TransferInput{OwnerUserId: 1, NewOwnerId: 3},
+		},
+		{
+			name:        "admin cannot transfer",
+			in:          TransferInput{OwnerUserId: 2, NewOwnerId: 3},
+			wantErr:     true,
+			expectedErr: errors.New("forbidden"),
+		},
+		{
+			name:        "new owner not in team",
+			in:          TransferInput{OwnerUserId: 1, NewOwnerId: 42},
+			wantErr:     true,
+			expectedErr: errors.New("member not found"),
+		},
+		{
+			name:        "transfer to self",
+			in:          TransferInput{OwnerUserId: 1, NewOwnerId: 1},
+			wantErr:     true,
+			expectedErr: errors.New("already an owner"),
+		},
+		{
+			name:        "outsider",
+			in:          TransferInput{OwnerUserId: 42, NewOwnerId: 1},
+			wantErr:     true,
+			expectedErr: errors.New("forbidden"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transferrer := &mockOwnershipTransferrer{}
+
+			getter := &stubMemberGetter{roles: roles}
+
+			e := NewExecutor(permission.NewEngine(getter, &stubOverridesLister{}), getter, transferrer)
+
+			err := e.Execute(context.Background(), tt.in)
+
+			if tt.wantErr {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+				assert.Zero(t, transferrer.to)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.in.OwnerUserId, transferrer.from)
+				assert.Equal(t, tt.in.NewOwnerId, transferrer.to)
+			}
+		})
+	}
+}
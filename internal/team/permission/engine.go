@@ -0,0 +1,157 @@
+package permission
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+	"mkk-luna-test-task/internal/task/attachment"
+	"mkk-luna-test-task/internal/task/comment"
+	"mkk-luna-test-task/internal/team/member"
+)
+
+var ErrForbidden = apperror.Forbidden("forbidden")
+
+// Resource — объект, над которым выполняется действие. OwnerIds — пользователи, для которых объект «свой».
+type Resource struct {
+	TeamId   int
+	OwnerIds []int
+}
+
+func TeamResource(teamId int) Resource {
+	return Resource{TeamId: teamId}
+}
+
+// TaskResource — задача «своя» для её создателя и любого из исполнителей.
+func TaskResource(t task.Model) Resource {
+	return Resource{
+		TeamId:   t.TeamId,
+		OwnerIds: append([]int{t.CreatorId}, t.AssigneeIds...),
+	}
+}
+
+// AttachmentResource — вложение «своё» для того, кто его загрузил.
+func AttachmentResource(teamId int, a attachment.Model) Resource {
+	return Resource{
+		TeamId:   teamId,
+		OwnerIds: []int{a.UploaderId},
+	}
+}
+
+// CommentResource — комментарий «свой» только для автора, даже если задача чужая.
+func CommentResource(teamId int, c comment.Model) Resource {
+	return Resource{
+		TeamId:   teamId,
+		OwnerIds: []int{c.CommenterId},
+	}
+}
+
+type memberGetter interface {
+	GetMember(ctx context.Context, teamId, userId int) (*member.Model, error)
+}
+
+type overridesLister interface {
+	ListPermissionOverrides(ctx context.Context, teamId int) ([]Override, error)
+}
+
+type Engine struct {
+	memberGetter    memberGetter
+	overridesLister overridesLister
+}
+
+func NewEngine(memberGetter memberGetter, overridesLister overridesLister) *Engine {
+	return &Engine{
+		memberGetter:    memberGetter,
+		overridesLister: overridesLister,
+	}
+}
+
+// Authorize возвращает участника команды, если ему разрешено действие, иначе ErrForbidden.
+func (e *Engine) Authorize(
+	ctx context.Context,
+	userId int,
+	action Action,
+	res Resource,
+) (*member.Model, error) {
+	m, err := e.memberGetter.GetMember(ctx, res.TeamId, userId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrForbidden
+		}
+
+		return nil, err
+	}
+
+	scope, err := e.scope(ctx, res.TeamId, m.Role, action)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch scope {
+	case AnyScope:
+		return m, nil
+	case OwnScope:
+		for _, ownerId := range res.OwnerIds {
+			if ownerId == userId {
+				return m, nil
+			}
+		}
+	}
+
+	return nil, ErrForbidden
+}
+
+// Policy возвращает действующую в команде политику с учётом переопределений.
+func (e *Engine) Policy(ctx context.Context, teamId int) (map[member.Role]map[Action]Scope, error) {
+	overrides, err := e.overridesLister.ListPermissionOverrides(ctx, teamId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	policy := make(map[member.Role]map[Action]Scope)
+
+	for _, role := range []member.Role{member.OwnerRole, member.AdminRole, member.NormalRole} {
+		policy[role] = make(map[Action]Scope, len(actions))
+
+		for _, action := range actions {
+			policy[role][action] = DefaultScope(role, action)
+		}
+	}
+
+	for _, o := range overrides {
+		if isApplicable(o.Role, o.Action) {
+			policy[o.Role][o.Action] = o.Scope
+		}
+	}
+
+	return policy, nil
+}
+
+func (e *Engine) scope(ctx context.Context, teamId int, role member.Role, action Action) (Scope, error) {
+	if !isApplicable(role, action) {
+		return DefaultScope(role, action), nil
+	}
+
+	overrides, err := e.overridesLister.ListPermissionOverrides(ctx, teamId)
+
+	if err != nil {
+		return "", err
+	}
+
+	for _, o := range overrides {
+		if o.Role == role && o.Action == action {
+			return o.Scope, nil
+		}
+	}
+
+	return DefaultScope(role, action), nil
+}
+
+func isApplicable(role member.Role, action Action) bool {
+	return role != member.OwnerRole && role.IsValid() && action.IsOverridable()
+}
@@ -0,0 +1,197 @@
+package permission
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"mkk-luna-test-task/internal/team/member"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubMemberGetter struct {
+	roles map[int]member.Role
+}
+
+func (s *stubMemberGetter) GetMember(ctx context.Context, teamId, userId int) (*member.Model, error) {
+	role, ok := s.roles[userId]
+
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return &member.Model{UserId: userId, TeamId: teamId, Role: role}, nil
+}
+
+type stubOverridesLister struct {
+	overrides []Override
+	err       error
+}
+
+func (s *stubOverridesLister) ListPermissionOverrides(ctx context.Context, teamId int) ([]Override, error) {
+	return s.overrides, s.err
+}
+
+const (
+	ownerId  = 1
+	adminId  = 2
+	normalId = 3
+	otherId  = 4
+	outsider = 99
+)
+
+func TestEngine_Authorize(t *testing.T) {
+	roles := map[int]member.Role{
+		ownerId:  member.OwnerRole,
+		adminId:  member.AdminRole,
+		normalId: member.NormalRole,
+		otherId:  member.NormalRole,
+	}
+
+	ownTask := Resource{TeamId: 10, OwnerIds: []int{normalId}}
+	foreignTask := Resource{TeamId: 10, OwnerIds: []int{otherId}}
+
+	tests := []struct {
+		name        string
+		userId      int
+		action      Action
+		res         Resource
+		overrides   *stubOverridesLister
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name:   "normal edits own task",
+			userId: normalId,
+			action: TaskEdit,
+			res:    ownTask,
+		},
+		{
+			name:        "normal cannot edit foreign task",
+			userId:      normalId,
+			action:      TaskEdit,
+			res:         foreignTask,
+			wantErr:     true,
+			expectedErr: ErrForbidden,
+		},
+		{
+			name:   "admin edits any task",
+			userId: adminId,
+			action: TaskEdit,
+			res:    foreignTask,
+		},
+		{
+			name:        "normal cannot invite",
+			userId:      normalId,
+			action:      MemberInvite,
+			res:         TeamResource(10),
+			wantErr:     true,
+			expectedErr: ErrForbidden,
+		},
+		{
+			name:        "outsider cannot view",
+			userId:      outsider,
+			action:      TaskView,
+			res:         TeamResource(10),
+			wantErr:     true,
+			expectedErr: ErrForbidden,
+		},
+		{
+			name:        "admin cannot transfer ownership",
+			userId:      adminId,
+			action:      TeamTransfer,
+			res:         TeamResource(10),
+			wantErr:     true,
+			expectedErr: ErrForbidden,
+		},
+		{
+			name:   "override lets normal edit any task",
+			userId: normalId,
+			action: TaskEdit,
+			res:    foreignTask,
+			overrides: &stubOverridesLister{overrides: []Override{
+				{TeamId: 10, Role: member.NormalRole, Action: TaskEdit, Scope: AnyScope},
+			}},
+		},
+		{
+			name:   "override restricts admin invites",
+			userId: adminId,
+			action: MemberInvite,
+			res:    TeamResource(10),
+			overrides: &stubOverridesLister{overrides: []Override{
+				{TeamId: 10, Role: member.AdminRole, Action: MemberInvite, Scope: NoneScope},
+			}},
+			wantErr:     true,
+			expectedErr: ErrForbidden,
+		},
+		{
+			name:   "owner is not affected by overrides",
+			userId: ownerId,
+			action: MemberInvite,
+			res:    TeamResource(10),
+			overrides: &stubOverridesLister{overrides: []Override{
+				{TeamId: 10, Role: member.OwnerRole, Action: MemberInvite, Scope: NoneScope},
+			}},
+		},
+		{
+			name:   "non overridable action ignores overrides",
+			userId: adminId,
+			action: TeamPermissions,
+			res:    TeamResource(10),
+			overrides: &stubOverridesLister{overrides: []Override{
+				{TeamId: 10, Role: member.AdminRole, Action: TeamPermissions, Scope: AnyScope},
+			}},
+			wantErr:     true,
+			expectedErr: ErrForbidden,
+		},
+		{
+			name:        "overrides error",
+			userId:      normalId,
+			action:      TaskEdit,
+			res:         ownTask,
+			overrides:   &stubOverridesLister{err: errors.New("db error")},
+			wantErr:     true,
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overrides := tt.overrides
+
+			if overrides == nil {
+				overrides = &stubOverridesLister{}
+			}
+
+			e := NewEngine(&stubMemberGetter{roles: roles}, overrides)
+
+			got, err := e.Authorize(context.Background(), tt.userId, tt.action, tt.res)
+
+			if tt.wantErr {
+				assert.Nil(t, got)
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.userId, got.UserId)
+				assert.Equal(t, roles[tt.userId], got.Role)
+			}
+		})
+	}
+}
+
+func TestEngine_Policy(t *testing.T) {
+	e := NewEngine(&stubMemberGetter{}, &stubOverridesLister{overrides: []Override{
+		{TeamId: 10, Role: member.NormalRole, Action: MemberInvite, Scope: AnyScope},
+		{TeamId: 10, Role: member.AdminRole, Action: TeamTransfer, Scope: AnyScope},
+	}})
+
+	policy, err := e.Policy(context.Background(), 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, AnyScope, policy[member.NormalRole][MemberInvite])
+	assert.Equal(t, OwnScope, policy[member.NormalRole][TaskEdit])
+	assert.Equal(t, NoneScope, policy[member.AdminRole][TeamTransfer])
+	assert.Equal(t, AnyScope, policy[member.OwnerRole][TeamPermissions])
+}
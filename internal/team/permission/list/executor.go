@@ -0,0 +1,53 @@
+package list
+
+import (
+	"context"
+
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type policyGetter interface {
+	Policy(ctx context.Context, teamId int) (map[member.Role]map[permission.Action]permission.Scope, error)
+}
+
+type executor struct {
+	authorizer   authorizer
+	policyGetter policyGetter
+}
+
+func NewExecutor(authorizer authorizer, policyGetter policyGetter) *executor {
+	return &executor{
+		authorizer:   authorizer,
+		policyGetter: policyGetter,
+	}
+}
+
+type ListInput struct {
+	UserId int
+	TeamId int
+}
+
+type ListResult struct {
+	Policy map[member.Role]map[permission.Action]permission.Scope
+}
+
+func (e *executor) Execute(ctx context.Context, in ListInput) (*ListResult, error) {
+	_, err := e.authorizer.Authorize(ctx, in.UserId, permission.MemberList, permission.TeamResource(in.TeamId))
+
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := e.policyGetter.Policy(ctx, in.TeamId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListResult{Policy: policy}, nil
+}
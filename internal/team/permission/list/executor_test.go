@@ -0,0 +1,72 @@
+package list
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubAuthorizer struct {
+	err error
+}
+
+func (s *stubAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
+}
+
+type stubPolicyGetter struct{}
+
+func (s *stubPolicyGetter) Policy(ctx context.Context, teamId int) (map[member.Role]map[permission.Action]permission.Scope, error) {
+	return map[member.Role]map[permission.Action]permission.Scope{
+		member.NormalRole: {permission.TaskEdit: permission.OwnScope},
+	}, nil
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		authorizer  authorizer
+		want        *ListResult
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name:       "success",
+			authorizer: &stubAuthorizer{},
+			want: &ListResult{Policy: map[member.Role]map[permission.Action]permission.Scope{
+				member.NormalRole: {permission.TaskEdit: permission.OwnScope},
+			}},
+		},
+		{
+			name:        "not a member",
+			authorizer:  &stubAuthorizer{err: permission.ErrForbidden},
+			wantErr:     true,
+			expectedErr: errors.New("forbidden"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(tt.authorizer, &stubPolicyGetter{})
+
+			got, err := e.Execute(context.Background(), ListInput{UserId: 1, TeamId: 10})
+
+			if tt.wantErr {
+				assert.Nil(t, got)
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package permission
+
+import (
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/team/member"
+)
+
+type Action string
+
+const (
+	TaskView   Action = "task.view"
+	TaskCreate Action = "task.create"
+	TaskEdit   Action = "task.edit"
+	TaskDelete Action = "task.delete"
+	TaskPurge  Action = "task.purge"
+
+	CommentCreate Action = "comment.create"
+	CommentEdit   Action = "comment.edit"
+	CommentDelete Action = "comment.delete"
+
+	MemberList   Action = "member.list"
+	MemberInvite Action = "member.invite"
+	MemberManage Action = "member.manage"
+
+	TeamTransfer    Action = "team.transfer"
+	TeamPermissions Action = "team.permissions"
+	TeamWorkflow    Action = "team.workflow"
+	TeamLabels      Action = "team.labels"
+)
+
+// Scope определяет, к каким объектам применимо действие: ни к каким, только к своим или к любым.
+type Scope string
+
+const NoneScope Scope = "none"
+const OwnScope Scope = "own"
+const AnyScope Scope = "any"
+
+type Override struct {
+	TeamId int
+	Role   member.Role
+	Action Action
+	Scope  Scope
+}
+
+var actions = []Action{
+	TaskView,
+	TaskCreate,
+	TaskEdit,
+	TaskDelete,
+	TaskPurge,
+	CommentCreate,
+	CommentEdit,
+	CommentDelete,
+	MemberList,
+	MemberInvite,
+	MemberManage,
+	TeamTransfer,
+	TeamPermissions,
+	TeamWorkflow,
+	TeamLabels,
+}
+
+var defaultPolicy = map[member.Role]map[Action]Scope{
+	member.AdminRole: {
+		TaskView:        AnyScope,
+		TaskCreate:      AnyScope,
+		TaskEdit:        AnyScope,
+		TaskDelete:      AnyScope,
+		TaskPurge:       AnyScope,
+		CommentCreate:   AnyScope,
+		CommentEdit:     AnyScope,
+		CommentDelete:   AnyScope,
+		MemberList:      AnyScope,
+		MemberInvite:    AnyScope,
+		MemberManage:    AnyScope,
+		TeamTransfer:    NoneScope,
+		TeamPermissions: NoneScope,
+		TeamWorkflow:    AnyScope,
+		TeamLabels:      AnyScope,
+	},
+	member.NormalRole: {
+		TaskView:        AnyScope,
+		TaskCreate:      AnyScope,
+		TaskEdit:        OwnScope,
+		TaskDelete:      OwnScope,
+		TaskPurge:       NoneScope,
+		CommentCreate:   AnyScope,
+		CommentEdit:     OwnScope,
+		CommentDelete:   OwnScope,
+		MemberList:      AnyScope,
+		MemberInvite:    NoneScope,
+		MemberManage:    NoneScope,
+		TeamTransfer:    NoneScope,
+		TeamPermissions: NoneScope,
+		TeamWorkflow:    NoneScope,
+		TeamLabels:      NoneScope,
+	},
+}
+
+func Actions() []Action {
+	return append([]Action(nil), actions...)
+}
+
+func (a Action) IsValid() bool {
+	for _, known := range actions {
+		if a == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Управление владением и самой политикой не переопределяется, иначе owner может потерять контроль над командой.
+// Безвозвратное удаление задач остаётся только за owner и admin.
+func (a Action) IsOverridable() bool {
+	return a.IsValid() && a != TeamTransfer && a != TeamPermissions && a != TaskPurge
+}
+
+func (s Scope) IsValid() bool {
+	return s == NoneScope || s == OwnScope || s == AnyScope
+}
+
+func (o Override) Validate() error {
+	if !o.Role.IsValid() {
+		return apperror.Validation("invalid role")
+	}
+
+	if o.Role == member.OwnerRole {
+		return apperror.Validation("owner permissions cannot be overridden")
+	}
+
+	if !o.Action.IsValid() {
+		return apperror.Validation("invalid action")
+	}
+
+	if !o.Action.IsOverridable() {
+		return apperror.Validation("action cannot be overridden")
+	}
+
+	if !o.Scope.IsValid() {
+		return apperror.Validation("invalid scope")
+	}
+
+	return nil
+}
+
+func DefaultScope(role member.Role, action Action) Scope {
+	if role == member.OwnerRole {
+		return AnyScope
+	}
+
+	scope, ok := defaultPolicy[role][action]
+
+	if !ok {
+		return NoneScope
+	}
+
+	return scope
+}
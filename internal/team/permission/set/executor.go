@@ -0,0 +1,57 @@
+package set
+
+import (
+	"context"
+
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+)
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type overrideSetter interface {
+	SetPermissionOverride(ctx context.Context, o permission.Override) error
+}
+
+type executor struct {
+	authorizer     authorizer
+	overrideSetter overrideSetter
+}
+
+func NewExecutor(authorizer authorizer, overrideSetter overrideSetter) *executor {
+	return &executor{
+		authorizer:     authorizer,
+		overrideSetter: overrideSetter,
+	}
+}
+
+type SetInput struct {
+	UserId int
+	TeamId int
+	Role   member.Role
+	Action permission.Action
+	Scope  permission.Scope
+}
+
+func (e *executor) Execute(ctx context.Context, in SetInput) error {
+	override := permission.Override{
+		TeamId: in.TeamId,
+		Role:   in.Role,
+		Action: in.Action,
+		Scope:  in.Scope,
+	}
+
+	if err := override.Validate(); err != nil {
+		return err
+	}
+
+	_, err := e.authorizer.Authorize(ctx, in.UserId, permission.TeamPermissions, permission.TeamResource(in.TeamId))
+
+	if err != nil {
+		return err
+	}
+
+	return e.overrideSetter.SetPermissionOverride(ctx, override)
+}
@@ -0,0 +1,119 @@
+package set
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockAuthorizer struct {
+	action permission.Action
+	err    error
+}
+
+func (m *mockAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	m.action = action
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.OwnerRole}, nil
+}
+
+type mockOverrideSetter struct {
+	saved *permission.Override
+}
+
+func (m *mockOverrideSetter) SetPermissionOverride(ctx context.Context, o permission.Override) error {
+	m.saved = &o
+
+	return nil
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		in          SetInput
+		authErr     error
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name: "owner lets normal members invite",
+			in:   SetInput{Role: member.NormalRole, Action: permission.MemberInvite, Scope: permission.AnyScope},
+		},
+		{
+			name:        "not allowed to manage permissions",
+			in:          SetInput{Role: member.NormalRole, Action: permission.MemberInvite, Scope: permission.AnyScope},
+			authErr:     permission.ErrForbidden,
+			wantErr:     true,
+			expectedErr: errors.New("forbidden"),
+		},
+		{
+			name:        "owner role",
+			in:          SetInput{Role: member.OwnerRole, Action: permission.TaskEdit, Scope: permission.NoneScope},
+			wantErr:     true,
+			expectedErr: errors.New("owner permissions cannot be overridden"),
+		},
+		{
+			name:        "non overridable action",
+			in:          SetInput{Role: member.AdminRole, Action: permission.TeamTransfer, Scope: permission.AnyScope},
+			wantErr:     true,
+			expectedErr: errors.New("action cannot be overridden"),
+		},
+		{
+			name:        "unknown action",
+			in:          SetInput{Role: member.AdminRole, Action: "task.fly", Scope: permission.AnyScope},
+			wantErr:     true,
+			expectedErr: errors.New("invalid action"),
+		},
+		{
+			name:        "unknown scope",
+			in:          SetInput{Role: member.AdminRole, Action: permission.TaskEdit, Scope: "some"},
+			wantErr:     true,
+			expectedErr: errors.New("invalid scope"),
+		},
+		{
+			name:        "unknown role",
+			in:          SetInput{Role: "guest", Action: permission.TaskEdit, Scope: permission.AnyScope},
+			wantErr:     true,
+			expectedErr: errors.New("invalid role"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := &mockAuthorizer{err: tt.authErr}
+			setter := &mockOverrideSetter{}
+
+			e := NewExecutor(auth, setter)
+
+			tt.in.UserId = 1
+			tt.in.TeamId = 10
+
+			err := e.Execute(context.Background(), tt.in)
+
+			if tt.wantErr {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+				assert.Nil(t, setter.saved)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, permission.TeamPermissions, auth.action)
+			assert.Equal(t, &permission.Override{
+				TeamId: 10,
+				Role:   tt.in.Role,
+				Action: tt.in.Action,
+				Scope:  tt.in.Scope,
+			}, setter.saved)
+		})
+	}
+}
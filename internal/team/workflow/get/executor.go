@@ -0,0 +1,45 @@
+package get
+
+import (
+	"context"
+
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+	"mkk-luna-test-task/internal/team/workflow"
+)
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type workflowGetter interface {
+	GetWorkflow(ctx context.Context, teamId int) (*workflow.Model, error)
+}
+
+type executor struct {
+	authorizer     authorizer
+	workflowGetter workflowGetter
+}
+
+func NewExecutor(authorizer authorizer, workflowGetter workflowGetter) *executor {
+	return &executor{
+		authorizer:     authorizer,
+		workflowGetter: workflowGetter,
+	}
+}
+
+type GetInput struct {
+	UserId int
+	TeamId int
+}
+
+func (e *executor) Execute(ctx context.Context, in GetInput) (*workflow.Model, error) {
+	// Процесс нужен всем, кто работает с задачами команды, поэтому достаточно права на просмотр задач.
+	_, err := e.authorizer.Authorize(ctx, in.UserId, permission.TaskView, permission.TeamResource(in.TeamId))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return e.workflowGetter.GetWorkflow(ctx, in.TeamId)
+}
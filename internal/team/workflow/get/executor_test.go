@@ -0,0 +1,86 @@
+package get
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+	"mkk-luna-test-task/internal/team/workflow"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockAuthorizer struct {
+	action permission.Action
+	err    error
+}
+
+func (m *mockAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	m.action = action
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.NormalRole}, nil
+}
+
+type stubWorkflowGetter struct {
+	err error
+}
+
+func (s *stubWorkflowGetter) GetWorkflow(ctx context.Context, teamId int) (*workflow.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return workflow.Default(teamId), nil
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		authErr     error
+		getErr      error
+		wantErr     bool
+		expectedErr string
+	}{
+		{
+			name: "member sees workflow",
+		},
+		{
+			name:        "not a member",
+			authErr:     permission.ErrForbidden,
+			wantErr:     true,
+			expectedErr: "forbidden",
+		},
+		{
+			name:        "db error",
+			getErr:      errors.New("db error"),
+			wantErr:     true,
+			expectedErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := &mockAuthorizer{err: tt.authErr}
+			e := NewExecutor(auth, &stubWorkflowGetter{err: tt.getErr})
+
+			got, err := e.Execute(context.Background(), GetInput{UserId: 1, TeamId: 10})
+
+			assert.Equal(t, permission.TaskView, auth.action)
+
+			if tt.wantErr {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 10, got.TeamId)
+				assert.Equal(t, "todo", got.Initial())
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package get
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/team/workflow"
+)
+
+type statusItem struct {
+	Name     string `json:"name"`
+	Terminal bool   `json:"terminal"`
+}
+
+type transitionItem struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type response struct {
+	Statuses    []statusItem     `json:"statuses"`
+	Transitions []transitionItem `json:"transitions"`
+}
+
+type Executor interface {
+	Execute(ctx context.Context, in GetInput) (*workflow.Model, error)
+}
+
+type handler struct {
+	exec Executor
+}
+
+func NewHandler(exec Executor) *handler {
+	return &handler{
+		exec: exec,
+	}
+}
+
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	userIdAny := r.Context().Value("userId")
+
+	userId, ok := userIdAny.(int)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
+	teamId, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("invalid team id"))
+
+		return
+	}
+
+	wf, err := h.exec.Execute(r.Context(), GetInput{UserId: userId, TeamId: teamId})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	resp := response{
+		Statuses:    make([]statusItem, 0, len(wf.Statuses)),
+		Transitions: make([]transitionItem, 0, len(wf.Transitions)),
+	}
+
+	for _, s := range wf.Statuses {
+		resp.Statuses = append(resp.Statuses, statusItem{Name: s.Name, Terminal: s.Terminal})
+	}
+
+	for _, t := range wf.Transitions {
+		resp.Transitions = append(resp.Transitions, transitionItem{From: t.From, To: t.To})
+	}
+
+	respBody, err := json.Marshal(resp)
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
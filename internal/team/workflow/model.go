@@ -0,0 +1,164 @@
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+const maxStatuses = 32
+
+var statusNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+type Status struct {
+	Name     string
+	Terminal bool
+}
+
+type Transition struct {
+	From string
+	To   string
+}
+
+// Model — процесс работы над задачами команды: статусы в порядке отображения и разрешённые переходы между ними.
+// Новая задача без явного статуса получает первый статус процесса.
+type Model struct {
+	TeamId      int
+	Statuses    []Status
+	Transitions []Transition
+}
+
+// Default используется, пока команда не настроила свой процесс.
+func Default(teamId int) *Model {
+	return &Model{
+		TeamId: teamId,
+		Statuses: []Status{
+			{Name: "todo"},
+			{Name: "in_progress"},
+			{Name: "done", Terminal: true},
+		},
+		Transitions: []Transition{
+			{From: "todo", To: "in_progress"},
+			{From: "todo", To: "done"},
+			{From: "in_progress", To: "todo"},
+			{From: "in_progress", To: "done"},
+			{From: "done", To: "in_progress"},
+		},
+	}
+}
+
+func (m *Model) Initial() string {
+	return m.Statuses[0].Name
+}
+
+func (m *Model) Has(status string) bool {
+	_, ok := m.find(status)
+
+	return ok
+}
+
+func (m *Model) IsTerminal(status string) bool {
+	s, ok := m.find(status)
+
+	return ok && s.Terminal
+}
+
+func (m *Model) CanTransition(from, to string) bool {
+	if from == to {
+		return true
+	}
+
+	for _, t := range m.Transitions {
+		if t.From == from && t.To == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckNew проверяет статус, в котором создаётся задача.
+func (m *Model) CheckNew(status string) error {
+	if !m.Has(status) {
+		return apperror.InvalidField("status", "unknown status for this team")
+	}
+
+	if m.IsTerminal(status) {
+		return apperror.InvalidField("status", "task cannot be created in a terminal status")
+	}
+
+	return nil
+}
+
+// CheckTransition проверяет смену статуса существующей задачи.
+func (m *Model) CheckTransition(from, to string) error {
+	if !m.Has(to) {
+		return apperror.InvalidField("status", "unknown status for this team")
+	}
+
+	// Задачи, созданные до настройки процесса, могут стоять в неизвестном ему статусе: выпускаем их в любой.
+	if m.Has(from) && !m.CanTransition(from, to) {
+		return apperror.Conflict(fmt.Sprintf("transition from %s to %s is not allowed", from, to))
+	}
+
+	return nil
+}
+
+func (m *Model) Validate() error {
+	if len(m.Statuses) == 0 {
+		return apperror.InvalidField("statuses", "at least one status is required")
+	}
+
+	if len(m.Statuses) > maxStatuses {
+		return apperror.InvalidField("statuses", fmt.Sprintf("must contain at most %d statuses", maxStatuses))
+	}
+
+	seen := make(map[string]bool, len(m.Statuses))
+
+	for _, s := range m.Statuses {
+		if !statusNamePattern.MatchString(s.Name) {
+			return apperror.InvalidField("statuses", fmt.Sprintf("invalid status name %q", s.Name))
+		}
+
+		if seen[s.Name] {
+			return apperror.InvalidField("statuses", fmt.Sprintf("duplicate status %q", s.Name))
+		}
+
+		seen[s.Name] = true
+	}
+
+	if m.Statuses[0].Terminal {
+		return apperror.InvalidField("statuses", "first status cannot be terminal")
+	}
+
+	seenTransitions := make(map[Transition]bool, len(m.Transitions))
+
+	for _, t := range m.Transitions {
+		if seenTransitions[t] {
+			return apperror.InvalidField("transitions", fmt.Sprintf("duplicate transition %s -> %s", t.From, t.To))
+		}
+
+		seenTransitions[t] = true
+
+		if !seen[t.From] || !seen[t.To] {
+			return apperror.InvalidField("transitions", fmt.Sprintf("transition %s -> %s references an unknown status", t.From, t.To))
+		}
+
+		if t.From == t.To {
+			return apperror.InvalidField("transitions", fmt.Sprintf("transition %s -> %s is redundant", t.From, t.To))
+		}
+	}
+
+	return nil
+}
+
+func (m *Model) find(status string) (Status, bool) {
+	for _, s := range m.Statuses {
+		if s.Name == status {
+			return s, true
+		}
+	}
+
+	return Status{}, false
+}
@@ -0,0 +1,94 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		wf          Model
+		expectedErr string
+	}{
+		{
+			name: "default",
+			wf:   *Default(1),
+		},
+		{
+			name: "qa and blocked",
+			wf: Model{
+				Statuses:    []Status{{Name: "todo"}, {Name: "blocked"}, {Name: "qa_review"}, {Name: "done", Terminal: true}},
+				Transitions: []Transition{{From: "todo", To: "blocked"}, {From: "blocked", To: "todo"}, {From: "todo", To: "qa_review"}, {From: "qa_review", To: "done"}},
+			},
+		},
+		{
+			name:        "no statuses",
+			wf:          Model{},
+			expectedErr: "validation failed",
+		},
+		{
+			name:        "invalid name",
+			wf:          Model{Statuses: []Status{{Name: "In Progress"}}},
+			expectedErr: "validation failed",
+		},
+		{
+			name:        "duplicate status",
+			wf:          Model{Statuses: []Status{{Name: "todo"}, {Name: "todo"}}},
+			expectedErr: "validation failed",
+		},
+		{
+			name:        "terminal first status",
+			wf:          Model{Statuses: []Status{{Name: "done", Terminal: true}, {Name: "todo"}}},
+			expectedErr: "validation failed",
+		},
+		{
+			name: "transition to unknown status",
+			wf: Model{
+				Statuses:    []Status{{Name: "todo"}},
+				Transitions: []Transition{{From: "todo", To: "done"}},
+			},
+			expectedErr: "validation failed",
+		},
+		{
+			name: "duplicate transition",
+			wf: Model{
+				Statuses:    []Status{{Name: "todo"}, {Name: "done"}},
+				Transitions: []Transition{{From: "todo", To: "done"}, {From: "todo", To: "done"}},
+			},
+			expectedErr: "validation failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.wf.Validate()
+
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestModel_CheckTransition(t *testing.T) {
+	wf := Default(1)
+
+	assert.NoError(t, wf.CheckTransition("todo", "in_progress"))
+	assert.NoError(t, wf.CheckTransition("done", "done"))
+	assert.NoError(t, wf.CheckTransition("legacy", "todo"))
+	assert.EqualError(t, wf.CheckTransition("done", "todo"), "transition from done to todo is not allowed")
+	assert.EqualError(t, wf.CheckTransition("todo", "blocked"), "validation failed")
+}
+
+func TestModel_CheckNew(t *testing.T) {
+	wf := Default(1)
+
+	assert.Equal(t, "todo", wf.Initial())
+	assert.NoError(t, wf.CheckNew("in_progress"))
+	assert.EqualError(t, wf.CheckNew("done"), "validation failed")
+	assert.EqualError(t, wf.CheckNew("blocked"), "validation failed")
+}
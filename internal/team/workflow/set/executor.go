@@ -0,0 +1,77 @@
+package set
+
+import (
+	"context"
+	"fmt"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+	"mkk-luna-test-task/internal/team/workflow"
+)
+
+type authorizer interface {
+	Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error)
+}
+
+type taskStatusLister interface {
+	ListTaskStatuses(ctx context.Context, teamId int) ([]string, error)
+}
+
+type workflowSetter interface {
+	SetWorkflow(ctx context.Context, wf *workflow.Model) error
+}
+
+type executor struct {
+	authorizer       authorizer
+	taskStatusLister taskStatusLister
+	workflowSetter   workflowSetter
+}
+
+func NewExecutor(authorizer authorizer, taskStatusLister taskStatusLister, workflowSetter workflowSetter) *executor {
+	return &executor{
+		authorizer:       authorizer,
+		taskStatusLister: taskStatusLister,
+		workflowSetter:   workflowSetter,
+	}
+}
+
+type SetInput struct {
+	UserId      int
+	TeamId      int
+	Statuses    []workflow.Status
+	Transitions []workflow.Transition
+}
+
+func (e *executor) Execute(ctx context.Context, in SetInput) error {
+	wf := &workflow.Model{
+		TeamId:      in.TeamId,
+		Statuses:    in.Statuses,
+		Transitions: in.Transitions,
+	}
+
+	if err := wf.Validate(); err != nil {
+		return err
+	}
+
+	_, err := e.authorizer.Authorize(ctx, in.UserId, permission.TeamWorkflow, permission.TeamResource(in.TeamId))
+
+	if err != nil {
+		return err
+	}
+
+	// Статус, в котором уже стоят задачи, убрать нельзя: иначе они застрянут вне процесса.
+	used, err := e.taskStatusLister.ListTaskStatuses(ctx, in.TeamId)
+
+	if err != nil {
+		return err
+	}
+
+	for _, status := range used {
+		if !wf.Has(status) {
+			return apperror.Conflict(fmt.Sprintf("status %s is still used by tasks", status))
+		}
+	}
+
+	return e.workflowSetter.SetWorkflow(ctx, wf)
+}
@@ -0,0 +1,121 @@
+package set
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"mkk-luna-test-task/internal/team/member"
+	"mkk-luna-test-task/internal/team/permission"
+	"mkk-luna-test-task/internal/team/workflow"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockAuthorizer struct {
+	action permission.Action
+	err    error
+}
+
+func (m *mockAuthorizer) Authorize(ctx context.Context, userId int, action permission.Action, res permission.Resource) (*member.Model, error) {
+	m.action = action
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &member.Model{UserId: userId, TeamId: res.TeamId, Role: member.AdminRole}, nil
+}
+
+type stubTaskStatusLister struct {
+	statuses []string
+	err      error
+}
+
+func (s *stubTaskStatusLister) ListTaskStatuses(ctx context.Context, teamId int) ([]string, error) {
+	return s.statuses, s.err
+}
+
+type mockWorkflowSetter struct {
+	saved *workflow.Model
+}
+
+func (m *mockWorkflowSetter) SetWorkflow(ctx context.Context, wf *workflow.Model) error {
+	m.saved = wf
+
+	return nil
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	qaWorkflow := SetInput{
+		UserId:      1,
+		TeamId:      10,
+		Statuses:    []workflow.Status{{Name: "todo"}, {Name: "qa"}, {Name: "done", Terminal: true}},
+		Transitions: []workflow.Transition{{From: "todo", To: "qa"}, {From: "qa", To: "done"}},
+	}
+
+	tests := []struct {
+		name        string
+		in          SetInput
+		authErr     error
+		used        []string
+		listErr     error
+		wantErr     bool
+		expectedErr string
+	}{
+		{
+			name: "admin sets qa workflow",
+			in:   qaWorkflow,
+			used: []string{"todo", "done"},
+		},
+		{
+			name:        "invalid workflow",
+			in:          SetInput{TeamId: 10},
+			wantErr:     true,
+			expectedErr: "validation failed",
+		},
+		{
+			name:        "not allowed",
+			in:          qaWorkflow,
+			authErr:     permission.ErrForbidden,
+			wantErr:     true,
+			expectedErr: "forbidden",
+		},
+		{
+			name:        "removes status still used by tasks",
+			in:          qaWorkflow,
+			used:        []string{"todo", "in_progress"},
+			wantErr:     true,
+			expectedErr: "status in_progress is still used by tasks",
+		},
+		{
+			name:        "status lookup fails",
+			in:          qaWorkflow,
+			listErr:     errors.New("db error"),
+			wantErr:     true,
+			expectedErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := &mockAuthorizer{err: tt.authErr}
+			setter := &mockWorkflowSetter{}
+			e := NewExecutor(auth, &stubTaskStatusLister{statuses: tt.used, err: tt.listErr}, setter)
+
+			err := e.Execute(context.Background(), tt.in)
+
+			if tt.wantErr {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Nil(t, setter.saved)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, permission.TeamWorkflow, auth.action)
+			assert.Equal(t, 10, setter.saved.TeamId)
+			assert.Equal(t, tt.in.Statuses, setter.saved.Statuses)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package set
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/team/workflow"
+)
+
+type statusItem struct {
+	Name     string `json:"name"`
+	Terminal bool   `json:"terminal"`
+}
+
+type transitionItem struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type request struct {
+	Statuses    []statusItem     `json:"statuses"`
+	Transitions []transitionItem `json:"transitions"`
+}
+
+type Executor interface {
+	Execute(ctx context.Context, in SetInput) error
+}
+
+type handler struct {
+	exec Executor
+}
+
+func NewHandler(exec Executor) *handler {
+	return &handler{
+		exec: exec,
+	}
+}
+
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	userIdAny := r.Context().Value("userId")
+
+	userId, ok := userIdAny.(int)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
+	teamId, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("invalid team id"))
+
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	defer r.Body.Close()
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	var req request
+
+	if err := json.Unmarshal(body, &req); err != nil {
+		apperror.Write(w, apperror.Validation("invalid json"))
+
+		return
+	}
+
+	in := SetInput{
+		UserId:      userId,
+		TeamId:      teamId,
+		Statuses:    make([]workflow.Status, 0, len(req.Statuses)),
+		Transitions: make([]workflow.Transition, 0, len(req.Transitions)),
+	}
+
+	for _, s := range req.Statuses {
+		in.Statuses = append(in.Statuses, workflow.Status{Name: s.Name, Terminal: s.Terminal})
+	}
+
+	for _, t := range req.Transitions {
+		in.Transitions = append(in.Transitions, workflow.Transition{From: t.From, To: t.To})
+	}
+
+	if err := h.exec.Execute(r.Context(), in); err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,20 +1,35 @@
 package user
 
 import (
+	"context"
+	"crypto"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt"
+
+	"mkk-luna-test-task/internal/apperror"
 )
 
+type accessTokenChecker interface {
+	IsAccessTokenActive(ctx context.Context, sessionId, tokenId string, now time.Time) (bool, error)
+}
+
+type verificationKeyProvider interface {
+	VerificationKey(id string) (jwt.SigningMethod, crypto.PublicKey, error)
+}
+
 type JwtUserFromRequestGetter struct {
-	secret []byte
+	keys               verificationKeyProvider
+	accessTokenChecker accessTokenChecker
 }
 
-func NewJwtUserFromRequestGetter(secret []byte) *JwtUserFromRequestGetter {
+func NewJwtUserFromRequestGetter(keys verificationKeyProvider, accessTokenChecker accessTokenChecker) *JwtUserFromRequestGetter {
 	return &JwtUserFromRequestGetter{
-		secret: secret,
+		keys:               keys,
+		accessTokenChecker: accessTokenChecker,
 	}
 }
 
@@ -22,36 +37,69 @@ func (g *JwtUserFromRequestGetter) GetUserFromRequest(r *http.Request) (Model, e
 	tokenStr := strings.TrimSpace(r.Header.Get("jwt-token"))
 
 	if tokenStr == "" {
-		return Model{}, fmt.Errorf("jwt-token header required")
+		return Model{}, apperror.Unauthorized("jwt-token header required")
 	}
 
 	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		keyId, ok := token.Header["kid"].(string)
+
+		if !ok {
+			return nil, fmt.Errorf("token missing kid")
+		}
+
+		method, key, err := g.keys.VerificationKey(keyId)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// Алгоритм берём из ключа, а не из заголовка токена, иначе возможна подмена alg.
+		if token.Method.Alg() != method.Alg() {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 
-		return g.secret, nil
+		return key, nil
 	})
 
 	if err != nil || !token.Valid {
-		return Model{}, fmt.Errorf("invalid token")
+		return Model{}, apperror.Unauthorized("invalid token")
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 
 	if !ok {
-		return Model{}, fmt.Errorf("invalid token claims")
+		return Model{}, apperror.Unauthorized("invalid token claims")
 	}
 
 	idFloat, hasId := claims["id"].(float64)
 	username, hasName := claims["name"].(string)
 
 	if !hasId || !hasName {
-		return Model{}, fmt.Errorf("token missing user id or username")
+		return Model{}, apperror.Unauthorized("token missing user id or username")
+	}
+
+	sessionId, hasSessionId := claims["sid"].(string)
+	tokenId, hasTokenId := claims["jti"].(string)
+
+	if !hasSessionId || !hasTokenId {
+		return Model{}, apperror.Unauthorized("token missing session")
+	}
+
+	// Подпись сама по себе не говорит, что сессия жива: её могли отозвать или токен уже ротирован.
+	// Сбой проверки — не повод считать токен недействительным, поэтому это не Unauthorized.
+	active, err := g.accessTokenChecker.IsAccessTokenActive(r.Context(), sessionId, tokenId, time.Now())
+
+	if err != nil {
+		return Model{}, fmt.Errorf("failed to check session: %w", err)
+	}
+
+	if !active {
+		return Model{}, apperror.Unauthorized("token revoked")
 	}
 
 	return Model{
-		Id:       int(idFloat),
-		Username: username,
+		Id:        int(idFloat),
+		Username:  username,
+		SessionId: sessionId,
 	}, nil
 }
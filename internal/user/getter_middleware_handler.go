@@ -2,7 +2,10 @@ package user
 
 import (
 	"context"
+	"errors"
 	"net/http"
+
+	"mkk-luna-test-task/internal/apperror"
 )
 
 type UserFromRequestGetter interface {
@@ -25,12 +28,21 @@ func (m *UserGetterMiddleware) Handle(w http.ResponseWriter, r *http.Request) {
 	user, err := m.getter.GetUserFromRequest(r)
 
 	if err != nil {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		// 401 — только если токен действительно не принят. Сбой хранилища сессий не повод
+		// разлогинивать клиента: он уходит как 503 или 500.
+		if errors.Is(err, apperror.ErrUnauthorized) {
+			apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+			return
+		}
+
+		apperror.Write(w, err)
 
 		return
 	}
 
 	ctx := context.WithValue(r.Context(), "userId", user.Id)
+	ctx = context.WithValue(ctx, "sessionId", user.SessionId)
 
 	m.next.ServeHTTP(w, r.WithContext(ctx))
 }
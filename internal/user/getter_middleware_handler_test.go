@@ -0,0 +1,74 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mkk-luna-test-task/internal/apperror"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubUserFromRequestGetter struct {
+	user Model
+	err  error
+}
+
+func (s *stubUserFromRequestGetter) GetUserFromRequest(r *http.Request) (Model, error) {
+	return s.user, s.err
+}
+
+func TestUserGetterMiddleware_Handle(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{
+			name:       "valid user",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "revoked token",
+			err:        apperror.Unauthorized("token revoked"),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "session storage unavailable",
+			err:        apperror.Unavailable("storage unavailable", errors.New("connection refused")),
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "session storage error",
+			err:        errors.New("failed to check session: Error 1205: Lock wait timeout exceeded"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUserId any
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotUserId = r.Context().Value("userId")
+
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			m := NewUserGetterMiddleware(&stubUserFromRequestGetter{user: Model{Id: 5}, err: tt.err}, next)
+
+			rr := httptest.NewRecorder()
+			m.Handle(rr, httptest.NewRequest("GET", "/", nil))
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+
+			if tt.err == nil {
+				assert.Equal(t, 5, gotUserId)
+			} else {
+				assert.Nil(t, gotUserId)
+			}
+		})
+	}
+}
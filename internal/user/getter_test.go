@@ -1,32 +1,74 @@
 package user
 
 import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"mkk-luna-test-task/internal/apperror"
+
 	"github.com/golang-jwt/jwt"
 	"github.com/stretchr/testify/assert"
 )
 
 var (
-	secret    = []byte("secret")
+	publicKey, privateKey, _ = ed25519.GenerateKey(rand.Reader)
+	_, otherPrivateKey, _    = ed25519.GenerateKey(rand.Reader)
+
+	validKid  = "key-1"
 	validId   = 99
 	validName = "luna"
+	validSid  = "session-1"
+	validJti  = "token-1"
 )
 
+type stubVerificationKeyProvider struct{}
+
+func (s *stubVerificationKeyProvider) VerificationKey(id string) (jwt.SigningMethod, crypto.PublicKey, error) {
+	if id != validKid {
+		return nil, nil, fmt.Errorf("unknown key id %q", id)
+	}
+
+	return jwt.SigningMethodEdDSA, publicKey, nil
+}
+
+func signToken(claims jwt.MapClaims, key ed25519.PrivateKey, kid string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = kid
+
+	s, _ := token.SignedString(key)
+
+	return s
+}
+
+type stubAccessTokenCheckerActive struct{}
+
+func (s *stubAccessTokenCheckerActive) IsAccessTokenActive(ctx context.Context, sessionId, tokenId string, now time.Time) (bool, error) {
+	return sessionId == validSid && tokenId == validJti, nil
+}
+
+type stubAccessTokenCheckerError struct{}
+
+func (s *stubAccessTokenCheckerError) IsAccessTokenActive(ctx context.Context, sessionId, tokenId string, now time.Time) (bool, error) {
+	return false, errors.New("db error")
+}
+
 func validToken() string {
 	claims := jwt.MapClaims{
 		"id":   validId,
 		"name": validName,
+		"sid":  validSid,
+		"jti":  validJti,
 		"exp":  time.Now().Add(60 * time.Second).Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	s, _ := token.SignedString(secret)
-
-	return s
+	return signToken(claims, privateKey, validKid)
 }
 
 func tokenMissingId() string {
@@ -35,11 +77,7 @@ func tokenMissingId() string {
 		"exp":  time.Now().Add(60 * time.Second).Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	s, _ := token.SignedString(secret)
-
-	return s
+	return signToken(claims, privateKey, validKid)
 }
 
 func tokenMissingUsername() string {
@@ -48,37 +86,77 @@ func tokenMissingUsername() string {
 		"exp": time.Now().Add(60 * time.Second).Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return signToken(claims, privateKey, validKid)
+}
 
-	s, _ := token.SignedString(secret)
+func invalidSignatureToken() string {
+	claims := jwt.MapClaims{
+		"id":   validId,
+		"name": validName,
+		"sid":  validSid,
+		"jti":  validJti,
+		"exp":  time.Now().Add(60 * time.Second).Unix(),
+	}
 
-	return s
+	return signToken(claims, otherPrivateKey, validKid)
 }
 
-func invalidSignatureToken() string {
+func expiredToken() string {
+	claims := jwt.MapClaims{
+		"id":   validId,
+		"name": validName,
+		"sid":  validSid,
+		"jti":  validJti,
+		"exp":  time.Now().Add(-60 * time.Second).Unix(),
+	}
+
+	return signToken(claims, privateKey, validKid)
+}
+
+func tokenWithSession(sid, jti string) string {
 	claims := jwt.MapClaims{
 		"id":   validId,
 		"name": validName,
 		"exp":  time.Now().Add(60 * time.Second).Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	if sid != "" {
+		claims["sid"] = sid
+	}
 
-	s, _ := token.SignedString([]byte("wrong"))
+	if jti != "" {
+		claims["jti"] = jti
+	}
 
-	return s
+	return signToken(claims, privateKey, validKid)
 }
 
-func expiredToken() string {
+func unknownKidToken() string {
 	claims := jwt.MapClaims{
 		"id":   validId,
 		"name": validName,
-		"exp":  time.Now().Add(-60 * time.Second).Unix(),
+		"sid":  validSid,
+		"jti":  validJti,
+		"exp":  time.Now().Add(60 * time.Second).Unix(),
+	}
+
+	return signToken(claims, privateKey, "key-0")
+}
+
+// HMAC-токен, подписанный публичным ключом как секретом, — классическая атака подменой alg.
+func hmacToken() string {
+	claims := jwt.MapClaims{
+		"id":   validId,
+		"name": validName,
+		"sid":  validSid,
+		"jti":  validJti,
+		"exp":  time.Now().Add(60 * time.Second).Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = validKid
 
-	s, _ := token.SignedString(secret)
+	s, _ := token.SignedString([]byte(publicKey))
 
 	return s
 }
@@ -88,7 +166,7 @@ func malformedToken() string {
 }
 
 func TestJwtUserFromRequestGetter_GetUserFromRequest(t *testing.T) {
-	getter := NewJwtUserFromRequestGetter(secret)
+	getter := NewJwtUserFromRequestGetter(&stubVerificationKeyProvider{}, &stubAccessTokenCheckerActive{})
 
 	tests := []struct {
 		name          string
@@ -102,7 +180,7 @@ func TestJwtUserFromRequestGetter_GetUserFromRequest(t *testing.T) {
 			name:          "valid token",
 			token:         validToken(),
 			tokenHeader:   true,
-			expectedModel: Model{Id: validId, Username: validName},
+			expectedModel: Model{Id: validId, Username: validName, SessionId: validSid},
 			wantErr:       false,
 		},
 		{
@@ -140,6 +218,41 @@ func TestJwtUserFromRequestGetter_GetUserFromRequest(t *testing.T) {
 			wantErr:     true,
 			expectedErr: "invalid token",
 		},
+		{
+			name:        "unknown key id",
+			token:       unknownKidToken(),
+			tokenHeader: true,
+			wantErr:     true,
+			expectedErr: "invalid token",
+		},
+		{
+			name:        "hmac token signed with public key",
+			token:       hmacToken(),
+			tokenHeader: true,
+			wantErr:     true,
+			expectedErr: "invalid token",
+		},
+		{
+			name:        "token without session",
+			token:       tokenWithSession("", ""),
+			tokenHeader: true,
+			wantErr:     true,
+			expectedErr: "token missing session",
+		},
+		{
+			name:        "revoked session",
+			token:       tokenWithSession("session-2", validJti),
+			tokenHeader: true,
+			wantErr:     true,
+			expectedErr: "token revoked",
+		},
+		{
+			name:        "rotated access token",
+			token:       tokenWithSession(validSid, "token-0"),
+			tokenHeader: true,
+			wantErr:     true,
+			expectedErr: "token revoked",
+		},
 		{
 			name:        "malformed token string",
 			token:       malformedToken(),
@@ -166,6 +279,8 @@ func TestJwtUserFromRequestGetter_GetUserFromRequest(t *testing.T) {
 				if tt.expectedErr != "" {
 					assert.Contains(t, err.Error(), tt.expectedErr)
 				}
+
+				assert.ErrorIs(t, err, apperror.ErrUnauthorized)
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.expectedModel, got)
@@ -173,3 +288,18 @@ func TestJwtUserFromRequestGetter_GetUserFromRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestJwtUserFromRequestGetter_GetUserFromRequest_CheckerError(t *testing.T) {
+	getter := NewJwtUserFromRequestGetter(&stubVerificationKeyProvider{}, &stubAccessTokenCheckerError{})
+
+	rr := httptest.NewRequest("GET", "/", nil)
+	rr.Header.Set("jwt-token", validToken())
+
+	got, err := getter.GetUserFromRequest(rr)
+
+	assert.Equal(t, Model{}, got)
+	assert.EqualError(t, err, "failed to check session: db error")
+	// Ошибка БД вроде lock wait timeout не должна выглядеть как отозванный токен.
+	assert.NotErrorIs(t, err, apperror.ErrUnauthorized)
+	assert.Equal(t, apperror.KindInternal, apperror.KindOf(err))
+}
@@ -0,0 +1,69 @@
+package jwks
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+
+	"mkk-luna-test-task/internal/user/session"
+)
+
+type keyLister interface {
+	Keys() []*session.Key
+}
+
+type executor struct {
+	keyLister keyLister
+}
+
+func NewExecutor(keyLister keyLister) *executor {
+	return &executor{keyLister: keyLister}
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSResult struct {
+	Keys []JWK
+}
+
+func (e *executor) Execute() (*JWKSResult, error) {
+	keys := e.keyLister.Keys()
+
+	items := make([]JWK, 0, len(keys))
+
+	for _, k := range keys {
+		jwk := JWK{
+			Kid: k.Id,
+			Use: "sig",
+			Alg: k.Method.Alg(),
+		}
+
+		switch pub := k.PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			return nil, fmt.Errorf("unsupported public key type %T", k.PublicKey)
+		}
+
+		items = append(items, jwk)
+	}
+
+	return &JWKSResult{Keys: items}, nil
+}
@@ -0,0 +1,83 @@
+package jwks
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"mkk-luna-test-task/internal/user/session"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubKeyLister struct {
+	keys []*session.Key
+}
+
+func (s *stubKeyLister) Keys() []*session.Key {
+	return s.keys
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	edKey, err := session.NewKey("2024-01", edPrivate)
+	assert.NoError(t, err)
+
+	rsaKey, err := session.NewPublicKey("2023-12", &rsaPrivate.PublicKey)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name string
+		keys []*session.Key
+		want *JWKSResult
+	}{
+		{
+			name: "ed25519 and retired rsa key",
+			keys: []*session.Key{rsaKey, edKey},
+			want: &JWKSResult{
+				Keys: []JWK{
+					{
+						Kty: "RSA",
+						Kid: "2023-12",
+						Use: "sig",
+						Alg: "RS256",
+						N:   base64.RawURLEncoding.EncodeToString(rsaPrivate.N.Bytes()),
+						E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaPrivate.E)).Bytes()),
+					},
+					{
+						Kty: "OKP",
+						Kid: "2024-01",
+						Use: "sig",
+						Alg: "EdDSA",
+						Crv: "Ed25519",
+						X:   base64.RawURLEncoding.EncodeToString(edPublic),
+					},
+				},
+			},
+		},
+		{
+			name: "no keys",
+			keys: nil,
+			want: &JWKSResult{Keys: []JWK{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &executor{keyLister: &stubKeyLister{keys: tt.keys}}
+
+			got, err := e.Execute()
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
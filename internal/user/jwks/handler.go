@@ -0,0 +1,47 @@
+package jwks
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+type response struct {
+	Keys []JWK `json:"keys"`
+}
+
+type JWKSExecutor interface {
+	Execute() (*JWKSResult, error)
+}
+
+type handler struct {
+	exec JWKSExecutor
+}
+
+func NewHandler(exec JWKSExecutor) *handler {
+	return &handler{exec: exec}
+}
+
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	result, err := h.exec.Execute()
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	respBytes, err := json.Marshal(response{Keys: result.Keys})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	// Проверяющие сервисы могут кэшировать ключи, но ненадолго — иначе ротация до них не доедет.
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(respBytes)
+}
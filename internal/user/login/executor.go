@@ -5,27 +5,44 @@ import (
 	"fmt"
 	"time"
 
-	"mkk-luna-test-task/internal/user"
-
-	"github.com/golang-jwt/jwt"
 	"golang.org/x/crypto/bcrypt"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/user"
+	"mkk-luna-test-task/internal/user/session"
 )
 
 type userGetter interface {
 	GetUser(ctx context.Context, username string) (*user.Model, error)
 }
 
+type sessionCreator interface {
+	CreateSession(ctx context.Context, s session.Model, refreshTokenHash string) error
+}
+
+type accessTokenIssuer interface {
+	IssueAccessToken(u user.Model, sessionId, tokenId string, now time.Time) (string, error)
+	AccessExpiry() time.Duration
+}
+
 type executor struct {
-	userGetter userGetter
-	jwtSecret  []byte
-	jwtExpiry  time.Duration
+	userGetter     userGetter
+	sessionCreator sessionCreator
+	tokenIssuer    accessTokenIssuer
+	refreshExpiry  time.Duration
 }
 
-func NewExecutor(userGetter userGetter, jwtSecret []byte, jwtExpiry time.Duration) *executor {
+func NewExecutor(
+	userGetter userGetter,
+	sessionCreator sessionCreator,
+	tokenIssuer accessTokenIssuer,
+	refreshExpiry time.Duration,
+) *executor {
 	return &executor{
-		userGetter: userGetter,
-		jwtSecret:  jwtSecret,
-		jwtExpiry:  jwtExpiry,
+		userGetter:     userGetter,
+		sessionCreator: sessionCreator,
+		tokenIssuer:    tokenIssuer,
+		refreshExpiry:  refreshExpiry,
 	}
 }
 
@@ -35,39 +52,69 @@ type LoginInput struct {
 }
 
 type LoginResult struct {
-	Token string
+	Token        string
+	RefreshToken string
+	ExpiresIn    time.Duration
 }
 
 func (e *executor) Execute(ctx context.Context, in LoginInput) (*LoginResult, error) {
 	if in.Username == "" || in.Password == "" {
-		return nil, fmt.Errorf("username and password required")
+		return nil, apperror.Validation("username and password required")
 	}
 
 	u, err := e.userGetter.GetUser(ctx, in.Username)
 
 	if err != nil || u == nil {
-		return nil, fmt.Errorf("invalid username or password")
+		return nil, apperror.Unauthorized("invalid username or password")
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(u.PasswordHashed), []byte(in.Password))
 
 	if err != nil {
-		return nil, fmt.Errorf("invalid username or password")
+		return nil, apperror.Unauthorized("invalid username or password")
+	}
+
+	sessionId, err := session.NewId()
+
+	if err != nil {
+		return nil, fmt.Errorf("could not create session: %w", err)
 	}
 
-	claims := jwt.MapClaims{
-		"id":   u.Id,
-		"name": u.Username,
-		"exp":  time.Now().Add(e.jwtExpiry).Unix(),
+	tokenId, err := session.NewId()
+
+	if err != nil {
+		return nil, fmt.Errorf("could not create session: %w", err)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	refreshToken, refreshTokenHash, err := session.NewRefreshToken()
+
+	if err != nil {
+		return nil, fmt.Errorf("could not create session: %w", err)
+	}
+
+	now := time.Now()
+
+	err = e.sessionCreator.CreateSession(ctx, session.Model{
+		Id:            sessionId,
+		UserId:        u.Id,
+		AccessTokenId: tokenId,
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(e.refreshExpiry),
+	}, refreshTokenHash)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not create session: %w", err)
+	}
 
-	tokenString, err := token.SignedString(e.jwtSecret)
+	tokenString, err := e.tokenIssuer.IssueAccessToken(*u, sessionId, tokenId, now)
 
 	if err != nil {
-		return nil, fmt.Errorf("could not create token: %w", err)
+		return nil, err
 	}
 
-	return &LoginResult{Token: tokenString}, nil
+	return &LoginResult{
+		Token:        tokenString,
+		RefreshToken: refreshToken,
+		ExpiresIn:    e.tokenIssuer.AccessExpiry(),
+	}, nil
 }
@@ -2,11 +2,14 @@ package login
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"errors"
 	"testing"
 	"time"
 
 	"mkk-luna-test-task/internal/user"
+	"mkk-luna-test-task/internal/user/session"
 
 	"github.com/golang-jwt/jwt"
 	"github.com/stretchr/testify/assert"
@@ -29,6 +32,24 @@ func (s *stubUserGetterFail) GetUser(ctx context.Context, username string) (*use
 	return nil, s.err
 }
 
+type mockSessionCreator struct {
+	created *session.Model
+	hash    string
+}
+
+func (m *mockSessionCreator) CreateSession(ctx context.Context, s session.Model, refreshTokenHash string) error {
+	m.created = &s
+	m.hash = refreshTokenHash
+
+	return nil
+}
+
+type stubSessionCreatorError struct{}
+
+func (s *stubSessionCreatorError) CreateSession(ctx context.Context, sess session.Model, refreshTokenHash string) error {
+	return errors.New("db error")
+}
+
 func hashedPassword(password string) string {
 	hash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	return string(hash)
@@ -44,10 +65,13 @@ func TestExecutor_Execute(t *testing.T) {
 		PasswordHashed: validHashedPassword,
 	}
 
+	_, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	signingKey, _ := session.NewKey("key-1", privateKey)
+	keys, _ := session.NewKeySet("", signingKey)
+
 	type fields struct {
-		userGetter userGetter
-		jwtSecret  []byte
-		jwtExpiry  time.Duration
+		userGetter     userGetter
+		sessionCreator sessionCreator
 	}
 	type args struct {
 		ctx context.Context
@@ -68,8 +92,7 @@ func TestExecutor_Execute(t *testing.T) {
 				userGetter: &stubUserGetterSuccess{
 					returnUser: validUser,
 				},
-				jwtSecret: []byte("secret"),
-				jwtExpiry: time.Hour,
+				sessionCreator: &mockSessionCreator{},
 			},
 			args: args{
 				ctx: context.Background(),
@@ -85,9 +108,8 @@ func TestExecutor_Execute(t *testing.T) {
 		{
 			name: "missing username",
 			fields: fields{
-				userGetter: &stubUserGetterSuccess{validUser},
-				jwtSecret:  []byte("secret"),
-				jwtExpiry:  time.Hour,
+				userGetter:     &stubUserGetterSuccess{validUser},
+				sessionCreator: &mockSessionCreator{},
 			},
 			args: args{
 				ctx: context.Background(),
@@ -103,9 +125,8 @@ func TestExecutor_Execute(t *testing.T) {
 		{
 			name: "missing password",
 			fields: fields{
-				userGetter: &stubUserGetterSuccess{validUser},
-				jwtSecret:  []byte("secret"),
-				jwtExpiry:  time.Hour,
+				userGetter:     &stubUserGetterSuccess{validUser},
+				sessionCreator: &mockSessionCreator{},
 			},
 			args: args{
 				ctx: context.Background(),
@@ -121,9 +142,8 @@ func TestExecutor_Execute(t *testing.T) {
 		{
 			name: "user getter returns error",
 			fields: fields{
-				userGetter: &stubUserGetterFail{err: errors.New("db error")},
-				jwtSecret:  []byte("secret"),
-				jwtExpiry:  time.Hour,
+				userGetter:     &stubUserGetterFail{err: errors.New("db error")},
+				sessionCreator: &mockSessionCreator{},
 			},
 			args: args{
 				ctx: context.Background(),
@@ -136,12 +156,28 @@ func TestExecutor_Execute(t *testing.T) {
 			wantErr:     true,
 			expectedErr: errors.New("invalid username or password"),
 		},
+		{
+			name: "session creation fails",
+			fields: fields{
+				userGetter:     &stubUserGetterSuccess{validUser},
+				sessionCreator: &stubSessionCreatorError{},
+			},
+			args: args{
+				ctx: context.Background(),
+				in: LoginInput{
+					Username: validUsername,
+					Password: validPassword,
+				},
+			},
+			want:        nil,
+			wantErr:     true,
+			expectedErr: errors.New("could not create session: db error"),
+		},
 		{
 			name: "user not found",
 			fields: fields{
-				userGetter: &stubUserGetterSuccess{returnUser: nil},
-				jwtSecret:  []byte("secret"),
-				jwtExpiry:  time.Hour,
+				userGetter:     &stubUserGetterSuccess{returnUser: nil},
+				sessionCreator: &mockSessionCreator{},
 			},
 			args: args{
 				ctx: context.Background(),
@@ -164,8 +200,7 @@ func TestExecutor_Execute(t *testing.T) {
 						PasswordHashed: hashedPassword("otherpass"),
 					},
 				},
-				jwtSecret: []byte("secret"),
-				jwtExpiry: time.Hour,
+				sessionCreator: &mockSessionCreator{},
 			},
 			args: args{
 				ctx: context.Background(),
@@ -182,9 +217,10 @@ func TestExecutor_Execute(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			e := &executor{
-				userGetter: tt.fields.userGetter,
-				jwtSecret:  tt.fields.jwtSecret,
-				jwtExpiry:  tt.fields.jwtExpiry,
+				userGetter:     tt.fields.userGetter,
+				sessionCreator: tt.fields.sessionCreator,
+				tokenIssuer:    session.NewJwtIssuer(keys, 15*time.Minute),
+				refreshExpiry:  time.Hour,
 			}
 
 			got, err := e.Execute(tt.args.ctx, tt.args.in)
@@ -201,9 +237,19 @@ func TestExecutor_Execute(t *testing.T) {
 
 				if tt.checkToken && got != nil {
 					assert.NotEmpty(t, got.Token)
+					assert.NotEmpty(t, got.RefreshToken)
+					assert.Equal(t, 15*time.Minute, got.ExpiresIn)
+
+					creator, _ := tt.fields.sessionCreator.(*mockSessionCreator)
+
+					assert.NotNil(t, creator.created)
+					assert.Equal(t, validUser.Id, creator.created.UserId)
+					assert.Equal(t, session.HashRefreshToken(got.RefreshToken), creator.hash)
 
 					parsed, perr := jwt.Parse(got.Token, func(token *jwt.Token) (interface{}, error) {
-						return tt.fields.jwtSecret, nil
+						assert.Equal(t, "key-1", token.Header["kid"])
+
+						return signingKey.PublicKey, nil
 					})
 
 					assert.NoError(t, perr)
@@ -212,6 +258,8 @@ func TestExecutor_Execute(t *testing.T) {
 						assert.Equal(t, float64(validUser.Id), claims["id"])
 						assert.Equal(t, validUsername, claims["name"])
 						assert.Contains(t, claims, "exp")
+						assert.Equal(t, creator.created.Id, claims["sid"])
+						assert.Equal(t, creator.created.AccessTokenId, claims["jti"])
 					} else {
 						t.Errorf("token invalid or no claims: %v", got.Token)
 					}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+
+	"mkk-luna-test-task/internal/apperror"
 )
 
 type request struct {
@@ -13,7 +15,9 @@ type request struct {
 }
 
 type response struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
 }
 
 type LoginExecutor interface {
@@ -33,7 +37,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
 	if err != nil {
-		http.Error(w, "could not read request body", http.StatusBadRequest)
+		apperror.Write(w, apperror.Validation("could not read request body"))
 
 		return
 	}
@@ -41,7 +45,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	var req request
 
 	if err := json.Unmarshal(body, &req); err != nil {
-		http.Error(w, "invalid json", http.StatusBadRequest)
+		apperror.Write(w, apperror.Validation("invalid json"))
 
 		return
 	}
@@ -52,17 +56,21 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	})
 
 	if err != nil {
-		http.Error(w, "error", http.StatusBadRequest)
+		apperror.Write(w, err)
 
 		return
 	}
 
-	resp := response{Token: result.Token}
+	resp := response{
+		Token:        result.Token,
+		RefreshToken: result.RefreshToken,
+		ExpiresIn:    int(result.ExpiresIn.Seconds()),
+	}
 
 	respBytes, err := json.Marshal(resp)
 
 	if err != nil {
-		http.Error(w, "could not marshal response", http.StatusInternalServerError)
+		apperror.Write(w, err)
 
 		return
 	}
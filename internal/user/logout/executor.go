@@ -0,0 +1,32 @@
+package logout
+
+import (
+	"context"
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+type sessionRevoker interface {
+	RevokeSession(ctx context.Context, sessionId string, revokedAt time.Time) error
+}
+
+type executor struct {
+	sessionRevoker sessionRevoker
+}
+
+func NewExecutor(sessionRevoker sessionRevoker) *executor {
+	return &executor{sessionRevoker: sessionRevoker}
+}
+
+type LogoutInput struct {
+	SessionId string
+}
+
+func (e *executor) Execute(ctx context.Context, in LogoutInput) error {
+	if in.SessionId == "" {
+		return apperror.Unauthorized("session required")
+	}
+
+	return e.sessionRevoker.RevokeSession(ctx, in.SessionId, time.Now())
+}
@@ -0,0 +1,71 @@
+package logout
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSessionRevoker struct {
+	revoked string
+	err     error
+}
+
+func (m *mockSessionRevoker) RevokeSession(ctx context.Context, sessionId string, revokedAt time.Time) error {
+	m.revoked = sessionId
+
+	return m.err
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	tests := []struct {
+		name          string
+		revoker       *mockSessionRevoker
+		in            LogoutInput
+		wantErr       bool
+		expectedErr   error
+		expectRevoked string
+	}{
+		{
+			name:          "success",
+			revoker:       &mockSessionRevoker{},
+			in:            LogoutInput{SessionId: "sid"},
+			wantErr:       false,
+			expectRevoked: "sid",
+		},
+		{
+			name:        "missing session",
+			revoker:     &mockSessionRevoker{},
+			in:          LogoutInput{},
+			wantErr:     true,
+			expectedErr: errors.New("session required"),
+		},
+		{
+			name:          "revoker error",
+			revoker:       &mockSessionRevoker{err: errors.New("db error")},
+			in:            LogoutInput{SessionId: "sid"},
+			wantErr:       true,
+			expectedErr:   errors.New("db error"),
+			expectRevoked: "sid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &executor{sessionRevoker: tt.revoker}
+
+			err := e.Execute(context.Background(), tt.in)
+
+			if tt.wantErr {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.expectRevoked, tt.revoker.revoked)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package logout
+
+import (
+	"context"
+	"net/http"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+type LogoutExecutor interface {
+	Execute(ctx context.Context, in LogoutInput) error
+}
+
+type handler struct {
+	exec LogoutExecutor
+}
+
+func NewHandler(exec LogoutExecutor) *handler {
+	return &handler{exec: exec}
+}
+
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	sessionIdAny := r.Context().Value("sessionId")
+
+	sessionId, ok := sessionIdAny.(string)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
+	if err := h.exec.Execute(r.Context(), LogoutInput{SessionId: sessionId}); err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -4,4 +4,5 @@ type Model struct {
 	Id             int
 	Username       string
 	PasswordHashed string
+	SessionId      string
 }
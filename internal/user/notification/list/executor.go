@@ -0,0 +1,101 @@
+package list
+
+import (
+	"context"
+	"time"
+
+	"mkk-luna-test-task/internal/user/notification"
+)
+
+const defaultLimit = 100
+
+type notificationLister interface {
+	ListUserNotifications(ctx context.Context, userId int, unreadOnly bool, startFromId int, limit int) ([]notification.Model, error)
+	CountUnreadNotifications(ctx context.Context, userId int) (int, error)
+}
+
+type executor struct {
+	notificationLister notificationLister
+}
+
+func NewExecutor(notificationLister notificationLister) *executor {
+	return &executor{
+		notificationLister: notificationLister,
+	}
+}
+
+type ListInput struct {
+	UserId     int
+	UnreadOnly bool
+	// StartFromId — id последнего уведомления предыдущей страницы; 0 — с самых свежих.
+	StartFromId int
+}
+
+type ListResult struct {
+	Notifications   []NotificationItem
+	UnreadCount     int
+	NextStartFromId int
+	HasMore         bool
+}
+
+type NotificationItem struct {
+	Id           int               `json:"id"`
+	Kind         notification.Kind `json:"kind"`
+	ActorId      *int              `json:"actor_id"`
+	TeamId       int               `json:"team_id"`
+	TaskId       *int              `json:"task_id"`
+	CommentId    *int              `json:"comment_id"`
+	InvitationId *int              `json:"invitation_id"`
+	Data         map[string]string `json:"data,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+	ReadAt       *time.Time        `json:"read_at"`
+}
+
+func (e *executor) Execute(ctx context.Context, in ListInput) (*ListResult, error) {
+	notifications, err := e.notificationLister.ListUserNotifications(ctx, in.UserId, in.UnreadOnly, in.StartFromId, defaultLimit+1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	unread, err := e.notificationLister.CountUnreadNotifications(ctx, in.UserId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ListResult{UnreadCount: unread}
+
+	if len(notifications) > defaultLimit {
+		notifications = notifications[:defaultLimit]
+
+		result.HasMore = true
+		result.NextStartFromId = notifications[defaultLimit-1].Id
+	}
+
+	result.Notifications = make([]NotificationItem, 0, len(notifications))
+
+	for _, n := range notifications {
+		// У событий без автора actor_id в ответе null.
+		var actorId *int
+
+		if n.ActorId != 0 {
+			actorId = &n.ActorId
+		}
+
+		result.Notifications = append(result.Notifications, NotificationItem{
+			Id:           n.Id,
+			Kind:         n.Kind,
+			ActorId:      actorId,
+			TeamId:       n.TeamId,
+			TaskId:       n.TaskId,
+			CommentId:    n.CommentId,
+			InvitationId: n.InvitationId,
+			Data:         n.Data,
+			CreatedAt:    n.CreatedAt,
+			ReadAt:       n.ReadAt,
+		})
+	}
+
+	return result, nil
+}
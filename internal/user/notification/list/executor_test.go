@@ -0,0 +1,172 @@
+package list
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/user/notification"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubNotificationLister struct {
+	notifications []notification.Model
+	unread        int
+	err           error
+	countErr      error
+
+	gotUserId      int
+	gotUnreadOnly  bool
+	gotStartFromId int
+	gotLimit       int
+}
+
+func (s *stubNotificationLister) ListUserNotifications(ctx context.Context, userId int, unreadOnly bool, startFromId int, limit int) ([]notification.Model, error) {
+	s.gotUserId = userId
+	s.gotUnreadOnly = unreadOnly
+	s.gotStartFromId = startFromId
+	s.gotLimit = limit
+
+	return s.notifications, s.err
+}
+
+func (s *stubNotificationLister) CountUnreadNotifications(ctx context.Context, userId int) (int, error) {
+	return s.unread, s.countErr
+}
+
+func makeNotification(id int) notification.Model {
+	taskId := 7
+
+	return notification.Model{
+		Id:        id,
+		UserId:    5,
+		Kind:      notification.TaskAssignedKind,
+		ActorId:   3,
+		TeamId:    2,
+		TaskId:    &taskId,
+		CreatedAt: time.Unix(int64(id), 0),
+	}
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	taskId := 7
+	actorId := 3
+	readAt := time.Unix(100, 0)
+
+	statusChanged := makeNotification(9)
+	statusChanged.Kind = notification.TaskStatusChangedKind
+	statusChanged.Data = map[string]string{"from": "todo", "to": "done"}
+	statusChanged.ReadAt = &readAt
+
+	overdue := makeNotification(10)
+	overdue.Kind = notification.TaskOverdueKind
+	overdue.ActorId = 0
+
+	full := make([]notification.Model, 0, defaultLimit+1)
+
+	for id := defaultLimit + 1; id >= 1; id-- {
+		full = append(full, makeNotification(id))
+	}
+
+	tests := []struct {
+		name        string
+		lister      *stubNotificationLister
+		in          ListInput
+		wantLen     int
+		wantFirst   *NotificationItem
+		wantUnread  int
+		wantNextId  int
+		wantHasMore bool
+		expectedErr string
+	}{
+		{
+			name:   "single notification",
+			lister: &stubNotificationLister{notifications: []notification.Model{statusChanged}, unread: 3},
+			in:     ListInput{UserId: 5},
+			wantFirst: &NotificationItem{
+				Id:        9,
+				Kind:      notification.TaskStatusChangedKind,
+				ActorId:   &actorId,
+				TeamId:    2,
+				TaskId:    &taskId,
+				Data:      map[string]string{"from": "todo", "to": "done"},
+				CreatedAt: time.Unix(9, 0),
+				ReadAt:    &readAt,
+			},
+			wantLen:    1,
+			wantUnread: 3,
+		},
+		{
+			name:   "event without actor",
+			lister: &stubNotificationLister{notifications: []notification.Model{overdue}},
+			in:     ListInput{UserId: 5},
+			wantFirst: &NotificationItem{
+				Id:        10,
+				Kind:      notification.TaskOverdueKind,
+				TeamId:    2,
+				TaskId:    &taskId,
+				CreatedAt: time.Unix(10, 0),
+			},
+			wantLen: 1,
+		},
+		{
+			name:    "only unread, empty",
+			lister:  &stubNotificationLister{notifications: []notification.Model{}},
+			in:      ListInput{UserId: 5, UnreadOnly: true, StartFromId: 3},
+			wantLen: 0,
+		},
+		{
+			name:        "has more",
+			lister:      &stubNotificationLister{notifications: full, unread: defaultLimit + 1},
+			in:          ListInput{UserId: 5},
+			wantLen:     defaultLimit,
+			wantUnread:  defaultLimit + 1,
+			wantNextId:  2,
+			wantHasMore: true,
+		},
+		{
+			name:        "db error",
+			lister:      &stubNotificationLister{err: errors.New("db error")},
+			in:          ListInput{UserId: 5},
+			expectedErr: "db error",
+		},
+		{
+			name:        "count error",
+			lister:      &stubNotificationLister{notifications: []notification.Model{}, countErr: errors.New("db error")},
+			in:          ListInput{UserId: 5},
+			expectedErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(tt.lister)
+
+			got, err := e.Execute(context.Background(), tt.in)
+
+			assert.Equal(t, tt.in.UserId, tt.lister.gotUserId)
+			assert.Equal(t, tt.in.UnreadOnly, tt.lister.gotUnreadOnly)
+			assert.Equal(t, tt.in.StartFromId, tt.lister.gotStartFromId)
+			assert.Equal(t, defaultLimit+1, tt.lister.gotLimit)
+
+			if tt.expectedErr != "" {
+				assert.Nil(t, got)
+				assert.EqualError(t, err, tt.expectedErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, got.Notifications, tt.wantLen)
+			assert.Equal(t, tt.wantUnread, got.UnreadCount)
+			assert.Equal(t, tt.wantNextId, got.NextStartFromId)
+			assert.Equal(t, tt.wantHasMore, got.HasMore)
+
+			if tt.wantFirst != nil {
+				assert.Equal(t, *tt.wantFirst, got.Notifications[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package list
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+type response struct {
+	Notifications   []NotificationItem `json:"notifications"`
+	UnreadCount     int                `json:"unread_count"`
+	NextStartFromId int                `json:"next_start_from_id,omitempty"`
+	HasMore         bool               `json:"has_more"`
+}
+
+type Executor interface {
+	Execute(ctx context.Context, in ListInput) (*ListResult, error)
+}
+
+type handler struct {
+	exec Executor
+}
+
+func NewHandler(exec Executor) *handler {
+	return &handler{
+		exec: exec,
+	}
+}
+
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	userIdAny := r.Context().Value("userId")
+
+	userId, ok := userIdAny.(int)
+
+	if !ok {
+		apperror.Write(w, apperror.Unauthorized("unauthorized"))
+
+		return
+	}
+
+	query := r.URL.Query()
+
+	startFromId := 0
+
+	if s := query.Get("start_from_id"); s != "" {
+		var err error
+
+		startFromId, err = strconv.Atoi(s)
+
+		if err != nil {
+			apperror.Write(w, apperror.Validation("invalid start_from_id"))
+
+			return
+		}
+	}
+
+	unreadOnly := false
+
+	if s := query.Get("unread"); s != "" {
+		var err error
+
+		unreadOnly, err = strconv.ParseBool(s)
+
+		if err != nil {
+			apperror.Write(w, apperror.Validation("invalid unread"))
+
+			return
+		}
+	}
+
+	result, err := h.exec.Execute(r.Context(), ListInput{
+		UserId:      userId,
+		UnreadOnly:  unreadOnly,
+		StartFromId: startFromId,
+	})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	respBody, err := json.Marshal(response{
+		Notifications:   result.Notifications,
+		UnreadCount:     result.UnreadCount,
+		NextStartFromId: result.NextStartFromId,
+		HasMore:         result.HasMore,
+	})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
@@ -0,0 +1,145 @@
+// Package notification — уведомления пользователя о событиях в его задачах и командах.
+package notification
+
+import (
+	"slices"
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/task"
+)
+
+type Kind string
+
+const (
+	TaskAssignedKind      Kind = "task_assigned"
+	TaskUnassignedKind    Kind = "task_unassigned"
+	TaskStatusChangedKind Kind = "task_status_changed"
+	TaskOverdueKind       Kind = "task_overdue"
+	CommentCreatedKind    Kind = "comment_created"
+	CommentMentionKind    Kind = "comment_mention"
+	TeamInvitationKind    Kind = "team_invitation"
+)
+
+var ErrNotFound = apperror.NotFound("notification not found")
+
+type Model struct {
+	Id int
+	// UserId — получатель уведомления.
+	UserId int
+	Kind   Kind
+	// ActorId — кто совершил действие; 0 — событие без автора, например просрочка.
+	ActorId      int
+	TeamId       int
+	TaskId       *int
+	CommentId    *int
+	InvitationId *int
+	// Data — подробности события, например прежний и новый статус.
+	Data      map[string]string
+	CreatedAt time.Time
+	// ReadAt — nil, пока уведомление не прочитано.
+	ReadAt *time.Time
+}
+
+func (m Model) IsRead() bool {
+	return m.ReadAt != nil
+}
+
+// Fanout размножает шаблон на получателей. Автор действия о нём не уведомляется, повторы отбрасываются.
+func Fanout(template Model, userIds []int) []Model {
+	var notifications []Model
+	var seen []int
+
+	for _, userId := range userIds {
+		if userId == template.ActorId || slices.Contains(seen, userId) {
+			continue
+		}
+
+		seen = append(seen, userId)
+
+		n := template
+		n.UserId = userId
+
+		notifications = append(notifications, n)
+	}
+
+	return notifications
+}
+
+// AssignmentChanges — уведомления тем, кого назначили на задачу или сняли с неё.
+func AssignmentChanges(before []int, after task.Model, actorId int, now time.Time) []Model {
+	var added, removed []int
+
+	for _, id := range after.AssigneeIds {
+		if !slices.Contains(before, id) {
+			added = append(added, id)
+		}
+	}
+
+	for _, id := range before {
+		if !after.IsAssignee(id) {
+			removed = append(removed, id)
+		}
+	}
+
+	template := Model{ActorId: actorId, TeamId: after.TeamId, TaskId: &after.Id, CreatedAt: now}
+
+	template.Kind = TaskAssignedKind
+	notifications := Fanout(template, added)
+
+	template.Kind = TaskUnassignedKind
+	notifications = append(notifications, Fanout(template, removed)...)
+
+	return notifications
+}
+
+// StatusChange — уведомления наблюдателям о смене статуса задачи.
+func StatusChange(from string, t task.Model, watcherIds []int, actorId int, now time.Time) []Model {
+	return Fanout(Model{
+		Kind:      TaskStatusChangedKind,
+		ActorId:   actorId,
+		TeamId:    t.TeamId,
+		TaskId:    &t.Id,
+		Data:      map[string]string{"from": from, "to": t.Status},
+		CreatedAt: now,
+	}, watcherIds)
+}
+
+// Overdue — уведомления исполнителям и наблюдателям о том, что срок задачи прошёл.
+func Overdue(t task.Model, watcherIds []int, now time.Time) []Model {
+	var data map[string]string
+
+	if t.DueAt != nil {
+		data = map[string]string{"due_at": t.DueAt.UTC().Format(time.RFC3339)}
+	}
+
+	return Fanout(Model{
+		Kind:      TaskOverdueKind,
+		TeamId:    t.TeamId,
+		TaskId:    &t.Id,
+		Data:      data,
+		CreatedAt: now,
+	}, append(slices.Clone(t.AssigneeIds), watcherIds...))
+}
+
+// NewComment — уведомления о комментарии: упомянутым — comment_mention,
+// остальным наблюдателям и исполнителям задачи — comment_created.
+func NewComment(t task.Model, commentId int, watcherIds []int, mentionIds []int, actorId int, now time.Time) []Model {
+	template := Model{ActorId: actorId, TeamId: t.TeamId, TaskId: &t.Id, CommentId: &commentId, CreatedAt: now}
+
+	template.Kind = CommentMentionKind
+	notifications := Fanout(template, mentionIds)
+
+	var followers []int
+
+	for _, id := range append(slices.Clone(watcherIds), t.AssigneeIds...) {
+		if !slices.Contains(mentionIds, id) {
+			followers = append(followers, id)
+		}
+	}
+
+	template.Kind = CommentCreatedKind
+	notifications = append(notifications, Fanout(template, followers)...)
+
+	return notifications
+}
@@ -0,0 +1,111 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/task"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recipient struct {
+	UserId int
+	Kind   Kind
+}
+
+func recipients(notifications []Model) []recipient {
+	var got []recipient
+
+	for _, n := range notifications {
+		got = append(got, recipient{UserId: n.UserId, Kind: n.Kind})
+	}
+
+	return got
+}
+
+func TestFanout(t *testing.T) {
+	got := Fanout(Model{Kind: TaskAssignedKind, ActorId: 1, TeamId: 10}, []int{3, 1, 2, 3})
+
+	assert.Equal(t, []recipient{{UserId: 3, Kind: TaskAssignedKind}, {UserId: 2, Kind: TaskAssignedKind}}, recipients(got))
+	assert.Equal(t, 10, got[0].TeamId)
+	assert.Empty(t, Fanout(Model{ActorId: 1}, []int{1}))
+}
+
+func TestAssignmentChanges(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		before []int
+		after  []int
+		want   []recipient
+	}{
+		{
+			name:   "added and removed",
+			before: []int{2, 3},
+			after:  []int{3, 4},
+			want:   []recipient{{UserId: 4, Kind: TaskAssignedKind}, {UserId: 2, Kind: TaskUnassignedKind}},
+		},
+		{
+			name:   "actor assigns themselves",
+			before: nil,
+			after:  []int{1, 5},
+			want:   []recipient{{UserId: 5, Kind: TaskAssignedKind}},
+		},
+		{
+			name:   "nothing changed",
+			before: []int{2},
+			after:  []int{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AssignmentChanges(tt.before, task.Model{Id: 7, TeamId: 10, AssigneeIds: tt.after}, 1, now)
+
+			assert.Equal(t, tt.want, recipients(got))
+
+			for _, n := range got {
+				assert.Equal(t, 7, *n.TaskId)
+				assert.Equal(t, now, n.CreatedAt)
+			}
+		})
+	}
+}
+
+func TestStatusChange(t *testing.T) {
+	got := StatusChange("todo", task.Model{Id: 7, TeamId: 10, Status: "done"}, []int{1, 2}, 1, time.Now())
+
+	assert.Equal(t, []recipient{{UserId: 2, Kind: TaskStatusChangedKind}}, recipients(got))
+	assert.Equal(t, map[string]string{"from": "todo", "to": "done"}, got[0].Data)
+}
+
+func TestOverdue(t *testing.T) {
+	dueAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	tk := task.Model{Id: 7, TeamId: 10, AssigneeIds: []int{2, 3}, DueAt: &dueAt}
+
+	got := Overdue(tk, []int{3, 4}, time.Now())
+
+	assert.Equal(t, []recipient{
+		{UserId: 2, Kind: TaskOverdueKind},
+		{UserId: 3, Kind: TaskOverdueKind},
+		{UserId: 4, Kind: TaskOverdueKind},
+	}, recipients(got))
+	assert.Zero(t, got[0].ActorId)
+	assert.Equal(t, map[string]string{"due_at": "2026-10-01T09:00:00Z"}, got[0].Data)
+}
+
+func TestNewComment(t *testing.T) {
+	tk := task.Model{Id: 7, TeamId: 10, AssigneeIds: []int{2, 3}}
+
+	got := NewComment(tk, 40, []int{1, 3, 4}, []int{4, 5}, 1, time.Now())
+
+	assert.Equal(t, []recipient{
+		{UserId: 4, Kind: CommentMentionKind},
+		{UserId: 5, Kind: CommentMentionKind},
+		{UserId: 3, Kind: CommentCreatedKind},
+		{UserId: 2, Kind: CommentCreatedKind},
+	}, recipients(got))
+	assert.Equal(t, 40, *got[0].CommentId)
+}
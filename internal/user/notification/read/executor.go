@@ -0,0 +1,40 @@
+package read
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"mkk-luna-test-task/internal/user/notification"
+)
+
+type notificationMarker interface {
+	MarkNotificationRead(ctx context.Context, userId, id int, readAt time.Time) error
+}
+
+type executor struct {
+	notificationMarker notificationMarker
+}
+
+func NewExecutor(notificationMarker notificationMarker) *executor {
+	return &executor{
+		notificationMarker: notificationMarker,
+	}
+}
+
+type ReadInput struct {
+	UserId         int
+	NotificationId int
+}
+
+// Execute отмечает уведомление прочитанным; чужое уведомление неотличимо от несуществующего.
+func (e *executor) Execute(ctx context.Context, in ReadInput) error {
+	err := e.notificationMarker.MarkNotificationRead(ctx, in.UserId, in.NotificationId, time.Now())
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return notification.ErrNotFound
+	}
+
+	return err
+}
@@ -0,0 +1,67 @@
+package read
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockNotificationMarker struct {
+	err error
+
+	gotUserId int
+	gotId     int
+}
+
+func (m *mockNotificationMarker) MarkNotificationRead(ctx context.Context, userId, id int, readAt time.Time) error {
+	m.gotUserId = userId
+	m.gotId = id
+
+	return m.err
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		marker      *mockNotificationMarker
+		expectedErr string
+	}{
+		{
+			name:   "success",
+			marker: &mockNotificationMarker{},
+		},
+		{
+			name:        "not found or not own",
+			marker:      &mockNotificationMarker{err: sql.ErrNoRows},
+			expectedErr: "notification not found",
+		},
+		{
+			name:        "db error",
+			marker:      &mockNotificationMarker{err: errors.New("db error")},
+			expectedErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(tt.marker)
+
+			err := e.Execute(context.Background(), ReadInput{UserId: 5, NotificationId: 9})
+
+			assert.Equal(t, 5, tt.marker.gotUserId)
+			assert.Equal(t, 9, tt.marker.gotId)
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
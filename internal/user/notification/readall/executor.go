@@ -0,0 +1,28 @@
+package readall
+
+import (
+	"context"
+	"time"
+)
+
+type notificationMarker interface {
+	MarkAllNotificationsRead(ctx context.Context, userId int, readAt time.Time) error
+}
+
+type executor struct {
+	notificationMarker notificationMarker
+}
+
+func NewExecutor(notificationMarker notificationMarker) *executor {
+	return &executor{
+		notificationMarker: notificationMarker,
+	}
+}
+
+type ReadAllInput struct {
+	UserId int
+}
+
+func (e *executor) Execute(ctx context.Context, in ReadAllInput) error {
+	return e.notificationMarker.MarkAllNotificationsRead(ctx, in.UserId, time.Now())
+}
@@ -0,0 +1,57 @@
+package readall
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockNotificationMarker struct {
+	err       error
+	gotUserId int
+}
+
+func (m *mockNotificationMarker) MarkAllNotificationsRead(ctx context.Context, userId int, readAt time.Time) error {
+	m.gotUserId = userId
+
+	return m.err
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		marker      *mockNotificationMarker
+		expectedErr string
+	}{
+		{
+			name:   "success",
+			marker: &mockNotificationMarker{},
+		},
+		{
+			name:        "db error",
+			marker:      &mockNotificationMarker{err: errors.New("db error")},
+			expectedErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(tt.marker)
+
+			err := e.Execute(context.Background(), ReadAllInput{UserId: 5})
+
+			assert.Equal(t, 5, tt.marker.gotUserId)
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
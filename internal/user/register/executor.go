@@ -4,9 +4,10 @@ import (
 	"context"
 	"fmt"
 
-	"mkk-luna-test-task/internal/user"
-
 	"golang.org/x/crypto/bcrypt"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/user"
 )
 
 type userRegisterer interface {
@@ -32,7 +33,7 @@ type RegisterResult struct {
 
 func (e *executor) Execute(ctx context.Context, in RegisterInput) (*RegisterResult, error) {
 	if in.Username == "" || in.Password == "" {
-		return nil, fmt.Errorf("username and password required")
+		return nil, apperror.Validation("username and password required")
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(in.Password), bcrypt.DefaultCost)
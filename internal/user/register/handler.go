@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+
+	"mkk-luna-test-task/internal/apperror"
 )
 
 type request struct {
@@ -33,7 +35,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
 	if err != nil {
-		http.Error(w, "could not read request body", http.StatusBadRequest)
+		apperror.Write(w, apperror.Validation("could not read request body"))
 
 		return
 	}
@@ -41,7 +43,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	var req request
 
 	if err := json.Unmarshal(body, &req); err != nil {
-		http.Error(w, "invalid json", http.StatusBadRequest)
+		apperror.Write(w, apperror.Validation("invalid json"))
 
 		return
 	}
@@ -52,7 +54,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	})
 
 	if err != nil {
-		http.Error(w, "error", http.StatusBadRequest)
+		apperror.Write(w, err)
 
 		return
 	}
@@ -62,7 +64,7 @@ func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
 	respBytes, err := json.Marshal(resp)
 
 	if err != nil {
-		http.Error(w, "could not marshal response", http.StatusInternalServerError)
+		apperror.Write(w, err)
 
 		return
 	}
@@ -0,0 +1,269 @@
+package session
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	privateKeySuffix = ".pem"
+	publicKeySuffix  = ".pub.pem"
+)
+
+// ErrNoKeys — в каталоге нет ни одного файла ключа; прочие файлы и подкаталоги не в счёт.
+var ErrNoKeys = errors.New("no jwt key files found")
+
+type Key struct {
+	Id         string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.PrivateKey
+	PublicKey  crypto.PublicKey
+}
+
+func NewKey(id string, privateKey crypto.PrivateKey) (*Key, error) {
+	switch k := privateKey.(type) {
+	case *rsa.PrivateKey:
+		return &Key{Id: id, Method: jwt.SigningMethodRS256, PrivateKey: k, PublicKey: &k.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return &Key{Id: id, Method: jwt.SigningMethodEdDSA, PrivateKey: k, PublicKey: k.Public()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T for key %s", privateKey, id)
+	}
+}
+
+func NewPublicKey(id string, publicKey crypto.PublicKey) (*Key, error) {
+	switch k := publicKey.(type) {
+	case *rsa.PublicKey:
+		return &Key{Id: id, Method: jwt.SigningMethodRS256, PublicKey: k}, nil
+	case ed25519.PublicKey:
+		return &Key{Id: id, Method: jwt.SigningMethodEdDSA, PublicKey: k}, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T for key %s", publicKey, id)
+	}
+}
+
+// Ключи, у которых есть только публичная часть, больше не подписывают,
+// но токены, выпущенные ими до ротации, продолжают проверяться.
+type KeySet struct {
+	active *Key
+	keys   map[string]*Key
+	ids    []string
+}
+
+func NewKeySet(activeKeyId string, keys ...*Key) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*Key, len(keys))}
+
+	for _, k := range keys {
+		if _, exists := ks.keys[k.Id]; exists {
+			return nil, fmt.Errorf("duplicate key id %s", k.Id)
+		}
+
+		ks.keys[k.Id] = k
+		ks.ids = append(ks.ids, k.Id)
+	}
+
+	sort.Strings(ks.ids)
+
+	// Если активный ключ явно не задан, подписываем последним по имени — удобно называть ключи датой.
+	if activeKeyId == "" {
+		for i := len(ks.ids) - 1; i >= 0; i-- {
+			if ks.keys[ks.ids[i]].PrivateKey != nil {
+				activeKeyId = ks.ids[i]
+
+				break
+			}
+		}
+	}
+
+	active, ok := ks.keys[activeKeyId]
+
+	if !ok || active.PrivateKey == nil {
+		return nil, fmt.Errorf("no private signing key %q found", activeKeyId)
+	}
+
+	ks.active = active
+
+	return ks, nil
+}
+
+func LoadKeySet(dir, activeKeyId string) (*KeySet, error) {
+	entries, err := os.ReadDir(dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*Key, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+
+		var key *Key
+
+		switch {
+		case strings.HasSuffix(name, publicKeySuffix):
+			key, err = loadPublicKey(filepath.Join(dir, name), strings.TrimSuffix(name, publicKeySuffix))
+		case strings.HasSuffix(name, privateKeySuffix):
+			key, err = loadPrivateKey(filepath.Join(dir, name), strings.TrimSuffix(name, privateKeySuffix))
+		default:
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w in %q", ErrNoKeys, dir)
+	}
+
+	return NewKeySet(activeKeyId, keys...)
+}
+
+// LoadOrGenerateKeySet загружает ключи из dir, а если ключей там нет (или нет самого каталога),
+// создаёт Ed25519-ключ newKeyId. generated сообщает, что ключ был создан.
+func LoadOrGenerateKeySet(dir, activeKeyId, newKeyId string) (ks *KeySet, generated bool, err error) {
+	ks, err = LoadKeySet(dir, activeKeyId)
+
+	if err == nil || (!errors.Is(err, ErrNoKeys) && !errors.Is(err, os.ErrNotExist)) {
+		return ks, false, err
+	}
+
+	// Явно заданный активный ключ молча подменять сгенерированным нельзя: это почти наверняка
+	// ошибка в JWT_KEYS_DIR, и токены, выпущенные тем ключом, перестанут проверяться.
+	if activeKeyId != "" && activeKeyId != newKeyId {
+		return nil, false, fmt.Errorf("signing key %q is configured, but %q has no key files", activeKeyId, dir)
+	}
+
+	if err := GenerateKeyFile(dir, newKeyId); err != nil {
+		return nil, false, err
+	}
+
+	ks, err = LoadKeySet(dir, activeKeyId)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return ks, true, nil
+}
+
+func GenerateKeyFile(dir, id string) error {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+
+	if err != nil {
+		return err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	return os.WriteFile(filepath.Join(dir, id+privateKeySuffix), data, 0o600)
+}
+
+func (ks *KeySet) SigningKey() *Key {
+	return ks.active
+}
+
+func (ks *KeySet) VerificationKey(id string) (jwt.SigningMethod, crypto.PublicKey, error) {
+	k, ok := ks.keys[id]
+
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown key id %q", id)
+	}
+
+	return k.Method, k.PublicKey, nil
+}
+
+func (ks *KeySet) Keys() []*Key {
+	keys := make([]*Key, 0, len(ks.ids))
+
+	for _, id := range ks.ids {
+		keys = append(keys, ks.keys[id])
+	}
+
+	return keys
+}
+
+func loadPrivateKey(path, id string) (*Key, error) {
+	block, err := readPEM(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var privateKey crypto.PrivateKey
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key %s: %w", path, err)
+	}
+
+	return NewKey(id, privateKey)
+}
+
+func loadPublicKey(path, id string) (*Key, error) {
+	block, err := readPEM(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key %s: %w", path, err)
+	}
+
+	return NewPublicKey(id, publicKey)
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data in %s", path)
+	}
+
+	return block, nil
+}
@@ -0,0 +1,217 @@
+package session
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeRSAPublicKey(t *testing.T, dir, id string) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	assert.NoError(t, err)
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, id+publicKeySuffix), data, 0o600))
+}
+
+func writeRSAPrivateKey(t *testing.T, dir, id string) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, id+privateKeySuffix), data, 0o600))
+}
+
+func TestLoadKeySet(t *testing.T) {
+	tests := []struct {
+		name         string
+		prepare      func(t *testing.T, dir string)
+		activeKeyId  string
+		wantErr      bool
+		wantActive   string
+		wantMethod   jwt.SigningMethod
+		wantKeyCount int
+	}{
+		{
+			name: "latest private key is active by default",
+			prepare: func(t *testing.T, dir string) {
+				writeRSAPrivateKey(t, dir, "2024-01")
+				assert.NoError(t, GenerateKeyFile(dir, "2024-02"))
+				writeRSAPublicKey(t, dir, "2024-03")
+			},
+			wantActive:   "2024-02",
+			wantMethod:   jwt.SigningMethodEdDSA,
+			wantKeyCount: 3,
+		},
+		{
+			name: "explicit active key",
+			prepare: func(t *testing.T, dir string) {
+				writeRSAPrivateKey(t, dir, "2024-01")
+				assert.NoError(t, GenerateKeyFile(dir, "2024-02"))
+			},
+			activeKeyId:  "2024-01",
+			wantActive:   "2024-01",
+			wantMethod:   jwt.SigningMethodRS256,
+			wantKeyCount: 2,
+		},
+		{
+			name: "active key without private part",
+			prepare: func(t *testing.T, dir string) {
+				assert.NoError(t, GenerateKeyFile(dir, "2024-01"))
+				writeRSAPublicKey(t, dir, "2024-02")
+			},
+			activeKeyId: "2024-02",
+			wantErr:     true,
+		},
+		{
+			name: "only public keys",
+			prepare: func(t *testing.T, dir string) {
+				writeRSAPublicKey(t, dir, "2024-01")
+			},
+			wantErr: true,
+		},
+		{
+			name: "garbage in key file",
+			prepare: func(t *testing.T, dir string) {
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, "bad.pem"), []byte("not a key"), 0o600))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			tt.prepare(t, dir)
+
+			got, err := LoadKeySet(dir, tt.activeKeyId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantActive, got.SigningKey().Id)
+			assert.Equal(t, tt.wantMethod, got.SigningKey().Method)
+			assert.Len(t, got.Keys(), tt.wantKeyCount)
+
+			for _, k := range got.Keys() {
+				method, publicKey, err := got.VerificationKey(k.Id)
+
+				assert.NoError(t, err)
+				assert.Equal(t, k.Method, method)
+				assert.NotNil(t, publicKey)
+			}
+
+			_, _, err = got.VerificationKey("missing")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadKeySet_NoKeyFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ".gitkeep"), nil, 0o600))
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "old"), 0o700))
+
+	got, err := LoadKeySet(dir, "")
+
+	assert.ErrorIs(t, err, ErrNoKeys)
+	assert.Nil(t, got)
+}
+
+func TestLoadOrGenerateKeySet(t *testing.T) {
+	tests := []struct {
+		name          string
+		prepare       func(t *testing.T, dir string)
+		activeKeyId   string
+		wantErr       bool
+		wantGenerated bool
+		wantActive    string
+	}{
+		{
+			name:          "missing dir",
+			prepare:       func(t *testing.T, dir string) { assert.NoError(t, os.Remove(dir)) },
+			wantGenerated: true,
+			wantActive:    "new",
+		},
+		{
+			name: "only non-key files",
+			prepare: func(t *testing.T, dir string) {
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, ".gitkeep"), nil, 0o600))
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("keys"), 0o600))
+				assert.NoError(t, os.Mkdir(filepath.Join(dir, "old"), 0o700))
+			},
+			wantGenerated: true,
+			wantActive:    "new",
+		},
+		{
+			name: "existing keys are loaded",
+			prepare: func(t *testing.T, dir string) {
+				assert.NoError(t, GenerateKeyFile(dir, "2024-01"))
+			},
+			wantActive: "2024-01",
+		},
+		{
+			name:        "configured key is not replaced by a generated one",
+			prepare:     func(t *testing.T, dir string) {},
+			activeKeyId: "2024-01",
+			wantErr:     true,
+		},
+		{
+			name:          "configured key with the generated id",
+			prepare:       func(t *testing.T, dir string) {},
+			activeKeyId:   "new",
+			wantGenerated: true,
+			wantActive:    "new",
+		},
+		{
+			name: "broken key file is not replaced",
+			prepare: func(t *testing.T, dir string) {
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, "bad.pem"), []byte("not a key"), 0o600))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "keys")
+
+			assert.NoError(t, os.Mkdir(dir, 0o700))
+
+			tt.prepare(t, dir)
+
+			got, generated, err := LoadOrGenerateKeySet(dir, tt.activeKeyId, "new")
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				assert.NoFileExists(t, filepath.Join(dir, "new"+privateKeySuffix))
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantGenerated, generated)
+			assert.Equal(t, tt.wantActive, got.SigningKey().Id)
+		})
+	}
+}
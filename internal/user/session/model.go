@@ -0,0 +1,29 @@
+package session
+
+import (
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+var ErrRefreshTokenReused = apperror.Unauthorized("refresh token reused")
+
+type Model struct {
+	Id            string
+	UserId        int
+	AccessTokenId string
+	CreatedAt     time.Time
+	ExpiresAt     time.Time
+	RevokedAt     *time.Time
+}
+
+func (m *Model) IsActive(now time.Time) bool {
+	return m.RevokedAt == nil && now.Before(m.ExpiresAt)
+}
+
+type RefreshToken struct {
+	Hash      string
+	SessionId string
+	CreatedAt time.Time
+	UsedAt    *time.Time
+}
@@ -0,0 +1,87 @@
+package session
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"mkk-luna-test-task/internal/user"
+
+	"github.com/golang-jwt/jwt"
+)
+
+type signingKeyProvider interface {
+	SigningKey() *Key
+}
+
+type JwtIssuer struct {
+	keys         signingKeyProvider
+	accessExpiry time.Duration
+}
+
+func NewJwtIssuer(keys signingKeyProvider, accessExpiry time.Duration) *JwtIssuer {
+	return &JwtIssuer{
+		keys:         keys,
+		accessExpiry: accessExpiry,
+	}
+}
+
+func (i *JwtIssuer) IssueAccessToken(u user.Model, sessionId, tokenId string, now time.Time) (string, error) {
+	claims := jwt.MapClaims{
+		"id":   u.Id,
+		"name": u.Username,
+		"sid":  sessionId,
+		"jti":  tokenId,
+		"iat":  now.Unix(),
+		"exp":  now.Add(i.accessExpiry).Unix(),
+	}
+
+	key := i.keys.SigningKey()
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.Id
+
+	tokenString, err := token.SignedString(key.PrivateKey)
+
+	if err != nil {
+		return "", fmt.Errorf("could not create token: %w", err)
+	}
+
+	return tokenString, nil
+}
+
+func (i *JwtIssuer) AccessExpiry() time.Duration {
+	return i.accessExpiry
+}
+
+func NewId() (string, error) {
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// В базе храним только хэш, чтобы утечка таблицы не давала готовых refresh-токенов.
+func NewRefreshToken() (token string, hash string, err error) {
+	b := make([]byte, 32)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(b)
+
+	return token, HashRefreshToken(token), nil
+}
+
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,171 @@
+package refresh
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/user"
+	"mkk-luna-test-task/internal/user/session"
+)
+
+type refreshTokenGetter interface {
+	GetRefreshToken(ctx context.Context, hash string) (*session.RefreshToken, error)
+}
+
+type sessionGetter interface {
+	GetSession(ctx context.Context, id string) (*session.Model, error)
+}
+
+type refreshTokenRotator interface {
+	RotateRefreshToken(ctx context.Context, sessionId string, oldHash, newHash string, accessTokenId string, now time.Time) error
+}
+
+type sessionRevoker interface {
+	RevokeSession(ctx context.Context, sessionId string, revokedAt time.Time) error
+}
+
+type userGetter interface {
+	GetUserById(ctx context.Context, id int) (*user.Model, error)
+}
+
+type accessTokenIssuer interface {
+	IssueAccessToken(u user.Model, sessionId, tokenId string, now time.Time) (string, error)
+	AccessExpiry() time.Duration
+}
+
+type executor struct {
+	refreshTokenGetter  refreshTokenGetter
+	sessionGetter       sessionGetter
+	refreshTokenRotator refreshTokenRotator
+	sessionRevoker      sessionRevoker
+	userGetter          userGetter
+	tokenIssuer         accessTokenIssuer
+}
+
+func NewExecutor(
+	refreshTokenGetter refreshTokenGetter,
+	sessionGetter sessionGetter,
+	refreshTokenRotator refreshTokenRotator,
+	sessionRevoker sessionRevoker,
+	userGetter userGetter,
+	tokenIssuer accessTokenIssuer,
+) *executor {
+	return &executor{
+		refreshTokenGetter:  refreshTokenGetter,
+		sessionGetter:       sessionGetter,
+		refreshTokenRotator: refreshTokenRotator,
+		sessionRevoker:      sessionRevoker,
+		userGetter:          userGetter,
+		tokenIssuer:         tokenIssuer,
+	}
+}
+
+type RefreshInput struct {
+	RefreshToken string
+}
+
+type RefreshResult struct {
+	Token        string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
+
+func (e *executor) Execute(ctx context.Context, in RefreshInput) (*RefreshResult, error) {
+	if in.RefreshToken == "" {
+		return nil, apperror.Validation("refresh token required")
+	}
+
+	now := time.Now()
+
+	oldHash := session.HashRefreshToken(in.RefreshToken)
+
+	stored, err := e.refreshTokenGetter.GetRefreshToken(ctx, oldHash)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.Unauthorized("invalid refresh token")
+		}
+
+		return nil, err
+	}
+
+	s, err := e.sessionGetter.GetSession(ctx, stored.SessionId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.Unauthorized("invalid refresh token")
+		}
+
+		return nil, err
+	}
+
+	if !s.IsActive(now) {
+		return nil, apperror.Unauthorized("invalid refresh token")
+	}
+
+	// Уже ротированный токен предъявили повторно: либо его украли, либо украли следующий.
+	// Не разбираемся, кто есть кто, и гасим всю сессию.
+	if stored.UsedAt != nil {
+		return nil, e.revokeOnReuse(ctx, s.Id, now)
+	}
+
+	u, err := e.userGetter.GetUserById(ctx, s.UserId)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.Unauthorized("invalid refresh token")
+		}
+
+		return nil, err
+	}
+
+	tokenId, err := session.NewId()
+
+	if err != nil {
+		return nil, err
+	}
+
+	newRefreshToken, newHash, err := session.NewRefreshToken()
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = e.refreshTokenRotator.RotateRefreshToken(ctx, s.Id, oldHash, newHash, tokenId, now)
+
+	if err != nil {
+		if errors.Is(err, session.ErrRefreshTokenReused) {
+			return nil, e.revokeOnReuse(ctx, s.Id, now)
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.Unauthorized("invalid refresh token")
+		}
+
+		return nil, err
+	}
+
+	accessToken, err := e.tokenIssuer.IssueAccessToken(*u, s.Id, tokenId, now)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &RefreshResult{
+		Token:        accessToken,
+		RefreshToken: newRefreshToken,
+		ExpiresIn:    e.tokenIssuer.AccessExpiry(),
+	}, nil
+}
+
+func (e *executor) revokeOnReuse(ctx context.Context, sessionId string, now time.Time) error {
+	if err := e.sessionRevoker.RevokeSession(ctx, sessionId, now); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return apperror.Unauthorized("refresh token reuse detected")
+}
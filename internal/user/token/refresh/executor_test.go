@@ -0,0 +1,293 @@
+package refresh
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"mkk-luna-test-task/internal/apperror"
+	"mkk-luna-test-task/internal/user"
+	"mkk-luna-test-task/internal/user/session"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const validRefreshToken = "refresh-token"
+
+type stubRefreshTokenGetter struct {
+	token *session.RefreshToken
+	err   error
+}
+
+func (s *stubRefreshTokenGetter) GetRefreshToken(ctx context.Context, hash string) (*session.RefreshToken, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	if hash != session.HashRefreshToken(validRefreshToken) {
+		return nil, sql.ErrNoRows
+	}
+
+	return s.token, nil
+}
+
+type stubSessionGetter struct {
+	session *session.Model
+	err     error
+}
+
+func (s *stubSessionGetter) GetSession(ctx context.Context, id string) (*session.Model, error) {
+	return s.session, s.err
+}
+
+type mockRefreshTokenRotator struct {
+	err     error
+	called  bool
+	newHash string
+}
+
+func (m *mockRefreshTokenRotator) RotateRefreshToken(ctx context.Context, sessionId string, oldHash, newHash string, accessTokenId string, now time.Time) error {
+	m.called = true
+	m.newHash = newHash
+
+	return m.err
+}
+
+type mockSessionRevoker struct {
+	revoked string
+}
+
+func (m *mockSessionRevoker) RevokeSession(ctx context.Context, sessionId string, revokedAt time.Time) error {
+	m.revoked = sessionId
+
+	return nil
+}
+
+type stubUserGetter struct {
+	err error
+}
+
+func (s *stubUserGetter) GetUserById(ctx context.Context, id int) (*user.Model, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &user.Model{Id: id, Username: "luna"}, nil
+}
+
+func activeSession() *session.Model {
+	return &session.Model{
+		Id:        "sid",
+		UserId:    5,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+func testKeySet() *session.KeySet {
+	_, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	key, _ := session.NewKey("key-1", privateKey)
+	keys, _ := session.NewKeySet("", key)
+
+	return keys
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	keys := testKeySet()
+
+	usedAt := time.Now().Add(-time.Minute)
+	revokedAt := time.Now().Add(-time.Minute)
+
+	type fields struct {
+		refreshTokenGetter  *stubRefreshTokenGetter
+		sessionGetter       *stubSessionGetter
+		refreshTokenRotator *mockRefreshTokenRotator
+		userGetter          *stubUserGetter
+	}
+	tests := []struct {
+		name             string
+		fields           fields
+		in               RefreshInput
+		wantErr          bool
+		expectedErr      error
+		wantUnauthorized bool
+		expectRevoked    bool
+	}{
+		{
+			name: "success - rotates token",
+			fields: fields{
+				refreshTokenGetter:  &stubRefreshTokenGetter{token: &session.RefreshToken{SessionId: "sid"}},
+				sessionGetter:       &stubSessionGetter{session: activeSession()},
+				refreshTokenRotator: &mockRefreshTokenRotator{},
+			},
+			in:      RefreshInput{RefreshToken: validRefreshToken},
+			wantErr: false,
+		},
+		{
+			name: "empty token",
+			fields: fields{
+				refreshTokenGetter:  &stubRefreshTokenGetter{},
+				sessionGetter:       &stubSessionGetter{},
+				refreshTokenRotator: &mockRefreshTokenRotator{},
+			},
+			in:          RefreshInput{},
+			wantErr:     true,
+			expectedErr: errors.New("refresh token required"),
+		},
+		{
+			name: "unknown token",
+			fields: fields{
+				refreshTokenGetter:  &stubRefreshTokenGetter{},
+				sessionGetter:       &stubSessionGetter{session: activeSession()},
+				refreshTokenRotator: &mockRefreshTokenRotator{},
+			},
+			in:               RefreshInput{RefreshToken: "other"},
+			wantErr:          true,
+			expectedErr:      errors.New("invalid refresh token"),
+			wantUnauthorized: true,
+		},
+		{
+			name: "token getter error",
+			fields: fields{
+				refreshTokenGetter:  &stubRefreshTokenGetter{err: errors.New("db error")},
+				sessionGetter:       &stubSessionGetter{session: activeSession()},
+				refreshTokenRotator: &mockRefreshTokenRotator{},
+			},
+			in:          RefreshInput{RefreshToken: validRefreshToken},
+			wantErr:     true,
+			expectedErr: errors.New("db error"),
+		},
+		{
+			name: "revoked session",
+			fields: fields{
+				refreshTokenGetter: &stubRefreshTokenGetter{token: &session.RefreshToken{SessionId: "sid"}},
+				sessionGetter: &stubSessionGetter{session: &session.Model{
+					Id:        "sid",
+					ExpiresAt: time.Now().Add(time.Hour),
+					RevokedAt: &revokedAt,
+				}},
+				refreshTokenRotator: &mockRefreshTokenRotator{},
+			},
+			in:               RefreshInput{RefreshToken: validRefreshToken},
+			wantErr:          true,
+			expectedErr:      errors.New("invalid refresh token"),
+			wantUnauthorized: true,
+		},
+		{
+			name: "expired session",
+			fields: fields{
+				refreshTokenGetter: &stubRefreshTokenGetter{token: &session.RefreshToken{SessionId: "sid"}},
+				sessionGetter: &stubSessionGetter{session: &session.Model{
+					Id:        "sid",
+					ExpiresAt: time.Now().Add(-time.Hour),
+				}},
+				refreshTokenRotator: &mockRefreshTokenRotator{},
+			},
+			in:               RefreshInput{RefreshToken: validRefreshToken},
+			wantErr:          true,
+			expectedErr:      errors.New("invalid refresh token"),
+			wantUnauthorized: true,
+		},
+		{
+			name: "user deleted",
+			fields: fields{
+				refreshTokenGetter:  &stubRefreshTokenGetter{token: &session.RefreshToken{SessionId: "sid"}},
+				sessionGetter:       &stubSessionGetter{session: activeSession()},
+				refreshTokenRotator: &mockRefreshTokenRotator{},
+				userGetter:          &stubUserGetter{err: sql.ErrNoRows},
+			},
+			in:               RefreshInput{RefreshToken: validRefreshToken},
+			wantErr:          true,
+			expectedErr:      errors.New("invalid refresh token"),
+			wantUnauthorized: true,
+		},
+		{
+			name: "user getter error is not unauthorized",
+			fields: fields{
+				refreshTokenGetter:  &stubRefreshTokenGetter{token: &session.RefreshToken{SessionId: "sid"}},
+				sessionGetter:       &stubSessionGetter{session: activeSession()},
+				refreshTokenRotator: &mockRefreshTokenRotator{},
+				userGetter:          &stubUserGetter{err: errors.New("db error")},
+			},
+			in:          RefreshInput{RefreshToken: validRefreshToken},
+			wantErr:     true,
+			expectedErr: errors.New("db error"),
+		},
+		{
+			name: "reused token revokes session",
+			fields: fields{
+				refreshTokenGetter:  &stubRefreshTokenGetter{token: &session.RefreshToken{SessionId: "sid", UsedAt: &usedAt}},
+				sessionGetter:       &stubSessionGetter{session: activeSession()},
+				refreshTokenRotator: &mockRefreshTokenRotator{},
+			},
+			in:               RefreshInput{RefreshToken: validRefreshToken},
+			wantErr:          true,
+			expectedErr:      errors.New("refresh token reuse detected"),
+			wantUnauthorized: true,
+			expectRevoked:    true,
+		},
+		{
+			name: "concurrent reuse detected on rotation",
+			fields: fields{
+				refreshTokenGetter:  &stubRefreshTokenGetter{token: &session.RefreshToken{SessionId: "sid"}},
+				sessionGetter:       &stubSessionGetter{session: activeSession()},
+				refreshTokenRotator: &mockRefreshTokenRotator{err: session.ErrRefreshTokenReused},
+			},
+			in:               RefreshInput{RefreshToken: validRefreshToken},
+			wantErr:          true,
+			expectedErr:      errors.New("refresh token reuse detected"),
+			wantUnauthorized: true,
+			expectRevoked:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revoker := &mockSessionRevoker{}
+
+			userGetter := tt.fields.userGetter
+
+			if userGetter == nil {
+				userGetter = &stubUserGetter{}
+			}
+
+			e := &executor{
+				refreshTokenGetter:  tt.fields.refreshTokenGetter,
+				sessionGetter:       tt.fields.sessionGetter,
+				refreshTokenRotator: tt.fields.refreshTokenRotator,
+				sessionRevoker:      revoker,
+				userGetter:          userGetter,
+				tokenIssuer:         session.NewJwtIssuer(keys, time.Minute),
+			}
+
+			got, err := e.Execute(context.Background(), tt.in)
+
+			if tt.wantErr {
+				assert.Nil(t, got)
+				assert.Error(t, err)
+
+				if tt.expectedErr != nil {
+					assert.EqualError(t, err, tt.expectedErr.Error())
+				}
+
+				// Сбои хранилища не должны выглядеть как 401, иначе клиент выбросит рабочую сессию.
+				assert.Equal(t, tt.wantUnauthorized, errors.Is(err, apperror.ErrUnauthorized))
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, got.Token)
+				assert.NotEmpty(t, got.RefreshToken)
+				assert.NotEqual(t, validRefreshToken, got.RefreshToken)
+				assert.Equal(t, session.HashRefreshToken(got.RefreshToken), tt.fields.refreshTokenRotator.newHash)
+			}
+
+			if tt.expectRevoked {
+				assert.Equal(t, "sid", revoker.revoked)
+			} else {
+				assert.Empty(t, revoker.revoked)
+			}
+		})
+	}
+}
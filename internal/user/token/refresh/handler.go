@@ -0,0 +1,76 @@
+package refresh
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+type request struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type response struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+type RefreshExecutor interface {
+	Execute(ctx context.Context, in RefreshInput) (*RefreshResult, error)
+}
+
+type handler struct {
+	exec RefreshExecutor
+}
+
+func NewHandler(exec RefreshExecutor) *handler {
+	return &handler{exec: exec}
+}
+
+func (h *handler) Handle(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	defer r.Body.Close()
+
+	if err != nil {
+		apperror.Write(w, apperror.Validation("could not read request body"))
+
+		return
+	}
+
+	var req request
+
+	if err := json.Unmarshal(body, &req); err != nil {
+		apperror.Write(w, apperror.Validation("invalid json"))
+
+		return
+	}
+
+	result, err := h.exec.Execute(r.Context(), RefreshInput{RefreshToken: req.RefreshToken})
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	resp := response{
+		Token:        result.Token,
+		RefreshToken: result.RefreshToken,
+		ExpiresIn:    int(result.ExpiresIn.Seconds()),
+	}
+
+	respBytes, err := json.Marshal(resp)
+
+	if err != nil {
+		apperror.Write(w, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(respBytes)
+}
@@ -1,10 +1,11 @@
 package utils
 
 import (
-	"fmt"
 	"net/http"
 	"sync"
 	"time"
+
+	"mkk-luna-test-task/internal/apperror"
 )
 
 type CircuitState int
@@ -42,7 +43,7 @@ func (cb *CircuitBreakerRoundTripper) RoundTrip(req *http.Request) (*http.Respon
 		} else {
 			cb.mu.Unlock()
 
-			return nil, fmt.Errorf("circuit breaker is open")
+			return nil, apperror.Unavailable("circuit breaker is open", nil)
 		}
 	}
 
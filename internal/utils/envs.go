@@ -6,19 +6,33 @@ import (
 	"time"
 
 	"mkk-luna-test-task/internal/repository"
+	"mkk-luna-test-task/internal/storage"
 
 	"github.com/joho/godotenv"
 )
 
 type Envs struct {
-	Port           int
-	JwtSecret      string
-	JwtExpiration  time.Duration
-	MigrationsPath string
+	Port            int
+	JwtKeysDir      string
+	JwtSigningKeyId string
+	JwtExpiration   time.Duration
+	RefreshExpiry   time.Duration
+	MigrationsPath  string
+
+	InviteTokenSecret string
+	InviteExpiry      time.Duration
 
 	MySQL    repository.MySQLConfig
 	Redis    repository.RedisConfig
 	RedisTTL time.Duration
+
+	OverdueCheckInterval time.Duration
+
+	// AttachmentsStorage — где хранить файлы вложений: local или s3.
+	AttachmentsStorage string
+	AttachmentsDir     string
+	AttachmentMaxSize  int64
+	S3                 storage.S3Config
 }
 
 const envFilePath = ".env"
@@ -37,17 +51,40 @@ func LoadEnvs() (Envs, error) {
 		return Envs{}, err
 	}
 
-	envs.JwtSecret = os.Getenv("JWT_SECRET")
+	envs.JwtKeysDir = os.Getenv("JWT_KEYS_DIR")
+	envs.JwtSigningKeyId = os.Getenv("JWT_SIGNING_KEY_ID")
+
+	jwtExpStr := os.Getenv("JWT_EXPIRATION_MINUTES")
+
+	expMinutes, err := strconv.Atoi(jwtExpStr)
+
+	if err != nil {
+		return Envs{}, err
+	}
+
+	envs.JwtExpiration = time.Duration(expMinutes) * time.Minute
 
-	jwtExpStr := os.Getenv("JWT_EXPIRATION_HOURS")
+	refreshExpStr := os.Getenv("REFRESH_TOKEN_EXPIRATION_HOURS")
 
-	expHours, err := strconv.Atoi(jwtExpStr)
+	refreshExpHours, err := strconv.Atoi(refreshExpStr)
 
 	if err != nil {
 		return Envs{}, err
 	}
 
-	envs.JwtExpiration = time.Duration(expHours) * time.Hour
+	envs.RefreshExpiry = time.Duration(refreshExpHours) * time.Hour
+
+	envs.InviteTokenSecret = os.Getenv("INVITE_TOKEN_SECRET")
+
+	inviteExpStr := os.Getenv("INVITE_EXPIRATION_HOURS")
+
+	inviteExpHours, err := strconv.Atoi(inviteExpStr)
+
+	if err != nil {
+		return Envs{}, err
+	}
+
+	envs.InviteExpiry = time.Duration(inviteExpHours) * time.Hour
 
 	envs.MigrationsPath = os.Getenv("MIGRATIONS_PATH")
 
@@ -145,5 +182,48 @@ func LoadEnvs() (Envs, error) {
 
 	envs.RedisTTL = time.Duration(redisTTLMinutes) * time.Minute
 
+	overdueIntervalStr := os.Getenv("OVERDUE_CHECK_INTERVAL_SECONDS")
+
+	overdueIntervalSec, err := strconv.Atoi(overdueIntervalStr)
+
+	if err != nil {
+		return Envs{}, err
+	}
+
+	envs.OverdueCheckInterval = time.Duration(overdueIntervalSec) * time.Second
+
+	envs.AttachmentsStorage = os.Getenv("ATTACHMENTS_STORAGE")
+	envs.AttachmentsDir = os.Getenv("ATTACHMENTS_DIR")
+
+	attachmentMaxSizeStr := os.Getenv("ATTACHMENTS_MAX_SIZE_MB")
+
+	attachmentMaxSizeMB, err := strconv.Atoi(attachmentMaxSizeStr)
+
+	if err != nil {
+		return Envs{}, err
+	}
+
+	envs.AttachmentMaxSize = int64(attachmentMaxSizeMB) << 20
+
+	// Для локального хранилища S3_PATH_STYLE можно не задавать.
+	s3PathStyle := false
+
+	if s3PathStyleStr := os.Getenv("S3_PATH_STYLE"); s3PathStyleStr != "" {
+		s3PathStyle, err = strconv.ParseBool(s3PathStyleStr)
+
+		if err != nil {
+			return Envs{}, err
+		}
+	}
+
+	envs.S3 = storage.S3Config{
+		Endpoint:        os.Getenv("S3_ENDPOINT"),
+		Region:          os.Getenv("S3_REGION"),
+		Bucket:          os.Getenv("S3_BUCKET"),
+		AccessKeyId:     os.Getenv("S3_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		PathStyle:       s3PathStyle,
+	}
+
 	return envs, nil
 }
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"golang.org/x/time/rate"
+
+	"mkk-luna-test-task/internal/apperror"
 )
 
 const (
@@ -47,7 +49,7 @@ func (m *RateLimitingMiddleware) Handle(w http.ResponseWriter, r *http.Request)
 	limiter := m.getLimiter(key)
 
 	if !limiter.Allow() {
-		http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+		apperror.Write(w, apperror.RateLimited("rate limit exceeded"))
 
 		return
 	}
@@ -0,0 +1,157 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"mkk-luna-test-task/internal/apperror"
+)
+
+// Struct проверяет поля структуры запроса по тегу `validate` и собирает ошибки по всем полям сразу.
+//
+// Поддерживаемые правила (через запятую):
+//   - required — строка не пустая (пробелы не считаются), число не ноль;
+//   - min=N, max=N — длина строки в символах или значение числа;
+//   - oneof=a b c — значение строки из перечисленных; пустая строка пропускается, если нет required.
+//
+// Поле-указатель равное nil считается непереданным и не проверяется (частичное обновление),
+// иначе правила применяются к значению, на которое оно указывает.
+//
+// Имя поля в ошибке берётся из тега `json`.
+func Struct(v any) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	rt := rv.Type()
+
+	var fields []apperror.FieldError
+
+	for i := 0; i < rt.NumField(); i++ {
+		tag := rt.Field(i).Tag.Get("validate")
+
+		if tag == "" {
+			continue
+		}
+
+		field := rv.Field(i)
+
+		if field.Kind() == reflect.Pointer {
+			if field.IsNil() {
+				continue
+			}
+
+			field = field.Elem()
+		}
+
+		if msg := checkField(field, tag); msg != "" {
+			fields = append(fields, apperror.FieldError{Field: fieldName(rt.Field(i)), Message: msg})
+		}
+	}
+
+	if len(fields) > 0 {
+		return apperror.InvalidFields(fields...)
+	}
+
+	return nil
+}
+
+func fieldName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+
+	if name == "" || name == "-" {
+		return f.Name
+	}
+
+	return name
+}
+
+// checkField возвращает текст первой нарушенной проверки или пустую строку.
+func checkField(v reflect.Value, tag string) string {
+	rules := strings.Split(tag, ",")
+
+	for _, rule := range rules {
+		if rule == "required" && isZero(v) {
+			return "is required"
+		}
+	}
+
+	// Необязательное пустое поле остальные правила не проверяют.
+	if isZero(v) {
+		return ""
+	}
+
+	for _, rule := range rules {
+		name, arg, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "required":
+		case "min":
+			if size(v) < mustInt(arg) {
+				return tooSmall(v, arg)
+			}
+		case "max":
+			if size(v) > mustInt(arg) {
+				return tooLarge(v, arg)
+			}
+		case "oneof":
+			allowed := strings.Fields(arg)
+
+			if !slices.Contains(allowed, v.String()) {
+				return "must be one of: " + strings.Join(allowed, ", ")
+			}
+		default:
+			panic(fmt.Sprintf("validation: unknown rule %q", name))
+		}
+	}
+
+	return ""
+}
+
+func isZero(v reflect.Value) bool {
+	if v.Kind() == reflect.String {
+		return strings.TrimSpace(v.String()) == ""
+	}
+
+	return v.IsZero()
+}
+
+func size(v reflect.Value) int {
+	switch v.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int())
+	case reflect.Slice, reflect.Map:
+		return v.Len()
+	default:
+		panic(fmt.Sprintf("validation: min/max not supported for %s", v.Kind()))
+	}
+}
+
+func tooSmall(v reflect.Value, arg string) string {
+	if v.Kind() == reflect.String {
+		return "must be at least " + arg + " characters"
+	}
+
+	return "must be at least " + arg
+}
+
+func tooLarge(v reflect.Value, arg string) string {
+	if v.Kind() == reflect.String {
+		return "must be at most " + arg + " characters"
+	}
+
+	return "must be at most " + arg
+}
+
+func mustInt(s string) int {
+	n, err := strconv.Atoi(s)
+
+	if err != nil {
+		panic(fmt.Sprintf("validation: invalid rule argument %q", s))
+	}
+
+	return n
+}
@@ -0,0 +1,95 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"mkk-luna-test-task/internal/apperror"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type request struct {
+	Status      string `json:"status" validate:"required,oneof=todo in_progress done"`
+	Title       string `json:"title" validate:"required,max=5"`
+	Description string `json:"description" validate:"min=2"`
+	TeamId      int    `json:"team_id" validate:"required,min=1"`
+	Ignored     string `json:"ignored"`
+}
+
+type patchRequest struct {
+	Title      *string `json:"title" validate:"required,max=5"`
+	AssigneeId *int    `json:"assignee_id" validate:"required,min=1"`
+}
+
+func TestStruct_Pointers(t *testing.T) {
+	empty := ""
+	short := "ok"
+	negative := -1
+
+	assert.NoError(t, Struct(patchRequest{}))
+	assert.NoError(t, Struct(patchRequest{Title: &short}))
+
+	var appErr *apperror.Error
+
+	assert.True(t, errors.As(Struct(patchRequest{Title: &empty, AssigneeId: &negative}), &appErr))
+	assert.Equal(t, []apperror.FieldError{
+		{Field: "title", Message: "is required"},
+		{Field: "assignee_id", Message: "must be at least 1"},
+	}, appErr.Fields)
+}
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        request
+		wantFields []apperror.FieldError
+	}{
+		{
+			name: "valid",
+			req:  request{Status: "todo", Title: "ok", TeamId: 1},
+		},
+		{
+			name: "required fields missing",
+			req:  request{Title: "   "},
+			wantFields: []apperror.FieldError{
+				{Field: "status", Message: "is required"},
+				{Field: "title", Message: "is required"},
+				{Field: "team_id", Message: "is required"},
+			},
+		},
+		{
+			name: "bounds and enum",
+			req:  request{Status: "archived", Title: "слишком", Description: "x", TeamId: -3},
+			wantFields: []apperror.FieldError{
+				{Field: "status", Message: "must be one of: todo, in_progress, done"},
+				{Field: "title", Message: "must be at most 5 characters"},
+				{Field: "description", Message: "must be at least 2 characters"},
+				{Field: "team_id", Message: "must be at least 1"},
+			},
+		},
+		{
+			name: "length counts characters, not bytes",
+			req:  request{Status: "done", Title: strings.Repeat("я", 5), TeamId: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Struct(tt.req)
+
+			if tt.wantFields == nil {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var appErr *apperror.Error
+
+			assert.True(t, errors.As(err, &appErr))
+			assert.Equal(t, apperror.KindValidation, appErr.Kind)
+			assert.Equal(t, tt.wantFields, appErr.Fields)
+		})
+	}
+}